@@ -15,6 +15,7 @@ type App struct {
 	metadatasAPI     *api.MetadatasAPI
 	appCache         *api.AppCacheApi
 	systemServiceAPI *api.SystemServiceAPI
+	queryAPI         *api.QueryAPI
 }
 
 // NewApp creates a new App application struct
@@ -24,6 +25,7 @@ func NewApp() *App {
 		metadatasAPI:     api.NewMetadatasAPI(),
 		appCache:         api.NewAppCacheApi(),
 		systemServiceAPI: api.NewSystemServiceAPI(),
+		queryAPI:         api.NewQueryAPI(),
 	}
 }
 
@@ -32,6 +34,7 @@ func NewApp() *App {
 func (a *App) Startup(ctx context.Context) {
     a.ctx = ctx
     a.systemServiceAPI.Init(ctx)
+    a.queryAPI.Init(ctx)
     a.sqliteAPI.Init()
 
     // 取消默认目录的缓存初始化：仅在前端选择/创建项目时初始化
@@ -24,9 +24,13 @@ func (a *MetadatasAPI) TestConnection(config connect.Config) error {
 	return service.TestConnection(config)
 }
 
-// InitCache 初始化缓存（按项目目录）
+// InitCache 初始化缓存（按项目目录），首次打开时导入旧版页面树与页面连线
 func (a *MetadatasAPI) InitCache(projectDir string) error {
-	return service.InitMetadataStorageService(projectDir)
+	if err := service.InitMetadataStorageService(projectDir); err != nil {
+		return err
+	}
+	service.ImportLegacyCacheOnce(projectDir)
+	return nil
 }
 
 func (a *MetadatasAPI) CloseAllConnections() error {
@@ -75,9 +79,239 @@ func (a *MetadatasAPI) ClearTableVOCacheByTableID(tableID int64) error {
     return nil
 }
 
-// ParseViewSQL 解析视图SQL语句，返回字段信息
-func (a *MetadatasAPI) ParseViewSQL(sql string) ([]models.FieldInfoVO, error) {
-    return service.ParseViewSQL(sql)
+// ParseViewSQL 按视图所在数据库的方言解析视图SQL语句，返回字段信息与解析警告
+func (a *MetadatasAPI) ParseViewSQL(databaseID int64, sql string) (models.ViewParseResult, error) {
+    return service.ParseViewSQL(databaseID, sql)
+}
+
+// ParseViewByID 按视图所在连接的方言解析视图定义，返回字段与解析警告
+func (a *MetadatasAPI) ParseViewByID(viewID int64) (models.ViewParseResult, error) {
+    return service.ParseViewByID(viewID)
+}
+
+// GetColumnUpstream 获取视图字段的上游血缘（穿过多层视图直到基础表），column 为空时取全部字段
+func (a *MetadatasAPI) GetColumnUpstream(viewID int64, column string, maxDepth int) (models.LineageGraph, error) {
+    return service.GetColumnUpstream(viewID, column, maxDepth)
+}
+
+// GetColumnDownstream 获取表或视图字段的下游血缘，sourceKind 为 table 或 view
+func (a *MetadatasAPI) GetColumnDownstream(sourceKind string, sourceID int64, column string, maxDepth int) (models.LineageGraph, error) {
+    return service.GetColumnDownstream(sourceKind, sourceID, column, maxDepth)
+}
+
+// RefreshViewLineage 重新解析数据库内的视图并重建字段血缘
+func (a *MetadatasAPI) RefreshViewLineage(databaseID int64) error {
+    return service.RefreshViewLineage(databaseID)
+}
+
+// InferJoinRelationships 从视图定义（可选包括查询历史）的等值连接推断候选关系，仅作建议
+func (a *MetadatasAPI) InferJoinRelationships(databaseID int64, includeHistory bool) ([]models.RelationshipCandidate, error) {
+    return service.InferJoinRelationships(databaseID, includeHistory)
+}
+
+// AcceptRelationshipCandidate 将候选关系作为连线添加到页面
+func (a *MetadatasAPI) AcceptRelationshipCandidate(pageID string, candidate models.RelationshipCandidate) error {
+    return service.AcceptRelationshipCandidate(pageID, candidate)
+}
+
+// InferNamingRelationships 按项目命名规则推断候选关系，仅作建议
+func (a *MetadatasAPI) InferNamingRelationships(databaseID int64) ([]models.RelationshipCandidate, error) {
+    return service.InferNamingRelationships(databaseID)
+}
+
+// GetNamingRules 获取当前项目的命名推断规则
+func (a *MetadatasAPI) GetNamingRules() (models.NamingRules, error) {
+    return service.GetNamingRules()
+}
+
+// SaveNamingRules 保存当前项目的命名推断规则
+func (a *MetadatasAPI) SaveNamingRules(rules models.NamingRules) error {
+    return service.SaveNamingRules(rules)
+}
+
+// VerifyRelationship 在真实数据上校验关系：孤儿行、空值率与实际基数
+func (a *MetadatasAPI) VerifyRelationship(req models.RelationshipCheckRequest) (models.RelationshipCheckResult, error) {
+    return service.VerifyRelationship(req)
+}
+
+// ListRelationships 列出关系；pageID 为 "*" 时不限页面，tableID 非0时仅返回涉及该表的关系
+func (a *MetadatasAPI) ListRelationships(pageID string, tableID int64) ([]models.RelationshipVO, error) {
+    return service.ListRelationships(pageID, tableID)
+}
+
+// GetRelationship 获取单个关系
+func (a *MetadatasAPI) GetRelationship(id int64) (models.RelationshipVO, error) {
+    return service.GetRelationship(id)
+}
+
+// CreateRelationship 新增关系
+func (a *MetadatasAPI) CreateRelationship(rel models.RelationshipVO) (models.RelationshipVO, error) {
+    return service.CreateRelationship(rel)
+}
+
+// UpdateRelationship 更新关系
+func (a *MetadatasAPI) UpdateRelationship(rel models.RelationshipVO) (models.RelationshipVO, error) {
+    return service.UpdateRelationship(rel)
+}
+
+// DeleteRelationship 删除关系
+func (a *MetadatasAPI) DeleteRelationship(id int64) error {
+    return service.DeleteRelationship(id)
+}
+
+// ImportBoltRelationships 将页面缓存中的连线导入为关系，pageID 为空时导入所有页面
+func (a *MetadatasAPI) ImportBoltRelationships(pageID string) (models.RelationshipImportResult, error) {
+    return service.ImportBoltRelationships(pageID)
+}
+
+// GetPageTree 获取页面树
+func (a *MetadatasAPI) GetPageTree() ([]models.PageVO, error) {
+    return service.GetPageTree()
+}
+
+// CreatePage 新建页面或分组
+func (a *MetadatasAPI) CreatePage(page models.PageVO) (models.PageVO, error) {
+    return service.CreatePage(page)
+}
+
+// UpdatePage 更新页面名称、图标、展开状态与样式
+func (a *MetadatasAPI) UpdatePage(page models.PageVO) (models.PageVO, error) {
+    return service.UpdatePage(page)
+}
+
+// MovePage 移动页面，position 为 before / after / inside
+func (a *MetadatasAPI) MovePage(key string, targetKey string, position string) error {
+    return service.MovePage(key, targetKey, position)
+}
+
+// DeletePage 删除页面或分组及其内容
+func (a *MetadatasAPI) DeletePage(key string) error {
+    return service.DeletePage(key)
+}
+
+// GetPageContent 获取页面上的节点与关系
+func (a *MetadatasAPI) GetPageContent(key string) (models.PageContent, error) {
+    return service.GetPageContent(key)
+}
+
+// SavePageNodes 保存页面上的全部节点
+func (a *MetadatasAPI) SavePageNodes(key string, nodes []models.PageNodeVO) ([]models.PageNodeVO, error) {
+    return service.SavePageNodes(key, nodes)
+}
+
+// ValidatePages 校验页面引用的表、视图与关系，key 为空时校验所有页面
+func (a *MetadatasAPI) ValidatePages(key string) ([]models.PageIssue, error) {
+    return service.ValidatePages(key)
+}
+
+// SearchPages 按名称搜索页面及页面上的表和视图
+func (a *MetadatasAPI) SearchPages(keyword string) ([]models.PageSearchHit, error) {
+    return service.SearchPages(keyword)
+}
+
+// GetPageTabs 获取打开的页面标签
+func (a *MetadatasAPI) GetPageTabs() (models.PageTabState, error) {
+    return service.GetPageTabs()
+}
+
+// SavePageTabs 保存打开的页面标签
+func (a *MetadatasAPI) SavePageTabs(state models.PageTabState) error {
+    return service.SavePageTabs(state)
+}
+
+// ImportBoltPages 将页面缓存中的页面树与节点导入
+func (a *MetadatasAPI) ImportBoltPages() (models.PageImportResult, error) {
+    return service.ImportBoltPages()
+}
+
+// LayoutPage 计算页面自动布局（分层或力导向），opts.Save 为 true 时保存节点位置
+func (a *MetadatasAPI) LayoutPage(key string, opts models.LayoutOptions) (models.LayoutResult, error) {
+    return service.LayoutPage(key, opts)
+}
+
+// GeneratePageFromNeighbourhood 按起始表的关系邻域新建页面并自动布局
+func (a *MetadatasAPI) GeneratePageFromNeighbourhood(req models.NeighbourhoodRequest) (models.NeighbourhoodResult, error) {
+    return service.GeneratePageFromNeighbourhood(req)
+}
+
+// ExportMarkdownDictionary 导出 Markdown 数据字典（单文件或每表一个文件）
+func (a *MetadatasAPI) ExportMarkdownDictionary(opts models.DocOptions) (models.DocResult, error) {
+    return service.ExportMarkdownDictionary(opts)
+}
+
+// ExportHTMLSite 生成静态 HTML 文档站点
+func (a *MetadatasAPI) ExportHTMLSite(opts models.DocOptions) (models.DocResult, error) {
+    return service.ExportHTMLSite(opts)
+}
+
+// ExportXLSXDictionary 导出 Excel 数据字典工作簿
+func (a *MetadatasAPI) ExportXLSXDictionary(opts models.DocOptions) (models.DocResult, error) {
+    return service.ExportXLSXDictionary(opts)
+}
+
+// PreviewXLSXDictionaryImport 预览编辑后的数据字典工作簿将产生的备注与别名修改
+func (a *MetadatasAPI) PreviewXLSXDictionaryImport(req models.DictionaryImportRequest) (models.DictionaryImportPreview, error) {
+    return service.PreviewXLSXDictionaryImport(req)
+}
+
+// ApplyDictionaryChanges 提交确认后的数据字典修改
+func (a *MetadatasAPI) ApplyDictionaryChanges(changes []models.DictionaryChange) (int, error) {
+    return service.ApplyDictionaryChanges(changes)
+}
+
+// GenerateDDL 生成表、视图或页面的建表脚本
+func (a *MetadatasAPI) GenerateDDL(req models.DDLRequest) (models.DDLResult, error) {
+    return service.GenerateDDL(req)
+}
+
+// ConvertSchema 将表定义转换为另一种数据库方言的建表脚本
+func (a *MetadatasAPI) ConvertSchema(req models.ConversionRequest) (models.ConversionResult, error) {
+    return service.ConvertSchema(req)
+}
+
+// ExportPageDiagram 将页面导出为 Mermaid、PlantUML、Graphviz DOT 或 DBML 文本
+func (a *MetadatasAPI) ExportPageDiagram(req models.DiagramExportRequest) (models.DiagramExportResult, error) {
+    return service.ExportPageDiagram(req)
+}
+
+// ImportSchemaFile 将 DBML 或 Prisma 设计文件导入为只读的虚拟连接
+func (a *MetadatasAPI) ImportSchemaFile(req models.SchemaFileImportRequest) (models.SchemaFileImportResult, error) {
+    return service.ImportSchemaFile(req)
+}
+
+// DiffConnectionSchemas 比较两个连接（如设计文件与真实数据库）的表结构
+func (a *MetadatasAPI) DiffConnectionSchemas(req models.SchemaDiffRequest) (models.SchemaDiffResult, error) {
+    return service.DiffConnectionSchemas(req)
+}
+
+// GenerateCode 根据选中表的元数据生成模型代码
+func (a *MetadatasAPI) GenerateCode(req models.CodeGenRequest) (models.CodeGenResult, error) {
+    return service.GenerateCode(req)
+}
+
+// ListCodeLanguages 支持生成代码的语言
+func (a *MetadatasAPI) ListCodeLanguages() []string {
+    return service.ListCodeLanguages()
+}
+
+// GetCodeTemplate 获取语言当前使用的代码模板
+func (a *MetadatasAPI) GetCodeTemplate(language string) (models.CodeTemplate, error) {
+    return service.GetCodeTemplate(language)
+}
+
+// SaveCodeTemplate 保存自定义代码模板，空模板恢复默认
+func (a *MetadatasAPI) SaveCodeTemplate(tpl models.CodeTemplate) error {
+    return service.SaveCodeTemplate(tpl)
+}
+
+// ExportSchemas 将选中的表导出为 JSON Schema 或 OpenAPI 文档
+func (a *MetadatasAPI) ExportSchemas(req models.SchemaExportRequest) (models.SchemaExportResult, error) {
+    return service.ExportSchemas(req)
+}
+
+// RenderPageImage 将页面 ER 图渲染为 SVG 或 PNG
+func (a *MetadatasAPI) RenderPageImage(req models.DiagramRenderRequest) (models.DiagramRenderResult, error) {
+    return service.RenderPageImage(req)
 }
 
 // UpdateFieldsSortByTableID 根据表ID批量更新字段排序
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"dbrun/app/export"
+	"dbrun/app/models"
+	"dbrun/app/service"
+	meta "dbrun/app/sqlite/metadata"
+	"dbrun/app/sqlparse"
+	"fmt"
+	"strings"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// QueryAPI 暴露给前端的SQL查询相关方法
+type QueryAPI struct {
+	ctx context.Context
+}
+
+// NewQueryAPI 创建新的查询API实例
+func NewQueryAPI() *QueryAPI {
+	return &QueryAPI{}
+}
+
+// Init 保存运行时上下文
+func (a *QueryAPI) Init(ctx context.Context) {
+	a.ctx = ctx
+}
+
+// ClassifySQL 按连接方言对SQL语句分类（只读/写入）
+func (a *QueryAPI) ClassifySQL(configID int64, sql string) ([]sqlparse.Statement, error) {
+	return service.ClassifySQL(configID, sql)
+}
+
+// ExecuteQuery 执行SQL，只读连接上的DML/DDL会被拒绝
+func (a *QueryAPI) ExecuteQuery(configID int64, sql string, maxRows int) ([]models.QueryResult, error) {
+	return service.ExecuteQuery(configID, sql, maxRows)
+}
+
+// PreviewTableData 分页预览表数据，支持列过滤与排序
+func (a *QueryAPI) PreviewTableData(tableID int64, filters []models.ColumnFilter, sort []models.SortOrder, page models.PageRequest) (models.TablePreview, error) {
+	return service.PreviewTableData(tableID, filters, sort, page)
+}
+
+// SearchQueryHistory 检索查询历史
+func (a *QueryAPI) SearchQueryHistory(filter meta.HistoryFilter) (meta.HistoryPage, error) {
+	return service.SearchQueryHistory(filter)
+}
+
+// DeleteQueryHistory 删除指定的历史记录
+func (a *QueryAPI) DeleteQueryHistory(ids []int64) error {
+	return service.DeleteQueryHistory(ids)
+}
+
+// ClearQueryHistory 清空历史记录，configID 为0时清空全部
+func (a *QueryAPI) ClearQueryHistory(configID int64) error {
+	return service.ClearQueryHistory(configID)
+}
+
+// SaveSnippet 新增或更新SQL片段
+func (a *QueryAPI) SaveSnippet(snippet meta.QuerySnippet) (meta.QuerySnippet, error) {
+	return service.SaveSnippet(snippet)
+}
+
+// ListSnippets 列出SQL片段，可按文件夹与关键字过滤
+func (a *QueryAPI) ListSnippets(folder string, keyword string) ([]meta.QuerySnippet, error) {
+	return service.ListSnippets(folder, keyword)
+}
+
+// DeleteSnippet 删除SQL片段
+func (a *QueryAPI) DeleteSnippet(id int64) error {
+	return service.DeleteSnippet(id)
+}
+
+// ExportSnippets 将片段导出到项目目录的 snippets 子目录
+func (a *QueryAPI) ExportSnippets() (int, error) {
+	return service.ExportSnippets()
+}
+
+// ImportSnippets 从项目目录的 snippets 子目录导入片段
+func (a *QueryAPI) ImportSnippets() (int, error) {
+	return service.ImportSnippets()
+}
+
+// 导出进度事件名
+const exportProgressEvent = "export:progress"
+
+// emitExportProgress 向前端推送导出进度
+func (a *QueryAPI) emitExportProgress(p models.ExportProgress) {
+	if a.ctx != nil {
+		runtime.EventsEmit(a.ctx, exportProgressEvent, p)
+	}
+}
+
+// ExportQuery 将查询结果导出为 CSV/NDJSON/XLSX/SQL 文件，进度通过 export:progress 事件推送
+func (a *QueryAPI) ExportQuery(configID int64, sql string, opts models.ExportOptions) (models.ExportResult, error) {
+	return service.ExportQuery(configID, sql, opts, a.emitExportProgress)
+}
+
+// ExportTableData 按过滤与排序条件导出表数据
+func (a *QueryAPI) ExportTableData(tableID int64, filters []models.ColumnFilter, sort []models.SortOrder, opts models.ExportOptions) (models.ExportResult, error) {
+	return service.ExportTableData(tableID, filters, sort, opts, a.emitExportProgress)
+}
+
+// CancelExport 取消正在进行的导出
+func (a *QueryAPI) CancelExport(exportID string) bool {
+	return service.CancelExport(exportID)
+}
+
+// SelectExportFile 打开保存文件对话框选择导出目标
+func (a *QueryAPI) SelectExportFile(defaultName string, format string) (string, error) {
+	if a.ctx == nil {
+		return "", fmt.Errorf("context is not set")
+	}
+	ext := export.DefaultExtension(format)
+	return runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "导出数据",
+		DefaultFilename: defaultName + ext,
+		Filters:         []runtime.FileFilter{{DisplayName: strings.ToUpper(strings.TrimPrefix(ext, ".")), Pattern: "*" + ext}},
+	})
+}
+
+// ExplainQuery 获取语句的执行计划并以统一的计划树返回
+func (a *QueryAPI) ExplainQuery(configID int64, sql string) (models.ExplainResult, error) {
+	return service.ExplainQuery(configID, sql)
+}
+
+// GetExplainPlan 读取历史记录中保存的执行计划
+func (a *QueryAPI) GetExplainPlan(historyID int64) (models.ExplainResult, error) {
+	return service.GetExplainPlan(historyID)
+}
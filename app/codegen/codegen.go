@@ -0,0 +1,389 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// 支持的目标语言
+const (
+	LangGo         = "go"         // 结构体，带 json / db / gorm 标签
+	LangTypeScript = "typescript" // 接口
+	LangJava       = "java"       // JPA 实体类，每个类一个文件
+	LangKotlin     = "kotlin"     // JPA 实体类
+	LangPython     = "python"     // dataclass
+	LangSQLAlchemy = "sqlalchemy" // SQLAlchemy 2.0 声明式模型
+)
+
+// Languages 支持的目标语言，按界面显示顺序
+var Languages = []string{LangGo, LangTypeScript, LangJava, LangKotlin, LangPython, LangSQLAlchemy}
+
+// Table 待生成代码的表
+type Table struct {
+	Database  string
+	Schema    string
+	Name      string
+	ModelName string // 用于生成类名的名称（如表名的单数形式），为空时使用表名
+	Comment   string // 别名、注释与备注，多行
+	Dialect   string // 表所属连接的方言，决定类型映射
+	Columns   []Column
+}
+
+// Column 待生成代码的字段
+type Column struct {
+	Name          string
+	Type          string // 数据库中的类型原文
+	Comment       string
+	Nullable      bool
+	PrimaryKey    bool
+	AutoIncrement bool
+	Default       string
+}
+
+// Options 代码生成选项；Template 非空时替换该语言的默认模板
+type Options struct {
+	Language string
+	Package  string // Go 包名、Java / Kotlin 包名，其他语言忽略
+	Template string
+}
+
+// File 生成的源文件
+type File struct {
+	Name    string
+	Content string
+}
+
+// Unit 模板数据：一个源文件及其中的模型
+type Unit struct {
+	Language string
+	Package  string
+	Imports  []string // Go 为导入路径，Java / Kotlin 为类全名，Python 为完整的 import 语句
+	Models   []Model
+}
+
+// Model 一个表对应的结构体或类
+type Model struct {
+	Database   string
+	Schema     string
+	Table      string
+	Name       string
+	Comment    string
+	Fields     []Field
+	PrimaryKey []string // 主键字段名（语言中的名称）
+}
+
+// Field 字段在目标语言中的表示
+type Field struct {
+	Column        string // 列名
+	Name          string // 语言中的名称
+	Type          string // 语言中的类型，可空字段已写为指针 / 可选类型
+	BaseType      string // 不含可空修饰的类型
+	DBType        string // 数据库中的类型原文
+	Kind          string // 与方言无关的类型分类
+	ORMType       string // SQLAlchemy 的列类型表达式
+	Length        int    // 字符串类型的长度，未知或不限时为 0
+	Nullable      bool
+	PrimaryKey    bool
+	AutoIncrement bool
+	Default       string
+	Comment       string
+}
+
+// Generate 按语言生成源文件，返回的警告列出无法精确映射的类型等需要人工确认的内容
+func Generate(tables []Table, opts Options) ([]File, []string, error) {
+	lang, ok := languages[opts.Language]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported language: %s", opts.Language)
+	}
+	text := opts.Template
+	if text == "" {
+		text = lang.template
+	}
+	tpl, err := Parse(opts.Language, text)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkg := opts.Package
+	if pkg == "" {
+		pkg = lang.pkg
+	}
+
+	var warnings []string
+	var models []Model
+	var imports [][]string
+	names := map[string]bool{}
+	for _, t := range tables {
+		m, used, warn := buildModel(lang, t, names)
+		models = append(models, m)
+		imports = append(imports, used)
+		warnings = append(warnings, warn...)
+	}
+
+	var units []Unit
+	var fileNames []string
+	if lang.perModel {
+		for i, m := range models {
+			units = append(units, Unit{Language: opts.Language, Package: pkg, Imports: lang.imports(imports[i]), Models: []Model{m}})
+			fileNames = append(fileNames, m.Name+lang.ext)
+		}
+	} else {
+		var all []string
+		for _, used := range imports {
+			all = append(all, used...)
+		}
+		units = append(units, Unit{Language: opts.Language, Package: pkg, Imports: lang.imports(all), Models: models})
+		fileNames = append(fileNames, lang.file)
+	}
+
+	files := make([]File, 0, len(units))
+	for i, u := range units {
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, u); err != nil {
+			return nil, warnings, fmt.Errorf("render %s failed: %w", fileNames[i], err)
+		}
+		content := buf.Bytes()
+		if opts.Language == LangGo {
+			// 自定义模板生成的代码无法格式化时保留原样，由用户自行检查
+			if formatted, err := format.Source(content); err == nil {
+				content = formatted
+			} else {
+				warnings = append(warnings, fmt.Sprintf("%s is not valid Go source: %v", fileNames[i], err))
+			}
+		}
+		files = append(files, File{Name: fileNames[i], Content: string(content)})
+	}
+	return files, warnings, nil
+}
+
+// DefaultTemplate 语言的默认模板，可复制后修改
+func DefaultTemplate(language string) (string, error) {
+	lang, ok := languages[language]
+	if !ok {
+		return "", fmt.Errorf("unsupported language: %s", language)
+	}
+	return lang.template, nil
+}
+
+// Parse 解析模板，可用于保存自定义模板前的校验
+func Parse(language, text string) (*template.Template, error) {
+	tpl, err := template.New(language).Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", language, err)
+	}
+	return tpl, nil
+}
+
+// buildModel 映射表与字段的名称与类型，返回用到的导入项；used 记录已用的模型名，重名时改名并给出警告
+func buildModel(lang language, t Table, used map[string]bool) (Model, []string, []string) {
+	m := Model{Database: t.Database, Schema: t.Schema, Table: t.Name, Comment: t.Comment}
+	convert := func(s string) string { return lang.escape(lang.className(s)) }
+	var imports, warnings []string
+	m.Name = uniqueName(t, used, convert)
+	name := t.ModelName
+	if name == "" {
+		name = t.Name
+	}
+	if want := convert(name); m.Name != want {
+		warnings = append(warnings, fmt.Sprintf("%s: model name %s is already used, renamed to %s", t.Name, want, m.Name))
+	}
+	for _, c := range t.Columns {
+		mapped := lang.mapType(t.Dialect, c)
+		if mapped.warning != "" {
+			warnings = append(warnings, fmt.Sprintf("%s.%s: %s", t.Name, c.Name, mapped.warning))
+		}
+		imports = append(imports, mapped.imports...)
+		f := Field{
+			Column: c.Name, Name: lang.escape(lang.fieldName(c.Name)), Type: mapped.typ, BaseType: mapped.base,
+			DBType: c.Type, Kind: mapped.kind, ORMType: mapped.orm, Length: mapped.length,
+			Nullable: c.Nullable && !c.PrimaryKey, PrimaryKey: c.PrimaryKey, AutoIncrement: c.AutoIncrement || mapped.serial,
+			Default: c.Default, Comment: c.Comment,
+		}
+		m.Fields = append(m.Fields, f)
+		if f.PrimaryKey {
+			m.PrimaryKey = append(m.PrimaryKey, f.Name)
+		}
+	}
+	if lang.requiredFirst {
+		// dataclass 中带默认值的字段必须位于无默认值字段之后
+		sort.SliceStable(m.Fields, func(i, j int) bool { return !m.Fields[i].Nullable && m.Fields[j].Nullable })
+	}
+	if len(m.PrimaryKey) > 1 && lang.compositeKeyWarning != "" {
+		warnings = append(warnings, fmt.Sprintf("%s: %s", t.Name, lang.compositeKeyWarning))
+	}
+	return m, imports, warnings
+}
+
+// commonInitialisms Go 命名中保持全大写的缩写
+var commonInitialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DB": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SQL": true, "TCP": true, "UI": true, "URI": true, "URL": true,
+	"UUID": true, "XML": true,
+}
+
+// words 将名称拆分为单词：下划线、连字符、空格与驼峰边界都视为分隔
+func words(name string) []string {
+	var out []string
+	var cur []rune
+	rs := []rune(name)
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = nil
+		}
+	}
+	for i, r := range rs {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(cur) > 0:
+			prev := rs[i-1]
+			nextLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return out
+}
+
+func capitalize(w string) string {
+	rs := []rune(strings.ToLower(w))
+	rs[0] = unicode.ToUpper(rs[0])
+	return string(rs)
+}
+
+// Pascal 转为大驼峰，如 order_items -> OrderItems
+func Pascal(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		b.WriteString(capitalize(w))
+	}
+	return identStart(b.String())
+}
+
+// Camel 转为小驼峰，如 created_at -> createdAt
+func Camel(name string) string {
+	ws := words(name)
+	var b strings.Builder
+	for i, w := range ws {
+		if i == 0 {
+			b.WriteString(strings.ToLower(w))
+			continue
+		}
+		b.WriteString(capitalize(w))
+	}
+	return identStart(b.String())
+}
+
+// Snake 转为小写下划线形式，如 createdAt -> created_at
+func Snake(name string) string {
+	ws := words(name)
+	for i := range ws {
+		ws[i] = strings.ToLower(ws[i])
+	}
+	return identStart(strings.Join(ws, "_"))
+}
+
+// goName 大驼峰并保留常见缩写，如 user_id -> UserID
+func goName(name string) string {
+	var b strings.Builder
+	for _, w := range words(name) {
+		if upper := strings.ToUpper(w); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(capitalize(w))
+	}
+	return identStart(b.String())
+}
+
+// identStart 名称为空或以数字开头时加前缀，保证是合法标识符
+func identStart(s string) string {
+	if s == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(s)[0]) {
+		return "X" + s
+	}
+	return s
+}
+
+// comment 为每行加上前缀，用于生成多行文档注释
+func comment(prefix, text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(prefix+strings.TrimSpace(l), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// oneLine 将多行说明合并为一行，用于行尾注释与注解参数
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+var funcs = template.FuncMap{
+	"pascal":  Pascal,
+	"camel":   Camel,
+	"snake":   Snake,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"join":    strings.Join,
+	"quote":   strconv.Quote,
+	"comment": comment,
+	"oneLine": oneLine,
+	"gormTag": gormTag,
+	// docSafe 避免说明中的 */ 提前结束块注释
+	"docSafe": func(s string) string { return strings.ReplaceAll(s, "*/", "*\\/") },
+	// kotlinQuote Kotlin 字符串中的 $ 表示模板，需要转义
+	"kotlinQuote": func(s string) string { return strings.ReplaceAll(strconv.Quote(s), "$", `\$`) },
+	"pyDoc":       pyDoc,
+}
+
+// pyDoc Python 文档字符串内容：转义引号与反斜杠，多行时后续行按类体缩进
+func pyDoc(text, indent string) string {
+	text = strings.NewReplacer(`\`, `\\`, `"""`, `\"\"\"`).Replace(strings.TrimSpace(text))
+	lines := strings.Split(text, "\n")
+	if len(lines) == 1 {
+		return lines[0]
+	}
+	for i := 1; i < len(lines); i++ {
+		if l := strings.TrimSpace(lines[i]); l != "" {
+			lines[i] = indent + l
+		} else {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n") + "\n" + indent
+}
+
+// gormTag GORM 标签内容：列名、主键、自增、类型与非空约束
+func gormTag(f Field) string {
+	parts := []string{"column:" + f.Column}
+	if f.PrimaryKey {
+		parts = append(parts, "primaryKey")
+	}
+	if f.AutoIncrement {
+		parts = append(parts, "autoIncrement")
+	}
+	if f.DBType != "" {
+		parts = append(parts, "type:"+strings.ReplaceAll(f.DBType, ";", ""))
+	}
+	if !f.Nullable && !f.PrimaryKey {
+		parts = append(parts, "not null")
+	}
+	if f.Default != "" && !f.AutoIncrement {
+		parts = append(parts, "default:"+strings.ReplaceAll(f.Default, ";", ""))
+	}
+	// 标签值写在反引号与双引号中
+	return strings.NewReplacer("`", "", `"`, "'").Replace(strings.Join(parts, ";"))
+}
@@ -0,0 +1,372 @@
+package codegen
+
+import (
+	"bytes"
+	"dbrun/app/ddl"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 结构描述文档的格式
+const (
+	FormatJSONSchema = "jsonschema" // 每张表一个 JSON Schema（draft 2020-12）文件，可空写为类型联合
+	FormatOpenAPI    = "openapi"    // 一个 OpenAPI 3.0 YAML 文档，表位于 components/schemas，可空写为 nullable
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// member 有序对象的一个键值
+type member struct {
+	key   string
+	value any
+}
+
+// object 保持键顺序的对象，使输出稳定且与字段顺序一致；数组统一使用 []any
+type object []member
+
+func (o object) get(key string) (any, bool) {
+	for _, m := range o {
+		if m.key == key {
+			return m.value, true
+		}
+	}
+	return nil, false
+}
+
+// set 替换已有的键，不存在时追加
+func (o object) set(key string, value any) object {
+	for i, m := range o {
+		if m.key == key {
+			o[i].value = value
+			return o
+		}
+	}
+	return append(o, member{key, value})
+}
+
+// MarshalJSON 按键顺序输出，不转义 HTML 字符
+func (o object) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, err := marshalJSON(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalJSON(m.value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func marshalJSON(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+// GenerateSchemas 将表导出为 JSON Schema 或 OpenAPI components/schemas；
+// 字段类型映射为 JSON 类型与 format，注释写入 description，enum 字段写出取值列表
+func GenerateSchemas(tables []Table, format, title string) ([]File, []string, error) {
+	if format != FormatJSONSchema && format != FormatOpenAPI {
+		return nil, nil, fmt.Errorf("unsupported schema format: %s", format)
+	}
+	openAPI := format == FormatOpenAPI
+	var warnings []string
+	var names []string
+	var schemas []object
+	used := map[string]bool{}
+	for _, t := range tables {
+		s, warn := tableSchema(t, openAPI)
+		warnings = append(warnings, warn...)
+		base := t.ModelName
+		if base == "" {
+			base = t.Name
+		}
+		name := schemaName(t, used)
+		if want := Pascal(base); name != want {
+			warnings = append(warnings, fmt.Sprintf("%s: schema name %s is already used, renamed to %s", t.Name, want, name))
+		}
+		names = append(names, name)
+		schemas = append(schemas, s)
+	}
+
+	if !openAPI {
+		files := make([]File, 0, len(schemas))
+		for i, s := range schemas {
+			doc := append(object{{"$schema", jsonSchemaDialect}, {"title", names[i]}}, s...)
+			var b bytes.Buffer
+			enc := json.NewEncoder(&b)
+			enc.SetEscapeHTML(false)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(doc); err != nil {
+				return nil, warnings, fmt.Errorf("encode %s failed: %w", names[i], err)
+			}
+			files = append(files, File{Name: names[i] + ".schema.json", Content: b.String()})
+		}
+		return files, warnings, nil
+	}
+
+	if title == "" {
+		title = "Database schemas"
+	}
+	components := object{}
+	for i, s := range schemas {
+		components = append(components, member{names[i], s})
+	}
+	doc := object{
+		{"openapi", "3.0.3"},
+		{"info", object{{"title", title}, {"version", "1.0.0"}}},
+		{"paths", object{}},
+		{"components", object{{"schemas", components}}},
+	}
+	var b strings.Builder
+	writeYAMLObject(&b, "", doc)
+	return []File{{Name: "openapi.yaml", Content: b.String()}}, warnings, nil
+}
+
+// schemaName 取模型名的大驼峰形式，不同 schema 下的同名表加上 schema 前缀区分
+func schemaName(t Table, used map[string]bool) string {
+	return uniqueName(t, used, Pascal)
+}
+
+// uniqueName 按 convert 转换模型名，与已用名称冲突（忽略大小写）时先加 schema 前缀，仍冲突再加序号
+func uniqueName(t Table, used map[string]bool, convert func(string) string) string {
+	base := t.ModelName
+	if base == "" {
+		base = t.Name
+	}
+	name := convert(base)
+	if used[strings.ToLower(name)] && t.Schema != "" {
+		name = convert(t.Schema + "_" + base)
+	}
+	for i := 2; used[strings.ToLower(name)]; i++ {
+		name = convert(fmt.Sprintf("%s%d", base, i))
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+// tableSchema 表的对象结构，非空字段列入 required，自增字段标记为 readOnly
+func tableSchema(t Table, openAPI bool) (object, []string) {
+	s := object{}
+	if t.Comment != "" {
+		s = append(s, member{"description", t.Comment})
+	}
+	s = append(s, member{"type", "object"})
+	var warnings []string
+	var required []any
+	properties := object{}
+	for _, c := range t.Columns {
+		p, warning := columnSchema(t.Dialect, c, openAPI)
+		if warning != "" {
+			warnings = append(warnings, fmt.Sprintf("%s.%s: %s", t.Name, c.Name, warning))
+		}
+		properties = append(properties, member{c.Name, p})
+		if !c.Nullable || c.PrimaryKey {
+			required = append(required, c.Name)
+		}
+	}
+	s = append(s, member{"properties", properties})
+	if len(required) > 0 {
+		s = append(s, member{"required", required})
+	}
+	return s, warnings
+}
+
+// columnSchema 字段的结构描述：类型与 format、长度、取值列表、可空与说明
+func columnSchema(dialect string, c Column, openAPI bool) (object, string) {
+	t := ddl.ClassifyType(dialect, c.Type)
+	s, warning := jsonType(t, openAPI)
+	if warning != "" {
+		warning = fmt.Sprintf("type %s is not recognized, %s", c.Type, warning)
+	}
+	if t.Array {
+		s = object{{"type", "array"}, {"items", s}}
+	}
+	if c.Nullable && !c.PrimaryKey {
+		s = nullable(s, openAPI)
+	}
+	if c.AutoIncrement || t.Serial {
+		s = append(s, member{"readOnly", true})
+	}
+	if c.Comment != "" {
+		s = append(object{{"description", c.Comment}}, s...)
+	}
+	return s, warning
+}
+
+// nullable JSON Schema 在类型与取值列表中加入 null，OpenAPI 3.0 使用 nullable；
+// 无类型约束（如 json 字段）时本身已接受 null
+func nullable(s object, openAPI bool) object {
+	typ, ok := s.get("type")
+	if !ok {
+		return s
+	}
+	if values, ok := s.get("enum"); ok {
+		s = s.set("enum", append(values.([]any), nil))
+	}
+	if openAPI {
+		return s.set("nullable", true)
+	}
+	return s.set("type", []any{typ, "null"})
+}
+
+// jsonType 按类型分类映射 JSON 类型；decimal 写为字符串以免丢失精度，无法识别时不限制类型
+func jsonType(t ddl.TypeInfo, openAPI bool) (object, string) {
+	str := func(f string) object {
+		if f == "" {
+			return object{{"type", "string"}}
+		}
+		return object{{"type", "string"}, {"format", f}}
+	}
+	switch t.Kind {
+	case "boolean":
+		return object{{"type", "boolean"}}, ""
+	case "tinyint", "smallint", "mediumint", "int", "year":
+		if t.Unsigned && t.Kind == "int" {
+			return object{{"type", "integer"}, {"format", "int64"}, {"minimum", 0}}, ""
+		}
+		if t.Unsigned {
+			return object{{"type", "integer"}, {"format", "int32"}, {"minimum", 0}}, ""
+		}
+		return object{{"type", "integer"}, {"format", "int32"}}, ""
+	case "bigint":
+		if t.Unsigned {
+			// 超出 int64 范围，不写 format
+			return object{{"type", "integer"}, {"minimum", 0}}, ""
+		}
+		return object{{"type", "integer"}, {"format", "int64"}}, ""
+	case "real":
+		return object{{"type", "number"}, {"format", "float"}}, ""
+	case "double":
+		return object{{"type", "number"}, {"format", "double"}}, ""
+	case "decimal", "money":
+		return str("decimal"), ""
+	case "char", "nchar", "varchar", "nvarchar":
+		s := str("")
+		if len(t.Args) > 0 && t.Args[0] > 0 {
+			s = append(s, member{"maxLength", t.Args[0]})
+		}
+		return s, ""
+	case "text", "ntext", "xml", "set":
+		return str(""), ""
+	case "enum":
+		s := str("")
+		if len(t.Values) > 0 {
+			values := make([]any, len(t.Values))
+			for i, v := range t.Values {
+				values[i] = v
+			}
+			s = append(s, member{"enum", values})
+		}
+		return s, ""
+	case "date":
+		return str("date"), ""
+	case "time":
+		return str("time"), ""
+	case "datetime", "timestamptz":
+		return str("date-time"), ""
+	case "interval":
+		return str("duration"), ""
+	case "uuid":
+		return str("uuid"), ""
+	case "bit":
+		if len(t.Args) == 0 || t.Args[0] == 1 {
+			return object{{"type", "boolean"}}, ""
+		}
+		return str(""), ""
+	case "binary", "varbinary", "blob", "rowversion":
+		if openAPI {
+			return str("byte"), ""
+		}
+		return object{{"type", "string"}, {"contentEncoding", "base64"}}, ""
+	case "json":
+		return object{}, ""
+	}
+	return object{}, "any value is accepted"
+}
+
+// writeYAMLObject 以块格式写出对象，字符串按需使用双引号
+func writeYAMLObject(b *strings.Builder, indent string, o object) {
+	for _, m := range o {
+		b.WriteString(indent + yamlScalar(m.key) + ":")
+		writeYAMLValue(b, indent+"  ", m.value)
+	}
+}
+
+// writeYAMLValue 写出键或 "-" 之后的值，非空的对象与数组另起一行
+func writeYAMLValue(b *strings.Builder, indent string, v any) {
+	switch x := v.(type) {
+	case object:
+		if len(x) == 0 {
+			b.WriteString(" {}\n")
+			return
+		}
+		b.WriteString("\n")
+		writeYAMLObject(b, indent, x)
+	case []any:
+		if len(x) == 0 {
+			b.WriteString(" []\n")
+			return
+		}
+		b.WriteString("\n")
+		for _, item := range x {
+			b.WriteString(indent + "-")
+			writeYAMLValue(b, indent+"  ", item)
+		}
+	default:
+		b.WriteString(" " + yamlScalar(x) + "\n")
+	}
+}
+
+// yamlReserved 不加引号时会被解析为布尔值或 null 的写法
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true, "y": true, "n": true,
+	"null": true, "~": true,
+}
+
+// yamlScalar 普通标识符直接写出，其余字符串使用 JSON 转义的双引号形式（同样是合法的 YAML）
+func yamlScalar(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		if plainYAML(x) {
+			return x
+		}
+		quoted, _ := marshalJSON(x)
+		return string(quoted)
+	default:
+		return fmt.Sprint(x)
+	}
+}
+
+func plainYAML(s string) bool {
+	if s == "" || yamlReserved[strings.ToLower(s)] {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case i > 0 && (r >= '0' && r <= '9' || r == '-' || r == '.' || r == '/'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,104 @@
+package codegen
+
+// 默认模板的数据为 Unit，可用函数见 funcs；用户可在项目中保存自定义模板替换这些默认值
+
+const goTemplate = `package {{.Package}}
+{{if .Imports}}
+import (
+{{range .Imports}}	{{quote .}}
+{{end}})
+{{end}}{{range .Models}}
+{{if .Comment}}{{comment "// " (print .Name " " .Comment)}}{{else}}// {{.Name}} maps table {{.Table}}.{{end}}
+type {{.Name}} struct {
+{{range .Fields}}{{if .Comment}}{{comment "	// " .Comment}}
+{{end}}	{{.Name}} {{.Type}} ` + "`" + `json:"{{.Column}}{{if .Nullable}},omitempty{{end}}" db:"{{.Column}}" gorm:"{{gormTag .}}"` + "`" + `
+{{end}}}
+
+// TableName returns the table name used by GORM.
+func ({{.Name}}) TableName() string {
+	return {{if .Schema}}{{quote (print .Schema "." .Table)}}{{else}}{{quote .Table}}{{end}}
+}
+{{end}}`
+
+const typeScriptTemplate = `{{range $i, $m := .Models}}{{if $i}}
+{{end}}{{if .Comment}}/**
+{{comment " * " (docSafe .Comment)}}
+ */
+{{end}}export interface {{.Name}} {
+{{range .Fields}}{{if .Comment}}  /** {{docSafe (oneLine .Comment)}} */
+{{end}}  {{.Name}}{{if .Nullable}}?{{end}}: {{.Type}};
+{{end}}}
+{{end}}`
+
+const javaTemplate = `package {{.Package}};
+
+{{range .Imports}}import {{.}};
+{{end}}{{range .Models}}
+{{if .Comment}}/**
+{{comment " * " (docSafe .Comment)}}
+ */
+{{end}}@Entity
+@Table(name = {{quote .Table}}{{if .Schema}}, schema = {{quote .Schema}}{{end}})
+public class {{.Name}} {
+{{range .Fields}}
+{{if .Comment}}    /**
+{{comment "     * " (docSafe .Comment)}}
+     */
+{{end}}{{if .PrimaryKey}}    @Id
+{{end}}{{if .AutoIncrement}}    @GeneratedValue(strategy = GenerationType.IDENTITY)
+{{end}}    @Column(name = {{quote .Column}}{{if not .Nullable}}, nullable = false{{end}}{{if .Length}}, length = {{.Length}}{{end}})
+    private {{.Type}} {{.Name}};
+{{end}}{{range .Fields}}
+    public {{.Type}} get{{pascal .Name}}() {
+        return {{.Name}};
+    }
+
+    public void set{{pascal .Name}}({{.Type}} {{.Name}}) {
+        this.{{.Name}} = {{.Name}};
+    }
+{{end}}}
+{{end}}`
+
+const kotlinTemplate = `package {{.Package}}
+
+{{range .Imports}}import {{.}}
+{{end}}{{range .Models}}
+{{if .Comment}}/**
+{{comment " * " (docSafe .Comment)}}
+ */
+{{end}}@Entity
+@Table(name = {{kotlinQuote .Table}}{{if .Schema}}, schema = {{kotlinQuote .Schema}}{{end}})
+class {{.Name}}(
+{{range .Fields}}{{if .Comment}}    /** {{docSafe (oneLine .Comment)}} */
+{{end}}{{if .PrimaryKey}}    @Id
+{{end}}{{if .AutoIncrement}}    @GeneratedValue(strategy = GenerationType.IDENTITY)
+{{end}}    @Column(name = {{kotlinQuote .Column}}{{if not .Nullable}}, nullable = false{{end}}{{if .Length}}, length = {{.Length}}{{end}})
+    var {{.Name}}: {{.Type}}{{if or .Nullable .AutoIncrement}} = null{{end}},
+{{end}})
+{{end}}`
+
+const pythonTemplate = `{{range .Imports}}{{.}}
+{{end}}{{range .Models}}
+
+@dataclass
+class {{.Name}}:
+    """{{if .Comment}}{{pyDoc .Comment "    "}}{{else}}Row of table {{.Table}}.{{end}}"""
+
+{{range .Fields}}    {{.Name}}: {{.Type}}{{if .Nullable}} = None{{end}}{{with .Comment}}  # {{oneLine .}}{{end}}
+{{end}}{{end}}`
+
+const sqlalchemyTemplate = `{{range .Imports}}{{.}}
+{{end}}
+
+class Base(DeclarativeBase):
+    pass
+{{range .Models}}
+
+class {{.Name}}(Base):
+    """{{if .Comment}}{{pyDoc .Comment "    "}}{{else}}Row of table {{.Table}}.{{end}}"""
+
+    __tablename__ = {{quote .Table}}
+{{if or .Schema .Comment}}    __table_args__ = { {{- if .Schema}}"schema": {{quote .Schema}}{{end}}{{if and .Schema .Comment}}, {{end}}{{if .Comment}}"comment": {{quote (oneLine .Comment)}}{{end -}} }
+{{end}}
+{{range .Fields}}    {{.Name}}: Mapped[{{.Type}}] = mapped_column({{if ne .Name .Column}}{{quote .Column}}, {{end}}{{.ORMType}}{{if .PrimaryKey}}, primary_key=True{{end}}{{if .AutoIncrement}}, autoincrement=True{{end}}{{if not .PrimaryKey}}, nullable={{if .Nullable}}True{{else}}False{{end}}{{end}}{{with .Comment}}, comment={{quote (oneLine .)}}{{end}})
+{{end}}{{end}}`
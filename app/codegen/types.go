@@ -0,0 +1,480 @@
+package codegen
+
+import (
+	"dbrun/app/ddl"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// language 目标语言的命名、类型映射与文件组织方式
+type language struct {
+	template            string
+	pkg                 string // 默认包名
+	file                string // 所有模型写入同一文件时的文件名
+	ext                 string // 每个模型一个文件时的扩展名
+	perModel            bool
+	requiredFirst       bool   // 可空字段（带默认值）排在后面
+	compositeKeyWarning string // 复合主键需要额外处理时的提示
+	className           func(string) string
+	fieldName           func(string) string
+	escape              func(string) string
+	mapType             func(dialect string, c Column) mapped
+	imports             func([]string) []string
+}
+
+// mapped 字段类型的映射结果
+type mapped struct {
+	typ     string
+	base    string
+	kind    string
+	orm     string
+	length  int
+	serial  bool
+	imports []string
+	warning string
+}
+
+var languages = map[string]language{
+	LangGo: {
+		template: goTemplate, pkg: "model", file: "models.go",
+		className: goName, fieldName: goName, escape: identity,
+		mapType: mapGo, imports: sortedUnique,
+	},
+	LangTypeScript: {
+		template: typeScriptTemplate, file: "models.ts",
+		className: Pascal, fieldName: identity, escape: tsProperty,
+		mapType: mapTypeScript, imports: sortedUnique,
+	},
+	LangJava: {
+		template: javaTemplate, pkg: "com.example.model", ext: ".java", perModel: true,
+		className: Pascal, fieldName: Camel, escape: javaIdent,
+		mapType: mapJava, imports: javaImports, compositeKeyWarning: jpaCompositeKey,
+	},
+	LangKotlin: {
+		template: kotlinTemplate, pkg: "com.example.model", file: "Entities.kt",
+		className: Pascal, fieldName: Camel, escape: kotlinIdent,
+		mapType: mapKotlin, imports: javaImports, compositeKeyWarning: jpaCompositeKey,
+	},
+	LangPython: {
+		template: pythonTemplate, file: "models.py", requiredFirst: true,
+		className: Pascal, fieldName: Snake, escape: keywordSuffix(pythonKeywords),
+		mapType: mapPython, imports: pythonImports,
+	},
+	LangSQLAlchemy: {
+		template: sqlalchemyTemplate, file: "models.py",
+		className: Pascal, fieldName: Snake, escape: keywordSuffix(pythonKeywords),
+		mapType: mapSQLAlchemy, imports: pythonImports,
+	},
+}
+
+const jpaCompositeKey = "composite primary keys need an @IdClass or @EmbeddedId"
+
+func identity(s string) string { return s }
+
+// classify 解析字段类型，字符串类型同时返回长度
+func classify(dialect string, c Column) (ddl.TypeInfo, int) {
+	t := ddl.ClassifyType(dialect, c.Type)
+	length := 0
+	switch t.Kind {
+	case "char", "nchar", "varchar", "nvarchar":
+		if len(t.Args) > 0 && t.Args[0] > 0 {
+			length = t.Args[0]
+		}
+	}
+	return t, length
+}
+
+func unknownWarning(c Column, fallback string) string {
+	return fmt.Sprintf("type %s is not recognized, mapped to %s", c.Type, fallback)
+}
+
+var goTypes = map[string]string{
+	"boolean": "bool", "tinyint": "int8", "smallint": "int16", "mediumint": "int32", "int": "int32", "bigint": "int64",
+	"decimal": "string", "money": "string", "real": "float32", "double": "float64",
+	"char": "string", "nchar": "string", "varchar": "string", "nvarchar": "string", "text": "string", "ntext": "string",
+	"date": "time.Time", "time": "string", "datetime": "time.Time", "timestamptz": "time.Time", "interval": "string", "year": "int16",
+	"binary": "[]byte", "varbinary": "[]byte", "blob": "[]byte", "bit": "[]byte", "rowversion": "[]byte",
+	"json": "json.RawMessage", "uuid": "string", "xml": "string", "enum": "string", "set": "string",
+}
+
+var goUnsigned = map[string]string{"tinyint": "uint8", "smallint": "uint16", "mediumint": "uint32", "int": "uint32", "bigint": "uint64"}
+
+// mapGo 可空字段写为指针；切片与 json.RawMessage 本身可以为 nil，不再加指针
+func mapGo(dialect string, c Column) mapped {
+	t, length := classify(dialect, c)
+	m := mapped{kind: t.Kind, length: length, serial: t.Serial}
+	base, ok := goTypes[t.Kind]
+	if t.Unsigned && goUnsigned[t.Kind] != "" {
+		base = goUnsigned[t.Kind]
+	}
+	if !ok {
+		base, m.warning = "string", unknownWarning(c, "string")
+	}
+	switch {
+	case strings.HasPrefix(base, "time."):
+		m.imports = append(m.imports, "time")
+	case strings.HasPrefix(base, "json."):
+		m.imports = append(m.imports, "encoding/json")
+	}
+	if t.Array {
+		base = "[]" + base
+		m.warning = "array columns need a driver specific type such as pq.Array when scanning"
+	}
+	m.base, m.typ = base, base
+	if c.Nullable && !c.PrimaryKey && !strings.HasPrefix(base, "[]") && base != "json.RawMessage" {
+		m.typ = "*" + base
+	}
+	return m
+}
+
+var tsTypes = map[string]string{
+	"boolean": "boolean", "tinyint": "number", "smallint": "number", "mediumint": "number", "int": "number", "bigint": "number",
+	"decimal": "string", "money": "string", "real": "number", "double": "number",
+	"char": "string", "nchar": "string", "varchar": "string", "nvarchar": "string", "text": "string", "ntext": "string",
+	"date": "string", "time": "string", "datetime": "string", "timestamptz": "string", "interval": "string", "year": "number",
+	"binary": "string", "varbinary": "string", "blob": "string", "bit": "string", "rowversion": "string",
+	"json": "unknown", "uuid": "string", "xml": "string", "enum": "string", "set": "string",
+}
+
+// mapTypeScript 日期与二进制按 JSON 序列化后的字符串表示，可空字段为 T | null
+func mapTypeScript(dialect string, c Column) mapped {
+	t, length := classify(dialect, c)
+	m := mapped{kind: t.Kind, length: length, serial: t.Serial}
+	base, ok := tsTypes[t.Kind]
+	if !ok {
+		base = "string"
+	}
+	if t.Array {
+		base += "[]"
+	}
+	if !ok {
+		// 警告中的类型与生成的类型一致，数组为 string[]
+		m.warning = unknownWarning(c, base)
+	}
+	m.base, m.typ = base, base
+	if c.Nullable && !c.PrimaryKey {
+		m.typ = base + " | null"
+	}
+	return m
+}
+
+// jvmType Java 与 Kotlin 的类型：primitive 为非空时使用的基本类型，为空表示没有基本类型
+type jvmType struct {
+	primitive string
+	boxed     string
+	kotlin    string
+	imports   []string
+}
+
+var jvmTypes = map[string]jvmType{
+	"boolean":     {"boolean", "Boolean", "Boolean", nil},
+	"tinyint":     {"byte", "Byte", "Byte", nil},
+	"smallint":    {"short", "Short", "Short", nil},
+	"mediumint":   {"int", "Integer", "Int", nil},
+	"int":         {"int", "Integer", "Int", nil},
+	"bigint":      {"long", "Long", "Long", nil},
+	"decimal":     {"", "BigDecimal", "BigDecimal", []string{"java.math.BigDecimal"}},
+	"money":       {"", "BigDecimal", "BigDecimal", []string{"java.math.BigDecimal"}},
+	"real":        {"float", "Float", "Float", nil},
+	"double":      {"double", "Double", "Double", nil},
+	"char":        {"", "String", "String", nil},
+	"nchar":       {"", "String", "String", nil},
+	"varchar":     {"", "String", "String", nil},
+	"nvarchar":    {"", "String", "String", nil},
+	"text":        {"", "String", "String", nil},
+	"ntext":       {"", "String", "String", nil},
+	"date":        {"", "LocalDate", "LocalDate", []string{"java.time.LocalDate"}},
+	"time":        {"", "LocalTime", "LocalTime", []string{"java.time.LocalTime"}},
+	"datetime":    {"", "LocalDateTime", "LocalDateTime", []string{"java.time.LocalDateTime"}},
+	"timestamptz": {"", "OffsetDateTime", "OffsetDateTime", []string{"java.time.OffsetDateTime"}},
+	"interval":    {"", "Duration", "Duration", []string{"java.time.Duration"}},
+	"year":        {"short", "Short", "Short", nil},
+	"binary":      {"", "byte[]", "ByteArray", nil},
+	"varbinary":   {"", "byte[]", "ByteArray", nil},
+	"blob":        {"", "byte[]", "ByteArray", nil},
+	"bit":         {"", "byte[]", "ByteArray", nil},
+	"rowversion":  {"", "byte[]", "ByteArray", nil},
+	"json":        {"", "String", "String", nil},
+	"uuid":        {"", "UUID", "UUID", []string{"java.util.UUID"}},
+	"xml":         {"", "String", "String", nil},
+	"enum":        {"", "String", "String", nil},
+	"set":         {"", "String", "String", nil},
+}
+
+// jvmUnsigned 无符号整数放大一档以容纳取值范围
+var jvmUnsigned = map[string]jvmType{
+	"tinyint":   {"short", "Short", "Short", nil},
+	"smallint":  {"int", "Integer", "Int", nil},
+	"mediumint": {"long", "Long", "Long", nil},
+	"int":       {"long", "Long", "Long", nil},
+	"bigint":    {"", "BigInteger", "BigInteger", []string{"java.math.BigInteger"}},
+}
+
+// jpaImports 默认实体模板用到的注解
+var jpaImports = []string{"jakarta.persistence.Column", "jakarta.persistence.Entity", "jakarta.persistence.Table"}
+
+func jvmMapping(dialect string, c Column) (jvmType, mapped) {
+	t, length := classify(dialect, c)
+	m := mapped{kind: t.Kind, length: length, serial: t.Serial}
+	jt, ok := jvmTypes[t.Kind]
+	if u, found := jvmUnsigned[t.Kind]; found && t.Unsigned {
+		jt = u
+	}
+	if !ok {
+		jt, m.warning = jvmTypes["varchar"], unknownWarning(c, "String")
+	}
+	if t.Array {
+		jt = jvmType{boxed: "List<" + jt.boxed + ">", kotlin: "List<" + jt.kotlin + ">", imports: append([]string{"java.util.List"}, jt.imports...)}
+		m.warning = "array columns need an AttributeConverter or a Hibernate array type"
+	}
+	m.imports = append(append(m.imports, jpaImports...), jt.imports...)
+	if c.PrimaryKey {
+		m.imports = append(m.imports, "jakarta.persistence.Id")
+	}
+	if c.AutoIncrement || t.Serial {
+		m.imports = append(m.imports, "jakarta.persistence.GeneratedValue", "jakarta.persistence.GenerationType")
+	}
+	return jt, m
+}
+
+// mapJava 非空字段使用基本类型，可空字段与自增主键使用包装类型
+func mapJava(dialect string, c Column) mapped {
+	jt, m := jvmMapping(dialect, c)
+	m.base, m.typ = jt.boxed, jt.boxed
+	if jt.primitive != "" && !c.Nullable && !c.AutoIncrement && !m.serial {
+		m.typ = jt.primitive
+	}
+	return m
+}
+
+// mapKotlin 可空字段与自增主键写为 T?，由数据库生成的主键在保存前为 null
+func mapKotlin(dialect string, c Column) mapped {
+	jt, m := jvmMapping(dialect, c)
+	m.base, m.typ = jt.kotlin, jt.kotlin
+	// Kotlin 的 List 来自 kotlin.collections，无需导入
+	imports := m.imports[:0]
+	for _, s := range m.imports {
+		if s != "java.util.List" {
+			imports = append(imports, s)
+		}
+	}
+	m.imports = imports
+	if (c.Nullable && !c.PrimaryKey) || c.AutoIncrement || m.serial {
+		m.typ += "?"
+	}
+	return m
+}
+
+func javaImports(used []string) []string {
+	var out []string
+	for _, s := range sortedUnique(used) {
+		// java.lang 无需导入
+		if !strings.HasPrefix(s, "java.lang.") {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// pythonType Python 类型与 SQLAlchemy 列类型；import 写为 module:name，整模块导入写为 import x
+type pythonType struct {
+	typ     string
+	orm     string
+	imports []string
+}
+
+var pythonTypes = map[string]pythonType{
+	"boolean":     {"bool", "Boolean", nil},
+	"tinyint":     {"int", "SmallInteger", nil},
+	"smallint":    {"int", "SmallInteger", nil},
+	"mediumint":   {"int", "Integer", nil},
+	"int":         {"int", "Integer", nil},
+	"bigint":      {"int", "BigInteger", nil},
+	"decimal":     {"Decimal", "Numeric", []string{"decimal:Decimal"}},
+	"money":       {"Decimal", "Numeric", []string{"decimal:Decimal"}},
+	"real":        {"float", "Float", nil},
+	"double":      {"float", "Double", nil},
+	"char":        {"str", "String", nil},
+	"nchar":       {"str", "String", nil},
+	"varchar":     {"str", "String", nil},
+	"nvarchar":    {"str", "String", nil},
+	"text":        {"str", "Text", nil},
+	"ntext":       {"str", "Text", nil},
+	"date":        {"date", "Date", []string{"datetime:date"}},
+	"time":        {"time", "Time", []string{"datetime:time"}},
+	"datetime":    {"datetime", "DateTime", []string{"datetime:datetime"}},
+	"timestamptz": {"datetime", "DateTime", []string{"datetime:datetime"}},
+	"interval":    {"timedelta", "Interval", []string{"datetime:timedelta"}},
+	"year":        {"int", "SmallInteger", nil},
+	"binary":      {"bytes", "LargeBinary", nil},
+	"varbinary":   {"bytes", "LargeBinary", nil},
+	"blob":        {"bytes", "LargeBinary", nil},
+	"bit":         {"bytes", "LargeBinary", nil},
+	"rowversion":  {"bytes", "LargeBinary", nil},
+	"json":        {"Any", "JSON", []string{"typing:Any"}},
+	"uuid":        {"uuid.UUID", "Uuid", []string{"import uuid"}},
+	"xml":         {"str", "Text", nil},
+	"enum":        {"str", "String", nil},
+	"set":         {"str", "String", nil},
+}
+
+func pythonMapping(dialect string, c Column) (pythonType, mapped) {
+	t, length := classify(dialect, c)
+	m := mapped{kind: t.Kind, length: length, serial: t.Serial}
+	pt, ok := pythonTypes[t.Kind]
+	if !ok {
+		pt, m.warning = pythonTypes["varchar"], unknownWarning(c, "str")
+	}
+	m.imports = append(m.imports, pt.imports...)
+	base := pt.typ
+	if t.Array {
+		base = "list[" + base + "]"
+		if !ok {
+			m.warning = unknownWarning(c, base)
+		}
+	}
+	m.base, m.typ = base, base
+	if c.Nullable && !c.PrimaryKey {
+		m.typ = "Optional[" + base + "]"
+		m.imports = append(m.imports, "typing:Optional")
+	}
+	orm := pt.orm
+	switch {
+	case orm == "String" && length > 0:
+		orm = fmt.Sprintf("String(%d)", length)
+	case orm == "Numeric" && len(t.Args) == 2:
+		orm = fmt.Sprintf("Numeric(%d, %d)", t.Args[0], t.Args[1])
+	case orm == "Numeric" && len(t.Args) == 1:
+		orm = fmt.Sprintf("Numeric(%d)", t.Args[0])
+	case t.Kind == "timestamptz":
+		orm = "DateTime(timezone=True)"
+	}
+	m.orm = orm
+	return pt, m
+}
+
+func mapPython(dialect string, c Column) mapped {
+	_, m := pythonMapping(dialect, c)
+	m.imports = append(m.imports, "dataclasses:dataclass")
+	return m
+}
+
+func mapSQLAlchemy(dialect string, c Column) mapped {
+	pt, m := pythonMapping(dialect, c)
+	m.imports = append(m.imports, "sqlalchemy:"+pt.orm, "sqlalchemy.orm:DeclarativeBase", "sqlalchemy.orm:Mapped", "sqlalchemy.orm:mapped_column")
+	if strings.HasPrefix(m.base, "list[") {
+		m.orm = "ARRAY(" + m.orm + ")"
+		m.imports = append(m.imports, "sqlalchemy:ARRAY")
+	}
+	return m
+}
+
+// pythonImports 合并 module:name 为 from module import a, b；标准库在前，第三方库在后并以空行分隔
+func pythonImports(used []string) []string {
+	names := map[string][]string{}
+	var plain []string
+	for _, s := range sortedUnique(used) {
+		if strings.HasPrefix(s, "import ") {
+			plain = append(plain, s)
+			continue
+		}
+		module, name, _ := strings.Cut(s, ":")
+		names[module] = append(names[module], name)
+	}
+	modules := make([]string, 0, len(names))
+	for module := range names {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	var std, third []string
+	std = append(std, plain...)
+	for _, module := range modules {
+		line := "from " + module + " import " + strings.Join(names[module], ", ")
+		if strings.HasPrefix(module, "sqlalchemy") {
+			third = append(third, line)
+		} else {
+			std = append(std, line)
+		}
+	}
+	sort.Strings(std)
+	if len(std) > 0 && len(third) > 0 {
+		std = append(std, "")
+	}
+	return append(std, third...)
+}
+
+func sortedUnique(items []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, s := range items {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+var javaKeywords = setOf("abstract", "assert", "boolean", "break", "byte", "case", "catch", "char", "class", "const",
+	"continue", "default", "do", "double", "else", "enum", "extends", "final", "finally", "float", "for", "goto", "if",
+	"implements", "import", "instanceof", "int", "interface", "long", "native", "new", "package", "private", "protected",
+	"public", "return", "short", "static", "strictfp", "super", "switch", "synchronized", "this", "throw", "throws",
+	"transient", "try", "void", "volatile", "while", "true", "false", "null", "record", "var", "yield")
+
+var kotlinKeywords = setOf("as", "break", "class", "continue", "do", "else", "false", "for", "fun", "if", "in",
+	"interface", "is", "null", "object", "package", "return", "super", "this", "throw", "true", "try", "typealias",
+	"typeof", "val", "var", "when", "while")
+
+var pythonKeywords = setOf("False", "None", "True", "and", "as", "assert", "async", "await", "break", "class",
+	"continue", "def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import", "in", "is",
+	"lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield")
+
+func setOf(items ...string) map[string]bool {
+	m := make(map[string]bool, len(items))
+	for _, s := range items {
+		m[s] = true
+	}
+	return m
+}
+
+// keywordSuffix 与关键字同名时追加下划线
+func keywordSuffix(keywords map[string]bool) func(string) string {
+	return func(s string) string {
+		if keywords[s] {
+			return s + "_"
+		}
+		return s
+	}
+}
+
+// javaIdent 与关键字同名时追加 Value，避免 class 生成与 Object.getClass 冲突的访问器
+func javaIdent(s string) string {
+	if javaKeywords[s] {
+		return s + "Value"
+	}
+	return s
+}
+
+// kotlinIdent Kotlin 可以用反引号包裹关键字
+func kotlinIdent(s string) string {
+	if kotlinKeywords[s] {
+		return "`" + s + "`"
+	}
+	return s
+}
+
+// tsProperty 列名不是合法标识符时写为带引号的属性名
+func tsProperty(s string) string {
+	for i, r := range s {
+		if !(r == '_' || r == '$' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return fmt.Sprintf("%q", s)
+		}
+	}
+	if s == "" {
+		return `""`
+	}
+	return s
+}
@@ -41,6 +41,9 @@ func GetConnection(config Config) (Connection, error) {
         conn, err = NewSQLServerConnection(config)
     case "mariadb":
         conn, err = NewMariaDBConnection(config)
+    case "virtual":
+        // 由设计文件导入的虚拟连接只有元数据，没有可连接的数据库
+        return nil, fmt.Errorf("connection %d is imported from a schema file and cannot be connected", config.ID)
     default:
         fmt.Printf("[ConnectManager] unsupported database type: id=%d type=%q\n", config.ID, config.Type)
         return nil, fmt.Errorf("unsupported database type: %s", config.Type)
@@ -60,6 +63,17 @@ func GetConnection(config Config) (Connection, error) {
     return conn, nil
 }
 
+// RemoveConnection 关闭并移除连接池中的指定连接（配置变更后需重新建立连接）
+func RemoveConnection(id int64) error {
+    conn, exists := connectionPool[id]
+    if !exists {
+        return nil
+    }
+    delete(connectionPool, id)
+    fmt.Printf("[ConnectManager] removed pooled connection: id=%d type=%q\n", id, conn.GetConfig().Type)
+    return conn.Close()
+}
+
 // CloseAllConnections 关闭所有数据库连接
 func CloseAllConnections() error {
 	for key, conn := range connectionPool {
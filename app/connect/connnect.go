@@ -1,6 +1,10 @@
 package connect
 
-import "time"
+import (
+	"context"
+	"database/sql"
+	"time"
+)
 
 // Connection 接口定义了所有数据库连接应该实现的方法
 type Connection interface {
@@ -9,6 +13,12 @@ type Connection interface {
 	GetViews(params QueryParams) ([]ViewInfo, error)
 	GetTableFields(params QueryParams) ([]FieldInfo, error)
 	GetSchemas(database string) ([]Schema, error)
+	// GetTableKeys 获取表的主键、唯一键、索引与外键
+	GetTableKeys(params QueryParams) ([]KeyInfo, error)
+	// OpenSession 从连接池取出一个独立会话；只读配置下会在方言支持时将会话设置为只读
+	OpenSession(ctx context.Context) (*sql.Conn, error)
+	// BeginReadOnly 在会话上开启只读事务（方言不支持时为普通事务），调用方执行完毕后必须回滚
+	BeginReadOnly(ctx context.Context, session *sql.Conn) (*sql.Tx, error)
 	GetConfig() Config
 	Test() error
 	Close() error
@@ -50,6 +60,7 @@ type TableInfo struct {
 	Name    string      `json:"name"`
 	Comment string      `json:"comment"`
 	Fields  []FieldInfo `json:"fields"`
+	Keys    []KeyInfo   `json:"keys,omitempty"` // 为 nil 时表示未获取键信息
 }
 
 // FieldInfo 存储字段的信息
@@ -64,6 +75,8 @@ type FieldInfo struct {
 	Key          string `json:"key"`
 	Comment      string `json:"comment,omitempty"`
 	DefaultValue string `json:"default_value,omitempty"`
+	// 自增/标识列
+	AutoIncrement bool `json:"auto_increment,omitempty"`
 }
 
 // Config 结构体用于存储数据库连接信息
@@ -79,5 +92,6 @@ type Config struct {
 	Database  string `json:"database"`
 	Instance  string `json:"instance"`
 	Options   string `json:"options"`
+	ReadOnly  bool   `json:"read_only"` // 只读连接：拒绝执行DML/DDL，并尽量以只读会话打开
 	CreatedAt time.Time `json:"created_at"`
 }
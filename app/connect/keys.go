@@ -0,0 +1,109 @@
+package connect
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// 键的类别
+const (
+	KeyPrimary = "primary" // 主键
+	KeyUnique  = "unique"  // 唯一约束或唯一索引
+	KeyIndex   = "index"   // 普通索引
+	KeyForeign = "foreign" // 外键
+)
+
+// KeyInfo 表的主键、唯一键、索引或外键，字段按定义中的顺序排列
+type KeyInfo struct {
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Columns    []string `json:"columns"`
+	RefSchema  string   `json:"ref_schema,omitempty"` // 外键引用表所在的 Schema（MySQL/MariaDB 为数据库）
+	RefTable   string   `json:"ref_table,omitempty"`
+	RefColumns []string `json:"ref_columns,omitempty"`
+}
+
+// scanKeys 读取按键名与字段顺序排列的结果集，每行依次为：
+// 键名、类别、字段、引用 Schema、引用表、引用字段。
+// 字段为空（如表达式索引）的键无法用字段列表表示，整体丢弃
+func scanKeys(rows *sql.Rows) ([]KeyInfo, error) {
+	defer rows.Close()
+	var keys []KeyInfo
+	skipped := map[string]bool{}
+	for rows.Next() {
+		var name, kind, column, refSchema, refTable, refColumn sql.NullString
+		if err := rows.Scan(&name, &kind, &column, &refSchema, &refTable, &refColumn); err != nil {
+			return nil, fmt.Errorf("failed to scan key info: %w", err)
+		}
+		id := kind.String + "\x00" + name.String
+		if !column.Valid || column.String == "" {
+			skipped[id] = true
+			continue
+		}
+		n := len(keys)
+		if n == 0 || keys[n-1].Name != name.String || keys[n-1].Kind != kind.String {
+			keys = append(keys, KeyInfo{Name: name.String, Kind: kind.String, RefSchema: refSchema.String, RefTable: refTable.String})
+			n++
+		}
+		keys[n-1].Columns = append(keys[n-1].Columns, column.String)
+		if kind.String == KeyForeign {
+			keys[n-1].RefColumns = append(keys[n-1].RefColumns, refColumn.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	out := make([]KeyInfo, 0, len(keys))
+	for _, k := range keys {
+		if !skipped[k.Kind+"\x00"+k.Name] {
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+// queryKeys 依次执行各查询并合并结果
+func queryKeys(db *sql.DB, queries []string, args ...interface{}) ([]KeyInfo, error) {
+	keys := []KeyInfo{}
+	for _, q := range queries {
+		rows, err := db.Query(q, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get table keys: %w", err)
+		}
+		part, err := scanKeys(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, part...)
+	}
+	return keys, nil
+}
+
+// ApplyKeyFlags 按键信息补全字段的索引标记（与 MySQL 的 COLUMN_KEY 含义一致）：
+// 主键字段为 PRI，单字段唯一键为 UNI，作为其他索引首字段时为 MUL；已有标记的字段保持不变
+func ApplyKeyFlags(fields []FieldInfo, keys []KeyInfo) {
+	flags := map[string]string{}
+	rank := map[string]int{"PRI": 3, "UNI": 2, "MUL": 1}
+	set := func(column, flag string) {
+		if rank[flag] > rank[flags[column]] {
+			flags[column] = flag
+		}
+	}
+	for _, k := range keys {
+		switch {
+		case k.Kind == KeyPrimary:
+			for _, c := range k.Columns {
+				set(c, "PRI")
+			}
+		case k.Kind == KeyUnique && len(k.Columns) == 1:
+			set(k.Columns[0], "UNI")
+		default:
+			set(k.Columns[0], "MUL")
+		}
+	}
+	for i := range fields {
+		if fields[i].Key == "" {
+			fields[i].Key = flags[fields[i].Name]
+		}
+	}
+}
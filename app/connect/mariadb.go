@@ -1,8 +1,10 @@
 package connect
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -166,7 +168,8 @@ func (c *MariaDBConnection) GetTableFields(params QueryParams) ([]FieldInfo, err
 			is_nullable,
 			column_key,
 			column_comment,
-			column_default
+			column_default,
+			extra
 		FROM information_schema.columns 
 		WHERE table_schema = ? 
 		AND table_name = ? 
@@ -183,6 +186,7 @@ func (c *MariaDBConnection) GetTableFields(params QueryParams) ([]FieldInfo, err
 		var field FieldInfo
 		var isNullable string
 		var defaultValue sql.NullString
+		var extra string
 		if err := rows.Scan(
 			&field.Name,
 			&field.Type,
@@ -190,11 +194,13 @@ func (c *MariaDBConnection) GetTableFields(params QueryParams) ([]FieldInfo, err
 			&field.Key,
 			&field.Comment,
 			&defaultValue,
+			&extra,
 		); err != nil {
 			return nil, err
 		}
 
 		field.Nullable = (isNullable == "YES")
+		field.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
 		if defaultValue.Valid {
 			field.DefaultValue = defaultValue.String
 		}
@@ -205,6 +211,22 @@ func (c *MariaDBConnection) GetTableFields(params QueryParams) ([]FieldInfo, err
 	return fields, nil
 }
 
+// GetTableKeys 从 STATISTICS 读取主键、唯一键与索引，从 KEY_COLUMN_USAGE 读取外键
+func (c *MariaDBConnection) GetTableKeys(params QueryParams) ([]KeyInfo, error) {
+	return queryKeys(c.db, []string{`
+		SELECT INDEX_NAME,
+			CASE WHEN INDEX_NAME = 'PRIMARY' THEN 'primary' WHEN NON_UNIQUE = 0 THEN 'unique' ELSE 'index' END,
+			COLUMN_NAME, NULL, NULL, NULL
+		FROM INFORMATION_SCHEMA.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX`, `
+		SELECT CONSTRAINT_NAME, 'foreign', COLUMN_NAME, REFERENCED_TABLE_SCHEMA, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION`,
+	}, params.Database, params.Table)
+}
+
 // GetSchemas 获取指定数据库的所有schema
 // MariaDB 不支持真正的 schema，返回空列表
 func (c *MariaDBConnection) GetSchemas(database string) ([]Schema, error) {
@@ -216,6 +238,19 @@ func (c *MariaDBConnection) GetConfig() Config {
 	return c.config
 }
 
+// OpenSession 打开独立会话，只读配置下执行 SET SESSION TRANSACTION READ ONLY
+func (c *MariaDBConnection) OpenSession(ctx context.Context) (*sql.Conn, error) {
+	if c.config.ReadOnly {
+		return openSession(ctx, c.db, "SET SESSION TRANSACTION READ ONLY")
+	}
+	return openSession(ctx, c.db)
+}
+
+// BeginReadOnly 以 START TRANSACTION READ ONLY 开启事务
+func (c *MariaDBConnection) BeginReadOnly(ctx context.Context, session *sql.Conn) (*sql.Tx, error) {
+	return beginReadOnly(ctx, session, &sql.TxOptions{ReadOnly: true})
+}
+
 // Test 测试数据库连接是否可用
 func (c *MariaDBConnection) Test() error {
 	return c.db.Ping()
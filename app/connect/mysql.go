@@ -1,8 +1,10 @@
 package connect
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -114,9 +116,10 @@ func (c *MySQLConnection) GetViews(params QueryParams) ([]ViewInfo, error) {
 
 func (c *MySQLConnection) GetTableFields(params QueryParams) ([]FieldInfo, error) {
 	query := `
-        SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, COLUMN_COMMENT
+        SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, COLUMN_COMMENT, COLUMN_DEFAULT, EXTRA
         FROM INFORMATION_SCHEMA.COLUMNS
         WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+        ORDER BY ORDINAL_POSITION
     `
 	rows, err := c.db.Query(query, params.Database, params.Table)
 	if err != nil {
@@ -127,17 +130,39 @@ func (c *MySQLConnection) GetTableFields(params QueryParams) ([]FieldInfo, error
 	var fields []FieldInfo
 	for rows.Next() {
 		var field FieldInfo
-		var isNullable string
-		if err := rows.Scan(&field.Name, &field.Type, &isNullable, &field.Key, &field.Comment); err != nil {
+		var isNullable, extra string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&field.Name, &field.Type, &isNullable, &field.Key, &field.Comment, &defaultValue, &extra); err != nil {
 			return nil, fmt.Errorf("failed to scan field info: %w", err)
 		}
 		field.Nullable = (isNullable == "YES")
+		if defaultValue.Valid {
+			field.DefaultValue = defaultValue.String
+		}
+		field.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
 		fields = append(fields, field)
 	}
 
 	return fields, nil
 }
 
+
+// GetTableKeys 从 STATISTICS 读取主键、唯一键与索引，从 KEY_COLUMN_USAGE 读取外键
+func (c *MySQLConnection) GetTableKeys(params QueryParams) ([]KeyInfo, error) {
+	return queryKeys(c.db, []string{`
+		SELECT INDEX_NAME,
+			CASE WHEN INDEX_NAME = 'PRIMARY' THEN 'primary' WHEN NON_UNIQUE = 0 THEN 'unique' ELSE 'index' END,
+			COLUMN_NAME, NULL, NULL, NULL
+		FROM INFORMATION_SCHEMA.STATISTICS
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+		ORDER BY INDEX_NAME, SEQ_IN_INDEX`, `
+		SELECT CONSTRAINT_NAME, 'foreign', COLUMN_NAME, REFERENCED_TABLE_SCHEMA, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION`,
+	}, params.Database, params.Table)
+}
+
 // GetSchemas 获取指定数据库的所有schema
 // MySQL doesn't have true schema support like Oracle, so we return an empty schema list
 func (c *MySQLConnection) GetSchemas(database string) ([]Schema, error) {
@@ -151,6 +176,19 @@ func (c *MySQLConnection) GetConfig() Config {
 	return c.config
 }
 
+// OpenSession 打开独立会话，只读配置下执行 SET SESSION TRANSACTION READ ONLY
+func (c *MySQLConnection) OpenSession(ctx context.Context) (*sql.Conn, error) {
+	if c.config.ReadOnly {
+		return openSession(ctx, c.db, "SET SESSION TRANSACTION READ ONLY")
+	}
+	return openSession(ctx, c.db)
+}
+
+// BeginReadOnly 以 START TRANSACTION READ ONLY 开启事务
+func (c *MySQLConnection) BeginReadOnly(ctx context.Context, session *sql.Conn) (*sql.Tx, error) {
+	return beginReadOnly(ctx, session, &sql.TxOptions{ReadOnly: true})
+}
+
 // Test 测试数据库连接是否可用
 func (c *MySQLConnection) Test() error {
 	return c.db.Ping()
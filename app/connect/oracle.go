@@ -1,6 +1,7 @@
 package connect
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -47,6 +48,32 @@ func (c *OracleConnection) GetConfig() Config {
 	return c.config
 }
 
+// GetTableKeys 从 ALL_CONSTRAINTS 读取主键、唯一约束与外键，
+// 从 ALL_INDEXES 读取不属于主键或唯一约束的索引
+func (c *OracleConnection) GetTableKeys(params QueryParams) ([]KeyInfo, error) {
+	return queryKeys(c.db, []string{`
+		SELECT cons.CONSTRAINT_NAME,
+			CASE cons.CONSTRAINT_TYPE WHEN 'P' THEN 'primary' WHEN 'U' THEN 'unique' ELSE 'foreign' END,
+			cols.COLUMN_NAME, r.OWNER, r.TABLE_NAME, rc.COLUMN_NAME
+		FROM ALL_CONSTRAINTS cons
+		JOIN ALL_CONS_COLUMNS cols ON cols.OWNER = cons.OWNER
+			AND cols.CONSTRAINT_NAME = cons.CONSTRAINT_NAME AND cols.TABLE_NAME = cons.TABLE_NAME
+		LEFT JOIN ALL_CONSTRAINTS r ON r.OWNER = cons.R_OWNER AND r.CONSTRAINT_NAME = cons.R_CONSTRAINT_NAME
+		LEFT JOIN ALL_CONS_COLUMNS rc ON rc.OWNER = r.OWNER
+			AND rc.CONSTRAINT_NAME = r.CONSTRAINT_NAME AND rc.POSITION = cols.POSITION
+		WHERE cons.OWNER = :1 AND cons.TABLE_NAME = :2 AND cons.CONSTRAINT_TYPE IN ('P', 'U', 'R')
+		ORDER BY cons.CONSTRAINT_NAME, cols.POSITION`, `
+		SELECT i.INDEX_NAME, CASE i.UNIQUENESS WHEN 'UNIQUE' THEN 'unique' ELSE 'index' END,
+			ic.COLUMN_NAME, NULL, NULL, NULL
+		FROM ALL_INDEXES i
+		JOIN ALL_IND_COLUMNS ic ON ic.INDEX_OWNER = i.OWNER AND ic.INDEX_NAME = i.INDEX_NAME
+		WHERE i.TABLE_OWNER = :1 AND i.TABLE_NAME = :2
+			AND NOT EXISTS (SELECT 1 FROM ALL_CONSTRAINTS c WHERE c.OWNER = i.TABLE_OWNER
+				AND c.INDEX_NAME = i.INDEX_NAME AND c.CONSTRAINT_TYPE IN ('P', 'U'))
+		ORDER BY i.INDEX_NAME, ic.COLUMN_POSITION`,
+	}, params.Schema, params.Table)
+}
+
 // GetSchemas 获取指定数据库的所有schema
 func (c *OracleConnection) GetSchemas(database string) ([]Schema, error) {
 	rows, err := c.db.Query(`
@@ -133,9 +160,11 @@ func (c *OracleConnection) GetViews(params QueryParams) ([]ViewInfo, error) {
 func (c *OracleConnection) GetTableFields(params QueryParams) ([]FieldInfo, error) {
 	// First, get all columns
 	columnsQuery := `
-		SELECT c.COLUMN_NAME, c.DATA_TYPE, c.NULLABLE
+		SELECT c.COLUMN_NAME, c.DATA_TYPE, c.NULLABLE,
+			c.DATA_LENGTH, c.CHAR_LENGTH, c.CHAR_USED, c.DATA_PRECISION, c.DATA_SCALE
 		FROM ALL_TAB_COLUMNS c
 		WHERE c.OWNER = :1 AND c.TABLE_NAME = :2
+		ORDER BY c.COLUMN_ID
 	`
     rows, err := c.db.Query(columnsQuery, params.Schema, params.Table)
 	if err != nil {
@@ -143,17 +172,23 @@ func (c *OracleConnection) GetTableFields(params QueryParams) ([]FieldInfo, erro
 	}
 	defer rows.Close()
 
-	// Create a map to store field information
+	// Create a map to store field information, names keeps the column order
 	fieldMap := make(map[string]FieldInfo)
+	var names []string
 
 	for rows.Next() {
 		var field FieldInfo
 		var nullable string
-		if err := rows.Scan(&field.Name, &field.Type, &nullable); err != nil {
+		var dataLength, charLength int64
+		var charUsed sql.NullString
+		var precision, scale sql.NullInt64
+		if err := rows.Scan(&field.Name, &field.Type, &nullable, &dataLength, &charLength, &charUsed, &precision, &scale); err != nil {
 			return nil, err
 		}
+		field.Type = oracleColumnType(field.Type, dataLength, charLength, charUsed.String, precision, scale)
 		field.Nullable = nullable == "Y"
 		fieldMap[field.Name] = field
+		names = append(names, field.Name)
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
@@ -235,13 +270,54 @@ func (c *OracleConnection) GetTableFields(params QueryParams) ([]FieldInfo, erro
 
 	// Build the fields slice from the map
 	var fields []FieldInfo
-	for _, field := range fieldMap {
-		fields = append(fields, field)
+	for _, name := range names {
+		fields = append(fields, fieldMap[name])
 	}
 
 	return fields, nil
 }
 
+// oracleColumnType 按 ALL_TAB_COLUMNS 的长度与精度拼出完整类型，如 VARCHAR2(20 CHAR)、NUMBER(10,2)
+func oracleColumnType(dataType string, dataLength, charLength int64, charUsed string, precision, scale sql.NullInt64) string {
+	switch dataType {
+	case "VARCHAR2", "CHAR":
+		if charUsed == "C" {
+			return fmt.Sprintf("%s(%d CHAR)", dataType, charLength)
+		}
+		return fmt.Sprintf("%s(%d)", dataType, dataLength)
+	case "NVARCHAR2", "NCHAR":
+		return fmt.Sprintf("%s(%d)", dataType, charLength)
+	case "RAW":
+		return fmt.Sprintf("RAW(%d)", dataLength)
+	case "FLOAT":
+		if precision.Valid {
+			return fmt.Sprintf("FLOAT(%d)", precision.Int64)
+		}
+	case "NUMBER":
+		switch {
+		case precision.Valid && scale.Valid && scale.Int64 != 0:
+			return fmt.Sprintf("NUMBER(%d,%d)", precision.Int64, scale.Int64)
+		case precision.Valid:
+			return fmt.Sprintf("NUMBER(%d)", precision.Int64)
+		case scale.Valid && scale.Int64 == 0:
+			// INTEGER 等类型为 NUMBER(*,0)，最多 38 位
+			return "NUMBER(38)"
+		}
+	}
+	return dataType
+}
+
+// OpenSession 打开独立会话
+// Oracle 的 SET TRANSACTION READ ONLY 仅对单个事务生效，只读保护由 BeginReadOnly 提供
+func (c *OracleConnection) OpenSession(ctx context.Context) (*sql.Conn, error) {
+	return openSession(ctx, c.db)
+}
+
+// BeginReadOnly 开启事务后首先执行 SET TRANSACTION READ ONLY（驱动不支持只读事务选项）
+func (c *OracleConnection) BeginReadOnly(ctx context.Context, session *sql.Conn) (*sql.Tx, error) {
+	return beginReadOnly(ctx, session, nil, "SET TRANSACTION READ ONLY")
+}
+
 // Test 测试数据库连接是否可用
 func (c *OracleConnection) Test() error {
 	return c.db.Ping()
@@ -1,8 +1,10 @@
 package connect
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	_ "github.com/lib/pq"
 )
@@ -61,6 +63,34 @@ func (c *PostgreSQLConnection) GetConfig() Config {
 	return c.config
 }
 
+// GetTableKeys 从 pg_index 读取主键、唯一键与索引（不含 INCLUDE 字段），从 pg_constraint 读取外键
+func (c *PostgreSQLConnection) GetTableKeys(params QueryParams) ([]KeyInfo, error) {
+	return queryKeys(c.db, []string{`
+		SELECT i.relname,
+			CASE WHEN x.indisprimary THEN 'primary' WHEN x.indisunique THEN 'unique' ELSE 'index' END,
+			a.attname, NULL, NULL, NULL
+		FROM pg_index x
+		JOIN pg_class t ON t.oid = x.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class i ON i.oid = x.indexrelid
+		CROSS JOIN LATERAL unnest(x.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum AND k.attnum > 0
+		WHERE n.nspname = $1 AND t.relname = $2 AND k.ord <= x.indnkeyatts
+		ORDER BY i.relname, k.ord`, `
+		SELECT con.conname, 'foreign', a.attname, rn.nspname, rt.relname, ra.attname
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class rt ON rt.oid = con.confrelid
+		JOIN pg_namespace rn ON rn.oid = rt.relnamespace
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refnum, ord)
+		JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refnum
+		WHERE con.contype = 'f' AND n.nspname = $1 AND t.relname = $2
+		ORDER BY con.conname, k.ord`,
+	}, params.Schema, params.Table)
+}
+
 // GetSchemas 获取指定数据库的所有schema
 func (c *PostgreSQLConnection) GetSchemas(database string) ([]Schema, error) {
 	query := `SELECT schema_name FROM information_schema.schemata 
@@ -140,7 +170,7 @@ func (c *PostgreSQLConnection) GetViews(params QueryParams) ([]ViewInfo, error)
 func (c *PostgreSQLConnection) GetTableFields(params QueryParams) ([]FieldInfo, error) {
 	query := `SELECT column_name, data_type, 
 					 col_description((quote_ident($1) || '.' || quote_ident($2))::regclass::oid, ordinal_position) as column_comment,
-					 is_nullable, column_default
+					 is_nullable, column_default, is_identity
 			  FROM information_schema.columns
 			  WHERE table_schema = $1 AND table_name = $2
 			  ORDER BY ordinal_position`
@@ -155,8 +185,8 @@ func (c *PostgreSQLConnection) GetTableFields(params QueryParams) ([]FieldInfo,
 	for rows.Next() {
 		var field FieldInfo
 		var comment, isNullable sql.NullString
-		var defaultValue sql.NullString
-		if err := rows.Scan(&field.Name, &field.Type, &comment, &isNullable, &defaultValue); err != nil {
+		var defaultValue, isIdentity sql.NullString
+		if err := rows.Scan(&field.Name, &field.Type, &comment, &isNullable, &defaultValue, &isIdentity); err != nil {
 			return nil, err
 		}
 		if comment.Valid {
@@ -168,12 +198,28 @@ func (c *PostgreSQLConnection) GetTableFields(params QueryParams) ([]FieldInfo,
 		if defaultValue.Valid {
 			field.DefaultValue = defaultValue.String
 		}
+		// serial 列以序列默认值实现
+		field.AutoIncrement = isIdentity.String == "YES" || strings.HasPrefix(field.DefaultValue, "nextval(")
 		fields = append(fields, field)
 	}
 
 	return fields, nil
 }
 
+// OpenSession 打开独立会话，只读配置下开启 default_transaction_read_only
+func (c *PostgreSQLConnection) OpenSession(ctx context.Context) (*sql.Conn, error) {
+	if c.config.ReadOnly {
+		return openSession(ctx, c.db, "SET SESSION default_transaction_read_only = on")
+	}
+	return openSession(ctx, c.db)
+}
+
+// BeginReadOnly 以 BEGIN READ ONLY 开启事务；执行查询后无法再切换为读写，
+// 语句中通过 set_config 等函数修改的会话设置也会随回滚撤销
+func (c *PostgreSQLConnection) BeginReadOnly(ctx context.Context, session *sql.Conn) (*sql.Tx, error) {
+	return beginReadOnly(ctx, session, &sql.TxOptions{ReadOnly: true})
+}
+
 // Test 测试数据库连接是否可用
 func (c *PostgreSQLConnection) Test() error {
 	return c.db.Ping()
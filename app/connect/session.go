@@ -0,0 +1,39 @@
+package connect
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// openSession 从 *sql.DB 中取出独立连接，并依次执行会话初始化语句
+// 会话设置随物理连接保留，因此只读配置的连接在归还连接池后仍保持只读
+func openSession(ctx context.Context, db *sql.DB, initSQL ...string) (*sql.Conn, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session: %w", err)
+	}
+	for _, stmt := range initSQL {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to initialize session with %q: %w", stmt, err)
+		}
+	}
+	return conn, nil
+}
+
+// beginReadOnly 在会话上开启事务并执行事务初始化语句，供只读连接执行用户SQL
+// 调用方执行完毕后必须回滚，语句中对数据或会话设置的修改随之撤销
+func beginReadOnly(ctx context.Context, session *sql.Conn, opts *sql.TxOptions, initSQL ...string) (*sql.Tx, error) {
+	tx, err := session.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin read-only transaction: %w", err)
+	}
+	for _, stmt := range initSQL {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to initialize transaction with %q: %w", stmt, err)
+		}
+	}
+	return tx, nil
+}
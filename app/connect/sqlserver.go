@@ -1,6 +1,7 @@
 package connect
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -64,6 +65,30 @@ func (c *SQLServerConnection) GetDBNames() ([]DatabaseInfo, error) {
 	return databases, nil
 }
 
+// GetTableKeys 从 sys.indexes 读取主键、唯一键与索引（不含包含列），从 sys.foreign_keys 读取外键
+func (c *SQLServerConnection) GetTableKeys(params QueryParams) ([]KeyInfo, error) {
+	return queryKeys(c.db, []string{`
+		SELECT i.name,
+			CASE WHEN i.is_primary_key = 1 THEN 'primary' WHEN i.is_unique = 1 THEN 'unique' ELSE 'index' END,
+			c.name, NULL, NULL, NULL
+		FROM sys.indexes i
+		JOIN sys.objects o ON o.object_id = i.object_id
+		JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+		JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+		WHERE o.schema_id = SCHEMA_ID(@p1) AND o.name = @p2 AND i.type > 0 AND ic.is_included_column = 0
+		ORDER BY i.name, ic.key_ordinal`, `
+		SELECT fk.name, 'foreign', pc.name, SCHEMA_NAME(rt.schema_id), rt.name, rc.name
+		FROM sys.foreign_keys fk
+		JOIN sys.objects o ON o.object_id = fk.parent_object_id
+		JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+		JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+		JOIN sys.objects rt ON rt.object_id = fkc.referenced_object_id
+		JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+		WHERE o.schema_id = SCHEMA_ID(@p1) AND o.name = @p2
+		ORDER BY fk.name, fkc.constraint_column_id`,
+	}, params.Schema, params.Table)
+}
+
 // GetSchemas 获取指定数据库的所有schema
 func (c *SQLServerConnection) GetSchemas(database string) ([]Schema, error) {
 	query := fmt.Sprintf("USE [%s]; SELECT name FROM sys.schemas WHERE name NOT IN ('sys', 'guest', 'INFORMATION_SCHEMA')", database)
@@ -142,10 +167,23 @@ func (c *SQLServerConnection) GetViews(params QueryParams) ([]ViewInfo, error) {
 
 // GetTableFields 获取指定表的所有字段信息
 func (c *SQLServerConnection) GetTableFields(params QueryParams) ([]FieldInfo, error) {
-	query := `SELECT c.name, t.name as data_type,
+	// 拼出带长度、精度的完整类型，nvarchar/nchar 的 max_length 为字节数
+	query := `SELECT c.name,
+					 CASE
+					   WHEN t.name IN ('varchar', 'char', 'varbinary', 'binary')
+					     THEN t.name + '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length AS VARCHAR(10)) END + ')'
+					   WHEN t.name IN ('nvarchar', 'nchar')
+					     THEN t.name + '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length / 2 AS VARCHAR(10)) END + ')'
+					   WHEN t.name IN ('decimal', 'numeric')
+					     THEN t.name + '(' + CAST(c.precision AS VARCHAR(10)) + ',' + CAST(c.scale AS VARCHAR(10)) + ')'
+					   WHEN t.name IN ('datetime2', 'time', 'datetimeoffset')
+					     THEN t.name + '(' + CAST(c.scale AS VARCHAR(10)) + ')'
+					   ELSE t.name
+					 END as data_type,
 					 CAST(ep.value AS NVARCHAR(MAX)) as column_comment,
 					 c.is_nullable,
-					 OBJECT_DEFINITION(c.default_object_id) as column_default
+					 OBJECT_DEFINITION(c.default_object_id) as column_default,
+					 c.is_identity
 			  FROM sys.columns c
 			  INNER JOIN sys.types t ON c.user_type_id = t.user_type_id
 			  INNER JOIN sys.objects o ON c.object_id = o.object_id
@@ -166,7 +204,7 @@ func (c *SQLServerConnection) GetTableFields(params QueryParams) ([]FieldInfo, e
 		var comment sql.NullString
 		var isNullable bool
 		var defaultValue sql.NullString
-		if err := rows.Scan(&field.Name, &field.Type, &comment, &isNullable, &defaultValue); err != nil {
+		if err := rows.Scan(&field.Name, &field.Type, &comment, &isNullable, &defaultValue, &field.AutoIncrement); err != nil {
 			return nil, err
 		}
 		if comment.Valid {
@@ -187,6 +225,18 @@ func (c *SQLServerConnection) GetConfig() Config {
 	return c.config
 }
 
+// OpenSession 打开独立会话
+// SQL Server 没有会话级只读开关，只读保护见 BeginReadOnly
+func (c *SQLServerConnection) OpenSession(ctx context.Context) (*sql.Conn, error) {
+	return openSession(ctx, c.db)
+}
+
+// BeginReadOnly SQL Server 没有只读事务，开启普通事务并由调用方回滚，
+// 语句分类校验之外漏过的数据修改与 DDL 随回滚撤销
+func (c *SQLServerConnection) BeginReadOnly(ctx context.Context, session *sql.Conn) (*sql.Tx, error) {
+	return beginReadOnly(ctx, session, nil)
+}
+
 // Test 测试数据库连接是否可用
 func (c *SQLServerConnection) Test() error {
 	return c.db.Ping()
@@ -0,0 +1,319 @@
+package ddl
+
+import (
+	"dbrun/app/dialect"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 有损转换的类别
+const (
+	IssueType     = "type"
+	IssueDefault  = "default"
+	IssueIdentity = "identity"
+	IssueIndex    = "index"
+	IssueView     = "view"
+)
+
+// Issue 转换中无法等价表达的内容
+type Issue struct {
+	Object  string // 表或视图名称
+	Column  string
+	Kind    string
+	Source  string // 源方言中的写法
+	Target  string // 目标方言中的写法
+	Message string
+}
+
+// Convert 将源方言的表定义转换为目标方言：映射字段类型、改写默认值表达式与自增列，
+// 返回转换后的定义与有损转换列表。视图定义依赖源方言的 SQL 语法，不做转换，仅记入列表
+func Convert(s Schema, from, to string) (Schema, []Issue, error) {
+	if _, err := dialect.Get(from); err != nil {
+		return Schema{}, nil, err
+	}
+	if _, err := dialect.Get(to); err != nil {
+		return Schema{}, nil, err
+	}
+	var out Schema
+	var issues []Issue
+	for _, t := range s.Tables {
+		ct := t
+		ct.Columns = make([]Column, len(t.Columns))
+		types := map[string]sqlType{}
+		for i, c := range t.Columns {
+			cc, parsed, found := convertColumn(from, to, c)
+			for j := range found {
+				found[j].Object = t.Name.String()
+			}
+			issues = append(issues, found...)
+			ct.Columns[i] = cc
+			types[strings.ToLower(c.Name)] = parsed
+		}
+		if to == "mysql" || to == "mariadb" {
+			issues = append(issues, mysqlIndexIssues(ct, types)...)
+		}
+		out.Tables = append(out.Tables, ct)
+	}
+	for _, v := range s.Views {
+		issues = append(issues, Issue{Object: v.Name.String(), Kind: IssueView, Message: "view definitions are not translated, recreate the view manually"})
+	}
+	return out, issues, nil
+}
+
+// convertColumn 转换单个字段，返回的 sqlType 为源类型的解析结果
+func convertColumn(from, to string, c Column) (Column, sqlType, []Issue) {
+	var issues []Issue
+	issue := func(kind, source, target, message string) {
+		issues = append(issues, Issue{Column: c.Name, Kind: kind, Source: source, Target: target, Message: message})
+	}
+
+	t, serial := parseType(from, c.Type)
+	typ, note := renderType(from, to, t)
+	if note != "" {
+		issue(IssueType, c.Type, typ, note)
+	}
+	out := c
+	out.Type, out.Default = typ, ""
+	out.AutoIncrement = c.AutoIncrement || serial
+
+	def := strings.TrimSpace(c.Default)
+	if sequenceDefault.MatchString(def) {
+		if !serial && !c.AutoIncrement {
+			issue(IssueIdentity, def, "identity", "sequence default replaced by an identity column, the current sequence value is not preserved")
+		}
+		out.AutoIncrement = true
+		def = ""
+	}
+	if out.AutoIncrement {
+		if !isInteger(t.kind) && !(t.kind == kindDecimal && (len(t.args) < 2 || t.args[1] == 0)) {
+			issue(IssueIdentity, c.Type, typ, "identity columns require an integer type")
+		}
+		return out, t, issues
+	}
+
+	expr, note := convertDefault(from, to, t, def)
+	out.Default = expr
+	if note != "" {
+		issue(IssueDefault, def, expr, note)
+	}
+	return out, t, issues
+}
+
+func isInteger(kind string) bool {
+	switch kind {
+	case kindTinyInt, kindSmallInt, kindMediumInt, kindInt, kindBigInt:
+		return true
+	}
+	return false
+}
+
+// mysqlIndexIssues MySQL 无法直接为 TEXT / BLOB 字段建立主键或索引
+func mysqlIndexIssues(t Table, types map[string]sqlType) []Issue {
+	var issues []Issue
+	seen := map[string]bool{}
+	check := func(columns []string) {
+		for _, name := range columns {
+			c := columnByName(t, name)
+			if c == nil || seen[strings.ToLower(name)] {
+				continue
+			}
+			lower := strings.ToLower(c.Type)
+			if strings.HasSuffix(lower, "text") || strings.HasSuffix(lower, "blob") || lower == "json" {
+				seen[strings.ToLower(name)] = true
+				issues = append(issues, Issue{Object: t.Name.String(), Column: c.Name, Kind: IssueIndex, Source: types[strings.ToLower(name)].raw, Target: c.Type,
+					Message: "MySQL cannot index this column without a prefix length, shorten the type or adjust the index"})
+			}
+		}
+	}
+	check(t.PrimaryKey)
+	for _, idx := range t.Indexes {
+		check(idx.Columns)
+	}
+	for _, fk := range t.ForeignKeys {
+		check(fk.Columns)
+	}
+	return issues
+}
+
+func columnByName(t Table, name string) *Column {
+	for i := range t.Columns {
+		if strings.EqualFold(t.Columns[i].Name, name) {
+			return &t.Columns[i]
+		}
+	}
+	return nil
+}
+
+// 默认值归一化后的类别
+const (
+	valueNull     = "null"
+	valueNumber   = "number"
+	valueString   = "string"
+	valueBool     = "bool"
+	valueNow      = "now"
+	valueToday    = "today"
+	valueUUID     = "uuid"
+	valueVerbatim = "verbatim"
+)
+
+var (
+	pgCast      = regexp.MustCompile(`(?s)^(.*?)::[a-zA-Z_][\w ."]*(\(\d+(,\d+)?\))?(\[\])?$`)
+	quotedValue = regexp.MustCompile(`(?s)^[nNeE]?'(.*)'$`)
+	nowFunction = regexp.MustCompile(`^(current_timestamp|now|localtimestamp|localtime|getdate|sysdatetime|getutcdate|sysutcdatetime|sysdate|systimestamp|statement_timestamp|transaction_timestamp|clock_timestamp|sysdatetimeoffset)(\(\d*\))?$`)
+	todayFn     = regexp.MustCompile(`^(current_date|curdate)(\(\))?$|^trunc\(sysdate\)$|^cast\(getdate\(\)asdate\)$|^convert\(date,getdate\(\)\)$`)
+	uuidFn      = regexp.MustCompile(`^(uuid|gen_random_uuid|uuid_generate_v4|newid|newsequentialid|sys_guid)\(\)$`)
+)
+
+// normalizeDefault 将源方言的默认值原文归一为值类别与值
+func normalizeDefault(from, def string) (string, string) {
+	v := strings.TrimSpace(def)
+	if v == "" || strings.EqualFold(v, "null") {
+		return valueNull, ""
+	}
+	// SQL Server 存储为 ((0))、('abc')，PostgreSQL 存储为 'abc'::character varying
+	for {
+		trimmed := v
+		if strings.HasPrefix(trimmed, "(") && strings.HasSuffix(trimmed, ")") && balanced(trimmed[1:len(trimmed)-1]) {
+			trimmed = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+		}
+		if from == "postgresql" {
+			if m := pgCast.FindStringSubmatch(trimmed); m != nil && balanced(m[1]) {
+				trimmed = strings.TrimSpace(m[1])
+			}
+		}
+		if trimmed == v {
+			break
+		}
+		v = trimmed
+	}
+	if strings.EqualFold(v, "null") {
+		return valueNull, ""
+	}
+	compact := strings.ToLower(strings.Join(strings.Fields(v), ""))
+	switch {
+	case numericDefault.MatchString(v):
+		return valueNumber, v
+	case compact == "true" || compact == "false":
+		return valueBool, compact
+	case nowFunction.MatchString(compact):
+		return valueNow, v
+	case todayFn.MatchString(compact):
+		return valueToday, v
+	case uuidFn.MatchString(compact):
+		return valueUUID, v
+	}
+	if m := quotedValue.FindStringSubmatch(v); m != nil && !strings.Contains(strings.ReplaceAll(m[1], "''", ""), "'") {
+		return valueString, strings.ReplaceAll(m[1], "''", "'")
+	}
+	if from == "mysql" && !strings.ContainsAny(v, "()") && !strings.HasPrefix(strings.ToLower(v), "b'") && !strings.HasPrefix(strings.ToLower(v), "x'") {
+		// MySQL 的 COLUMN_DEFAULT 中字符串默认值不带引号
+		return valueString, v
+	}
+	return valueVerbatim, v
+}
+
+// balanced 括号是否成对，用于判断外层括号能否去掉
+func balanced(s string) bool {
+	depth := 0
+	for _, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// convertDefault 将默认值改写为目标方言的表达式，返回的说明非空时表示需要人工确认
+func convertDefault(from, to string, t sqlType, def string) (string, string) {
+	kind, value := normalizeDefault(from, def)
+	d, _ := dialect.Get(to)
+	switch kind {
+	case valueNull:
+		return "", ""
+	case valueNumber:
+		if t.kind == kindBoolean && to == "postgresql" {
+			if value == "0" {
+				return "FALSE", ""
+			}
+			return "TRUE", ""
+		}
+		return value, ""
+	case valueBool:
+		if to == "postgresql" {
+			return strings.ToUpper(value), ""
+		}
+		if value == "true" {
+			return "1", ""
+		}
+		return "0", ""
+	case valueString:
+		if t.kind == kindBoolean && to == "postgresql" {
+			switch strings.ToLower(value) {
+			case "0", "f", "false", "n", "no", "off":
+				return "FALSE", ""
+			case "1", "t", "true", "y", "yes", "on":
+				return "TRUE", ""
+			}
+		}
+		return d.Literal(value), ""
+	case valueNow:
+		switch to {
+		case "mysql", "mariadb":
+			if n := t.length(); n > 0 && (t.kind == kindDatetime || t.kind == kindTimestampTZ) {
+				// 默认值的精度必须与字段一致
+				return fmt.Sprintf("CURRENT_TIMESTAMP(%d)", min(n, 6)), ""
+			}
+			return "CURRENT_TIMESTAMP", ""
+		case "sqlserver":
+			if t.kind == kindTimestampTZ {
+				return "SYSDATETIMEOFFSET()", ""
+			}
+			return "SYSDATETIME()", ""
+		case "oracle":
+			if t.kind == kindDatetime && from == "oracle" && strings.EqualFold(strings.TrimSpace(t.raw), "date") {
+				return "SYSDATE", ""
+			}
+			return "SYSTIMESTAMP", ""
+		}
+		return "CURRENT_TIMESTAMP", ""
+	case valueToday:
+		switch to {
+		case "mysql", "mariadb":
+			return "(CURRENT_DATE)", "expression defaults require MySQL 8.0.13 or MariaDB 10.2"
+		case "sqlserver":
+			return "CAST(GETDATE() AS date)", ""
+		case "oracle":
+			return "TRUNC(SYSDATE)", ""
+		}
+		return "CURRENT_DATE", ""
+	case valueUUID:
+		switch to {
+		case "mysql", "mariadb":
+			return "(UUID())", "expression defaults require MySQL 8.0.13 or MariaDB 10.2"
+		case "sqlserver":
+			return "NEWID()", ""
+		case "oracle":
+			return "SYS_GUID()", "SYS_GUID() returns RAW(16), convert it if the column is a string"
+		}
+		return "gen_random_uuid()", ""
+	}
+	if from == to || (isMySQLName(from) && isMySQLName(to)) {
+		return value, ""
+	}
+	if isMySQLName(to) && !strings.HasPrefix(value, "(") {
+		// MySQL 的表达式默认值需要加括号，否则会被当作字符串
+		value = "(" + value + ")"
+	}
+	return value, "default expression copied verbatim, check that it is valid in the target dialect"
+}
+
+func isMySQLName(name string) bool {
+	return name == "mysql" || name == "mariadb"
+}
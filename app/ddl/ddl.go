@@ -0,0 +1,485 @@
+package ddl
+
+import (
+	"dbrun/app/dialect"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Name 数据库对象名称
+type Name struct {
+	Database string
+	Schema   string
+	Name     string
+}
+
+func (n Name) key() string {
+	return strings.ToLower(n.Database + "\x00" + n.Schema + "\x00" + n.Name)
+}
+
+func (n Name) String() string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{n.Database, n.Schema, n.Name} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// Column 表字段，Default 为元数据中的默认值原文
+type Column struct {
+	Name          string
+	Type          string
+	Nullable      bool
+	Default       string
+	Comment       string
+	AutoIncrement bool // 自增/标识列，按目标方言输出 AUTO_INCREMENT、IDENTITY 等
+}
+
+// Index 索引，名称为空时自动生成
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// ForeignKey 外键约束，名称为空时自动生成
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   Name
+	RefColumns []string
+}
+
+// Table 建表所需的表定义
+type Table struct {
+	Name        Name
+	Comment     string
+	Columns     []Column
+	PrimaryKey  []string
+	Indexes     []Index
+	ForeignKeys []ForeignKey
+}
+
+// View 视图定义；DependsOn 为视图引用的表或视图，用于排序
+type View struct {
+	Name       Name
+	Definition string
+	DependsOn  []Name
+}
+
+// Schema 一次生成的全部对象
+type Schema struct {
+	Tables []Table
+	Views  []View
+}
+
+// Options 生成选项
+type Options struct {
+	Qualify bool // 对象名带库/Schema限定
+}
+
+// generator 单次生成的状态
+type generator struct {
+	d        dialect.Dialect
+	opts     Options
+	b        strings.Builder
+	names    map[string]bool // 已使用的约束与索引名
+	warnings []string
+}
+
+// Generate 按依赖顺序生成建表与建视图脚本：被引用的表先于引用方创建，
+// 循环引用的外键在所有表创建后以 ALTER TABLE 补充；返回脚本与提示信息
+func Generate(d dialect.Dialect, s Schema, opts Options) (string, []string) {
+	g := &generator{d: d, opts: opts, names: map[string]bool{}}
+
+	inScript := map[string]bool{}
+	for _, t := range s.Tables {
+		inScript[t.Name.key()] = true
+	}
+	for _, v := range s.Views {
+		inScript[v.Name.key()] = true
+	}
+
+	type deferred struct {
+		table Table
+		fk    ForeignKey
+	}
+	var later []deferred
+	created := map[string]bool{}
+	for _, t := range orderTables(s.Tables) {
+		var inline []ForeignKey
+		for _, fk := range t.ForeignKeys {
+			ref := fk.RefTable.key()
+			switch {
+			case !inScript[ref]:
+				g.warn("foreign key %s(%s) -> %s omitted: referenced table is not in the script", t.Name, strings.Join(fk.Columns, ", "), fk.RefTable)
+			case created[ref] || ref == t.Name.key():
+				inline = append(inline, fk)
+			default:
+				later = append(later, deferred{t, fk})
+			}
+		}
+		g.createTable(t, inline)
+		created[t.Name.key()] = true
+	}
+	for _, l := range later {
+		g.stmt(fmt.Sprintf("ALTER TABLE %s ADD %s", g.name(l.table.Name), g.foreignKey(l.table, l.fk)))
+	}
+
+	views, cyclic := orderViews(s.Views)
+	for _, v := range cyclic {
+		g.warn("view %s is part of a dependency cycle", v)
+	}
+	for _, v := range views {
+		for _, dep := range v.DependsOn {
+			if !inScript[dep.key()] {
+				g.warn("view %s depends on %s which is not in the script", v.Name, dep)
+			}
+		}
+		g.createView(v)
+	}
+	return g.b.String(), g.warnings
+}
+
+// orderTables 按外键依赖拓扑排序，保持输入顺序；遇到循环时取剩余的第一张表继续
+func orderTables(tables []Table) []Table {
+	index := map[string]int{}
+	for i, t := range tables {
+		index[t.Name.key()] = i
+	}
+	deps := make([][]int, len(tables))
+	for i, t := range tables {
+		for _, fk := range t.ForeignKeys {
+			if j, ok := index[fk.RefTable.key()]; ok && j != i {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+	return topoOrder(tables, deps)
+}
+
+// orderViews 按视图间的引用排序，返回排序结果与处于循环中的视图
+func orderViews(views []View) ([]View, []Name) {
+	index := map[string]int{}
+	for i, v := range views {
+		index[v.Name.key()] = i
+	}
+	deps := make([][]int, len(views))
+	for i, v := range views {
+		for _, dep := range v.DependsOn {
+			if j, ok := index[dep.key()]; ok && j != i {
+				deps[i] = append(deps[i], j)
+			}
+		}
+	}
+	ordered := topoOrder(views, deps)
+	// 排在依赖之前的视图即处于循环中
+	pos := map[string]int{}
+	for i, v := range ordered {
+		pos[v.Name.key()] = i
+	}
+	var cyclic []Name
+	for i, v := range ordered {
+		for _, j := range deps[index[v.Name.key()]] {
+			if pos[views[j].Name.key()] > i {
+				cyclic = append(cyclic, v.Name)
+				break
+			}
+		}
+	}
+	return ordered, cyclic
+}
+
+func topoOrder[T any](items []T, deps [][]int) []T {
+	done := make([]bool, len(items))
+	out := make([]T, 0, len(items))
+	for len(out) < len(items) {
+		pick, first := -1, -1
+		for i := range items {
+			if done[i] {
+				continue
+			}
+			if first < 0 {
+				first = i
+			}
+			ready := true
+			for _, j := range deps[i] {
+				if !done[j] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				pick = i
+				break
+			}
+		}
+		if pick < 0 {
+			pick = first
+		}
+		done[pick] = true
+		out = append(out, items[pick])
+	}
+	return out
+}
+
+func (g *generator) warn(format string, args ...interface{}) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+// stmt 写出一条语句；SQL Server 需以 GO 分隔批次（CREATE VIEW 必须是批次中的第一条语句）
+func (g *generator) stmt(sql string) {
+	g.b.WriteString(sql)
+	g.b.WriteString(";\n")
+	if g.d.Name() == "sqlserver" {
+		g.b.WriteString("GO\n")
+	}
+}
+
+func (g *generator) name(n Name) string {
+	if g.opts.Qualify {
+		return g.d.QualifiedName(n.Database, n.Schema, n.Name)
+	}
+	return g.d.QuoteIdent(n.Name)
+}
+
+func (g *generator) idents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = g.d.QuoteIdent(n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func (g *generator) createTable(t Table, fks []ForeignKey) {
+	if g.b.Len() > 0 {
+		g.b.WriteString("\n")
+	}
+	mysql := isMySQL(g.d)
+	lines := make([]string, 0, len(t.Columns)+len(t.Indexes)+len(fks)+1)
+	for _, c := range t.Columns {
+		lines = append(lines, g.column(t, c))
+	}
+	if len(t.PrimaryKey) > 0 {
+		lines = append(lines, fmt.Sprintf("CONSTRAINT %s PRIMARY KEY (%s)", g.d.QuoteIdent(g.constraintName("pk", t.Name.Name)), g.idents(t.PrimaryKey)))
+	}
+	var indexes []Index
+	for _, idx := range t.Indexes {
+		if !idx.Unique {
+			indexes = append(indexes, idx)
+			continue
+		}
+		name := idx.Name
+		if name == "" {
+			name = g.constraintName(append([]string{"uk", t.Name.Name}, idx.Columns...)...)
+		}
+		lines = append(lines, fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", g.d.QuoteIdent(name), g.idents(idx.Columns)))
+	}
+	for _, fk := range fks {
+		lines = append(lines, g.foreignKey(t, fk))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n  %s\n)", g.name(t.Name), strings.Join(lines, ",\n  "))
+	if mysql && t.Comment != "" {
+		fmt.Fprintf(&b, " COMMENT=%s", g.d.Literal(t.Comment))
+	}
+	g.stmt(b.String())
+
+	for _, idx := range indexes {
+		name := idx.Name
+		if name == "" {
+			name = g.constraintName(append([]string{"idx", t.Name.Name}, idx.Columns...)...)
+		}
+		g.stmt(fmt.Sprintf("CREATE INDEX %s ON %s (%s)", g.d.QuoteIdent(name), g.name(t.Name), g.idents(idx.Columns)))
+	}
+	if !mysql {
+		g.comments(t)
+	}
+}
+
+func (g *generator) column(t Table, c Column) string {
+	var b strings.Builder
+	b.WriteString(g.d.QuoteIdent(c.Name))
+	b.WriteString(" ")
+	b.WriteString(c.Type)
+	if missingLength(g.d.Name(), c.Type) {
+		g.warn("column %s.%s: type %s has no length in the stored metadata", t.Name, c.Name, c.Type)
+	}
+	def := strings.TrimSpace(c.Default)
+	if c.AutoIncrement || sequenceDefault.MatchString(def) {
+		// 序列默认值同样改写为标识列，避免依赖未创建的序列
+		b.WriteString(identityClause(g.d.Name(), c.Type))
+	} else if expr := defaultExpr(g.d, def); expr != "" {
+		b.WriteString(" DEFAULT ")
+		b.WriteString(expr)
+	}
+	if !c.Nullable {
+		b.WriteString(" NOT NULL")
+	}
+	if isMySQL(g.d) && c.Comment != "" {
+		b.WriteString(" COMMENT ")
+		b.WriteString(g.d.Literal(c.Comment))
+	}
+	return b.String()
+}
+
+func (g *generator) foreignKey(t Table, fk ForeignKey) string {
+	name := fk.Name
+	if name == "" {
+		name = g.constraintName("fk", t.Name.Name, fk.RefTable.Name)
+	}
+	return fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		g.d.QuoteIdent(name), g.idents(fk.Columns), g.name(fk.RefTable), g.idents(fk.RefColumns))
+}
+
+// comments 非 MySQL 方言的表与字段注释语句
+func (g *generator) comments(t Table) {
+	type item struct{ column, text string }
+	items := []item{{"", t.Comment}}
+	for _, c := range t.Columns {
+		items = append(items, item{c.Name, c.Comment})
+	}
+	for _, it := range items {
+		if it.text == "" {
+			continue
+		}
+		if g.d.Name() == "sqlserver" {
+			schema := defaultString(t.Name.Schema, "dbo")
+			sql := fmt.Sprintf("EXEC sp_addextendedproperty 'MS_Description', %s, 'SCHEMA', %s, 'TABLE', %s",
+				g.d.Literal(it.text), g.d.Literal(schema), g.d.Literal(t.Name.Name))
+			if it.column != "" {
+				sql += fmt.Sprintf(", 'COLUMN', %s", g.d.Literal(it.column))
+			}
+			g.stmt(sql)
+			continue
+		}
+		if it.column == "" {
+			g.stmt(fmt.Sprintf("COMMENT ON TABLE %s IS %s", g.name(t.Name), g.d.Literal(it.text)))
+		} else {
+			g.stmt(fmt.Sprintf("COMMENT ON COLUMN %s.%s IS %s", g.name(t.Name), g.d.QuoteIdent(it.column), g.d.Literal(it.text)))
+		}
+	}
+}
+
+var createPrefix = regexp.MustCompile(`(?i)^\s*create\s`)
+
+// createView 元数据中的视图定义为完整 CREATE 语句时原样输出，否则视为查询体
+func (g *generator) createView(v View) {
+	g.b.WriteString("\n")
+	def := strings.TrimRight(strings.TrimSpace(v.Definition), "; \t\r\n")
+	if def == "" {
+		g.warn("view %s has no stored definition", v.Name)
+		return
+	}
+	if createPrefix.MatchString(def) {
+		g.stmt(def)
+		return
+	}
+	g.stmt(fmt.Sprintf("CREATE VIEW %s AS\n%s", g.name(v.Name), def))
+}
+
+// constraintName 由前缀、表名与字段名生成约束名，在脚本内唯一并符合方言长度限制
+func (g *generator) constraintName(parts ...string) string {
+	limit := 64
+	if g.d.Name() == "oracle" {
+		limit = 30
+	}
+	base := strings.ToLower(strings.Join(parts, "_"))
+	base = nonIdentChars.ReplaceAllString(base, "_")
+	if len(base) > limit {
+		base = base[:limit]
+	}
+	name := base
+	for i := 2; g.names[name]; i++ {
+		suffix := fmt.Sprintf("_%d", i)
+		if len(base)+len(suffix) > limit {
+			name = base[:limit-len(suffix)] + suffix
+		} else {
+			name = base + suffix
+		}
+	}
+	g.names[name] = true
+	return name
+}
+
+var nonIdentChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// sequenceDefault PostgreSQL 的 nextval('seq') 与 Oracle 的 seq.NEXTVAL 默认值
+var sequenceDefault = regexp.MustCompile(`(?i)^nextval\(|\.nextval$`)
+
+// identityClause 各方言的自增列写法；PostgreSQL 的 serial 类型本身即自增
+func identityClause(dialectName, typ string) string {
+	switch dialectName {
+	case "mysql", "mariadb":
+		return " AUTO_INCREMENT"
+	case "sqlserver":
+		return " IDENTITY(1,1)"
+	case "postgresql":
+		if strings.HasSuffix(strings.ToLower(typ), "serial") {
+			return ""
+		}
+	}
+	return " GENERATED BY DEFAULT AS IDENTITY"
+}
+
+func isMySQL(d dialect.Dialect) bool {
+	return d.Name() == "mysql" || d.Name() == "mariadb"
+}
+
+var (
+	numericDefault = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+	mysqlDefaultFn = regexp.MustCompile(`(?i)^(current_timestamp|now|localtime|localtimestamp|curdate|current_date|current_time|uuid)(\(\d*\))?$`)
+)
+
+// defaultExpr 将元数据中的默认值转换为 DEFAULT 子句表达式
+// MySQL 的 COLUMN_DEFAULT 为未加引号的值，需要按字面量输出；其他方言存储的已是表达式
+func defaultExpr(d dialect.Dialect, value string) string {
+	if value == "" || strings.EqualFold(value, "NULL") {
+		return ""
+	}
+	if !isMySQL(d) {
+		return value
+	}
+	switch {
+	case numericDefault.MatchString(value),
+		mysqlDefaultFn.MatchString(value),
+		strings.HasPrefix(value, "'"),
+		strings.HasPrefix(value, "("),
+		strings.HasPrefix(strings.ToLower(value), "b'"),
+		strings.HasPrefix(strings.ToLower(value), "x'"):
+		return value
+	default:
+		return d.Literal(value)
+	}
+}
+
+// missingLength 元数据中缺少长度、在目标库中会使用默认长度或无法执行的类型
+func missingLength(dialectName, typ string) bool {
+	if strings.Contains(typ, "(") {
+		return false
+	}
+	switch dialectName {
+	case "oracle":
+		switch strings.ToUpper(typ) {
+		case "VARCHAR2", "NVARCHAR2", "RAW":
+			return true
+		}
+	case "sqlserver":
+		switch strings.ToLower(typ) {
+		case "varchar", "nvarchar", "char", "nchar", "varbinary", "binary":
+			return true
+		}
+	}
+	return false
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
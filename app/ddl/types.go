@@ -0,0 +1,818 @@
+package ddl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 与方言无关的字段类型分类
+const (
+	kindBoolean     = "boolean"
+	kindTinyInt     = "tinyint"
+	kindSmallInt    = "smallint"
+	kindMediumInt   = "mediumint"
+	kindInt         = "int"
+	kindBigInt      = "bigint"
+	kindDecimal     = "decimal"
+	kindReal        = "real"
+	kindDouble      = "double"
+	kindMoney       = "money"
+	kindChar        = "char"
+	kindNChar       = "nchar"
+	kindVarchar     = "varchar"
+	kindNVarchar    = "nvarchar"
+	kindText        = "text"
+	kindNText       = "ntext"
+	kindDate        = "date"
+	kindTime        = "time"
+	kindDatetime    = "datetime"
+	kindTimestampTZ = "timestamptz"
+	kindInterval    = "interval"
+	kindYear        = "year"
+	kindBinary      = "binary"
+	kindVarbinary   = "varbinary"
+	kindBlob        = "blob"
+	kindBit         = "bit"
+	kindJSON        = "json"
+	kindUUID        = "uuid"
+	kindXML         = "xml"
+	kindEnum        = "enum"
+	kindSet         = "set"
+	kindRowVersion  = "rowversion"
+	kindUnknown     = "unknown"
+)
+
+// maxLength 表示 varchar(max) 等不限长度的写法
+const maxLength = -1
+
+// sqlType 解析后的字段类型
+type sqlType struct {
+	kind     string
+	args     []int // 长度，或精度与小数位
+	unsigned bool
+	size     string // 文本与二进制大对象的档位：tiny / medium / long
+	values   string // enum / set 的取值列表原文
+	raw      string
+}
+
+func (t sqlType) length() int {
+	if len(t.args) == 0 {
+		return 0
+	}
+	return t.args[0]
+}
+
+// baseKinds 各方言通用的类型名称；依赖方言的名称在 parseType 中单独处理
+var baseKinds = map[string]string{
+	"bool": kindBoolean, "boolean": kindBoolean,
+	"tinyint": kindTinyInt, "smallint": kindSmallInt, "int2": kindSmallInt, "mediumint": kindMediumInt,
+	"int": kindInt, "integer": kindInt, "int4": kindInt, "bigint": kindBigInt, "int8": kindBigInt,
+	"decimal": kindDecimal, "numeric": kindDecimal, "dec": kindDecimal,
+	"real": kindReal, "float4": kindReal, "binary_float": kindReal,
+	"double": kindDouble, "double precision": kindDouble, "float8": kindDouble, "binary_double": kindDouble,
+	"money": kindMoney, "smallmoney": kindMoney,
+	"char": kindChar, "character": kindChar, "bpchar": kindChar, "nchar": kindNChar,
+	"varchar": kindVarchar, "character varying": kindVarchar, "varchar2": kindVarchar,
+	"nvarchar": kindNVarchar, "nvarchar2": kindNVarchar,
+	"tinytext": kindText, "text": kindText, "mediumtext": kindText, "longtext": kindText, "clob": kindText, "long": kindText,
+	"ntext": kindNText, "nclob": kindNText,
+	"date": kindDate, "time": kindTime, "time without time zone": kindTime, "time with time zone": kindTime, "timetz": kindTime,
+	"datetime": kindDatetime, "datetime2": kindDatetime, "smalldatetime": kindDatetime,
+	"timestamp": kindDatetime, "timestamp without time zone": kindDatetime,
+	"timestamptz": kindTimestampTZ, "timestamp with time zone": kindTimestampTZ,
+	"timestamp with local time zone": kindTimestampTZ, "datetimeoffset": kindTimestampTZ,
+	"interval": kindInterval, "year": kindYear,
+	"binary": kindBinary, "varbinary": kindVarbinary, "raw": kindVarbinary,
+	"bytea": kindBlob, "tinyblob": kindBlob, "blob": kindBlob, "mediumblob": kindBlob, "longblob": kindBlob,
+	"image": kindBlob, "long raw": kindBlob,
+	"bit": kindBit, "bit varying": kindBit, "varbit": kindBit,
+	"json": kindJSON, "jsonb": kindJSON,
+	"uuid": kindUUID, "uniqueidentifier": kindUUID,
+	"xml": kindXML, "xmltype": kindXML,
+	"enum": kindEnum, "set": kindSet,
+	"rowversion": kindRowVersion,
+}
+
+// parseType 解析源方言的字段类型，返回类型以及是否为 serial 等隐含自增的类型
+func parseType(dialectName, raw string) (sqlType, bool) {
+	t := sqlType{raw: raw, kind: kindUnknown}
+	lower := strings.ToLower(strings.TrimSpace(raw))
+	base, argText := lower, ""
+	if open := strings.Index(lower, "("); open >= 0 {
+		if end := strings.LastIndex(lower, ")"); end > open {
+			argText = strings.TrimSpace(raw[open+1 : end])
+			base = lower[:open] + " " + lower[end+1:]
+		}
+	}
+	// INTERVAL DAY(2) TO SECOND(6) 等形式含多组括号
+	if strings.HasPrefix(lower, "interval") {
+		t.kind = kindInterval
+		return t, false
+	}
+	var words []string
+	for _, w := range strings.Fields(base) {
+		switch w {
+		case "unsigned":
+			t.unsigned = true
+		case "zerofill", "signed":
+		default:
+			words = append(words, w)
+		}
+	}
+	base = strings.Join(words, " ")
+	if strings.HasSuffix(base, "[]") || base == "array" {
+		return t, false
+	}
+
+	if base == "enum" || base == "set" {
+		t.kind, t.values = base, argText
+		return t, false
+	}
+	for _, a := range strings.Split(argText, ",") {
+		a = strings.TrimSpace(strings.ToLower(a))
+		if a == "" {
+			continue
+		}
+		if a == "max" {
+			t.args = append(t.args, maxLength)
+			continue
+		}
+		// Oracle 的 VARCHAR2(20 CHAR)
+		if f := strings.Fields(a); len(f) > 0 {
+			if n, err := strconv.Atoi(f[0]); err == nil {
+				t.args = append(t.args, n)
+			}
+		}
+	}
+
+	serial := false
+	switch base {
+	case "smallserial", "serial2":
+		t.kind, serial = kindSmallInt, true
+	case "serial", "serial4":
+		t.kind, serial = kindInt, true
+	case "bigserial", "serial8":
+		t.kind, serial = kindBigInt, true
+	default:
+		if k, ok := baseKinds[base]; ok {
+			t.kind = k
+		}
+	}
+	if strings.HasPrefix(base, "tiny") || strings.HasPrefix(base, "medium") || strings.HasPrefix(base, "long") {
+		if t.kind == kindText || t.kind == kindBlob {
+			t.size = strings.TrimSuffix(strings.TrimSuffix(base, "text"), "blob")
+		}
+	}
+
+	switch dialectName {
+	case "mysql", "mariadb":
+		switch {
+		case base == "tinyint" && t.length() == 1 && !t.unsigned:
+			t.kind, t.args = kindBoolean, nil
+		case base == "bit" && t.length() <= 1:
+			t.kind, t.args = kindBoolean, nil
+		case base == "float":
+			t.kind = kindReal
+			if t.length() > 24 {
+				t.kind = kindDouble
+			}
+		case base == "real":
+			t.kind = kindDouble
+		case t.kind == kindTinyInt || t.kind == kindSmallInt || t.kind == kindMediumInt || t.kind == kindInt || t.kind == kindBigInt:
+			// 整数的显示宽度没有意义
+			t.args = nil
+		}
+	case "postgresql":
+		if base == "float" {
+			t.kind = kindDouble
+		}
+	case "sqlserver":
+		switch base {
+		case "tinyint":
+			// SQL Server 的 tinyint 为 0-255
+			t.unsigned = true
+		case "float":
+			t.kind = kindDouble
+			if n := t.length(); n > 0 && n <= 24 {
+				t.kind = kindReal
+			}
+		case "timestamp":
+			t.kind = kindRowVersion
+		case "text":
+			t.size = "long"
+		case "bit":
+			t.kind = kindBoolean
+		}
+	case "oracle":
+		switch base {
+		case "date":
+			t.kind = kindDatetime
+		case "float":
+			t.kind = kindDouble
+		case "number":
+			t.kind = kindDecimal
+			if len(t.args) == 1 || (len(t.args) == 2 && t.args[1] == 0) {
+				switch p := t.args[0]; {
+				case p <= 2:
+					t.kind = kindTinyInt
+				case p <= 4:
+					t.kind = kindSmallInt
+				case p <= 9:
+					t.kind = kindInt
+				case p <= 18:
+					t.kind = kindBigInt
+				}
+				if t.kind != kindDecimal {
+					t.args = nil
+				}
+			}
+		case "long", "clob":
+			t.size = "long"
+		}
+	}
+	return t, serial
+}
+
+// renderType 将类型写为目标方言的类型名，返回的说明非空时表示转换有损
+func renderType(from, to string, t sqlType) (string, string) {
+	switch to {
+	case "mysql", "mariadb":
+		return renderMySQL(from, t)
+	case "postgresql":
+		return renderPostgres(from, t)
+	case "sqlserver":
+		return renderSQLServer(from, t)
+	case "oracle":
+		return renderOracle(from, t)
+	}
+	return t.raw, ""
+}
+
+func withArgs(name string, args ...int) string {
+	parts := make([]string, 0, len(args))
+	for _, a := range args {
+		if a == maxLength {
+			parts = append(parts, "max")
+		} else {
+			parts = append(parts, strconv.Itoa(a))
+		}
+	}
+	if len(parts) == 0 {
+		return name
+	}
+	return name + "(" + strings.Join(parts, ",") + ")"
+}
+
+// fsp 时间类型的小数秒精度，超出目标上限时截断
+func fsp(name string, t sqlType, limit int) (string, string) {
+	n := t.length()
+	if n <= 0 {
+		return name, ""
+	}
+	if n > limit {
+		return withArgs(name, limit), fmt.Sprintf("fractional seconds precision reduced from %d to %d", n, limit)
+	}
+	return withArgs(name, n), ""
+}
+
+// decimalArgs 精度超出目标上限时截断
+func decimalArgs(name string, t sqlType, maxPrecision int) (string, string) {
+	if len(t.args) == 0 {
+		return name, ""
+	}
+	args := append([]int(nil), t.args...)
+	note := ""
+	if args[0] > maxPrecision {
+		note = fmt.Sprintf("precision reduced from %d to %d", args[0], maxPrecision)
+		args[0] = maxPrecision
+		if len(args) > 1 && args[1] > maxPrecision {
+			args[1] = maxPrecision
+		}
+	}
+	return withArgs(name, args...), note
+}
+
+// enumWidth enum / set 取值的最大长度（set 为全部取值拼接后的长度）
+func enumWidth(t sqlType) int {
+	width := 0
+	values := strings.Split(t.values, ",")
+	for _, v := range values {
+		v = strings.Trim(strings.TrimSpace(v), "'")
+		if t.kind == kindSet {
+			width += len(v) + 1
+		} else if len(v) > width {
+			width = len(v)
+		}
+	}
+	if width == 0 {
+		width = 255
+	}
+	return width
+}
+
+func enumNote(t sqlType) string {
+	return fmt.Sprintf("%s(%s) becomes a plain string column, allowed values are not enforced", t.kind, t.values)
+}
+
+func unknownNote(t sqlType) string {
+	return fmt.Sprintf("type %s has no mapping, copied verbatim", t.raw)
+}
+
+func renderMySQL(from string, t sqlType) (string, string) {
+	unsigned := func(name string) string {
+		if t.unsigned {
+			return name + " unsigned"
+		}
+		return name
+	}
+	switch t.kind {
+	case kindBoolean:
+		return "tinyint(1)", ""
+	case kindTinyInt, kindSmallInt, kindMediumInt, kindInt, kindBigInt:
+		return unsigned(t.kind), ""
+	case kindDecimal:
+		if len(t.args) == 0 {
+			return "decimal(65,30)", "unbounded numeric mapped to decimal(65,30)"
+		}
+		return decimalArgs("decimal", t, 65)
+	case kindReal:
+		return "float", ""
+	case kindDouble:
+		return "double", ""
+	case kindMoney:
+		return "decimal(19,4)", ""
+	case kindChar, kindNChar:
+		if n := t.length(); n > 255 || n == maxLength {
+			return mysqlVarchar(t)
+		}
+		if t.length() == 0 {
+			return "char(1)", lengthUnknown(1)
+		}
+		return withArgs("char", t.args...), ""
+	case kindVarchar, kindNVarchar:
+		return mysqlVarchar(t)
+	case kindText, kindNText:
+		if from == "mysql" || from == "mariadb" {
+			return t.size + "text", ""
+		}
+		return "longtext", ""
+	case kindDate:
+		return "date", ""
+	case kindTime:
+		return fsp("time", t, 6)
+	case kindDatetime:
+		return fsp("datetime", t, 6)
+	case kindTimestampTZ:
+		typ, _ := fsp("datetime", t, 6)
+		return typ, "time zone offset is not stored"
+	case kindInterval:
+		return "varchar(64)", "interval stored as text"
+	case kindYear:
+		return "year", ""
+	case kindBinary:
+		if n := t.length(); n > 255 || n == maxLength {
+			return "longblob", ""
+		}
+		if t.length() == 0 {
+			return "binary(1)", lengthUnknown(1)
+		}
+		return withArgs("binary", t.args...), ""
+	case kindVarbinary:
+		switch n := t.length(); {
+		case n == maxLength:
+			return "longblob", ""
+		case n == 0:
+			return "varbinary(255)", lengthUnknown(255)
+		}
+		return withArgs("varbinary", t.args...), ""
+	case kindBlob:
+		if from == "mysql" || from == "mariadb" {
+			return t.size + "blob", ""
+		}
+		return "longblob", ""
+	case kindBit:
+		return withArgs("bit", t.args...), ""
+	case kindJSON:
+		return "json", ""
+	case kindUUID:
+		return "char(36)", ""
+	case kindXML:
+		return "longtext", "XML stored as text"
+	case kindEnum, kindSet:
+		if from == "mysql" || from == "mariadb" {
+			return fmt.Sprintf("%s(%s)", t.kind, t.values), ""
+		}
+		return fmt.Sprintf("varchar(%d)", enumWidth(t)), enumNote(t)
+	case kindRowVersion:
+		return "binary(8)", "rowversion is not maintained automatically"
+	}
+	return t.raw, unknownNote(t)
+}
+
+// lengthUnknown 源类型缺少长度、使用默认长度时的提示
+func lengthUnknown(n int) string {
+	return fmt.Sprintf("length unknown, defaulted to %d", n)
+}
+
+func mysqlVarchar(t sqlType) (string, string) {
+	switch n := t.length(); {
+	case n == maxLength:
+		return "longtext", ""
+	case n == 0:
+		return "varchar(255)", lengthUnknown(255)
+	case n > 16383:
+		// utf8mb4 下 varchar 最多 16383 个字符
+		return "mediumtext", fmt.Sprintf("varchar(%d) exceeds the MySQL row limit, stored as mediumtext", n)
+	}
+	return withArgs("varchar", t.args...), ""
+}
+
+func renderPostgres(from string, t sqlType) (string, string) {
+	switch t.kind {
+	case kindBoolean:
+		return "boolean", ""
+	case kindTinyInt:
+		return "smallint", ""
+	case kindSmallInt:
+		if t.unsigned {
+			return "integer", ""
+		}
+		return "smallint", ""
+	case kindMediumInt:
+		return "integer", ""
+	case kindInt:
+		if t.unsigned {
+			return "bigint", ""
+		}
+		return "integer", ""
+	case kindBigInt:
+		if t.unsigned {
+			// 统一映射为 bigint：标识列只能是整数类型，外键列需与被引用的主键类型一致
+			return "bigint", "unsigned bigint stored as signed bigint, values above 9223372036854775807 do not fit"
+		}
+		return "bigint", ""
+	case kindDecimal:
+		return decimalArgs("numeric", t, 1000)
+	case kindReal:
+		return "real", ""
+	case kindDouble:
+		return "double precision", ""
+	case kindMoney:
+		return "numeric(19,4)", ""
+	case kindChar, kindNChar:
+		switch t.length() {
+		case maxLength:
+			return "text", ""
+		case 0:
+			return "char(1)", lengthUnknown(1)
+		}
+		return withArgs("char", t.args...), ""
+	case kindVarchar, kindNVarchar:
+		switch t.length() {
+		case maxLength:
+			return "text", ""
+		case 0:
+			// PostgreSQL 的 varchar 不写长度即不限长度
+			if from == "postgresql" {
+				return "varchar", ""
+			}
+			return "varchar", "length unknown, left unbounded"
+		}
+		return withArgs("varchar", t.args...), ""
+	case kindText, kindNText:
+		return "text", ""
+	case kindDate:
+		return "date", ""
+	case kindTime:
+		return fsp("time", t, 6)
+	case kindDatetime:
+		return fsp("timestamp", t, 6)
+	case kindTimestampTZ:
+		return fsp("timestamptz", t, 6)
+	case kindInterval:
+		return "interval", ""
+	case kindYear:
+		return "smallint", "YEAR stored as smallint"
+	case kindBinary, kindVarbinary, kindBlob:
+		return "bytea", ""
+	case kindBit:
+		return withArgs("bit", t.args...), ""
+	case kindJSON:
+		if from == "postgresql" {
+			return strings.ToLower(t.raw), ""
+		}
+		return "jsonb", ""
+	case kindUUID:
+		return "uuid", ""
+	case kindXML:
+		return "xml", ""
+	case kindEnum, kindSet:
+		return fmt.Sprintf("varchar(%d)", enumWidth(t)), enumNote(t)
+	case kindRowVersion:
+		return "bytea", "rowversion is not maintained automatically"
+	}
+	return t.raw, unknownNote(t)
+}
+
+func renderSQLServer(from string, t sqlType) (string, string) {
+	// 其他方言的字符类型默认可存储 Unicode，对应 SQL Server 的 n 系列类型
+	unicode := from != "sqlserver"
+	switch t.kind {
+	case kindBoolean:
+		return "bit", ""
+	case kindTinyInt:
+		if t.unsigned {
+			return "tinyint", ""
+		}
+		return "smallint", ""
+	case kindSmallInt:
+		if t.unsigned {
+			return "int", ""
+		}
+		return "smallint", ""
+	case kindMediumInt:
+		return "int", ""
+	case kindInt:
+		if t.unsigned {
+			return "bigint", ""
+		}
+		return "int", ""
+	case kindBigInt:
+		if t.unsigned {
+			return "decimal(20,0)", ""
+		}
+		return "bigint", ""
+	case kindDecimal:
+		if len(t.args) == 0 {
+			return "decimal(38,10)", "unbounded numeric mapped to decimal(38,10)"
+		}
+		return decimalArgs("decimal", t, 38)
+	case kindReal:
+		return "real", ""
+	case kindDouble:
+		return "float", ""
+	case kindMoney:
+		return "money", ""
+	case kindChar, kindNChar, kindVarchar, kindNVarchar:
+		name := "varchar"
+		limit := 8000
+		if t.kind == kindChar || t.kind == kindNChar {
+			name = "char"
+		}
+		if unicode || t.kind == kindNChar || t.kind == kindNVarchar {
+			name, limit = "n"+name, 4000
+		}
+		switch n := t.length(); {
+		case n == maxLength || n > limit:
+			// char 没有 max 写法，统一使用 varchar(max)
+			return strings.Replace(strings.Replace(name, "varchar", "char", 1), "char", "varchar", 1) + "(max)", ""
+		case n == 0 && (t.kind == kindVarchar || t.kind == kindNVarchar):
+			return name + "(255)", lengthUnknown(255)
+		case n == 0:
+			return name + "(1)", lengthUnknown(1)
+		}
+		return withArgs(name, t.args...), ""
+	case kindText:
+		if unicode {
+			return "nvarchar(max)", ""
+		}
+		return "varchar(max)", ""
+	case kindNText:
+		return "nvarchar(max)", ""
+	case kindDate:
+		return "date", ""
+	case kindTime:
+		return fsp("time", t, 7)
+	case kindDatetime:
+		return fsp("datetime2", t, 7)
+	case kindTimestampTZ:
+		return fsp("datetimeoffset", t, 7)
+	case kindInterval:
+		return "nvarchar(64)", "interval stored as text"
+	case kindYear:
+		return "smallint", "YEAR stored as smallint"
+	case kindBinary, kindVarbinary:
+		name := "binary"
+		if t.kind == kindVarbinary {
+			name = "varbinary"
+		}
+		switch n := t.length(); {
+		case n == maxLength || n > 8000:
+			return "varbinary(max)", ""
+		case n == 0 && t.kind == kindVarbinary:
+			return "varbinary(255)", lengthUnknown(255)
+		case n == 0:
+			return "binary(1)", lengthUnknown(1)
+		}
+		return withArgs(name, t.args...), ""
+	case kindBlob:
+		return "varbinary(max)", ""
+	case kindBit:
+		if n := t.length(); n > 1 {
+			return fmt.Sprintf("binary(%d)", (n+7)/8), fmt.Sprintf("bit(%d) stored as binary", n)
+		}
+		return "bit", ""
+	case kindJSON:
+		return "nvarchar(max)", "SQL Server has no JSON type, stored as nvarchar(max)"
+	case kindUUID:
+		return "uniqueidentifier", ""
+	case kindXML:
+		return "xml", ""
+	case kindEnum, kindSet:
+		return fmt.Sprintf("nvarchar(%d)", enumWidth(t)), enumNote(t)
+	case kindRowVersion:
+		return "rowversion", ""
+	}
+	return t.raw, unknownNote(t)
+}
+
+func renderOracle(from string, t sqlType) (string, string) {
+	integer := func(digits, unsignedDigits int) (string, string) {
+		if t.unsigned {
+			digits = unsignedDigits
+		}
+		return fmt.Sprintf("NUMBER(%d)", digits), ""
+	}
+	switch t.kind {
+	case kindBoolean:
+		return "NUMBER(1)", ""
+	case kindTinyInt:
+		return integer(3, 3)
+	case kindSmallInt:
+		return integer(5, 5)
+	case kindMediumInt:
+		return integer(7, 8)
+	case kindInt:
+		return integer(10, 10)
+	case kindBigInt:
+		return integer(19, 20)
+	case kindDecimal:
+		return decimalArgs("NUMBER", t, 38)
+	case kindReal:
+		return "BINARY_FLOAT", ""
+	case kindDouble:
+		return "BINARY_DOUBLE", ""
+	case kindMoney:
+		return "NUMBER(19,4)", ""
+	case kindChar:
+		if n := t.length(); n == maxLength || n > 2000 {
+			return "CLOB", fmt.Sprintf("char(%s) exceeds the CHAR limit, stored as CLOB", argText(t))
+		}
+		if n := t.length(); n > 0 {
+			return fmt.Sprintf("CHAR(%d CHAR)", n), ""
+		}
+		return "CHAR(1 CHAR)", lengthUnknown(1)
+	case kindNChar:
+		if n := t.length(); n == maxLength || n > 1000 {
+			return "NCLOB", fmt.Sprintf("nchar(%s) exceeds the NCHAR limit, stored as NCLOB", argText(t))
+		}
+		if t.length() == 0 {
+			return "NCHAR(1)", lengthUnknown(1)
+		}
+		return withArgs("NCHAR", t.args...), ""
+	case kindVarchar:
+		switch n := t.length(); {
+		case n == maxLength || n > 4000:
+			return "CLOB", fmt.Sprintf("varchar(%s) exceeds the VARCHAR2 limit, stored as CLOB", argText(t))
+		case n == 0:
+			return "VARCHAR2(4000 CHAR)", lengthUnknown(4000)
+		default:
+			return fmt.Sprintf("VARCHAR2(%d CHAR)", n), ""
+		}
+	case kindNVarchar:
+		switch n := t.length(); {
+		case n == maxLength || n > 2000:
+			return "NCLOB", fmt.Sprintf("nvarchar(%s) exceeds the NVARCHAR2 limit, stored as NCLOB", argText(t))
+		case n == 0:
+			return "NVARCHAR2(2000)", lengthUnknown(2000)
+		default:
+			return withArgs("NVARCHAR2", n), ""
+		}
+	case kindText:
+		return "CLOB", ""
+	case kindNText:
+		return "NCLOB", ""
+	case kindDate:
+		return "DATE", ""
+	case kindTime:
+		typ, _ := fsp("TIMESTAMP", t, 9)
+		return typ, "Oracle has no TIME type, stored as TIMESTAMP"
+	case kindDatetime:
+		if from == "oracle" && strings.EqualFold(strings.TrimSpace(t.raw), "date") {
+			return "DATE", ""
+		}
+		return fsp("TIMESTAMP", t, 9)
+	case kindTimestampTZ:
+		typ, note := fsp("TIMESTAMP", t, 9)
+		return typ + " WITH TIME ZONE", note
+	case kindInterval:
+		return "INTERVAL DAY TO SECOND", ""
+	case kindYear:
+		return "NUMBER(4)", ""
+	case kindBinary, kindVarbinary:
+		switch n := t.length(); {
+		case n == maxLength || n > 2000:
+			return "BLOB", ""
+		case n == 0:
+			return "RAW(2000)", lengthUnknown(2000)
+		default:
+			return withArgs("RAW", n), ""
+		}
+	case kindBlob:
+		return "BLOB", ""
+	case kindBit:
+		if n := t.length(); n > 1 {
+			return fmt.Sprintf("RAW(%d)", (n+7)/8), fmt.Sprintf("bit(%d) stored as RAW", n)
+		}
+		return "NUMBER(1)", ""
+	case kindJSON:
+		return "CLOB", "JSON stored as CLOB, add an IS JSON check constraint if needed"
+	case kindUUID:
+		return "VARCHAR2(36)", ""
+	case kindXML:
+		return "XMLTYPE", ""
+	case kindEnum, kindSet:
+		return fmt.Sprintf("VARCHAR2(%d CHAR)", enumWidth(t)), enumNote(t)
+	case kindRowVersion:
+		return "RAW(8)", "rowversion is not maintained automatically"
+	}
+	return t.raw, unknownNote(t)
+}
+
+func argText(t sqlType) string {
+	if t.length() == maxLength {
+		return "max"
+	}
+	return strconv.Itoa(t.length())
+}
+
+// SameType 两个方言中的类型是否等价：将 a 写为 b 的方言后与 b 的规范写法比较；
+// serial 与标识列在元数据中的写法不同，此时只比较类型类别
+func SameType(dialectA, typeA, dialectB, typeB string) bool {
+	a, serialA := parseType(dialectA, typeA)
+	b, serialB := parseType(dialectB, typeB)
+	if serialA != serialB {
+		return a.kind == b.kind
+	}
+	ra, _ := renderType(dialectA, dialectB, a)
+	rb, _ := renderType(dialectB, dialectB, b)
+	return strings.EqualFold(strings.Join(strings.Fields(ra), ""), strings.Join(strings.Fields(rb), ""))
+}
+
+// TypeInfo 与方言无关的字段类型分类，供代码生成等按类别映射类型；
+// Kind 取 boolean、int、bigint、decimal、varchar、datetime、timestamptz、json 等，无法识别时为 unknown
+type TypeInfo struct {
+	Kind     string
+	Args     []int // 长度，或精度与小数位；-1 表示 max
+	Unsigned bool
+	Array    bool     // PostgreSQL 数组，Kind 为元素类型
+	Serial   bool     // serial 等隐含自增的类型
+	Values   []string // enum / set 的取值
+}
+
+// ClassifyType 按方言解析字段类型
+func ClassifyType(dialectName, raw string) TypeInfo {
+	trimmed := strings.TrimSpace(raw)
+	array := false
+	for strings.HasSuffix(trimmed, "[]") {
+		trimmed, array = strings.TrimSpace(strings.TrimSuffix(trimmed, "[]")), true
+	}
+	if strings.EqualFold(trimmed, "array") {
+		return TypeInfo{Kind: kindUnknown, Array: true}
+	}
+	if dialectName == "postgresql" && strings.HasPrefix(trimmed, "_") {
+		// information_schema 中数组的 udt_name 为 _int4 等
+		trimmed, array = trimmed[1:], true
+	}
+	t, serial := parseType(dialectName, trimmed)
+	return TypeInfo{Kind: t.kind, Args: t.args, Unsigned: t.unsigned, Array: array, Serial: serial, Values: enumValues(t.values)}
+}
+
+// enumValues 拆分 'a','b' 形式的取值列表，两个连续单引号表示引号本身
+func enumValues(text string) []string {
+	var values []string
+	var cur strings.Builder
+	quoted, started := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quoted && c == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			cur.WriteByte(c)
+			i++
+		case c == '\'':
+			quoted, started = !quoted, true
+		case !quoted && c == ',':
+			values = append(values, cur.String())
+			cur.Reset()
+			started = false
+		case quoted || (c != ' ' && c != '\t'):
+			cur.WriteByte(c)
+			started = true
+		}
+	}
+	if started {
+		values = append(values, cur.String())
+	}
+	return values
+}
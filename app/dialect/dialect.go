@@ -0,0 +1,84 @@
+package dialect
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect 定义各数据库方言在SQL生成上的差异
+type Dialect interface {
+	// Name 方言名称，与 connect.Config.Type 一致
+	Name() string
+	// QuoteIdent 为标识符加引号
+	QuoteIdent(name string) string
+	// QualifiedName 生成带库/Schema限定的对象名
+	QualifiedName(database, schema, name string) string
+	// Placeholder 返回第 n 个（从1开始）绑定参数占位符
+	Placeholder(n int) string
+	// SelectPage 生成分页查询，where/orderBy 为空时省略对应子句
+	SelectPage(columns []string, from, where, orderBy string, limit, offset int) string
+	// Literal 将Go值格式化为该方言的SQL字面量（用于生成 INSERT 脚本）
+	Literal(v interface{}) string
+}
+
+// Get 根据数据库类型获取方言
+func Get(dbType string) (Dialect, error) {
+	switch dbType {
+	case "mysql":
+		return mysqlDialect{name: "mysql"}, nil
+	case "mariadb":
+		return mysqlDialect{name: "mariadb"}, nil
+	case "postgresql":
+		return postgresDialect{}, nil
+	case "sqlserver":
+		return sqlServerDialect{}, nil
+	case "oracle":
+		return oracleDialect{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported database type: %s", dbType)
+	}
+}
+
+// quoteWith 使用成对的引号包裹标识符，并对内部出现的结束引号进行转义
+func quoteWith(name, open, close string) string {
+	return open + strings.ReplaceAll(name, close, close+close) + close
+}
+
+// joinQualified 拼接非空的限定部分
+func joinQualified(d Dialect, parts ...string) string {
+	quoted := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p != "" {
+			quoted = append(quoted, d.QuoteIdent(p))
+		}
+	}
+	return strings.Join(quoted, ".")
+}
+
+// SelectAll 生成不分页的查询，各方言语法一致
+func SelectAll(columns []string, from, where, orderBy string) string {
+	return selectClause("", columns, from, where, orderBy)
+}
+
+// selectClause 拼接 SELECT ... FROM ... WHERE ... ORDER BY ... 的公共部分
+func selectClause(prefix string, columns []string, from, where, orderBy string) string {
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	b.WriteString(prefix)
+	if len(columns) == 0 {
+		b.WriteString("*")
+	} else {
+		b.WriteString(strings.Join(columns, ", "))
+	}
+	b.WriteString(" FROM ")
+	b.WriteString(from)
+	if where != "" {
+		b.WriteString(" WHERE ")
+		b.WriteString(where)
+	}
+	if orderBy != "" {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(orderBy)
+	}
+	return b.String()
+}
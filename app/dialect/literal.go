@@ -0,0 +1,97 @@
+package dialect
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// literalStyle 各方言字面量写法的差异
+type literalStyle struct {
+	backslashEscape bool   // 字符串中的反斜杠需要转义（MySQL）
+	stringPrefix    string // 字符串前缀，如 SQL Server 的 N
+	trueLit         string
+	falseLit        string
+	timeLayout      string
+	timePrefix      string // 时间字面量前缀，如 Oracle 的 TIMESTAMP
+	binary          func(b []byte) string
+}
+
+// formatLiteral 按方言风格格式化字面量；非UTF-8的 []byte 按二进制输出
+func formatLiteral(style literalStyle, v interface{}) string {
+	quote := func(s string) string {
+		if style.backslashEscape {
+			s = strings.ReplaceAll(s, `\`, `\\`)
+		}
+		return style.stringPrefix + "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	}
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if val {
+			return style.trueLit
+		}
+		return style.falseLit
+	case int:
+		return strconv.Itoa(val)
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", val)
+	case float32:
+		return formatFloat(float64(val), quote)
+	case float64:
+		return formatFloat(val, quote)
+	case []byte:
+		if utf8.Valid(val) {
+			return quote(string(val))
+		}
+		return style.binary(val)
+	case time.Time:
+		return style.timePrefix + "'" + val.Format(style.timeLayout) + "'"
+	case string:
+		return quote(val)
+	default:
+		return quote(fmt.Sprint(val))
+	}
+}
+
+func formatFloat(f float64, quote func(string) string) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return quote(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+var (
+	mysqlLiteral = literalStyle{
+		backslashEscape: true,
+		trueLit:         "1",
+		falseLit:        "0",
+		timeLayout:      "2006-01-02 15:04:05.999999",
+		binary:          func(b []byte) string { return "X'" + hex.EncodeToString(b) + "'" },
+	}
+	postgresLiteral = literalStyle{
+		trueLit:    "TRUE",
+		falseLit:   "FALSE",
+		timeLayout: "2006-01-02 15:04:05.999999-07:00",
+		binary:     func(b []byte) string { return `'\x` + hex.EncodeToString(b) + "'::bytea" },
+	}
+	sqlServerLiteral = literalStyle{
+		stringPrefix: "N",
+		trueLit:      "1",
+		falseLit:     "0",
+		timeLayout:   "2006-01-02T15:04:05.9999999",
+		binary:       func(b []byte) string { return "0x" + strings.ToUpper(hex.EncodeToString(b)) },
+	}
+	oracleLiteral = literalStyle{
+		trueLit:    "1",
+		falseLit:   "0",
+		timeLayout: "2006-01-02 15:04:05.999999999",
+		timePrefix: "TIMESTAMP ",
+		binary:     func(b []byte) string { return "HEXTORAW('" + strings.ToUpper(hex.EncodeToString(b)) + "')" },
+	}
+)
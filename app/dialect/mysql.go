@@ -0,0 +1,28 @@
+package dialect
+
+import "fmt"
+
+// mysqlDialect MySQL 与 MariaDB 方言
+type mysqlDialect struct {
+	name string
+}
+
+func (d mysqlDialect) Name() string { return d.name }
+
+func (d mysqlDialect) QuoteIdent(name string) string { return quoteWith(name, "`", "`") }
+
+// QualifiedName MySQL 中数据库即Schema，形如 `db`.`table`
+func (d mysqlDialect) QualifiedName(database, schema, name string) string {
+	if schema != "" {
+		database = schema
+	}
+	return joinQualified(d, database, name)
+}
+
+func (d mysqlDialect) Placeholder(n int) string { return "?" }
+
+func (d mysqlDialect) SelectPage(columns []string, from, where, orderBy string, limit, offset int) string {
+	return selectClause("", columns, from, where, orderBy) + fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+}
+
+func (d mysqlDialect) Literal(v interface{}) string { return formatLiteral(mysqlLiteral, v) }
@@ -0,0 +1,23 @@
+package dialect
+
+import "fmt"
+
+// oracleDialect Oracle 方言（分页语法要求 12c 及以上）
+type oracleDialect struct{}
+
+func (oracleDialect) Name() string { return "oracle" }
+
+func (oracleDialect) QuoteIdent(name string) string { return quoteWith(name, `"`, `"`) }
+
+// QualifiedName 形如 "SCHEMA"."TABLE"，数据库为服务名，不参与限定
+func (d oracleDialect) QualifiedName(database, schema, name string) string {
+	return joinQualified(d, schema, name)
+}
+
+func (oracleDialect) Placeholder(n int) string { return fmt.Sprintf(":%d", n) }
+
+func (oracleDialect) SelectPage(columns []string, from, where, orderBy string, limit, offset int) string {
+	return selectClause("", columns, from, where, orderBy) + fmt.Sprintf(" OFFSET %d ROWS FETCH FIRST %d ROWS ONLY", offset, limit)
+}
+
+func (oracleDialect) Literal(v interface{}) string { return formatLiteral(oracleLiteral, v) }
@@ -0,0 +1,23 @@
+package dialect
+
+import "fmt"
+
+// postgresDialect PostgreSQL 方言
+type postgresDialect struct{}
+
+func (postgresDialect) Name() string { return "postgresql" }
+
+func (postgresDialect) QuoteIdent(name string) string { return quoteWith(name, `"`, `"`) }
+
+// QualifiedName PostgreSQL 不支持跨库查询，仅使用 "schema"."table"
+func (d postgresDialect) QualifiedName(database, schema, name string) string {
+	return joinQualified(d, schema, name)
+}
+
+func (postgresDialect) Placeholder(n int) string { return fmt.Sprintf("$%d", n) }
+
+func (postgresDialect) SelectPage(columns []string, from, where, orderBy string, limit, offset int) string {
+	return selectClause("", columns, from, where, orderBy) + fmt.Sprintf(" LIMIT %d OFFSET %d", limit, offset)
+}
+
+func (postgresDialect) Literal(v interface{}) string { return formatLiteral(postgresLiteral, v) }
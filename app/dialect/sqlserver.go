@@ -0,0 +1,30 @@
+package dialect
+
+import "fmt"
+
+// sqlServerDialect SQL Server 方言
+type sqlServerDialect struct{}
+
+func (sqlServerDialect) Name() string { return "sqlserver" }
+
+func (sqlServerDialect) QuoteIdent(name string) string { return quoteWith(name, "[", "]") }
+
+// QualifiedName 形如 [db].[schema].[table]
+func (d sqlServerDialect) QualifiedName(database, schema, name string) string {
+	return joinQualified(d, database, schema, name)
+}
+
+func (sqlServerDialect) Placeholder(n int) string { return fmt.Sprintf("@p%d", n) }
+
+// SelectPage 首页使用 TOP，其余页使用 OFFSET ... FETCH NEXT（要求存在 ORDER BY）
+func (sqlServerDialect) SelectPage(columns []string, from, where, orderBy string, limit, offset int) string {
+	if offset == 0 {
+		return selectClause(fmt.Sprintf("TOP %d ", limit), columns, from, where, orderBy)
+	}
+	if orderBy == "" {
+		orderBy = "(SELECT NULL)"
+	}
+	return selectClause("", columns, from, where, orderBy) + fmt.Sprintf(" OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit)
+}
+
+func (sqlServerDialect) Literal(v interface{}) string { return formatLiteral(sqlServerLiteral, v) }
@@ -0,0 +1,460 @@
+package docs
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// displayName 节点显示名称：有别名时为 "别名 (名称)"
+func (n DiagramNode) displayName() string {
+	if n.Alias != "" && n.Alias != n.Title {
+		return n.Alias + " (" + n.Title + ")"
+	}
+	return n.Title
+}
+
+// foreignKeyColumns 作为关系源端参与关联的字段
+func (d Diagram) foreignKeyColumns() map[string]bool {
+	out := map[string]bool{}
+	for _, e := range d.Edges {
+		for _, c := range e.FromColumns {
+			out[e.From+"\x00"+c] = true
+		}
+	}
+	return out
+}
+
+var nonWordChars = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+// textIDs 为节点生成在文本格式中可用的唯一标识
+func (d Diagram) textIDs() map[string]string {
+	ids := map[string]string{}
+	used := map[string]bool{}
+	for _, n := range d.Nodes {
+		base := strings.Trim(nonWordChars.ReplaceAllString(n.Title, "_"), "_")
+		if base == "" || (base[0] >= '0' && base[0] <= '9') {
+			base = "t_" + base
+		}
+		id := base
+		for i := 2; used[strings.ToLower(id)]; i++ {
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[strings.ToLower(id)] = true
+		ids[n.Key] = id
+	}
+	return ids
+}
+
+// columnNote 字段说明：别名优先，其次为注释
+func columnNote(c DiagramColumn) string {
+	if c.Alias != "" {
+		return c.Alias
+	}
+	return c.Comment
+}
+
+// crowFoot Mermaid 与 PlantUML 共用的鸦脚记号，left 表示关系左侧的端点
+func crowFoot(cardinality string, left bool) string {
+	switch cardinality {
+	case "one":
+		return "||"
+	case "many":
+		if left {
+			return "}o"
+		}
+		return "o{"
+	default:
+		if left {
+			return "|o"
+		}
+		return "o|"
+	}
+}
+
+// edgeLabel 关系说明，未设置时使用关联字段
+func edgeLabel(e DiagramEdge) string {
+	if e.Label != "" {
+		return e.Label
+	}
+	pairs := make([]string, 0, len(e.FromColumns))
+	for i := range e.FromColumns {
+		if i < len(e.ToColumns) {
+			pairs = append(pairs, e.FromColumns[i]+" = "+e.ToColumns[i])
+		}
+	}
+	return strings.Join(pairs, ", ")
+}
+
+var mermaidTypeChars = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]+`)
+
+// WriteMermaid 将 ER 图写为 Mermaid erDiagram
+func WriteMermaid(w io.Writer, d Diagram) error {
+	bw := bufio.NewWriter(w)
+	ids, fks := d.textIDs(), d.foreignKeyColumns()
+	if d.Title != "" {
+		fmt.Fprintf(bw, "---\ntitle: %s\n---\n", strings.ReplaceAll(d.Title, "\n", " "))
+	}
+	fmt.Fprintln(bw, "erDiagram")
+	for _, n := range d.Nodes {
+		name := ids[n.Key]
+		if label := n.displayName(); label != name {
+			name += `["` + strings.ReplaceAll(label, `"`, "'") + `"]`
+		}
+		if len(n.Columns) == 0 {
+			fmt.Fprintf(bw, "    %s {\n    }\n", name)
+			continue
+		}
+		fmt.Fprintf(bw, "    %s {\n", name)
+		for _, c := range n.Columns {
+			typ := strings.Trim(mermaidTypeChars.ReplaceAllString(c.Type, "_"), "_")
+			if typ == "" {
+				typ = "unknown"
+			}
+			col := nonWordChars.ReplaceAllString(c.Name, "_")
+			line := fmt.Sprintf("        %s %s", typ, col)
+			var keys []string
+			switch strings.ToUpper(c.Key) {
+			case "PRI":
+				keys = append(keys, "PK")
+			case "UNI":
+				keys = append(keys, "UK")
+			}
+			if strings.EqualFold(c.Key, "FOR") || fks[n.Key+"\x00"+c.Name] {
+				keys = append(keys, "FK")
+			}
+			if len(keys) > 0 {
+				line += " " + strings.Join(keys, ",")
+			}
+			note := columnNote(c)
+			if col != c.Name {
+				note = strings.TrimSpace(c.Name + " " + note)
+			}
+			if note != "" {
+				line += ` "` + strings.ReplaceAll(note, `"`, "'") + `"`
+			}
+			fmt.Fprintln(bw, line)
+		}
+		fmt.Fprintln(bw, "    }")
+	}
+	for _, e := range d.Edges {
+		from, ok1 := ids[e.From]
+		to, ok2 := ids[e.To]
+		if !ok1 || !ok2 {
+			continue
+		}
+		label := edgeLabel(e)
+		if label == "" {
+			label = "references"
+		}
+		fmt.Fprintf(bw, "    %s %s--%s %s : \"%s\"\n", from, crowFoot(e.FromCardinality, true), crowFoot(e.ToCardinality, false), to, strings.ReplaceAll(label, `"`, "'"))
+	}
+	return bw.Flush()
+}
+
+// WritePlantUML 将 ER 图写为 PlantUML 实体图，主键字段位于分隔线之上，非空字段以 * 标记
+func WritePlantUML(w io.Writer, d Diagram) error {
+	bw := bufio.NewWriter(w)
+	ids, fks := d.textIDs(), d.foreignKeyColumns()
+	fmt.Fprintln(bw, "@startuml")
+	if d.Title != "" {
+		fmt.Fprintf(bw, "title %s\n", d.Title)
+	}
+	fmt.Fprintln(bw, "hide circle")
+	fmt.Fprintln(bw, "skinparam linetype ortho")
+	fmt.Fprintln(bw)
+	for _, n := range d.Nodes {
+		stereotype := ""
+		if n.View {
+			stereotype = " <<view>>"
+		}
+		color := ""
+		if n.Color != "" {
+			color = " " + n.Color
+		}
+		fmt.Fprintf(bw, "entity \"%s\" as %s%s%s {\n", strings.ReplaceAll(n.displayName(), `"`, "'"), ids[n.Key], stereotype, color)
+		var keys, others []DiagramColumn
+		for _, c := range n.Columns {
+			if strings.EqualFold(c.Key, "PRI") {
+				keys = append(keys, c)
+			} else {
+				others = append(others, c)
+			}
+		}
+		line := func(c DiagramColumn) {
+			mark := "    "
+			if !c.Nullable {
+				mark = "  * "
+			}
+			text := c.Name
+			if c.Type != "" {
+				text += " : " + c.Type
+			}
+			switch {
+			case strings.EqualFold(c.Key, "PRI"):
+				text += " <<PK>>"
+			case strings.EqualFold(c.Key, "UNI"):
+				text += " <<UK>>"
+			}
+			if strings.EqualFold(c.Key, "FOR") || fks[n.Key+"\x00"+c.Name] {
+				text += " <<FK>>"
+			}
+			if note := columnNote(c); note != "" {
+				text += " // " + note
+			}
+			fmt.Fprintln(bw, mark+text)
+		}
+		for _, c := range keys {
+			line(c)
+		}
+		if len(keys) > 0 {
+			fmt.Fprintln(bw, "  --")
+		}
+		for _, c := range others {
+			line(c)
+		}
+		fmt.Fprintln(bw, "}")
+		fmt.Fprintln(bw)
+	}
+	for _, e := range d.Edges {
+		from, ok1 := ids[e.From]
+		to, ok2 := ids[e.To]
+		if !ok1 || !ok2 {
+			continue
+		}
+		rel := fmt.Sprintf("%s %s--%s %s", from, crowFoot(e.FromCardinality, true), crowFoot(e.ToCardinality, false), to)
+		if label := edgeLabel(e); label != "" {
+			rel += " : " + label
+		}
+		fmt.Fprintln(bw, rel)
+	}
+	fmt.Fprintln(bw, "@enduml")
+	return bw.Flush()
+}
+
+// dotArrow Graphviz 箭头形状：多为鸦脚，一为竖线
+func dotArrow(cardinality string) string {
+	switch cardinality {
+	case "one":
+		return "tee"
+	case "many":
+		return "crow"
+	default:
+		return "none"
+	}
+}
+
+// dotQuote Graphviz 的双引号字符串
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// WriteDOT 将 ER 图写为 Graphviz DOT，节点使用 HTML 表格标签，关系连接到字段行
+func WriteDOT(w io.Writer, d Diagram) error {
+	bw := bufio.NewWriter(w)
+	ids := d.textIDs()
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(defaultTitle(d.Title)))
+	fmt.Fprintln(bw, `    graph [rankdir=LR, splines=true, nodesep=0.6, ranksep=1.2];`)
+	fmt.Fprintln(bw, `    node [shape=plaintext, fontname="Helvetica", fontsize=11];`)
+	fmt.Fprintln(bw, `    edge [fontname="Helvetica", fontsize=10, color="#6b7280", dir=both];`)
+	ports := map[string]string{}
+	for _, n := range d.Nodes {
+		header := n.Color
+		if header == "" {
+			header = "#3b82f6"
+			if n.View {
+				header = "#8b5cf6"
+			}
+		}
+		var b strings.Builder
+		b.WriteString(`<table border="0" cellborder="1" cellspacing="0" cellpadding="4">`)
+		fmt.Fprintf(&b, `<tr><td colspan="2" bgcolor="%s"><font color="#ffffff"><b>%s</b></font></td></tr>`, html.EscapeString(header), html.EscapeString(n.displayName()))
+		for i, c := range n.Columns {
+			port := fmt.Sprintf("c%d", i)
+			ports[n.Key+"\x00"+c.Name] = port
+			name := html.EscapeString(c.Name)
+			if strings.EqualFold(c.Key, "PRI") {
+				name = "<b>" + name + "</b>"
+			}
+			if c.Alias != "" {
+				name += ` <font color="#6b7280">` + html.EscapeString(c.Alias) + `</font>`
+			}
+			fmt.Fprintf(&b, `<tr><td port="%s" align="left">%s</td><td align="left"><font color="#6b7280">%s</font></td></tr>`, port, name, html.EscapeString(c.Type))
+		}
+		b.WriteString(`</table>`)
+		fmt.Fprintf(bw, "    %s [label=<%s>];\n", ids[n.Key], b.String())
+	}
+	for _, e := range d.Edges {
+		from, ok1 := ids[e.From]
+		to, ok2 := ids[e.To]
+		if !ok1 || !ok2 {
+			continue
+		}
+		if p, ok := ports[e.From+"\x00"+e.FromColumn]; ok {
+			from += ":" + p
+		}
+		if p, ok := ports[e.To+"\x00"+e.ToColumn]; ok {
+			to += ":" + p
+		}
+		attrs := fmt.Sprintf("arrowtail=%s, arrowhead=%s", dotArrow(e.FromCardinality), dotArrow(e.ToCardinality))
+		if e.Label != "" {
+			attrs += ", label=" + dotQuote(e.Label)
+		}
+		fmt.Fprintf(bw, "    %s -> %s [%s];\n", from, to, attrs)
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+func defaultTitle(title string) string {
+	if title == "" {
+		return "ER"
+	}
+	return title
+}
+
+var dbmlPlainName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func dbmlName(s string) string {
+	if dbmlPlainName.MatchString(s) {
+		return s
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+func dbmlString(s string) string {
+	return "'" + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), "'", `\'`) + "'"
+}
+
+var dbmlPlainType = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\([0-9, ]*\))?(\[\])?$`)
+
+// WriteDBML 将 ER 图写为 DBML；DBML 不支持视图，视图以注释列出。
+// 被关系引用但未显示的字段仍会写出，保证引用有效
+func WriteDBML(w io.Writer, d Diagram) error {
+	bw := bufio.NewWriter(w)
+	if d.Title != "" {
+		fmt.Fprintf(bw, "Project %s {\n  note: %s\n}\n\n", dbmlName(d.Title), dbmlString(d.Title))
+	}
+	nodes := map[string]DiagramNode{}
+	referenced := map[string]bool{}
+	for _, n := range d.Nodes {
+		nodes[n.Key] = n
+	}
+	for _, e := range d.Edges {
+		for _, c := range e.FromColumns {
+			referenced[e.From+"\x00"+c] = true
+		}
+		for _, c := range e.ToColumns {
+			referenced[e.To+"\x00"+c] = true
+		}
+	}
+	tableName := func(n DiagramNode) string {
+		if n.Schema != "" {
+			return dbmlName(n.Schema) + "." + dbmlName(n.Title)
+		}
+		return dbmlName(n.Title)
+	}
+
+	var views []string
+	for _, n := range d.Nodes {
+		if n.View || n.Missing {
+			if n.View {
+				views = append(views, n.displayName())
+			}
+			continue
+		}
+		var settings []string
+		if n.Color != "" {
+			settings = append(settings, "headercolor: "+n.Color)
+		}
+		line := "Table " + tableName(n)
+		if n.Alias != "" && n.Alias != n.Title {
+			line += " as " + dbmlName(n.Alias)
+		}
+		if len(settings) > 0 {
+			line += " [" + strings.Join(settings, ", ") + "]"
+		}
+		fmt.Fprintln(bw, line+" {")
+		columns := append([]DiagramColumn(nil), n.Columns...)
+		for _, c := range n.Hidden {
+			if referenced[n.Key+"\x00"+c.Name] {
+				columns = append(columns, c)
+			}
+		}
+		for _, c := range columns {
+			typ := c.Type
+			if typ == "" {
+				typ = "unknown"
+			}
+			if !dbmlPlainType.MatchString(typ) {
+				typ = `"` + strings.ReplaceAll(typ, `"`, `\"`) + `"`
+			}
+			var attrs []string
+			switch strings.ToUpper(c.Key) {
+			case "PRI":
+				attrs = append(attrs, "pk")
+			case "UNI":
+				attrs = append(attrs, "unique")
+			}
+			if !c.Nullable {
+				attrs = append(attrs, "not null")
+			}
+			if note := columnNote(c); note != "" {
+				attrs = append(attrs, "note: "+dbmlString(note))
+			}
+			col := "  " + dbmlName(c.Name) + " " + typ
+			if len(attrs) > 0 {
+				col += " [" + strings.Join(attrs, ", ") + "]"
+			}
+			fmt.Fprintln(bw, col)
+		}
+		note := n.Comment
+		if n.Alias != "" && note == "" {
+			note = n.Alias
+		}
+		if note != "" {
+			fmt.Fprintf(bw, "\n  Note: %s\n", dbmlString(note))
+		}
+		fmt.Fprintln(bw, "}")
+		fmt.Fprintln(bw)
+	}
+
+	for _, e := range d.Edges {
+		from, ok1 := nodes[e.From]
+		to, ok2 := nodes[e.To]
+		if !ok1 || !ok2 || from.View || to.View || len(e.FromColumns) == 0 || len(e.FromColumns) != len(e.ToColumns) {
+			continue
+		}
+		op := "-"
+		switch {
+		case e.FromCardinality == "many" && e.ToCardinality == "many":
+			op = "<>"
+		case e.FromCardinality == "many":
+			op = ">"
+		case e.ToCardinality == "many":
+			op = "<"
+		}
+		cols := func(names []string) string {
+			if len(names) == 1 {
+				return dbmlName(names[0])
+			}
+			quoted := make([]string, len(names))
+			for i, c := range names {
+				quoted[i] = dbmlName(c)
+			}
+			return "(" + strings.Join(quoted, ", ") + ")"
+		}
+		ref := "Ref"
+		if e.Label != "" && dbmlPlainName.MatchString(e.Label) {
+			ref += " " + e.Label
+		}
+		fmt.Fprintf(bw, "%s: %s.%s %s %s.%s\n", ref, tableName(from), cols(e.FromColumns), op, tableName(to), cols(e.ToColumns))
+	}
+	if len(views) > 0 {
+		fmt.Fprintf(bw, "\n// views are not supported by DBML: %s\n", strings.Join(views, ", "))
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,127 @@
+package docs
+
+import (
+	"dbrun/app/models"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Table 文档中的表及其所在位置
+type Table struct {
+	Database string
+	Schema   string
+	File     string // 单独成文件时的文件名（不含扩展名），由 AssignFiles 分配
+	Info     models.TableInfoVO
+}
+
+// View 文档中的视图及其所在位置
+type View struct {
+	Database string
+	Schema   string
+	File     string // 单独成文件时的文件名（不含扩展名），由 AssignFiles 分配
+	Info     models.ViewInfoVO
+}
+
+// Dictionary 数据字典内容，表与视图按库、Schema、名称排序
+type Dictionary struct {
+	Title  string
+	Tables []Table
+	Views  []View
+}
+
+// QualifiedName 库名.Schema.表名，Schema 为空时省略
+func (t Table) QualifiedName() string { return qualified(t.Database, t.Schema, t.Info.Name) }
+
+// FileBase 表单独成文件时的文件名（不含扩展名）
+func (t Table) FileBase() string {
+	if t.File != "" {
+		return t.File
+	}
+	return FileName(t.QualifiedName())
+}
+
+// Anchor 表在文档内的锚点
+func (t Table) Anchor() string { return fmt.Sprintf("table-%d", t.Info.ID) }
+
+// QualifiedName 库名.Schema.视图名，Schema 为空时省略
+func (v View) QualifiedName() string { return qualified(v.Database, v.Schema, v.Info.Name) }
+
+// FileBase 视图单独成文件时的文件名（不含扩展名）
+func (v View) FileBase() string {
+	if v.File != "" {
+		return v.File
+	}
+	return FileName(v.QualifiedName())
+}
+
+// Anchor 视图在文档内的锚点
+func (v View) Anchor() string { return fmt.Sprintf("view-%d", v.Info.ID) }
+
+func qualified(parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, ".")
+}
+
+var unsafeFileChars = regexp.MustCompile(`[\\/:*?"<>|\s]+`)
+
+// FileName 将限定名转换为安全的文件名（不含扩展名）
+func FileName(qualifiedName string) string {
+	name := unsafeFileChars.ReplaceAllString(qualifiedName, "_")
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+// AssignFiles 为表与视图分配互不相同的文件名：替换字符后相同或仅大小写不同的名称
+// （在不区分大小写的文件系统上会互相覆盖）依次加上 _2、_3 后缀
+func AssignFiles(d *Dictionary) {
+	used := map[string]bool{}
+	unique := func(qualifiedName string) string {
+		base := FileName(qualifiedName)
+		name := base
+		for i := 2; used[strings.ToLower(name)]; i++ {
+			name = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[strings.ToLower(name)] = true
+		return name
+	}
+	// index 为按表分文件时的目录页
+	used["index"] = true
+	for i := range d.Tables {
+		d.Tables[i].File = unique(d.Tables[i].QualifiedName())
+	}
+	for i := range d.Views {
+		d.Views[i].File = unique(d.Views[i].QualifiedName())
+	}
+}
+
+// keyLabel 字段索引标记的说明
+func keyLabel(key string) string {
+	switch strings.ToUpper(key) {
+	case "PRI":
+		return "主键"
+	case "UNI":
+		return "唯一"
+	case "MUL", "IDX":
+		return "索引"
+	case "FOR":
+		return "外键"
+	default:
+		return key
+	}
+}
+
+// yesNo 可空标记
+func yesNo(v bool) string {
+	if v {
+		return "是"
+	}
+	return "否"
+}
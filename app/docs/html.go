@@ -0,0 +1,267 @@
+package docs
+
+import (
+	"bytes"
+	"html/template"
+	"path"
+	"strings"
+)
+
+// Site 静态文档站点内容
+type Site struct {
+	Title    string
+	Tables   []SiteTable
+	Views    []SiteView
+	Diagrams []SiteDiagram
+}
+
+// SiteTable 表页面：字段、关系、引用该表的视图与字段血缘
+type SiteTable struct {
+	Table
+	Outgoing []SiteRelation // 本表引用的表
+	Incoming []SiteRelation // 引用本表的表
+	Views    []SiteLink     // 引用本表的视图
+	Lineage  []SiteLineage  // 本表字段流向的视图字段
+}
+
+// SiteView 视图页面：定义与字段来源
+type SiteView struct {
+	View
+	Lineage []SiteLineage // 视图字段的来源
+}
+
+// SiteRelation 关系：另一端的表与字段对
+type SiteRelation struct {
+	Other       SiteLink
+	Columns     string // 如 "user_id = id"
+	Cardinality string // 如 "N:1"
+	Label       string
+}
+
+// SiteLineage 字段血缘：Column 为当前对象的字段，Other/OtherColumn 为另一端
+type SiteLineage struct {
+	Column      string
+	Other       SiteLink
+	OtherColumn string
+	Transform   string
+}
+
+// SiteLink 站点内链接，Href 为空时只显示名称
+type SiteLink struct {
+	Name string
+	Href string
+}
+
+// SiteDiagram 页面 ER 图
+type SiteDiagram struct {
+	Key     string
+	Title   string
+	Diagram Diagram
+}
+
+// TableHref 表页面相对站点根目录的路径
+func TableHref(t Table) string { return "tables/" + t.FileBase() + ".html" }
+
+// ViewHref 视图页面相对站点根目录的路径
+func ViewHref(v View) string { return "views/" + v.FileBase() + ".html" }
+
+// DiagramHref ER 图页面相对站点根目录的路径
+func DiagramHref(key string) string { return "diagrams/" + FileName(key) + ".html" }
+
+// RenderSite 生成站点所有文件，返回相对路径到内容的映射
+func RenderSite(s Site) (map[string][]byte, error) {
+	files := map[string][]byte{"style.css": []byte(siteCSS)}
+	render := func(name string, tpl *template.Template, data interface{}) error {
+		var buf bytes.Buffer
+		if err := tpl.Execute(&buf, data); err != nil {
+			return err
+		}
+		files[name] = buf.Bytes()
+		return nil
+	}
+	if err := render("index.html", siteIndexTpl, s); err != nil {
+		return nil, err
+	}
+	for _, t := range s.Tables {
+		if err := render(TableHref(t.Table), siteTableTpl, sitePage{Site: s, Root: "../", Item: t}); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range s.Views {
+		if err := render(ViewHref(v.View), siteViewTpl, sitePage{Site: s, Root: "../", Item: v}); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range s.Diagrams {
+		var svg bytes.Buffer
+		if err := WriteSVG(&svg, d.Diagram); err != nil {
+			return nil, err
+		}
+		data := sitePage{Site: s, Root: "../", Item: d, SVG: template.HTML(svg.String())}
+		if err := render(DiagramHref(d.Key), siteDiagramTpl, data); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// sitePage 单个页面的模板数据；Root 为回到站点根目录的相对前缀
+type sitePage struct {
+	Site Site
+	Root string
+	Item interface{}
+	SVG  template.HTML
+}
+
+var siteFuncs = template.FuncMap{
+	"tableHref":   TableHref,
+	"viewHref":    ViewHref,
+	"diagramHref": DiagramHref,
+	"keyLabel":    keyLabel,
+	"yesNo":       yesNo,
+	// rel 将站点根目录下的路径转换为相对当前页面的路径
+	"rel": func(root, href string) string {
+		if href == "" {
+			return ""
+		}
+		return path.Clean(root + href)
+	},
+	"lower": strings.ToLower,
+	"inc":   func(i int) int { return i + 1 },
+	// dict 组装传给子模板的参数
+	"dict": func(pairs ...interface{}) map[string]interface{} {
+		m := map[string]interface{}{}
+		for i := 0; i+1 < len(pairs); i += 2 {
+			if k, ok := pairs[i].(string); ok {
+				m[k] = pairs[i+1]
+			}
+		}
+		return m
+	},
+}
+
+const siteHead = `{{define "head"}}<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.Root}}style.css">
+</head>
+<body>
+<header><a href="{{.Root}}index.html">{{.SiteTitle}}</a></header>
+<main>
+{{end}}
+{{define "foot"}}</main>
+</body>
+</html>
+{{end}}
+{{define "link"}}{{if .Link.Href}}<a href="{{rel .Root .Link.Href}}">{{.Link.Name}}</a>{{else}}{{.Link.Name}}{{end}}{{end}}`
+
+var siteIndexTpl = template.Must(template.New("index").Funcs(siteFuncs).Parse(siteHead + `
+{{template "head" (dict "Title" .Title "SiteTitle" .Title "Root" "")}}
+<h1>{{.Title}}</h1>
+<input id="search" type="search" placeholder="搜索表、视图、字段、备注…" autofocus>
+<h2>表（{{len .Tables}}）</h2>
+<table class="index" id="objects">
+<thead><tr><th>名称</th><th>位置</th><th>别名</th><th>说明</th></tr></thead>
+<tbody>
+{{range .Tables}}<tr data-search="{{lower .Info.Name}} {{lower .Info.Alias}} {{lower .Info.Comment}} {{lower .Info.Remark}}{{range .Info.Fields}} {{lower .Name}} {{lower .Alias}}{{end}}">
+<td><a href="{{tableHref .Table}}">{{.Info.Name}}</a></td><td>{{.QualifiedName}}</td><td>{{.Info.Alias}}</td><td>{{.Info.Comment}} {{.Info.Remark}}</td></tr>
+{{end}}</tbody>
+</table>
+{{if .Views}}<h2>视图（{{len .Views}}）</h2>
+<table class="index">
+<thead><tr><th>名称</th><th>位置</th><th>别名</th><th>备注</th></tr></thead>
+<tbody>
+{{range .Views}}<tr data-search="{{lower .Info.Name}} {{lower .Info.Alias}} {{lower .Info.Remark}}">
+<td><a href="{{viewHref .View}}">{{.Info.Name}}</a></td><td>{{.QualifiedName}}</td><td>{{.Info.Alias}}</td><td>{{.Info.Remark}}</td></tr>
+{{end}}</tbody>
+</table>{{end}}
+{{if .Diagrams}}<h2>ER 图</h2>
+<ul>{{range .Diagrams}}<li><a href="{{diagramHref .Key}}">{{.Title}}</a>（{{len .Diagram.Nodes}} 个对象）</li>{{end}}</ul>{{end}}
+<script>
+document.getElementById('search').addEventListener('input', function (e) {
+  var q = e.target.value.trim().toLowerCase();
+  document.querySelectorAll('tr[data-search]').forEach(function (row) {
+    row.style.display = !q || row.getAttribute('data-search').indexOf(q) >= 0 ? '' : 'none';
+  });
+});
+</script>
+{{template "foot"}}`))
+
+var siteTableTpl = template.Must(template.New("table").Funcs(siteFuncs).Parse(siteHead + `
+{{$root := .Root}}{{with .Item}}
+{{template "head" (dict "Title" .Info.Name "SiteTitle" $.Site.Title "Root" $root)}}
+<h1>{{.Info.Name}}{{if .Info.Alias}} <small>{{.Info.Alias}}</small>{{end}}</h1>
+<p class="meta">{{.QualifiedName}}</p>
+{{if .Info.Comment}}<p>{{.Info.Comment}}</p>{{end}}
+{{if .Info.Remark}}<p class="remark">{{.Info.Remark}}</p>{{end}}
+<h2>字段</h2>
+<table>
+<thead><tr><th>#</th><th>字段</th><th>别名</th><th>类型</th><th>可空</th><th>索引</th><th>默认值</th><th>注释</th><th>备注</th></tr></thead>
+<tbody>
+{{range $i, $f := .Info.Fields}}<tr><td>{{inc $i}}</td><td><code>{{$f.Name}}</code></td><td>{{$f.Alias}}</td><td>{{$f.Type}}</td><td>{{yesNo $f.Nullable}}</td><td>{{keyLabel $f.Key}}</td><td>{{$f.DefaultValue}}</td><td>{{$f.Comment}}</td><td>{{$f.Remark}}</td></tr>
+{{end}}</tbody>
+</table>
+{{if or .Outgoing .Incoming}}<h2>关系</h2>
+<table>
+<thead><tr><th>方向</th><th>关联表</th><th>字段</th><th>基数</th><th>说明</th></tr></thead>
+<tbody>
+{{range .Outgoing}}<tr><td>引用</td><td>{{template "link" (dict "Link" .Other "Root" $root)}}</td><td><code>{{.Columns}}</code></td><td>{{.Cardinality}}</td><td>{{.Label}}</td></tr>
+{{end}}{{range .Incoming}}<tr><td>被引用</td><td>{{template "link" (dict "Link" .Other "Root" $root)}}</td><td><code>{{.Columns}}</code></td><td>{{.Cardinality}}</td><td>{{.Label}}</td></tr>
+{{end}}</tbody>
+</table>{{end}}
+{{if .Views}}<h2>引用该表的视图</h2>
+<ul>{{range .Views}}<li>{{template "link" (dict "Link" . "Root" $root)}}</li>{{end}}</ul>{{end}}
+{{if .Lineage}}<h2>字段血缘</h2>
+<table>
+<thead><tr><th>字段</th><th>视图</th><th>视图字段</th><th>转换</th></tr></thead>
+<tbody>
+{{range .Lineage}}<tr><td><code>{{.Column}}</code></td><td>{{template "link" (dict "Link" .Other "Root" $root)}}</td><td><code>{{.OtherColumn}}</code></td><td>{{.Transform}}</td></tr>
+{{end}}</tbody>
+</table>{{end}}
+{{end}}
+{{template "foot"}}`))
+
+var siteViewTpl = template.Must(template.New("view").Funcs(siteFuncs).Parse(siteHead + `
+{{$root := .Root}}{{with .Item}}
+{{template "head" (dict "Title" .Info.Name "SiteTitle" $.Site.Title "Root" $root)}}
+<h1>{{.Info.Name}}{{if .Info.Alias}} <small>{{.Info.Alias}}</small>{{end}}</h1>
+<p class="meta">视图 · {{.QualifiedName}}</p>
+{{if .Info.Remark}}<p class="remark">{{.Info.Remark}}</p>{{end}}
+{{if .Lineage}}<h2>字段来源</h2>
+<table>
+<thead><tr><th>字段</th><th>来源</th><th>来源字段</th><th>转换</th></tr></thead>
+<tbody>
+{{range .Lineage}}<tr><td><code>{{.Column}}</code></td><td>{{template "link" (dict "Link" .Other "Root" $root)}}</td><td><code>{{.OtherColumn}}</code></td><td>{{.Transform}}</td></tr>
+{{end}}</tbody>
+</table>{{end}}
+<h2>定义</h2>
+<pre><code>{{.Info.Definition}}</code></pre>
+{{end}}
+{{template "foot"}}`))
+
+var siteDiagramTpl = template.Must(template.New("diagram").Funcs(siteFuncs).Parse(siteHead + `
+{{$root := .Root}}{{$svg := .SVG}}{{with .Item}}
+{{template "head" (dict "Title" .Title "SiteTitle" $.Site.Title "Root" $root)}}
+<h1>{{.Title}}</h1>
+<div class="diagram">{{$svg}}</div>
+{{end}}
+{{template "foot"}}`))
+
+const siteCSS = `body { margin: 0; font-family: -apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; color: #111827; font-size: 14px; }
+header { background: #1f2937; padding: 10px 24px; }
+header a { color: #fff; text-decoration: none; font-weight: bold; }
+main { padding: 16px 24px 48px; max-width: 1200px; }
+h1 small { color: #6b7280; font-weight: normal; font-size: 0.6em; }
+.meta { color: #6b7280; margin-top: -8px; }
+.remark { background: #fefce8; border-left: 3px solid #facc15; padding: 6px 10px; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 16px; }
+th, td { border: 1px solid #e5e7eb; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f9fafb; }
+#search { width: 100%; max-width: 480px; padding: 6px 10px; font-size: 14px; }
+pre { background: #f3f4f6; padding: 12px; overflow: auto; }
+.diagram { overflow: auto; border: 1px solid #e5e7eb; }
+a { color: #2563eb; }
+`
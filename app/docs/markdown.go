@@ -0,0 +1,158 @@
+package docs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown 将整个数据字典写为单个 Markdown 文件：目录在前，每个库/Schema 一节，每张表一小节
+func WriteMarkdown(w io.Writer, d Dictionary) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n\n", mdText(d.Title))
+	writeMarkdownIndex(bw, d, func(anchor, file string) string { return "#" + anchor })
+
+	section := ""
+	for _, t := range d.Tables {
+		if s := qualified(t.Database, t.Schema); s != section {
+			section = s
+			fmt.Fprintf(bw, "## %s\n\n", mdText(section))
+		}
+		writeMarkdownTable(bw, t, "###")
+	}
+	if len(d.Views) > 0 {
+		fmt.Fprintf(bw, "## 视图\n\n")
+		for _, v := range d.Views {
+			writeMarkdownView(bw, v, "###")
+		}
+	}
+	return bw.Flush()
+}
+
+// WriteMarkdownIndex 写出按表分文件时的索引页，链接指向各表文件
+func WriteMarkdownIndex(w io.Writer, d Dictionary) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n\n", mdText(d.Title))
+	writeMarkdownIndex(bw, d, func(anchor, file string) string { return file + ".md" })
+	return bw.Flush()
+}
+
+// WriteMarkdownTable 写出单张表的 Markdown 文件
+func WriteMarkdownTable(w io.Writer, t Table) error {
+	bw := bufio.NewWriter(w)
+	writeMarkdownTable(bw, t, "#")
+	fmt.Fprintf(bw, "[返回目录](index.md)\n")
+	return bw.Flush()
+}
+
+// WriteMarkdownView 写出单个视图的 Markdown 文件
+func WriteMarkdownView(w io.Writer, v View) error {
+	bw := bufio.NewWriter(w)
+	writeMarkdownView(bw, v, "#")
+	fmt.Fprintf(bw, "[返回目录](index.md)\n")
+	return bw.Flush()
+}
+
+// writeMarkdownIndex 目录：按库/Schema 分组列出表，视图单独列出
+func writeMarkdownIndex(w *bufio.Writer, d Dictionary, link func(anchor, file string) string) {
+	fmt.Fprintf(w, "共 %d 张表，%d 个视图。\n\n", len(d.Tables), len(d.Views))
+	section := ""
+	for i, t := range d.Tables {
+		if s := qualified(t.Database, t.Schema); s != section || i == 0 {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			section = s
+			fmt.Fprintf(w, "**%s**\n\n", mdText(section))
+		}
+		fmt.Fprintf(w, "- [%s](%s)%s\n", mdText(t.Info.Name), link(t.Anchor(), t.FileBase()), summary(t.Info.Alias, t.Info.Comment))
+	}
+	if len(d.Views) > 0 {
+		fmt.Fprintf(w, "\n**视图**\n\n")
+		for _, v := range d.Views {
+			fmt.Fprintf(w, "- [%s](%s)%s\n", mdText(v.QualifiedName()), link(v.Anchor(), v.FileBase()), summary(v.Info.Alias, v.Info.Remark))
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+func writeMarkdownTable(w *bufio.Writer, t Table, level string) {
+	info := t.Info
+	fmt.Fprintf(w, "<a id=\"%s\"></a>\n\n%s %s\n\n", t.Anchor(), level, mdText(info.Name))
+	fmt.Fprintf(w, "- 位置：`%s`\n", t.QualifiedName())
+	writeMarkdownAttr(w, "别名", info.Alias)
+	writeMarkdownAttr(w, "注释", info.Comment)
+	writeMarkdownAttr(w, "备注", info.Remark)
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "| # | 字段 | 别名 | 类型 | 可空 | 索引 | 默认值 | 注释 | 备注 |\n")
+	fmt.Fprintf(w, "|---|---|---|---|---|---|---|---|---|\n")
+	for i, f := range info.Fields {
+		fmt.Fprintf(w, "| %d | %s | %s | %s | %s | %s | %s | %s | %s |\n", i+1,
+			mdCell(f.Name), mdCell(f.Alias), mdCell(f.Type), yesNo(f.Nullable), mdCell(keyLabel(f.Key)),
+			mdCell(f.DefaultValue), mdCell(f.Comment), mdCell(f.Remark))
+	}
+	fmt.Fprintln(w)
+}
+
+func writeMarkdownView(w *bufio.Writer, v View, level string) {
+	info := v.Info
+	fmt.Fprintf(w, "<a id=\"%s\"></a>\n\n%s %s\n\n", v.Anchor(), level, mdText(info.Name))
+	fmt.Fprintf(w, "- 位置：`%s`\n", v.QualifiedName())
+	writeMarkdownAttr(w, "别名", info.Alias)
+	writeMarkdownAttr(w, "备注", info.Remark)
+	definition := strings.TrimSpace(info.Definition)
+	fence := codeFence(definition)
+	fmt.Fprintf(w, "\n%ssql\n%s\n%s\n\n", fence, definition, fence)
+}
+
+// codeFence 代码块的围栏，比内容中最长的连续反引号多一个，且至少三个
+func codeFence(content string) string {
+	longest, run := 0, 0
+	for _, r := range content {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+func writeMarkdownAttr(w *bufio.Writer, label, value string) {
+	if value != "" {
+		fmt.Fprintf(w, "- %s：%s\n", label, mdText(value))
+	}
+}
+
+// summary 目录项后附的别名或说明
+func summary(alias, comment string) string {
+	var parts []string
+	for _, p := range []string{alias, comment} {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, mdText(p))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " — " + strings.Join(parts, "，")
+}
+
+var mdEscaper = strings.NewReplacer("\\", "\\\\", "*", "\\*", "_", "\\_", "`", "\\`", "[", "\\[", "]", "\\]", "<", "&lt;", ">", "&gt;", "#", "\\#")
+
+// mdText 转义行内 Markdown 文本并合并换行
+func mdText(s string) string {
+	return mdEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
+
+// mdCell 表格单元格：额外转义竖线
+func mdCell(s string) string {
+	return strings.ReplaceAll(mdText(s), "|", "\\|")
+}
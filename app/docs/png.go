@@ -0,0 +1,278 @@
+package docs
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxImageSide PNG 单边的最大像素数，避免超大页面耗尽内存
+const maxImageSide = 16384
+
+// curveSegments 栅格化贝塞尔曲线时的折线段数
+const curveSegments = 32
+
+// WritePNG 将 ER 图栅格化为 PNG，布局与 WriteSVG 一致；scale 为每个坐标单位的像素数，不大于 0 时为 2。
+// 文字使用内置的 5x7 点阵字体，只覆盖 ASCII，其他字符显示为方框；返回显示为方框的字符数
+func WritePNG(w io.Writer, d Diagram, scale float64) (int, error) {
+	if scale <= 0 {
+		scale = 2
+	}
+	minX, minY, width, height := d.Bounds()
+	pw, ph := int(math.Ceil(width*scale)), int(math.Ceil(height*scale))
+	if pw > maxImageSide || ph > maxImageSide {
+		return 0, fmt.Errorf("image too large: %dx%d pixels, use a smaller scale", pw, ph)
+	}
+	r := &raster{img: image.NewRGBA(image.Rect(0, 0, pw, ph)), minX: minX, minY: minY, scale: scale}
+	r.fill(minX, minY, width, height, 0, color.NRGBA{0xff, 0xff, 0xff, 0xff})
+
+	nodes := map[string]DiagramNode{}
+	for _, n := range d.Nodes {
+		nodes[n.Key] = n
+	}
+	for _, e := range d.Edges {
+		from, ok1 := nodes[e.From]
+		to, ok2 := nodes[e.To]
+		if ok1 && ok2 {
+			r.edge(from, to, e)
+		}
+	}
+	for _, n := range d.Nodes {
+		r.node(n)
+	}
+	return r.boxed, png.Encode(w, r.img)
+}
+
+// raster 以图的坐标单位绘制到位图，边缘按像素覆盖率做抗锯齿
+type raster struct {
+	img        *image.RGBA
+	minX, minY float64
+	scale      float64
+	boxed      int // 点阵字体未覆盖、绘制为方框的字符数
+}
+
+var (
+	colorBorder    = color.NRGBA{0xd1, 0xd5, 0xdb, 0xff}
+	colorSeparator = color.NRGBA{0xf3, 0xf4, 0xf6, 0xff}
+	colorText      = color.NRGBA{0x11, 0x18, 0x27, 0xff}
+	colorMuted     = color.NRGBA{0x6b, 0x72, 0x80, 0xff}
+	colorLabel     = color.NRGBA{0x37, 0x41, 0x51, 0xff}
+	colorWhite     = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+func (r *raster) node(n DiagramNode) {
+	width, height := n.width(), n.Height()
+	r.fill(n.X-0.5, n.Y-0.5, width+1, height+1, 6.5, colorBorder)
+	r.fill(n.X+0.5, n.Y+0.5, width-1, height-1, 5.5, colorWhite)
+	r.fill(n.X, n.Y, width, NodeHeaderHeight, 0, parseColor(headerColor(n), color.NRGBA{0x3b, 0x82, 0xf6, 0xff}))
+	r.text(n.X+12, n.Y+NodeHeaderHeight/2, fitText(n.Title, width-24), colorWhite, "start", true)
+	if n.Collapsed {
+		return
+	}
+	area := badgeArea(n)
+	for i, c := range n.Columns {
+		y := n.Y + NodeHeaderHeight + float64(i)*NodeRowHeight
+		cy := y + NodeRowHeight/2
+		if i > 0 {
+			r.fill(n.X+0.5, y-0.5, width-1, 1, 0, colorSeparator)
+		}
+		for j, b := range columnBadges(c) {
+			bx, by := n.X+10+float64(j)*(badgeWidth+badgeGap), y+(NodeRowHeight-badgeHeight)/2
+			r.fill(bx, by, badgeWidth, badgeHeight, 3, parseColor(badgeColors[b], colorMuted))
+			r.text(bx+badgeWidth/2, by+badgeHeight/2, b, colorWhite, "middle", false)
+		}
+		typ := fitText(c.Type, (width-24-area)/2)
+		r.text(n.X+width-12, cy, typ, colorMuted, "end", false)
+		name := fitText(c.Name, width-24-area-textWidth(typ)-8)
+		r.text(n.X+12+area, cy, name, colorText, "start", c.Key == "PRI")
+	}
+}
+
+func (r *raster) edge(from, to DiagramNode, e DiagramEdge) {
+	c := routeEdge(from, to, e)
+	points := make([][2]float64, 0, curveSegments+1)
+	for i := 0; i <= curveSegments; i++ {
+		t := float64(i) / curveSegments
+		u := 1 - t
+		points = append(points, [2]float64{
+			u*u*u*c.x1 + 3*u*u*t*c.c1x + 3*u*t*t*c.c2x + t*t*t*c.x2,
+			u*u*u*c.y1 + 3*u*u*t*c.c1y + 3*u*t*t*c.c2y + t*t*t*c.y2,
+		})
+	}
+	r.stroke(points, 1.5, colorMuted)
+	marks := append(cardinalityMarks(c.x1, c.y1, c.dir, e.FromCardinality), cardinalityMarks(c.x2, c.y2, -c.dir, e.ToCardinality)...)
+	for _, m := range marks {
+		r.stroke([][2]float64{{m[0], m[1]}, {m[2], m[3]}}, 1.5, colorMuted)
+	}
+	if e.Label != "" {
+		r.text((c.x1+c.x2)/2, (c.y1+c.y2)/2-10, e.Label, colorLabel, "middle", false)
+	}
+}
+
+// px 图坐标转为像素坐标
+func (r *raster) px(x, y float64) (float64, float64) {
+	return (x - r.minX) * r.scale, (y - r.minY) * r.scale
+}
+
+// fill 填充圆角矩形，radius 为 0 时为普通矩形
+func (r *raster) fill(x, y, w, h, radius float64, c color.NRGBA) {
+	x0, y0 := r.px(x, y)
+	x1, y1 := r.px(x+w, y+h)
+	rad := math.Min(radius*r.scale, math.Min(x1-x0, y1-y0)/2)
+	cx, cy := (x0+x1)/2, (y0+y1)/2
+	hw, hh := (x1-x0)/2-rad, (y1-y0)/2-rad
+	bounds := r.img.Bounds()
+	for py := max(int(math.Floor(y0)), bounds.Min.Y); py < min(int(math.Ceil(y1)), bounds.Max.Y); py++ {
+		for px := max(int(math.Floor(x0)), bounds.Min.X); px < min(int(math.Ceil(x1)), bounds.Max.X); px++ {
+			// 像素中心到圆角矩形边界的有向距离
+			qx := math.Abs(float64(px)+0.5-cx) - hw
+			qy := math.Abs(float64(py)+0.5-cy) - hh
+			d := math.Hypot(math.Max(qx, 0), math.Max(qy, 0)) + math.Min(math.Max(qx, qy), 0) - rad
+			r.blend(px, py, c, clamp01(0.5-d))
+		}
+	}
+}
+
+// stroke 以给定线宽描绘折线，同一像素取各段覆盖率的最大值，避免接头处颜色加深
+func (r *raster) stroke(points [][2]float64, width float64, c color.NRGBA) {
+	if len(points) < 2 {
+		return
+	}
+	half := math.Max(width*r.scale, 1) / 2
+	pts := make([][2]float64, len(points))
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for i, p := range points {
+		x, y := r.px(p[0], p[1])
+		pts[i] = [2]float64{x, y}
+		minX, minY, maxX, maxY = math.Min(minX, x), math.Min(minY, y), math.Max(maxX, x), math.Max(maxY, y)
+	}
+	bounds := r.img.Bounds()
+	left, top := max(int(math.Floor(minX-half-1)), bounds.Min.X), max(int(math.Floor(minY-half-1)), bounds.Min.Y)
+	right, bottom := min(int(math.Ceil(maxX+half+1)), bounds.Max.X), min(int(math.Ceil(maxY+half+1)), bounds.Max.Y)
+	if right <= left || bottom <= top {
+		return
+	}
+	stride := right - left
+	coverage := make([]float64, stride*(bottom-top))
+	for i := 1; i < len(pts); i++ {
+		a, b := pts[i-1], pts[i]
+		sx0, sy0 := max(int(math.Floor(math.Min(a[0], b[0])-half-1)), left), max(int(math.Floor(math.Min(a[1], b[1])-half-1)), top)
+		sx1, sy1 := min(int(math.Ceil(math.Max(a[0], b[0])+half+1)), right), min(int(math.Ceil(math.Max(a[1], b[1])+half+1)), bottom)
+		for py := sy0; py < sy1; py++ {
+			for px := sx0; px < sx1; px++ {
+				d := segmentDistance(float64(px)+0.5, float64(py)+0.5, a, b)
+				k := (py-top)*stride + px - left
+				coverage[k] = math.Max(coverage[k], clamp01(half+0.5-d))
+			}
+		}
+	}
+	for k, a := range coverage {
+		if a > 0 {
+			r.blend(left+k%stride, top+k/stride, c, a)
+		}
+	}
+}
+
+// text 以点阵字体绘制单行文字，cy 为文字的垂直中心；anchor 取 start / middle / end，与 SVG 一致
+func (r *raster) text(x, cy float64, s string, c color.NRGBA, anchor string, bold bool) {
+	switch anchor {
+	case "middle":
+		x -= textWidth(s) / 2
+	case "end":
+		x -= textWidth(s)
+	}
+	top := cy - glyphHeight/2.0
+	dot := 1.0
+	if bold {
+		dot = 1.6
+	}
+	for i, ch := range []rune(s) {
+		g := glyphOf(ch)
+		if !hasGlyph(ch) {
+			r.boxed++
+		}
+		gx := x + float64(i*glyphAdvance)
+		for col := 0; col < glyphWidth; col++ {
+			for row := 0; row < glyphHeight; row++ {
+				if g[col]&(1<<row) != 0 {
+					r.fill(gx+float64(col), top+float64(row), dot, 1, 0, c)
+				}
+			}
+		}
+	}
+}
+
+// blend 按覆盖率将颜色叠加到像素上
+func (r *raster) blend(px, py int, c color.NRGBA, coverage float64) {
+	a := coverage * float64(c.A) / 0xff
+	if a <= 0 {
+		return
+	}
+	i := r.img.PixOffset(px, py)
+	p := r.img.Pix[i : i+4 : i+4]
+	mix := func(dst, src uint8) uint8 {
+		return uint8(math.Round(float64(dst)*(1-a) + float64(src)*a))
+	}
+	p[0], p[1], p[2] = mix(p[0], c.R), mix(p[1], c.G), mix(p[2], c.B)
+	p[3] = uint8(math.Round(float64(p[3])*(1-a) + 0xff*a))
+}
+
+func segmentDistance(x, y float64, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t := 0.0
+	if l := dx*dx + dy*dy; l > 0 {
+		t = clamp01(((x-a[0])*dx + (y-a[1])*dy) / l)
+	}
+	return math.Hypot(x-a[0]-t*dx, y-a[1]-t*dy)
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// parseColor 解析 #rgb、#rrggbb、#rrggbbaa 与 rgb()/rgba() 写法，无法解析时返回 fallback
+func parseColor(s string, fallback color.NRGBA) color.NRGBA {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if strings.HasPrefix(s, "#") {
+		hex := s[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+		if v, err := strconv.ParseUint(hex, 16, 32); err == nil && len(hex) == 8 {
+			return color.NRGBA{uint8(v >> 24), uint8(v >> 16), uint8(v >> 8), uint8(v)}
+		}
+		return fallback
+	}
+	open, end := strings.Index(s, "("), strings.LastIndex(s, ")")
+	if (strings.HasPrefix(s, "rgb(") || strings.HasPrefix(s, "rgba(")) && end > open {
+		parts := strings.FieldsFunc(s[open+1:end], func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+		if len(parts) < 3 {
+			return fallback
+		}
+		var rgba [4]uint8
+		rgba[3] = 0xff
+		for i, p := range parts[:min(len(parts), 4)] {
+			v, err := strconv.ParseFloat(strings.TrimSuffix(p, "%"), 64)
+			if err != nil {
+				return fallback
+			}
+			switch {
+			case strings.HasSuffix(p, "%"):
+				v = v / 100 * 0xff
+			case i == 3:
+				v *= 0xff
+			}
+			rgba[i] = uint8(math.Round(math.Max(0, math.Min(0xff, v))))
+		}
+		return color.NRGBA{rgba[0], rgba[1], rgba[2], rgba[3]}
+	}
+	return fallback
+}
@@ -0,0 +1,279 @@
+package docs
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ER 图节点尺寸，与前端表节点及服务端布局一致
+const (
+	NodeWidth        = 256.0
+	NodeHeaderHeight = 40.0
+	NodeRowHeight    = 32.0
+	diagramMargin    = 20.0
+)
+
+// Diagram ER 图内容，坐标沿用页面节点位置
+type Diagram struct {
+	Title string
+	Nodes []DiagramNode
+	Edges []DiagramEdge
+}
+
+// DiagramNode ER 图中的表或视图
+type DiagramNode struct {
+	Key       string
+	Title     string
+	Database  string
+	Schema    string
+	Alias     string
+	Comment   string
+	Link      string // 非空时节点可点击
+	Color     string // 表头颜色，为空时使用默认色
+	View      bool
+	Missing   bool // 引用的对象已不存在
+	Collapsed bool // 仅显示表头
+	X, Y      float64
+	Width     float64
+	Columns   []DiagramColumn // 显示的字段，按字段排序
+	Hidden    []DiagramColumn // 未显示的字段，仅用于需要完整引用的文本格式
+}
+
+// DiagramColumn 节点中的字段行
+type DiagramColumn struct {
+	Name     string
+	Type     string
+	Key      string
+	Alias    string
+	Comment  string
+	Nullable bool
+	Foreign  bool // 作为关系的引用方字段
+}
+
+// DiagramEdge 两个节点之间的关系，基数为 one / many / none
+// FromColumn / ToColumn 为第一对关联字段，FromColumns / ToColumns 为全部关联字段
+type DiagramEdge struct {
+	From, To                       string
+	FromColumn, ToColumn           string
+	FromColumns, ToColumns         []string
+	FromCardinality, ToCardinality string
+	Label                          string
+}
+
+// Height 节点高度：表头加字段行，折叠的节点只有表头
+func (n DiagramNode) Height() float64 {
+	if n.Collapsed {
+		return NodeHeaderHeight
+	}
+	return NodeHeaderHeight + float64(len(n.Columns))*NodeRowHeight
+}
+
+func (n DiagramNode) width() float64 {
+	if n.Width > 0 {
+		return n.Width
+	}
+	return NodeWidth
+}
+
+// columnY 字段行中心的纵坐标，字段不存在或节点折叠时为表头中心
+func (n DiagramNode) columnY(name string) float64 {
+	if n.Collapsed {
+		return n.Y + NodeHeaderHeight/2
+	}
+	for i, c := range n.Columns {
+		if c.Name == name {
+			return n.Y + NodeHeaderHeight + float64(i)*NodeRowHeight + NodeRowHeight/2
+		}
+	}
+	return n.Y + NodeHeaderHeight/2
+}
+
+// Bounds 图的外接矩形（含边距）
+func (d Diagram) Bounds() (minX, minY, width, height float64) {
+	if len(d.Nodes) == 0 {
+		return 0, 0, 2 * diagramMargin, 2 * diagramMargin
+	}
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, n := range d.Nodes {
+		minX, minY = math.Min(minX, n.X), math.Min(minY, n.Y)
+		maxX, maxY = math.Max(maxX, n.X+n.width()), math.Max(maxY, n.Y+n.Height())
+	}
+	minX, minY = minX-diagramMargin, minY-diagramMargin
+	return minX, minY, maxX + diagramMargin - minX, maxY + diagramMargin - minY
+}
+
+// WriteSVG 将 ER 图写为独立的 SVG 文档
+func WriteSVG(w io.Writer, d Diagram) error {
+	bw := bufio.NewWriter(w)
+	minX, minY, width, height := d.Bounds()
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s" width="%s" height="%s" font-family="sans-serif" font-size="13">`+"\n",
+		num(minX), num(minY), num(width), num(height), num(width), num(height))
+	if d.Title != "" {
+		fmt.Fprintf(bw, "<title>%s</title>\n", html.EscapeString(d.Title))
+	}
+	fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s" fill="#ffffff"/>`+"\n", num(minX), num(minY), num(width), num(height))
+
+	nodes := map[string]DiagramNode{}
+	for _, n := range d.Nodes {
+		nodes[n.Key] = n
+	}
+	for _, e := range d.Edges {
+		from, ok1 := nodes[e.From]
+		to, ok2 := nodes[e.To]
+		if ok1 && ok2 {
+			writeSVGEdge(bw, from, to, e)
+		}
+	}
+	for _, n := range d.Nodes {
+		writeSVGNode(bw, n)
+	}
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// 字段行的键标记：主键、外键与唯一键
+const (
+	badgeWidth  = 22.0
+	badgeHeight = 14.0
+	badgeGap    = 3.0
+)
+
+var badgeColors = map[string]string{"PK": "#f59e0b", "FK": "#10b981", "UK": "#8b5cf6"}
+
+// headerColor 表头颜色：节点或表设置的颜色，未设置时表与视图使用不同的默认色，缺失的对象为灰色
+func headerColor(n DiagramNode) string {
+	if n.Missing {
+		return "#9ca3af"
+	}
+	if n.Color != "" {
+		return n.Color
+	}
+	if n.View {
+		return "#8b5cf6"
+	}
+	return "#3b82f6"
+}
+
+// columnBadges 字段的键标记，主键同时是外键时两者都显示
+func columnBadges(c DiagramColumn) []string {
+	var badges []string
+	if c.Key == "PRI" {
+		badges = append(badges, "PK")
+	}
+	if c.Foreign {
+		badges = append(badges, "FK")
+	}
+	if c.Key == "UNI" {
+		badges = append(badges, "UK")
+	}
+	return badges
+}
+
+// badgeArea 节点内为键标记预留的宽度，按标记最多的字段计算，使字段名对齐
+func badgeArea(n DiagramNode) float64 {
+	most := 0
+	for _, c := range n.Columns {
+		most = max(most, len(columnBadges(c)))
+	}
+	if most == 0 {
+		return 0
+	}
+	return float64(most)*(badgeWidth+badgeGap) + 2
+}
+
+func writeSVGNode(w *bufio.Writer, n DiagramNode) {
+	width, height := n.width(), n.Height()
+	if n.Link != "" {
+		fmt.Fprintf(w, `<a href="%s">`, html.EscapeString(n.Link))
+	}
+	fmt.Fprintf(w, `<g class="node"><rect x="%s" y="%s" width="%s" height="%s" rx="6" fill="#ffffff" stroke="#d1d5db"/>`,
+		num(n.X), num(n.Y), num(width), num(height))
+	fmt.Fprintf(w, `<path d="M%s %s h%s v%s h-%s z" fill="%s"/>`,
+		num(n.X), num(n.Y), num(width), num(NodeHeaderHeight), num(width), html.EscapeString(headerColor(n)))
+	fmt.Fprintf(w, `<text x="%s" y="%s" fill="#ffffff" font-weight="bold">%s</text>`,
+		num(n.X+12), num(n.Y+NodeHeaderHeight/2+5), html.EscapeString(n.Title))
+	if !n.Collapsed {
+		area := badgeArea(n)
+		for i, c := range n.Columns {
+			y := n.Y + NodeHeaderHeight + float64(i)*NodeRowHeight
+			if i > 0 {
+				fmt.Fprintf(w, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="#f3f4f6"/>`, num(n.X), num(y), num(n.X+width), num(y))
+			}
+			for j, b := range columnBadges(c) {
+				bx, by := n.X+10+float64(j)*(badgeWidth+badgeGap), y+(NodeRowHeight-badgeHeight)/2
+				fmt.Fprintf(w, `<rect x="%s" y="%s" width="%s" height="%s" rx="3" fill="%s"/>`, num(bx), num(by), num(badgeWidth), num(badgeHeight), badgeColors[b])
+				fmt.Fprintf(w, `<text x="%s" y="%s" fill="#ffffff" font-size="9" font-weight="bold" text-anchor="middle">%s</text>`,
+					num(bx+badgeWidth/2), num(by+badgeHeight/2+3), b)
+			}
+			weight := "normal"
+			if c.Key == "PRI" {
+				weight = "bold"
+			}
+			fmt.Fprintf(w, `<text x="%s" y="%s" fill="#111827" font-weight="%s">%s</text>`, num(n.X+12+area), num(y+NodeRowHeight/2+5), weight, html.EscapeString(c.Name))
+			fmt.Fprintf(w, `<text x="%s" y="%s" fill="#6b7280" text-anchor="end">%s</text>`, num(n.X+width-12), num(y+NodeRowHeight/2+5), html.EscapeString(c.Type))
+		}
+	}
+	fmt.Fprint(w, "</g>")
+	if n.Link != "" {
+		fmt.Fprint(w, "</a>")
+	}
+	fmt.Fprintln(w)
+}
+
+// edgeCurve 关系连线：三次贝塞尔曲线的端点与控制点，dir 为离开源节点的水平方向
+type edgeCurve struct {
+	x1, y1, c1x, c1y, c2x, c2y, x2, y2 float64
+	dir                                float64
+}
+
+// routeEdge 从源节点字段行连到目标节点字段行，按左右位置选择出入边
+func routeEdge(from, to DiagramNode, e DiagramEdge) edgeCurve {
+	c := edgeCurve{y1: from.columnY(e.FromColumn), y2: to.columnY(e.ToColumn)}
+	if from.X+from.width()/2 <= to.X+to.width()/2 {
+		c.x1, c.x2, c.dir = from.X+from.width(), to.X, 1
+	} else {
+		c.x1, c.x2, c.dir = from.X, to.X+to.width(), -1
+	}
+	dx := math.Max(40, math.Abs(c.x2-c.x1)/2)
+	c.c1x, c.c1y = c.x1+c.dir*dx, c.y1
+	c.c2x, c.c2y = c.x2-c.dir*dx, c.y2
+	return c
+}
+
+// cardinalityMarks 端点处的鸦脚标记线段：one 为一条竖线，many 为三叉；
+// (x, y) 为节点边上的端点，out 为连线离开节点的方向
+func cardinalityMarks(x, y, out float64, cardinality string) [][4]float64 {
+	switch cardinality {
+	case "one":
+		return [][4]float64{{x + out*10, y - 6, x + out*10, y + 6}}
+	case "many":
+		return [][4]float64{{x + out*12, y, x, y - 6}, {x + out*12, y, x, y + 6}}
+	}
+	return nil
+}
+
+// writeSVGEdge 画出连线与两端的基数标记
+func writeSVGEdge(w *bufio.Writer, from, to DiagramNode, e DiagramEdge) {
+	c := routeEdge(from, to, e)
+	fmt.Fprintf(w, `<path d="M%s %s C%s %s, %s %s, %s %s" fill="none" stroke="#6b7280" stroke-width="1.5"/>`,
+		num(c.x1), num(c.y1), num(c.c1x), num(c.c1y), num(c.c2x), num(c.c2y), num(c.x2), num(c.y2))
+	marks := append(cardinalityMarks(c.x1, c.y1, c.dir, e.FromCardinality), cardinalityMarks(c.x2, c.y2, -c.dir, e.ToCardinality)...)
+	for _, m := range marks {
+		fmt.Fprintf(w, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="#6b7280" stroke-width="1.5"/>`, num(m[0]), num(m[1]), num(m[2]), num(m[3]))
+	}
+	if e.Label != "" {
+		fmt.Fprintf(w, `<text x="%s" y="%s" fill="#374151" text-anchor="middle" font-size="12">%s</text>`,
+			num((c.x1+c.x2)/2), num((c.y1+c.y2)/2-6), html.EscapeString(e.Label))
+	}
+	fmt.Fprintln(w)
+}
+
+// num 输出紧凑的坐标值
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,142 @@
+package docs
+
+import (
+	"dbrun/app/xlsx"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 数据字典工作簿的列，导入时按表头名称定位，列顺序可调整
+const (
+	ColDatabase = "数据库"
+	ColSchema   = "Schema"
+	ColTable    = "表"
+	ColColumn   = "字段"
+	ColType     = "类型"
+	ColComment  = "注释"
+	ColRemark   = "备注"
+	ColAlias    = "别名"
+	ColDisplay  = "显示"
+)
+
+var workbookHeader = []string{ColDatabase, ColSchema, ColTable, ColColumn, ColType, ColComment, ColRemark, ColAlias, ColDisplay}
+
+// WriteXLSX 将数据字典写为工作簿：每个库（或 Schema）一个工作表，
+// 每张表先写一行表信息（字段列为空），随后逐行写出字段
+func WriteXLSX(w io.Writer, d Dictionary) error {
+	xw := xlsx.NewWriter(w)
+	current := ""
+	for i, t := range d.Tables {
+		group := qualified(t.Database, t.Schema)
+		if i == 0 || group != current {
+			current = group
+			if err := xw.NewSheet(defaultSheetName(group)); err != nil {
+				return err
+			}
+			if err := xw.WriteHeader(workbookHeader); err != nil {
+				return err
+			}
+		}
+		if err := xw.WriteRow([]interface{}{t.Database, t.Schema, t.Info.Name, "", "", t.Info.Comment, t.Info.Remark, t.Info.Alias, ""}); err != nil {
+			return err
+		}
+		for _, f := range t.Info.Fields {
+			if err := xw.WriteRow([]interface{}{t.Database, t.Schema, t.Info.Name, f.Name, f.Type, f.Comment, f.Remark, f.Alias, yesNo(f.Display)}); err != nil {
+				return err
+			}
+		}
+	}
+	if len(d.Tables) == 0 {
+		if err := xw.NewSheet(defaultSheetName(d.Title)); err != nil {
+			return err
+		}
+		if err := xw.WriteHeader(workbookHeader); err != nil {
+			return err
+		}
+	}
+	return xw.Close()
+}
+
+func defaultSheetName(name string) string {
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
+
+// WorkbookRow 工作簿中的一行；Remark / Alias 为 nil 表示工作表缺少该列，不参与导入
+type WorkbookRow struct {
+	Sheet    string
+	Line     int // Excel 中的行号，从 1 开始
+	Database string
+	Schema   string
+	Table    string
+	Column   string // 为空表示表信息行
+	Remark   *string
+	Alias    *string
+}
+
+// ReadXLSX 读取由 WriteXLSX 导出并经人工编辑的工作簿
+// 每个工作表的第一行为表头，必须包含数据库与表两列，缺少表头的工作表将被跳过
+func ReadXLSX(path string) ([]WorkbookRow, error) {
+	sheets, err := xlsx.OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rows []WorkbookRow
+	for _, s := range sheets {
+		if len(s.Rows) == 0 {
+			continue
+		}
+		cols := map[string]int{}
+		for i, name := range s.Rows[0] {
+			cols[strings.TrimSpace(name)] = i
+		}
+		if _, ok := cols[ColDatabase]; !ok {
+			continue
+		}
+		if _, ok := cols[ColTable]; !ok {
+			return nil, fmt.Errorf("sheet %s: missing column %s", s.Name, ColTable)
+		}
+		cell := func(r []string, name string) (string, bool) {
+			i, ok := cols[name]
+			if !ok {
+				return "", false
+			}
+			if i >= len(r) {
+				return "", true
+			}
+			return r[i], true
+		}
+		text := func(r []string, name string) string {
+			v, _ := cell(r, name)
+			return strings.TrimSpace(v)
+		}
+		optional := func(r []string, name string) *string {
+			v, ok := cell(r, name)
+			if !ok {
+				return nil
+			}
+			v = strings.TrimSpace(v)
+			return &v
+		}
+		for i, r := range s.Rows[1:] {
+			row := WorkbookRow{
+				Sheet:    s.Name,
+				Line:     i + 2,
+				Database: text(r, ColDatabase),
+				Schema:   text(r, ColSchema),
+				Table:    text(r, ColTable),
+				Column:   text(r, ColColumn),
+				Remark:   optional(r, ColRemark),
+				Alias:    optional(r, ColAlias),
+			}
+			if row.Table == "" {
+				continue
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
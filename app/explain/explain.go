@@ -0,0 +1,93 @@
+package explain
+
+import (
+	"dbrun/app/models"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Command 返回指定方言用于获取计划的语句（Oracle 与 SQL Server 需多步执行，由调用方处理）
+func Command(dialect, sql string) (string, error) {
+	switch dialect {
+	case "mysql", "mariadb":
+		return "EXPLAIN FORMAT=JSON " + sql, nil
+	case "postgresql":
+		return "EXPLAIN (FORMAT JSON) " + sql, nil
+	default:
+		return "", fmt.Errorf("explain command not available for %s", dialect)
+	}
+}
+
+// toFloat 将 JSON 中的数字或数字字符串转换为浮点数
+func toFloat(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return f
+	case int64:
+		return float64(val)
+	}
+	return 0
+}
+
+// scalarDetails 收集对象中的标量属性作为节点详情
+func scalarDetails(obj map[string]interface{}, skip ...string) map[string]string {
+	skipped := map[string]bool{}
+	for _, k := range skip {
+		skipped[k] = true
+	}
+	details := map[string]string{}
+	for k, v := range obj {
+		if skipped[k] {
+			continue
+		}
+		switch val := v.(type) {
+		case string:
+			details[k] = val
+		case float64:
+			details[k] = strconv.FormatFloat(val, 'f', -1, 64)
+		case bool:
+			details[k] = strconv.FormatBool(val)
+		case []interface{}:
+			parts := make([]string, 0, len(val))
+			for _, item := range val {
+				if s, ok := item.(string); ok {
+					parts = append(parts, s)
+				}
+			}
+			if len(parts) == len(val) && len(parts) > 0 {
+				details[k] = strings.Join(parts, ", ")
+			}
+		}
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return details
+}
+
+// sortedKeys 返回排序后的键，保证输出稳定
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// wrapRoots 多个根节点时包装为单一根节点
+func wrapRoots(operator string, roots []*models.PlanNode) *models.PlanNode {
+	if len(roots) == 1 {
+		return roots[0]
+	}
+	root := &models.PlanNode{Operator: operator, Children: roots}
+	for _, r := range roots {
+		root.Cost += r.Cost
+	}
+	return root
+}
@@ -0,0 +1,222 @@
+package explain
+
+import (
+	"dbrun/app/models"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// MySQL/MariaDB 中作为中间算子出现的对象键
+var mysqlOperations = map[string]string{
+	"ordering_operation": "Sort",
+	"grouping_operation": "Group",
+	"duplicates_removal": "Distinct",
+	"windowing":          "Window",
+	"buffer_result":      "Buffer Result",
+	"filesort":           "Filesort",
+	"temporary_table":    "Temporary Table",
+	"read_sorted_file":   "Read Sorted File",
+	"block-nl-join":      "Block Nested Loop",
+	"expression_cache":   "Expression Cache",
+	"materialized":       "Materialize",
+	"union_result":       "Union",
+}
+
+// MySQL 中保存子查询列表的键
+var mysqlSubqueryLists = []string{
+	"attached_subqueries", "optimized_away_subqueries", "select_list_subqueries",
+	"having_subqueries", "order_by_subqueries", "group_by_subqueries", "subqueries",
+	"query_specifications",
+}
+
+// ParseMySQL 解析 EXPLAIN FORMAT=JSON 的输出（MySQL 与 MariaDB 通用）
+func ParseMySQL(raw string) (*models.PlanNode, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, fmt.Errorf("invalid mysql explain json: %w", err)
+	}
+	block, ok := doc["query_block"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("mysql explain json has no query_block")
+	}
+	return mysqlQueryBlock(block), nil
+}
+
+func mysqlQueryBlock(block map[string]interface{}) *models.PlanNode {
+	node := &models.PlanNode{Operator: "Query Block"}
+	if id, ok := block["select_id"]; ok {
+		node.Operator = fmt.Sprintf("Query Block #%v", id)
+	}
+	if cost, ok := block["cost_info"].(map[string]interface{}); ok {
+		node.Cost = toFloat(cost["query_cost"])
+	} else if c, ok := block["cost"]; ok {
+		node.Cost = toFloat(c)
+	}
+	node.Details = scalarDetails(block, "select_id")
+	node.Children = mysqlChildren(block)
+	return node
+}
+
+// mysqlChildren 遍历对象中的子结构
+func mysqlChildren(obj map[string]interface{}) []*models.PlanNode {
+	var children []*models.PlanNode
+	for _, key := range sortedKeys(obj) {
+		switch val := obj[key].(type) {
+		case map[string]interface{}:
+			if n := mysqlObject(key, val); n != nil {
+				children = append(children, n)
+			}
+		case []interface{}:
+			if key == "nested_loop" {
+				loop := &models.PlanNode{Operator: "Nested Loop"}
+				for _, item := range val {
+					if m, ok := item.(map[string]interface{}); ok {
+						loop.Children = append(loop.Children, mysqlChildren(m)...)
+					}
+				}
+				for _, c := range loop.Children {
+					if c.Cost > loop.Cost {
+						loop.Cost = c.Cost
+					}
+					if c.EstimatedRows > loop.EstimatedRows {
+						loop.EstimatedRows = c.EstimatedRows
+					}
+				}
+				children = append(children, loop)
+				continue
+			}
+			if !isSubqueryList(key) {
+				continue
+			}
+			for _, item := range val {
+				m, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if qb, ok := m["query_block"].(map[string]interface{}); ok {
+					sub := mysqlQueryBlock(qb)
+					if details := scalarDetails(m); details != nil {
+						mergeDetails(sub, details)
+					}
+					children = append(children, sub)
+				} else {
+					children = append(children, mysqlChildren(m)...)
+				}
+			}
+		}
+	}
+	return children
+}
+
+func isSubqueryList(key string) bool {
+	for _, k := range mysqlSubqueryLists {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// mysqlObject 将对象键转换为计划节点
+func mysqlObject(key string, obj map[string]interface{}) *models.PlanNode {
+	switch key {
+	case "query_block":
+		return mysqlQueryBlock(obj)
+	case "table":
+		return mysqlTable(obj)
+	case "materialized_from_subquery":
+		node := &models.PlanNode{Operator: "Materialize"}
+		node.Details = scalarDetails(obj)
+		node.Children = mysqlChildren(obj)
+		return node
+	case "cost_info":
+		return nil
+	}
+	op, ok := mysqlOperations[key]
+	if !ok {
+		// 未识别的结构直接展开其子节点
+		children := mysqlChildren(obj)
+		if len(children) == 1 {
+			return children[0]
+		}
+		if len(children) == 0 {
+			return nil
+		}
+		return &models.PlanNode{Operator: key, Children: children}
+	}
+	node := &models.PlanNode{Operator: op, Details: scalarDetails(obj)}
+	if cost, ok := obj["cost_info"].(map[string]interface{}); ok {
+		node.Cost = toFloat(cost["sort_cost"])
+	}
+	node.Children = mysqlChildren(obj)
+	for _, c := range node.Children {
+		if c.Cost > node.Cost {
+			node.Cost = c.Cost
+		}
+		if c.EstimatedRows > node.EstimatedRows {
+			node.EstimatedRows = c.EstimatedRows
+		}
+	}
+	return node
+}
+
+// mysql 访问类型的可读名称
+var mysqlAccessTypes = map[string]string{
+	"ALL":             "Full Table Scan",
+	"index":           "Full Index Scan",
+	"range":           "Index Range Scan",
+	"ref":             "Non-Unique Key Lookup",
+	"eq_ref":          "Unique Key Lookup",
+	"const":           "Single Row Lookup",
+	"system":          "System Table",
+	"fulltext":        "Fulltext Index Search",
+	"ref_or_null":     "Key Lookup (with NULL)",
+	"index_merge":     "Index Merge",
+	"unique_subquery": "Unique Key Lookup (subquery)",
+	"index_subquery":  "Non-Unique Key Lookup (subquery)",
+}
+
+func mysqlTable(obj map[string]interface{}) *models.PlanNode {
+	access, _ := obj["access_type"].(string)
+	op := mysqlAccessTypes[access]
+	if op == "" {
+		op = "Table Access"
+		if access != "" {
+			op += " (" + access + ")"
+		}
+	}
+	node := &models.PlanNode{Operator: op}
+	node.Object, _ = obj["table_name"].(string)
+	if key, ok := obj["key"].(string); ok && key != "" {
+		node.Object = strings.TrimSpace(node.Object + " [" + key + "]")
+	}
+	switch {
+	case obj["rows_produced_per_join"] != nil:
+		node.EstimatedRows = toFloat(obj["rows_produced_per_join"])
+	case obj["rows_examined_per_scan"] != nil:
+		node.EstimatedRows = toFloat(obj["rows_examined_per_scan"])
+	default:
+		node.EstimatedRows = toFloat(obj["rows"])
+	}
+	if cost, ok := obj["cost_info"].(map[string]interface{}); ok {
+		node.Cost = toFloat(cost["prefix_cost"])
+		if node.Cost == 0 {
+			node.Cost = toFloat(cost["read_cost"]) + toFloat(cost["eval_cost"])
+		}
+	} else if c, ok := obj["cost"]; ok {
+		node.Cost = toFloat(c)
+	}
+	node.Details = scalarDetails(obj, "table_name")
+	node.Children = mysqlChildren(obj)
+	return node
+}
+
+func mergeDetails(node *models.PlanNode, details map[string]string) {
+	if node.Details == nil {
+		node.Details = map[string]string{}
+	}
+	for k, v := range details {
+		node.Details[k] = v
+	}
+}
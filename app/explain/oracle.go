@@ -0,0 +1,67 @@
+package explain
+
+import (
+	"dbrun/app/models"
+	"fmt"
+	"strings"
+)
+
+// OraclePlanRow PLAN_TABLE 中的一行
+type OraclePlanRow struct {
+	ID          int64
+	ParentID    *int64
+	Operation   string
+	Options     string
+	ObjectOwner string
+	ObjectName  string
+	Cardinality float64
+	Cost        float64
+	Access      string // access_predicates
+	Filter      string // filter_predicates
+}
+
+// OracleStatementID 生成 EXPLAIN PLAN 使用的 STATEMENT_ID
+func OracleStatementID(seed int64) string {
+	return fmt.Sprintf("DBRUN_%d", seed)
+}
+
+// BuildOracle 按 parent_id 将 PLAN_TABLE 行组装为树
+func BuildOracle(rows []OraclePlanRow) (*models.PlanNode, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("oracle plan table returned no rows")
+	}
+	nodes := make(map[int64]*models.PlanNode, len(rows))
+	for _, r := range rows {
+		op := strings.TrimSpace(r.Operation + " " + r.Options)
+		node := &models.PlanNode{Operator: op, EstimatedRows: r.Cardinality, Cost: r.Cost}
+		if r.ObjectName != "" {
+			node.Object = r.ObjectName
+			if r.ObjectOwner != "" {
+				node.Object = r.ObjectOwner + "." + r.ObjectName
+			}
+		}
+		details := map[string]string{}
+		if r.Access != "" {
+			details["access_predicates"] = r.Access
+		}
+		if r.Filter != "" {
+			details["filter_predicates"] = r.Filter
+		}
+		if len(details) > 0 {
+			node.Details = details
+		}
+		nodes[r.ID] = node
+	}
+	var roots []*models.PlanNode
+	for _, r := range rows {
+		node := nodes[r.ID]
+		if r.ParentID != nil {
+			if parent, ok := nodes[*r.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return wrapRoots("Plans", roots), nil
+}
@@ -0,0 +1,65 @@
+package explain
+
+import (
+	"dbrun/app/models"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParsePostgres 解析 EXPLAIN (FORMAT JSON) 的输出
+func ParsePostgres(raw string) (*models.PlanNode, error) {
+	var docs []map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &docs); err != nil {
+		return nil, fmt.Errorf("invalid postgresql explain json: %w", err)
+	}
+	var roots []*models.PlanNode
+	for _, doc := range docs {
+		if plan, ok := doc["Plan"].(map[string]interface{}); ok {
+			roots = append(roots, postgresNode(plan))
+		}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("postgresql explain json has no plan")
+	}
+	return wrapRoots("Plans", roots), nil
+}
+
+func postgresNode(plan map[string]interface{}) *models.PlanNode {
+	op, _ := plan["Node Type"].(string)
+	if jt, ok := plan["Join Type"].(string); ok && jt != "" && jt != "Inner" {
+		op += " (" + jt + ")"
+	}
+	node := &models.PlanNode{
+		Operator:      op,
+		EstimatedRows: toFloat(plan["Plan Rows"]),
+		Cost:          toFloat(plan["Total Cost"]),
+	}
+	var object []string
+	if rel, ok := plan["Relation Name"].(string); ok && rel != "" {
+		if schema, ok := plan["Schema"].(string); ok && schema != "" {
+			rel = schema + "." + rel
+		}
+		object = append(object, rel)
+	}
+	if idx, ok := plan["Index Name"].(string); ok && idx != "" {
+		object = append(object, "["+idx+"]")
+	}
+	if len(object) == 0 {
+		if name, ok := plan["CTE Name"].(string); ok {
+			object = append(object, name)
+		} else if name, ok := plan["Function Name"].(string); ok {
+			object = append(object, name)
+		}
+	}
+	node.Object = strings.Join(object, " ")
+	node.Details = scalarDetails(plan, "Node Type", "Plan Rows", "Total Cost", "Relation Name", "Index Name")
+	if subs, ok := plan["Plans"].([]interface{}); ok {
+		for _, s := range subs {
+			if m, ok := s.(map[string]interface{}); ok {
+				node.Children = append(node.Children, postgresNode(m))
+			}
+		}
+	}
+	return node
+}
@@ -0,0 +1,157 @@
+package explain
+
+import (
+	"dbrun/app/models"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// xmlElement 通用XML元素，用于遍历 showplan 的嵌套结构
+type xmlElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Children []xmlElement `xml:",any"`
+}
+
+func (e *xmlElement) attr(name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// ParseSQLServer 解析 SET SHOWPLAN_XML ON 返回的 showplan XML
+func ParseSQLServer(raw string) (*models.PlanNode, error) {
+	var doc xmlElement
+	if err := xml.Unmarshal([]byte(raw), &doc); err != nil {
+		return nil, fmt.Errorf("invalid sqlserver showplan xml: %w", err)
+	}
+	var roots []*models.PlanNode
+	var walkStmts func(e *xmlElement)
+	walkStmts = func(e *xmlElement) {
+		if strings.HasPrefix(e.XMLName.Local, "Stmt") && e.attr("StatementText") != "" {
+			stmt := &models.PlanNode{
+				Operator:      e.attr("StatementType"),
+				EstimatedRows: toFloat(e.attr("StatementEstRows")),
+				Cost:          toFloat(e.attr("StatementSubTreeCost")),
+				Details:       map[string]string{"StatementText": strings.TrimSpace(e.attr("StatementText"))},
+			}
+			stmt.Children = relOpChildren(e)
+			roots = append(roots, stmt)
+			return
+		}
+		for i := range e.Children {
+			walkStmts(&e.Children[i])
+		}
+	}
+	walkStmts(&doc)
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("sqlserver showplan has no statements")
+	}
+	return wrapRoots("Batch", roots), nil
+}
+
+// relOpChildren 查找元素下最近一层的 RelOp（不跨越其它 RelOp）
+func relOpChildren(e *xmlElement) []*models.PlanNode {
+	var nodes []*models.PlanNode
+	for i := range e.Children {
+		c := &e.Children[i]
+		if c.XMLName.Local == "RelOp" {
+			nodes = append(nodes, relOpNode(c))
+			continue
+		}
+		nodes = append(nodes, relOpChildren(c)...)
+	}
+	return nodes
+}
+
+func relOpNode(e *xmlElement) *models.PlanNode {
+	op := e.attr("PhysicalOp")
+	if logical := e.attr("LogicalOp"); logical != "" && logical != op {
+		op += " (" + logical + ")"
+	}
+	node := &models.PlanNode{
+		Operator:      op,
+		EstimatedRows: toFloat(e.attr("EstimateRows")),
+		Cost:          toFloat(e.attr("EstimatedTotalSubtreeCost")),
+		Details:       map[string]string{},
+	}
+	for _, a := range e.Attrs {
+		switch a.Name.Local {
+		case "PhysicalOp", "LogicalOp", "EstimateRows", "EstimatedTotalSubtreeCost":
+		default:
+			node.Details[a.Name.Local] = a.Value
+		}
+	}
+	if obj := findObject(e); obj != nil {
+		var parts []string
+		for _, name := range []string{"Database", "Schema", "Table"} {
+			if v := obj.attr(name); v != "" {
+				parts = append(parts, v)
+			}
+		}
+		node.Object = strings.Join(parts, ".")
+		if idx := obj.attr("Index"); idx != "" {
+			node.Object = strings.TrimSpace(node.Object + " " + idx)
+		}
+	}
+	if pred := findPredicate(e); pred != "" {
+		node.Details["Predicate"] = pred
+	}
+	if len(node.Details) == 0 {
+		node.Details = nil
+	}
+	node.Children = relOpChildren(e)
+	return node
+}
+
+// findObject 查找当前算子直接访问的对象（不进入子 RelOp）
+func findObject(e *xmlElement) *xmlElement {
+	for i := range e.Children {
+		c := &e.Children[i]
+		if c.XMLName.Local == "RelOp" {
+			continue
+		}
+		if c.XMLName.Local == "Object" {
+			return c
+		}
+		if o := findObject(c); o != nil {
+			return o
+		}
+	}
+	return nil
+}
+
+// findPredicate 查找当前算子的谓词文本（ScalarOperator 的 ScalarString）
+func findPredicate(e *xmlElement) string {
+	for i := range e.Children {
+		c := &e.Children[i]
+		if c.XMLName.Local == "RelOp" {
+			continue
+		}
+		if c.XMLName.Local == "Predicate" || c.XMLName.Local == "SeekPredicates" {
+			if s := findScalarString(c); s != "" {
+				return s
+			}
+		}
+		if s := findPredicate(c); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func findScalarString(e *xmlElement) string {
+	if s := e.attr("ScalarString"); s != "" {
+		return s
+	}
+	for i := range e.Children {
+		if s := findScalarString(&e.Children[i]); s != "" {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+package export
+
+import (
+	"dbrun/app/models"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+// csvWriter CSV 写入器，支持自定义分隔符与编码
+type csvWriter struct {
+	w      *csv.Writer
+	closer io.Closer // 编码转换器，需要在结束时刷新
+	header bool
+	record []string
+}
+
+func newCSVWriter(w io.Writer, opts models.ExportOptions) (*csvWriter, error) {
+	comma, err := parseDelimiter(opts.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+	out, closer, err := encodeWriter(w, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	cw := csv.NewWriter(out)
+	cw.Comma = comma
+	return &csvWriter{w: cw, closer: closer, header: !opts.NoHeader}, nil
+}
+
+// parseDelimiter 解析分隔符，支持 \t 与 tab 写法
+func parseDelimiter(s string) (rune, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return ',', nil
+	case `\t`, "tab":
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("invalid csv delimiter: %q", s)
+	}
+	return r, nil
+}
+
+// encodeWriter 按编码名称包装输出；utf-8-bom 会先写入BOM，无法表示的字符以替代符输出
+func encodeWriter(w io.Writer, name string) (io.Writer, io.Closer, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "utf-8", "utf8":
+		return w, nil, nil
+	case "utf-8-bom", "utf8-bom", "utf-8-sig":
+		if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+			return nil, nil, err
+		}
+		return w, nil, nil
+	}
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unsupported encoding: %s", name)
+	}
+	tw := transform.NewWriter(w, encoding.ReplaceUnsupported(enc.NewEncoder()))
+	return tw, tw, nil
+}
+
+func (c *csvWriter) WriteHeader(columns []Column) error {
+	c.record = make([]string, len(columns))
+	if !c.header {
+		return nil
+	}
+	for i, col := range columns {
+		c.record[i] = col.Name
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) WriteRow(values []interface{}) error {
+	if len(c.record) != len(values) {
+		c.record = make([]string, len(values))
+	}
+	for i, v := range values {
+		c.record[i], _ = textValue(v)
+	}
+	return c.w.Write(c.record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+	if c.closer != nil {
+		return c.closer.Close()
+	}
+	return nil
+}
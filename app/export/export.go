@@ -0,0 +1,77 @@
+package export
+
+import (
+	"dbrun/app/dialect"
+	"dbrun/app/models"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 支持的导出格式
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatXLSX   = "xlsx"
+	FormatSQL    = "sql"
+)
+
+// Column 导出列信息
+type Column struct {
+	Name         string
+	DatabaseType string // 驱动返回的类型名，用于判断数值列
+}
+
+// RowWriter 按行写出结果集，实现不得缓存全部数据
+type RowWriter interface {
+	WriteHeader(columns []Column) error
+	WriteRow(values []interface{}) error
+	// Close 刷新缓冲并写出文件尾，不关闭底层 io.Writer
+	Close() error
+}
+
+// WarningWriter 写入过程中有需要提示的数据转换时实现此接口，Close 之后调用
+type WarningWriter interface {
+	Warnings() []string
+}
+
+// NewRowWriter 根据导出选项创建写入器；sourceDialect 为数据来源连接的类型
+func NewRowWriter(w io.Writer, opts models.ExportOptions, sourceDialect string) (RowWriter, error) {
+	switch strings.ToLower(opts.Format) {
+	case FormatCSV:
+		return newCSVWriter(w, opts)
+	case FormatNDJSON, "json", "jsonl":
+		return newNDJSONWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, opts), nil
+	case FormatSQL:
+		name := opts.Dialect
+		if name == "" {
+			name = sourceDialect
+		}
+		d, err := dialect.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(opts.TableName) == "" {
+			return nil, fmt.Errorf("table name is required for sql export")
+		}
+		return newSQLWriter(w, d, opts.TableName, opts.BatchSize), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", opts.Format)
+	}
+}
+
+// DefaultExtension 返回格式对应的文件扩展名
+func DefaultExtension(format string) string {
+	switch strings.ToLower(format) {
+	case FormatNDJSON, "json", "jsonl":
+		return ".ndjson"
+	case FormatXLSX:
+		return ".xlsx"
+	case FormatSQL:
+		return ".sql"
+	default:
+		return ".csv"
+	}
+}
@@ -0,0 +1,94 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ndjsonWriter 每行输出一个 JSON 对象，键顺序与列顺序一致
+type ndjsonWriter struct {
+	w    *bufio.Writer
+	keys [][]byte // 预先编码好的列名
+	buf  bytes.Buffer
+	enc  *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	n := &ndjsonWriter{w: bufio.NewWriterSize(w, 64*1024)}
+	n.enc = json.NewEncoder(&n.buf)
+	n.enc.SetEscapeHTML(false)
+	return n
+}
+
+// encode 编码单个值（不转义HTML字符，去掉 Encoder 追加的换行）
+func (n *ndjsonWriter) encode(v interface{}) ([]byte, error) {
+	n.buf.Reset()
+	if err := n.enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(n.buf.Bytes(), []byte("\n")), nil
+}
+
+func (n *ndjsonWriter) WriteHeader(columns []Column) error {
+	n.keys = make([][]byte, len(columns))
+	for i, col := range columns {
+		b, err := n.encode(col.Name)
+		if err != nil {
+			return err
+		}
+		n.keys[i] = append([]byte(nil), b...)
+	}
+	return nil
+}
+
+func (n *ndjsonWriter) WriteRow(values []interface{}) error {
+	n.w.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			n.w.WriteByte(',')
+		}
+		if i < len(n.keys) {
+			n.w.Write(n.keys[i])
+		} else {
+			n.w.WriteString(`""`)
+		}
+		n.w.WriteByte(':')
+		b, err := n.encode(jsonValue(v))
+		if err != nil {
+			return err
+		}
+		n.w.Write(b)
+	}
+	_, err := n.w.WriteString("}\n")
+	return err
+}
+
+// jsonValue 数值与布尔保持原类型，其余按文本输出
+func jsonValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, bool, int, int32, int64, uint64:
+		return val
+	case float32:
+		return jsonFloat(float64(val))
+	case float64:
+		return jsonFloat(val)
+	}
+	s, _ := textValue(v)
+	return s
+}
+
+// jsonFloat JSON 不支持 NaN 与 Inf，按文本输出
+func jsonFloat(f float64) interface{} {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	return f
+}
+
+func (n *ndjsonWriter) Close() error {
+	return n.w.Flush()
+}
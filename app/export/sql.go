@@ -0,0 +1,128 @@
+package export
+
+import (
+	"bufio"
+	"dbrun/app/dialect"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// 默认每条 INSERT 包含的行数
+const defaultInsertBatch = 100
+
+// sqlWriter 生成目标方言的 INSERT 语句
+type sqlWriter struct {
+	w       *bufio.Writer
+	d       dialect.Dialect
+	table   string
+	prefix  string // INSERT INTO t (cols) VALUES
+	batch   int
+	pending int // 当前语句中已写入的行数
+	columns []Column
+	// nonFinite 按列统计 NaN/Inf 值的个数，用于生成警告
+	nonFinite map[string]int
+}
+
+func newSQLWriter(w io.Writer, d dialect.Dialect, tableName string, batch int) *sqlWriter {
+	if batch <= 0 {
+		batch = defaultInsertBatch
+	}
+	switch d.Name() {
+	case "oracle":
+		// Oracle 不支持多行 VALUES
+		batch = 1
+	case "sqlserver":
+		if batch > 1000 {
+			batch = 1000
+		}
+	}
+	parts := strings.Split(tableName, ".")
+	for i, p := range parts {
+		parts[i] = d.QuoteIdent(strings.TrimSpace(p))
+	}
+	return &sqlWriter{w: bufio.NewWriterSize(w, 64*1024), d: d, table: strings.Join(parts, "."), batch: batch, nonFinite: map[string]int{}}
+}
+
+func (s *sqlWriter) WriteHeader(columns []Column) error {
+	s.columns = columns
+	names := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = s.d.QuoteIdent(c.Name)
+	}
+	s.prefix = "INSERT INTO " + s.table + " (" + strings.Join(names, ", ") + ") VALUES"
+	return nil
+}
+
+func (s *sqlWriter) WriteRow(values []interface{}) error {
+	if s.pending == 0 {
+		s.w.WriteString(s.prefix)
+		s.w.WriteString("\n  (")
+	} else {
+		s.w.WriteString(",\n  (")
+	}
+	for i, v := range values {
+		if i > 0 {
+			s.w.WriteString(", ")
+		}
+		var col Column
+		if i < len(s.columns) {
+			col = s.columns[i]
+		}
+		if text, ok := nonFiniteText(v, col); ok {
+			s.w.WriteString(s.nonFiniteLiteral(text))
+			s.nonFinite[col.Name]++
+		} else if num, ok := numericText(v, col); ok {
+			s.w.WriteString(num)
+		} else {
+			s.w.WriteString(s.d.Literal(v))
+		}
+	}
+	s.w.WriteString(")")
+	s.pending++
+	if s.pending >= s.batch {
+		return s.endStatement()
+	}
+	return nil
+}
+
+// nonFiniteLiteral PostgreSQL 的浮点与 numeric 类型接受 'NaN'、'Infinity' 字面量，其余方言没有对应写法，输出 NULL
+func (s *sqlWriter) nonFiniteLiteral(text string) string {
+	if s.d.Name() == "postgresql" {
+		return s.d.Literal(text)
+	}
+	return "NULL"
+}
+
+// Warnings 列出含 NaN/Inf 值的列及其写法
+func (s *sqlWriter) Warnings() []string {
+	var warnings []string
+	for _, c := range s.columns {
+		n := s.nonFinite[c.Name]
+		if n == 0 {
+			continue
+		}
+		if s.d.Name() == "postgresql" {
+			warnings = append(warnings, fmt.Sprintf("%s: %d NaN/Infinity values written as quoted literals", c.Name, n))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("%s: %d NaN/Infinity values written as NULL, %s has no literal for them", c.Name, n, s.d.Name()))
+		}
+		delete(s.nonFinite, c.Name)
+	}
+	return warnings
+}
+
+func (s *sqlWriter) endStatement() error {
+	s.pending = 0
+	_, err := s.w.WriteString(";\n")
+	return err
+}
+
+func (s *sqlWriter) Close() error {
+	if s.pending > 0 {
+		if err := s.endStatement(); err != nil {
+			return err
+		}
+	}
+	return s.w.Flush()
+}
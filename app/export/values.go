@@ -0,0 +1,102 @@
+package export
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 时间值统一输出格式
+const timeLayout = "2006-01-02 15:04:05.999999999"
+
+// textValue 将驱动值转换为文本；第二个返回值表示是否为 NULL
+func textValue(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case nil:
+		return "", true
+	case []byte:
+		if utf8.Valid(val) {
+			return string(val), false
+		}
+		return "0x" + hex.EncodeToString(val), false
+	case string:
+		return val, false
+	case time.Time:
+		return val.Format(timeLayout), false
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64), false
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32), false
+	default:
+		return fmt.Sprint(val), false
+	}
+}
+
+// isNumericType 根据驱动类型名判断是否为数值列
+func isNumericType(databaseType string) bool {
+	t := strings.ToUpper(databaseType)
+	for _, k := range []string{"INT", "DECIMAL", "NUMERIC", "NUMBER", "FLOAT", "DOUBLE", "REAL", "MONEY", "SERIAL"} {
+		if strings.Contains(t, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// numericText 若值为数值列中的文本（如 DECIMAL 以 []byte 返回），返回其数字文本
+func numericText(v interface{}, col Column) (string, bool) {
+	if !isNumericType(col.DatabaseType) {
+		return "", false
+	}
+	var s string
+	switch val := v.(type) {
+	case []byte:
+		s = string(val)
+	case string:
+		s = val
+	default:
+		return "", false
+	}
+	s = strings.TrimSpace(s)
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	return s, true
+}
+
+// nonFiniteText 若值为 NaN 或 ±Inf（浮点值或数值列中的文本），返回其规范写法 NaN / Infinity / -Infinity
+func nonFiniteText(v interface{}, col Column) (string, bool) {
+	var f float64
+	switch val := v.(type) {
+	case float64:
+		f = val
+	case float32:
+		f = float64(val)
+	case []byte, string:
+		if !isNumericType(col.DatabaseType) {
+			return "", false
+		}
+		s, _ := textValue(val)
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return "", false
+		}
+		f = parsed
+	default:
+		return "", false
+	}
+	switch {
+	case math.IsNaN(f):
+		return "NaN", true
+	case math.IsInf(f, 1):
+		return "Infinity", true
+	case math.IsInf(f, -1):
+		return "-Infinity", true
+	}
+	return "", false
+}
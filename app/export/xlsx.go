@@ -0,0 +1,129 @@
+package export
+
+import (
+	"dbrun/app/models"
+	"dbrun/app/xlsx"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"unicode/utf8"
+)
+
+// xlsxWriter XLSX 写入器，超过单表行数上限时自动续写到新工作表
+type xlsxWriter struct {
+	w         *xlsx.Writer
+	sheetName string
+	sheets    int
+	header    bool
+	columns   []Column
+	cells     []interface{}
+}
+
+func newXLSXWriter(w io.Writer, opts models.ExportOptions) *xlsxWriter {
+	name := opts.SheetName
+	if name == "" {
+		name = "Sheet1"
+	}
+	return &xlsxWriter{w: xlsx.NewWriter(w), sheetName: name, header: !opts.NoHeader}
+}
+
+func (x *xlsxWriter) WriteHeader(columns []Column) error {
+	x.columns = columns
+	return x.startSheet()
+}
+
+func (x *xlsxWriter) startSheet() error {
+	x.sheets++
+	name := x.sheetName
+	if x.sheets > 1 {
+		name = fmt.Sprintf("%s (%d)", x.sheetName, x.sheets)
+	}
+	if err := x.w.NewSheet(name); err != nil {
+		return err
+	}
+	if !x.header {
+		return nil
+	}
+	names := make([]string, len(x.columns))
+	for i, c := range x.columns {
+		names[i] = c.Name
+	}
+	return x.w.WriteHeader(names)
+}
+
+func (x *xlsxWriter) WriteRow(values []interface{}) error {
+	if x.w.Rows() >= xlsx.MaxRows {
+		if err := x.startSheet(); err != nil {
+			return err
+		}
+	}
+	if len(x.cells) != len(values) {
+		x.cells = make([]interface{}, len(values))
+	}
+	for i, v := range values {
+		var col Column
+		if i < len(x.columns) {
+			col = x.columns[i]
+		}
+		x.cells[i] = xlsxValue(v, col)
+	}
+	return x.w.WriteRow(x.cells)
+}
+
+// Excel 数值精度为15位有效数字，超出部分按文本保存以免丢失精度
+const maxExactDigits = 15
+
+// xlsxValue 转换为单元格值：数值列中可精确表示的数字按数值输出，其余按文本
+func xlsxValue(v interface{}, col Column) interface{} {
+	switch val := v.(type) {
+	case nil, bool, float32, float64:
+		return val
+	case int64:
+		if val > 1e15 || val < -1e15 {
+			return strconv.FormatInt(val, 10)
+		}
+		return val
+	case uint64:
+		if val > 1e15 {
+			return strconv.FormatUint(val, 10)
+		}
+		return val
+	case int, int32:
+		return val
+	}
+	if s, ok := numericText(v, col); ok && significantDigits(s) <= maxExactDigits {
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) {
+			return f
+		}
+	}
+	s, _ := textValue(v)
+	// 单元格文本上限为32767个字符
+	if utf8.RuneCountInString(s) > 32767 {
+		s = string([]rune(s)[:32767])
+	}
+	return s
+}
+
+// significantDigits 统计数字文本中的有效数字位数（忽略符号、小数点与前导零）
+func significantDigits(s string) int {
+	n, leading := 0, true
+	for _, r := range s {
+		if r == 'e' || r == 'E' {
+			break
+		}
+		if r < '0' || r > '9' {
+			continue
+		}
+		if leading && r == '0' {
+			continue
+		}
+		leading = false
+		n++
+	}
+	return n
+}
+
+func (x *xlsxWriter) Close() error {
+	return x.w.Close()
+}
@@ -0,0 +1,53 @@
+package models
+
+// CodeGenRequest 代码生成的范围与选项；表与页面可同时指定
+type CodeGenRequest struct {
+	TableIDs  []int64 `json:"tableIds"`
+	PageKey   string  `json:"pageKey"`
+	Language  string  `json:"language"`  // go / typescript / java / kotlin / python / sqlalchemy
+	Package   string  `json:"package"`   // Go、Java、Kotlin 的包名，为空时使用默认值
+	Singular  bool    `json:"singular"`  // 类名取表名的单数形式，如 order_items -> OrderItem
+	Template  string  `json:"template"`  // 仅本次使用的模板，为空时使用项目保存的模板或默认模板
+	OutputDir string  `json:"outputDir"` // 非空时写出文件
+}
+
+// CodeFile 生成的源文件
+type CodeFile struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// CodeGenResult 生成结果；Warnings 列出无法识别的类型、复合主键等需要人工确认的内容
+type CodeGenResult struct {
+	Language  string     `json:"language"`
+	Files     []CodeFile `json:"files"`
+	Tables    int        `json:"tables"`
+	OutputDir string     `json:"outputDir"`
+	Warnings  []string   `json:"warnings"`
+}
+
+// CodeTemplate 语言的代码模板，Custom 表示项目中保存了自定义模板
+type CodeTemplate struct {
+	Language string `json:"language"`
+	Template string `json:"template"`
+	Custom   bool   `json:"custom"`
+}
+
+// SchemaExportRequest 导出 JSON Schema 或 OpenAPI components/schemas；表与页面可同时指定
+type SchemaExportRequest struct {
+	TableIDs  []int64 `json:"tableIds"`
+	PageKey   string  `json:"pageKey"`
+	Format    string  `json:"format"`    // jsonschema：每张表一个文件；openapi：一个 YAML 文档
+	Title     string  `json:"title"`     // OpenAPI 的 info.title，为空时使用默认值
+	Singular  bool    `json:"singular"`  // 结构名取表名的单数形式
+	OutputDir string  `json:"outputDir"` // 非空时写出文件
+}
+
+// SchemaExportResult 导出结果；Warnings 列出无法识别、未限制类型的字段
+type SchemaExportResult struct {
+	Format    string     `json:"format"`
+	Files     []CodeFile `json:"files"`
+	Tables    int        `json:"tables"`
+	OutputDir string     `json:"outputDir"`
+	Warnings  []string   `json:"warnings"`
+}
@@ -0,0 +1,49 @@
+package models
+
+// DDLRequest 生成建表脚本的范围与选项；表、视图与页面可同时指定
+type DDLRequest struct {
+	TableIDs            []int64 `json:"tableIds"`
+	ViewIDs             []int64 `json:"viewIds"`
+	PageKey             string  `json:"pageKey"`             // 非空时包含页面上的全部表与视图
+	Dialect             string  `json:"dialect"`             // 为空时使用对象所属连接的类型
+	Qualify             bool    `json:"qualify"`             // 对象名带库/Schema限定
+	IncludeDependencies bool    `json:"includeDependencies"` // 同时生成外键引用的表与视图引用的对象
+}
+
+// DDLResult 生成的脚本；Warnings 列出被省略的外键、缺少长度的类型等需要人工确认的内容
+type DDLResult struct {
+	Dialect  string   `json:"dialect"`
+	Script   string   `json:"script"`
+	Tables   int      `json:"tables"`
+	Views    int      `json:"views"`
+	Warnings []string `json:"warnings"`
+}
+
+// ConversionRequest 跨方言转换的范围与目标方言，源方言取对象所属连接的类型
+type ConversionRequest struct {
+	TableIDs            []int64 `json:"tableIds"`
+	PageKey             string  `json:"pageKey"`
+	TargetDialect       string  `json:"targetDialect"`
+	Qualify             bool    `json:"qualify"`
+	IncludeDependencies bool    `json:"includeDependencies"`
+}
+
+// ConversionIssue 无法等价转换的类型、默认值或自增列
+type ConversionIssue struct {
+	Table   string `json:"table"`
+	Column  string `json:"column"`
+	Kind    string `json:"kind"` // type / default / identity / index / view
+	Source  string `json:"source"`
+	Target  string `json:"target"`
+	Message string `json:"message"`
+}
+
+// ConversionResult 转换后的目标方言建表脚本与有损转换列表
+type ConversionResult struct {
+	SourceDialect string            `json:"sourceDialect"`
+	TargetDialect string            `json:"targetDialect"`
+	Script        string            `json:"script"`
+	Tables        int               `json:"tables"`
+	Lossy         []ConversionIssue `json:"lossy"`
+	Warnings      []string          `json:"warnings"`
+}
@@ -0,0 +1,119 @@
+package models
+
+// 数据字典输出方式
+const (
+	DictionarySingleFile = "single"    // 单个 Markdown 文件
+	DictionaryPerTable   = "per_table" // 每张表一个文件，另生成 index.md
+)
+
+// DocOptions 文档导出选项
+type DocOptions struct {
+	ConfigID   int64  `json:"configId"`   // 连接ID；为0时由页面上的表推断
+	PageKey    string `json:"pageKey"`    // 非空时仅导出该页面上的表与视图
+	OutputPath string `json:"outputPath"` // 单文件时为文件路径，否则为目录
+	Mode       string `json:"mode"`       // Markdown 输出方式：single / per_table
+	Title      string `json:"title"`
+}
+
+// DocResult 文档导出结果
+type DocResult struct {
+	OutputPath string   `json:"outputPath"`
+	Files      []string `json:"files"` // 写出的文件（相对 OutputPath 所在目录）
+	Tables     int      `json:"tables"`
+	Views      int      `json:"views"`
+}
+
+// 数据字典导入可修改的属性
+const (
+	DictionaryRemark = "remark"
+	DictionaryAlias  = "alias"
+)
+
+// DictionaryImportRequest 数据字典工作簿导入参数，匹配范围与导出时相同
+type DictionaryImportRequest struct {
+	ConfigID  int64  `json:"configId"`
+	PageKey   string `json:"pageKey"`
+	InputPath string `json:"inputPath"`
+}
+
+// DictionaryChange 导入工作簿产生的一项修改；FieldID 为0时修改的是表
+type DictionaryChange struct {
+	TableID   int64  `json:"tableId"`
+	FieldID   int64  `json:"fieldId"`
+	Database  string `json:"database"`
+	Schema    string `json:"schema"`
+	Table     string `json:"table"`
+	Column    string `json:"column"`
+	Attribute string `json:"attribute"` // remark / alias
+	OldValue  string `json:"oldValue"`
+	NewValue  string `json:"newValue"`
+}
+
+// DictionaryImportIssue 工作簿中无法匹配或存在冲突的行
+type DictionaryImportIssue struct {
+	Sheet   string `json:"sheet"`
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// DictionaryImportPreview 导入预览：待提交的修改与被忽略的行
+type DictionaryImportPreview struct {
+	Rows    int                     `json:"rows"`
+	Changes []DictionaryChange      `json:"changes"`
+	Issues  []DictionaryImportIssue `json:"issues"`
+}
+
+// 页面 ER 图的文本格式
+const (
+	DiagramMermaid  = "mermaid"
+	DiagramPlantUML = "plantuml"
+	DiagramDOT      = "dot"
+	DiagramDBML     = "dbml"
+)
+
+// DiagramExportRequest 页面 ER 图的文本导出参数
+type DiagramExportRequest struct {
+	PageKey    string `json:"pageKey"`
+	Format     string `json:"format"`     // mermaid / plantuml / dot / dbml
+	OutputPath string `json:"outputPath"` // 为空时只返回文本，不写文件
+}
+
+// DiagramExportResult 导出的 ER 图文本
+type DiagramExportResult struct {
+	Format        string `json:"format"`
+	Text          string `json:"text"`
+	OutputPath    string `json:"outputPath"`
+	Nodes         int    `json:"nodes"`
+	Relationships int    `json:"relationships"`
+}
+
+// 页面 ER 图的图片格式
+const (
+	DiagramSVG = "svg"
+	DiagramPNG = "png"
+)
+
+// DiagramRenderRequest 页面 ER 图的图片渲染参数；节点使用页面保存的位置，
+// 均未布局（都在原点）或 AutoLayout 为 true 时按 Layout 自动布局，不写回页面
+type DiagramRenderRequest struct {
+	PageKey    string        `json:"pageKey"`
+	Format     string        `json:"format"`     // svg / png
+	Scale      float64       `json:"scale"`      // PNG 每个坐标单位的像素数，默认 2
+	AutoLayout bool          `json:"autoLayout"` // 忽略保存的位置
+	Layout     LayoutOptions `json:"layout"`
+	OutputPath string        `json:"outputPath"` // 为空时只返回内容，不写文件
+}
+
+// DiagramRenderResult 渲染结果，SVG 返回文本，PNG 返回图片数据（JSON 中为 base64）
+type DiagramRenderResult struct {
+	Format        string   `json:"format"`
+	Text          string   `json:"text"`
+	Image         []byte   `json:"image"`
+	OutputPath    string   `json:"outputPath"`
+	Width         float64  `json:"width"` // 图的尺寸（坐标单位），PNG 像素数为其乘以 Scale
+	Height        float64  `json:"height"`
+	Nodes         int      `json:"nodes"`
+	Relationships int      `json:"relationships"`
+	AutoLayout    bool     `json:"autoLayout"` // 是否使用了自动布局
+	Warnings      []string `json:"warnings"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// PlanNode 统一的执行计划节点
+type PlanNode struct {
+	Operator      string            `json:"operator"`          // 算子，如 Seq Scan、Nested Loop、TABLE ACCESS FULL
+	Object        string            `json:"object,omitempty"`  // 访问的对象（表/索引）
+	EstimatedRows float64           `json:"estimatedRows"`     // 估算行数
+	Cost          float64           `json:"cost"`              // 估算代价（各数据库单位不同）
+	Details       map[string]string `json:"details,omitempty"` // 其它原始属性，如过滤条件、使用的索引
+	Children      []*PlanNode       `json:"children,omitempty"`
+}
+
+// ExplainResult 执行计划结果
+type ExplainResult struct {
+	SQL        string    `json:"sql"`
+	Dialect    string    `json:"dialect"`
+	Format     string    `json:"format"` // 原始输出格式：json / xml / text
+	Raw        string    `json:"raw"`    // 数据库返回的原始计划
+	Root       *PlanNode `json:"root"`
+	HistoryID  int64     `json:"historyId"` // 对应的查询历史记录
+	DurationMs int64     `json:"durationMs"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// ExportOptions 导出选项
+type ExportOptions struct {
+	ExportID  string `json:"exportId"`  // 由前端生成，用于关联进度事件与取消
+	FilePath  string `json:"filePath"`  // 目标文件
+	Format    string `json:"format"`    // csv / ndjson / xlsx / sql
+	Delimiter string `json:"delimiter"` // CSV 分隔符，默认逗号，支持 \t
+	Encoding  string `json:"encoding"`  // CSV 编码，默认 utf-8，另支持 utf-8-bom、gbk、gb18030、big5、shift_jis、utf-16le 等
+	NoHeader  bool   `json:"noHeader"`  // CSV/XLSX 不输出表头
+	TableName string `json:"tableName"` // SQL INSERT 的目标表名，可带 schema 前缀
+	Dialect   string `json:"dialect"`   // SQL INSERT 的目标方言，默认与连接一致
+	BatchSize int    `json:"batchSize"` // SQL 每条 INSERT 包含的行数，默认100
+	SheetName string `json:"sheetName"` // XLSX 工作表名称
+}
+
+// ExportProgress 导出进度事件
+type ExportProgress struct {
+	ExportID string `json:"exportId"`
+	Rows     int64  `json:"rows"`  // 已写出行数
+	Bytes    int64  `json:"bytes"` // 已写出字节数
+	Done     bool   `json:"done"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ExportResult 导出结果；Warnings 列出 NaN/Inf 等无法原样写出的值
+type ExportResult struct {
+	ExportID   string   `json:"exportId"`
+	FilePath   string   `json:"filePath"`
+	Format     string   `json:"format"`
+	Rows       int64    `json:"rows"`
+	Bytes      int64    `json:"bytes"`
+	DurationMs int64    `json:"durationMs"`
+	Warnings   []string `json:"warnings"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// 布局算法
+const (
+	LayoutLayered = "layered" // 分层（Sugiyama）布局
+	LayoutForce   = "force"   // 力导向布局
+)
+
+// LayoutOptions 自动布局参数，零值使用默认值
+type LayoutOptions struct {
+	Algorithm   string  `json:"algorithm"`   // layered / force
+	Direction   string  `json:"direction"`   // 分层方向：LR / RL / TB / BT，默认 LR
+	NodeSpacing float64 `json:"nodeSpacing"` // 同层节点间距
+	RankSpacing float64 `json:"rankSpacing"` // 层间距（力导向布局中为理想连线长度）
+	Iterations  int     `json:"iterations"`  // 交叉优化或力导向迭代次数
+	Save        bool    `json:"save"`        // 是否写回页面节点位置
+}
+
+// LayoutNode 布局后的节点位置（左上角坐标）
+type LayoutNode struct {
+	Key    string  `json:"key"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// LayoutResult 布局结果
+type LayoutResult struct {
+	Algorithm string       `json:"algorithm"`
+	Nodes     []LayoutNode `json:"nodes"`
+	Width     float64      `json:"width"`  // 布局整体宽度
+	Height    float64      `json:"height"` // 布局整体高度
+}
@@ -0,0 +1,27 @@
+package models
+
+// LineageNode 血缘图中的字段节点
+type LineageNode struct {
+	ID       string `json:"id"`       // 节点键：kind:objectId:column
+	Kind     string `json:"kind"`     // table / view
+	ObjectID int64  `json:"objectId"` // 原始表或视图ID
+	Object   string `json:"object"`   // 表或视图名称（含 Schema）
+	Database string `json:"database"`
+	Column   string `json:"column"`
+	Depth    int    `json:"depth"` // 距起始字段的层数
+}
+
+// LineageEdge 血缘边：From 为来源字段，To 为视图字段
+type LineageEdge struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	Transform  string `json:"transform"`
+	Expression string `json:"expression"`
+}
+
+// LineageGraph 从起始字段出发的上游或下游血缘
+type LineageGraph struct {
+	Root  string        `json:"root"`
+	Nodes []LineageNode `json:"nodes"`
+	Edges []LineageEdge `json:"edges"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import "time"
+
+// PageStyle 页面级样式
+type PageStyle struct {
+	Background string  `json:"background,omitempty"` // 画布背景色
+	EdgeType   string  `json:"edgeType,omitempty"`   // 连线样式：button / smoothstep / straight 等
+	ShowGrid   bool    `json:"showGrid"`
+	Zoom       float64 `json:"zoom,omitempty"`
+	ViewportX  float64 `json:"viewportX,omitempty"`
+	ViewportY  float64 `json:"viewportY,omitempty"`
+}
+
+// NodeStyle 节点级样式
+type NodeStyle struct {
+	Color     string `json:"color,omitempty"`     // 表头颜色
+	Collapsed bool   `json:"collapsed,omitempty"` // 仅显示表名
+}
+
+// PageVO 页面树节点，分组包含子节点
+type PageVO struct {
+	Key       string    `json:"key"`
+	ParentKey string    `json:"parentKey"` // 为空表示根级
+	Type      string    `json:"type"`      // page / group
+	Label     string    `json:"label"`
+	Icon      string    `json:"icon"`
+	Expanded  bool      `json:"expanded"`
+	Style     PageStyle `json:"style"`
+	NodeCount int       `json:"nodeCount"`
+	Children  []PageVO  `json:"children"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// PageNodeVO 页面上的表或视图节点
+type PageNodeVO struct {
+	Key          string    `json:"key"`        // 前端节点ID
+	ObjectKind   string    `json:"objectKind"` // table / view
+	ObjectID     int64     `json:"objectId"`
+	ObjectName   string    `json:"objectName"`
+	DatabaseID   int64     `json:"databaseId"`
+	DatabaseName string    `json:"databaseName"`
+	SchemaName   string    `json:"schemaName"`
+	X            float64   `json:"x"`
+	Y            float64   `json:"y"`
+	Width        float64   `json:"width"`
+	Height       float64   `json:"height"`
+	Style        NodeStyle `json:"style"`
+	Missing      bool      `json:"missing"` // 引用的表或视图已不存在
+}
+
+// PageContent 页面完整内容：节点与关系
+type PageContent struct {
+	Page          PageVO           `json:"page"`
+	Nodes         []PageNodeVO     `json:"nodes"`
+	Relationships []RelationshipVO `json:"relationships"`
+}
+
+// PageTabState 打开的页面标签
+type PageTabState struct {
+	OpenTabs  []PageTab `json:"openTabs"`
+	ActiveTab string    `json:"activeTab"`
+}
+
+// PageTab 单个页面标签
+type PageTab struct {
+	Key    string `json:"key"`
+	Label  string `json:"label"`
+	Active bool   `json:"active"`
+}
+
+// PageIssue 页面校验发现的问题
+type PageIssue struct {
+	PageKey        string `json:"pageKey"`
+	PageLabel      string `json:"pageLabel"`
+	Kind           string `json:"kind"`    // missing_object / broken_relationship / dangling_relationship
+	NodeKey        string `json:"nodeKey"` // 相关节点
+	RelationshipID int64  `json:"relationshipId"`
+	Message        string `json:"message"`
+}
+
+// PageSearchHit 页面搜索结果
+type PageSearchHit struct {
+	PageKey    string `json:"pageKey"`
+	PageLabel  string `json:"pageLabel"`
+	Path       string `json:"path"`  // 分组路径，如 "业务/订单"
+	Match      string `json:"match"` // page / table / view
+	NodeKey    string `json:"nodeKey"`
+	ObjectName string `json:"objectName"`
+}
+
+// PageImportResult 旧版页面缓存导入结果
+type PageImportResult struct {
+	Pages   int      `json:"pages"`
+	Groups  int      `json:"groups"`
+	Nodes   int      `json:"nodes"`
+	Skipped int      `json:"skipped"` // 已存在的页面
+	Failed  []string `json:"failed"`
+}
+
+// 邻域遍历方向
+const (
+	NeighbourhoodOut  = "out"  // 沿引用方向：当前表引用的表
+	NeighbourhoodIn   = "in"   // 逆引用方向：引用当前表的表
+	NeighbourhoodBoth = "both" // 两个方向
+)
+
+// NeighbourhoodRequest 按表的关系邻域生成页面
+type NeighbourhoodRequest struct {
+	TableID   int64         `json:"tableId"`   // 起始表
+	Depth     int           `json:"depth"`     // 跳数，默认 1
+	Direction string        `json:"direction"` // out / in / both，默认 both
+	Exclude   []string      `json:"exclude"`   // 排除的表名通配符，如 "*_log"、"audit_*"，同时匹配表名与 schema.表名（忽略大小写）
+	MaxTables int           `json:"maxTables"` // 表数量上限，默认 200
+	Label     string        `json:"label"`     // 页面名称，默认为起始表名
+	ParentKey string        `json:"parentKey"` // 所属分组
+	Layout    LayoutOptions `json:"layout"`
+}
+
+// NeighbourhoodResult 生成的页面及被排除或截断的表
+type NeighbourhoodResult struct {
+	Content   PageContent `json:"content"`
+	Excluded  []string    `json:"excluded"`  // 按通配符排除的表
+	Truncated bool        `json:"truncated"` // 达到表数量上限后停止遍历
+}
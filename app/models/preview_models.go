@@ -0,0 +1,44 @@
+package models
+
+// ColumnFilter 预览数据的列过滤条件
+// Operator 支持：=、!=、>、>=、<、<=、like、not like、contains、starts_with、ends_with、
+// in、not in、between、is null、is not null
+type ColumnFilter struct {
+	Column   string        `json:"column"`
+	Operator string        `json:"operator"`
+	Value    interface{}   `json:"value"`
+	Values   []interface{} `json:"values"` // in/not in/between 使用
+}
+
+// SortOrder 排序条件
+type SortOrder struct {
+	Column string `json:"column"`
+	Desc   bool   `json:"desc"`
+}
+
+// PageRequest 分页参数，Page 从1开始
+type PageRequest struct {
+	Page     int `json:"page"`
+	PageSize int `json:"pageSize"`
+}
+
+// PreviewColumn 预览结果列，包含元数据类型与驱动类型
+type PreviewColumn struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`         // 元数据中记录的字段类型
+	DatabaseType string `json:"databaseType"` // 驱动返回的类型名
+	Nullable     bool   `json:"nullable"`
+	Key          string `json:"key"`
+}
+
+// TablePreview 表数据预览结果
+type TablePreview struct {
+	TableID    int64           `json:"tableId"`
+	Columns    []PreviewColumn `json:"columns"`
+	Rows       [][]interface{} `json:"rows"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	HasMore    bool            `json:"hasMore"`
+	SQL        string          `json:"sql"`
+	DurationMs int64           `json:"durationMs"`
+}
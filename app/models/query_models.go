@@ -0,0 +1,20 @@
+package models
+
+// QueryColumn 查询结果列信息
+type QueryColumn struct {
+	Name         string `json:"name"`
+	DatabaseType string `json:"databaseType"` // 驱动返回的数据库类型名
+	Nullable     *bool  `json:"nullable,omitempty"`
+}
+
+// QueryResult 单条语句的执行结果
+type QueryResult struct {
+	SQL          string          `json:"sql"`
+	Kind         string          `json:"kind"` // 语句类别，见 sqlparse.StatementKind
+	Columns      []QueryColumn   `json:"columns"`
+	Rows         [][]interface{} `json:"rows"`
+	RowCount     int             `json:"rowCount"`
+	RowsAffected int64           `json:"rowsAffected"`
+	Truncated    bool            `json:"truncated"` // 结果行数超过上限被截断
+	DurationMs   int64           `json:"durationMs"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import "time"
+
+// RelationshipEvidence 推断关系的依据
+type RelationshipEvidence struct {
+	Kind       string `json:"kind"`       // view / query
+	ID         int64  `json:"id"`         // 视图ID或查询历史ID
+	Name       string `json:"name"`       // 视图名称或查询语句摘要
+	Expression string `json:"expression"` // 连接条件原文
+	Implicit   bool   `json:"implicit"`   // 来自 WHERE 中的隐式连接
+}
+
+// RelationshipCandidate 推断出的候选关系：From 为引用方（多），To 为被引用方（一）
+type RelationshipCandidate struct {
+	Key          string                 `json:"key"`
+	FromTableID  int64                  `json:"fromTableId"`
+	FromTable    string                 `json:"fromTable"`
+	FromColumn   string                 `json:"fromColumn"`
+	ToTableID    int64                  `json:"toTableId"`
+	ToTable      string                 `json:"toTable"`
+	ToColumn     string                 `json:"toColumn"`
+	Confidence   float64                `json:"confidence"`   // 0~1
+	TypeMismatch bool                   `json:"typeMismatch"` // 两侧字段类型不兼容
+	Evidence     []RelationshipEvidence `json:"evidence"`
+}
+
+// NamingRules 按命名约定推断关系的规则，按项目保存
+type NamingRules struct {
+	// 外键列名模式，{table} 为被引用表名（单复数均可），{pk} 为被引用表主键名；
+	// 驼峰列名会先转为下划线形式再匹配，如 customerId -> customer_id
+	ForeignKeyPatterns []string `json:"foreignKeyPatterns"`
+	PrimaryKeyNames    []string `json:"primaryKeyNames"` // 无主键标记时视为主键的列名，支持 {table}
+	TablePrefixes      []string `json:"tablePrefixes"`   // 匹配前从表名去除的前缀，如 tbl_、t_
+	Pluralization      bool     `json:"pluralization"`   // 表名单复数视为相同
+	RoleNames          bool     `json:"roleNames"`       // 允许带角色前缀的列名，如 billing_customer_id -> customer
+	CrossSchema        bool     `json:"crossSchema"`     // 允许匹配其他 Schema 的表（同 Schema 优先）
+	RequireTypeMatch   bool     `json:"requireTypeMatch"`
+	MinConfidence      float64  `json:"minConfidence"`
+}
+
+// 关系两端的基数，与前端 RelationshipType 一致
+const (
+	CardinalityOne  = "one"
+	CardinalityMany = "many"
+	CardinalityNone = "none"
+)
+
+// RelationshipCheckRequest 待校验的关系：Source 的列引用 Target 的列，Declared* 为连线上声明的两端基数
+type RelationshipCheckRequest struct {
+	SourceTableID  int64    `json:"sourceTableId"`
+	SourceColumns  []string `json:"sourceColumns"`
+	TargetTableID  int64    `json:"targetTableId"`
+	TargetColumns  []string `json:"targetColumns"`
+	DeclaredSource string   `json:"declaredSource"` // one / many / none，为空或 none 时不比较
+	DeclaredTarget string   `json:"declaredTarget"`
+	TimeoutSeconds int      `json:"timeoutSeconds"` // 单条统计语句的超时，0 使用默认值
+}
+
+// RelationshipCheckResult 基于真实数据的关系校验结果
+type RelationshipCheckResult struct {
+	Request              RelationshipCheckRequest `json:"request"`
+	SourceRows           int64                    `json:"sourceRows"`           // 来源表总行数
+	NullRows             int64                    `json:"nullRows"`             // 引用列为空的行数
+	NullRate             float64                  `json:"nullRate"`             // NullRows / SourceRows
+	OrphanRows           int64                    `json:"orphanRows"`           // 引用列非空但在目标表中找不到的行数
+	OrphanRate           float64                  `json:"orphanRate"`           // OrphanRows / 非空行数
+	MaxChildrenPerParent int64                    `json:"maxChildrenPerParent"` // 同一引用值在来源表中的最大行数
+	MaxTargetRowsPerKey  int64                    `json:"maxTargetRowsPerKey"`  // 同一键值在目标表中的最大行数
+	ObservedSource       string                   `json:"observedSource"`       // 数据体现的来源端基数
+	ObservedTarget       string                   `json:"observedTarget"`       // 数据体现的目标端基数
+	Mismatch             bool                     `json:"mismatch"`             // 声明的基数与数据不符
+	Issues               []string                 `json:"issues"`
+	SQL                  []string                 `json:"sql"` // 执行的统计语句
+	DurationMs           int64                    `json:"durationMs"`
+}
+
+// RelationshipColumnVO 关系中的一对字段；新增或更新时字段ID为0则按名称查找
+type RelationshipColumnVO struct {
+	SourceFieldID int64  `json:"sourceFieldId"`
+	SourceField   string `json:"sourceField"`
+	TargetFieldID int64  `json:"targetFieldId"`
+	TargetField   string `json:"targetField"`
+}
+
+// RelationshipVO 保存在项目中的表关系
+type RelationshipVO struct {
+	ID                int64                  `json:"id"`
+	PageID            string                 `json:"pageId"`
+	SourceTableID     int64                  `json:"sourceTableId"`
+	SourceTable       string                 `json:"sourceTable"`
+	TargetTableID     int64                  `json:"targetTableId"`
+	TargetTable       string                 `json:"targetTable"`
+	Columns           []RelationshipColumnVO `json:"columns"`
+	SourceCardinality string                 `json:"sourceCardinality"` // one / many / none
+	TargetCardinality string                 `json:"targetCardinality"`
+	Label             string                 `json:"label"`
+	Notes             string                 `json:"notes"`
+	Origin            string                 `json:"origin"`
+	Broken            bool                   `json:"broken"` // 引用的表或字段已不存在
+	UpdatedAt         time.Time              `json:"updatedAt"`
+}
+
+// RelationshipImportResult 从页面连线导入关系的结果
+type RelationshipImportResult struct {
+	Pages    int      `json:"pages"`
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"` // 已导入过的连线
+	Failed   []string `json:"failed"`  // 无法解析的连线及原因
+}
@@ -0,0 +1,56 @@
+package models
+
+// SchemaFileImportRequest 导入 DBML / Prisma 设计文件为虚拟连接
+type SchemaFileImportRequest struct {
+	Path     string `json:"path"`
+	Label    string `json:"label"`    // 连接名称，为空时使用文件中的项目名或文件名
+	Dialect  string `json:"dialect"`  // 覆盖文件中声明的数据库类型
+	ConfigID int64  `json:"configId"` // 非0时重新导入到已有的虚拟连接，按名称更新表与字段
+}
+
+// SchemaFileImportResult 导入结果；Skipped 列出无法对应到表或字段的引用
+type SchemaFileImportResult struct {
+	ConfigID      int64    `json:"configId"`
+	Format        string   `json:"format"`
+	Dialect       string   `json:"dialect"`
+	Tables        int      `json:"tables"`
+	Columns       int      `json:"columns"`
+	Relationships int      `json:"relationships"` // 新建的关系数，已存在的不重复创建
+	Skipped       []string `json:"skipped"`
+	Warnings      []string `json:"warnings"`
+}
+
+// 结构差异的类别
+const (
+	SchemaDiffMissingTable  = "missing_table"  // 只在设计中存在
+	SchemaDiffExtraTable    = "extra_table"    // 只在数据库中存在
+	SchemaDiffMissingColumn = "missing_column" // 只在设计中存在
+	SchemaDiffExtraColumn   = "extra_column"   // 只在数据库中存在
+	SchemaDiffType          = "type"
+	SchemaDiffNullable      = "nullable"
+	SchemaDiffPrimaryKey    = "primary_key"
+)
+
+// SchemaDiffRequest 比较两个连接的表结构，通常 Source 为设计文件导入的虚拟连接，Target 为真实数据库
+type SchemaDiffRequest struct {
+	SourceConfigID int64 `json:"sourceConfigId"`
+	TargetConfigID int64 `json:"targetConfigId"`
+}
+
+// SchemaDiffItem 一处差异，Source / Target 为两侧的写法
+type SchemaDiffItem struct {
+	Kind   string `json:"kind"`
+	Table  string `json:"table"`
+	Column string `json:"column"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// SchemaDiffResult 表与字段按名称匹配（忽略大小写）后的差异
+type SchemaDiffResult struct {
+	SourceDialect string           `json:"sourceDialect"`
+	TargetDialect string           `json:"targetDialect"`
+	Matched       int              `json:"matched"` // 两侧都存在的表数
+	Items         []SchemaDiffItem `json:"items"`
+	Warnings      []string         `json:"warnings"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// ViewParseResult 视图定义的解析结果，部分内容无法解析时 Warnings 说明原因
+type ViewParseResult struct {
+	ViewID   int64         `json:"viewId"`
+	Dialect  string        `json:"dialect"`
+	Fields   []FieldInfoVO `json:"fields"`
+	Warnings []string      `json:"warnings"`
+}
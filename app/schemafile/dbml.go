@@ -0,0 +1,603 @@
+package schemafile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseDBML 解析 DBML（dbdiagram.io）：Project、Table（含 indexes 与 Note）、Ref 与 Enum；
+// TableGroup、独立 Note 与 Records 等与结构无关的块将被跳过
+func ParseDBML(src string) (File, error) {
+	f := File{Format: FormatDBML}
+	toks, err := lex(src, false)
+	if err != nil {
+		return f, err
+	}
+	// DBML 没有文档注释，/// 按普通注释处理
+	kept := toks[:0]
+	for _, t := range toks {
+		if t.kind != tokDoc {
+			kept = append(kept, t)
+		}
+	}
+	p := &dbmlParser{parser: parser{toks: kept}, file: &f, enums: map[string][]string{}, aliases: map[string]TableName{}}
+	if err := p.parse(); err != nil {
+		return f, err
+	}
+	// Ref 可以通过 Table x as X 定义的别名引用表，别名可能在表定义之前使用
+	for i := range f.Refs {
+		f.Refs[i].From = p.resolveAlias(f.Refs[i].From)
+		f.Refs[i].To = p.resolveAlias(f.Refs[i].To)
+	}
+	// Enum 可以定义在使用它的表之后，MySQL 中改写为 enum(...) 类型
+	if f.Dialect == "mysql" || f.Dialect == "mariadb" {
+		for i := range f.Tables {
+			for j, c := range f.Tables[i].Columns {
+				if values, ok := p.enums[strings.ToLower(c.Type)]; ok {
+					f.Tables[i].Columns[j].Type = enumType(values)
+				}
+			}
+		}
+	}
+	return f, nil
+}
+
+type dbmlParser struct {
+	parser
+	file    *File
+	enums   map[string][]string
+	aliases map[string]TableName // Table 的 as 别名
+}
+
+// resolveAlias 将不带 schema 的别名替换为对应的表名
+func (p *dbmlParser) resolveAlias(name TableName) TableName {
+	if name.Schema != "" {
+		return name
+	}
+	if t, ok := p.aliases[name.Name]; ok {
+		return t
+	}
+	return name
+}
+
+func (p *dbmlParser) parse() error {
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		if t.kind == tokEOF {
+			return nil
+		}
+		if t.kind != tokWord {
+			return fmt.Errorf("line %d: unexpected %q", t.line, t.text)
+		}
+		var err error
+		switch strings.ToLower(t.text) {
+		case "project":
+			err = p.project()
+		case "table":
+			err = p.table()
+		case "ref":
+			err = p.ref()
+		case "enum":
+			err = p.enum()
+		default:
+			p.file.Warnings = append(p.file.Warnings, fmt.Sprintf("line %d: %s block skipped", t.line, t.text))
+			err = p.skipBlock()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// project Project name { database_type: 'PostgreSQL'  Note: '...' }
+func (p *dbmlParser) project() error {
+	p.next()
+	if p.peek().kind == tokWord || p.peek().kind == tokQuoted {
+		p.file.Name = p.next().text
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.accept("}") {
+			return nil
+		}
+		key, err := p.name()
+		if err != nil {
+			return err
+		}
+		switch strings.ToLower(key) {
+		case "database_type":
+			if err := p.expect(":"); err != nil {
+				return err
+			}
+			p.file.Dialect = dialectName(p.next().text)
+		case "note":
+			note, err := p.note()
+			if err != nil {
+				return err
+			}
+			p.file.Note = note
+		default:
+			p.skipLine()
+		}
+	}
+}
+
+// note Note: '...' 或 Note { '...' }
+func (p *dbmlParser) note() (string, error) {
+	if p.accept(":") {
+		return p.next().text, nil
+	}
+	if err := p.expect("{"); err != nil {
+		return "", err
+	}
+	p.skipNewlines()
+	text := p.next().text
+	p.skipNewlines()
+	return text, p.expect("}")
+}
+
+// skipLine 跳过当前行剩余内容（含其中的方括号设置）
+func (p *dbmlParser) skipLine() {
+	depth := 0
+	for {
+		t := p.peek()
+		if t.kind == tokEOF || (t.kind == tokNewline && depth == 0) || (depth == 0 && t.kind == tokPunct && t.text == "}") {
+			return
+		}
+		if t.kind == tokPunct && (t.text == "[" || t.text == "(" || t.text == "{") {
+			depth++
+		} else if t.kind == tokPunct && (t.text == "]" || t.text == ")" || t.text == "}") {
+			depth--
+		}
+		p.next()
+	}
+}
+
+// qualifiedName schema.name 或 name
+func (p *dbmlParser) qualifiedName() (TableName, error) {
+	first, err := p.name()
+	if err != nil {
+		return TableName{}, err
+	}
+	if p.accept(".") {
+		second, err := p.name()
+		if err != nil {
+			return TableName{}, err
+		}
+		return TableName{Schema: first, Name: second}, nil
+	}
+	return TableName{Name: first}, nil
+}
+
+// settings 读取 [a, b: c, ...]，每项为词法单元序列
+func (p *dbmlParser) settings() ([][]token, error) {
+	var items [][]token
+	if !p.accept("[") {
+		return nil, nil
+	}
+	var cur []token
+	depth := 0
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return nil, fmt.Errorf("line %d: unterminated settings", t.line)
+		case t.kind == tokNewline:
+			continue
+		case t.kind == tokPunct && (t.text == "(" || t.text == "["):
+			depth++
+		case t.kind == tokPunct && t.text == ")":
+			depth--
+		case t.kind == tokPunct && t.text == "]":
+			if depth == 0 {
+				if len(cur) > 0 {
+					items = append(items, cur)
+				}
+				return items, nil
+			}
+			depth--
+		case t.kind == tokPunct && t.text == "," && depth == 0:
+			if len(cur) > 0 {
+				items = append(items, cur)
+			}
+			cur = nil
+			continue
+		}
+		cur = append(cur, t)
+	}
+}
+
+// settingKey 设置项的名称（小写）与冒号后的值
+func settingKey(item []token) (string, []token) {
+	for i, t := range item {
+		if t.kind == tokPunct && t.text == ":" {
+			var words []string
+			for _, w := range item[:i] {
+				words = append(words, strings.ToLower(w.text))
+			}
+			return strings.Join(words, " "), item[i+1:]
+		}
+	}
+	var words []string
+	for _, w := range item {
+		words = append(words, strings.ToLower(w.text))
+	}
+	return strings.Join(words, " "), nil
+}
+
+// table Table schema.name [as alias] [settings] { 字段 / indexes / Note }
+func (p *dbmlParser) table() error {
+	p.next()
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	t := Table{Schema: name.Schema, Name: name.Name}
+	if p.peek().kind == tokWord && strings.EqualFold(p.peek().text, "as") {
+		p.next()
+		alias, err := p.name()
+		if err != nil {
+			return err
+		}
+		p.aliases[alias] = name
+	}
+	items, err := p.settings()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if key, value := settingKey(item); key == "note" && len(value) > 0 {
+			t.Note = value[0].text
+		}
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.accept("}") {
+			break
+		}
+		head := p.peek()
+		switch {
+		case head.kind == tokWord && strings.EqualFold(head.text, "note") && (p.peekAt(1).text == ":" || p.peekAt(1).text == "{"):
+			p.next()
+			if t.Note, err = p.note(); err != nil {
+				return err
+			}
+		case head.kind == tokWord && strings.EqualFold(head.text, "indexes") && p.peekAt(1).text == "{":
+			p.next()
+			if err := p.indexes(&t); err != nil {
+				return err
+			}
+		case head.kind == tokPunct && head.text == "~":
+			// 引用 TablePartial，字段无法展开
+			p.file.Warnings = append(p.file.Warnings, fmt.Sprintf("line %d: table partial in %s skipped", head.line, t.Name))
+			p.skipLine()
+		default:
+			if err := p.column(&t); err != nil {
+				return err
+			}
+		}
+	}
+	p.file.Tables = append(p.file.Tables, t)
+	return nil
+}
+
+// column name type [settings]
+func (p *dbmlParser) column(t *Table) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	c := Column{Name: name, Nullable: true}
+	var typ strings.Builder
+	var last token
+	for {
+		tok := p.peek()
+		if tok.kind == tokEOF || tok.kind == tokNewline || (tok.kind == tokPunct && (tok.text == "[" || tok.text == "}")) {
+			break
+		}
+		p.next()
+		if tok.kind == tokWord && last.kind == tokWord && typ.Len() > 0 {
+			typ.WriteString(" ")
+		}
+		typ.WriteString(tok.text)
+		last = tok
+	}
+	c.Type = typ.String()
+	if c.Type == "" {
+		return fmt.Errorf("line %d: column %s.%s has no type", p.peek().line, t.Name, name)
+	}
+	items, err := p.settings()
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		key, value := settingKey(item)
+		switch key {
+		case "pk", "primary key":
+			c.PrimaryKey, c.Nullable = true, false
+		case "not null":
+			c.Nullable = false
+		case "null":
+			c.Nullable = true
+		case "unique":
+			c.Unique = true
+		case "increment":
+			c.Increment = true
+		case "note":
+			if len(value) > 0 {
+				c.Note = value[0].text
+			}
+		case "default":
+			c.Default = defaultValue(value)
+		case "ref":
+			r, err := p.inlineRef(TableName{Schema: t.Schema, Name: t.Name}, c.Name, value)
+			if err != nil {
+				return err
+			}
+			p.file.Refs = append(p.file.Refs, r)
+		}
+	}
+	t.Columns = append(t.Columns, c)
+	return nil
+}
+
+// defaultValue 将 DBML 默认值写为 SQL 表达式：字符串加引号，反引号表达式原样输出
+func defaultValue(value []token) string {
+	var b strings.Builder
+	for _, t := range value {
+		switch t.kind {
+		case tokString:
+			b.WriteString("'" + strings.ReplaceAll(t.text, "'", "''") + "'")
+		default:
+			b.WriteString(t.text)
+		}
+	}
+	return b.String()
+}
+
+// inlineRef 字段设置中的 ref: > table.column
+func (p *dbmlParser) inlineRef(from TableName, column string, value []token) (Ref, error) {
+	if len(value) < 2 || value[0].kind != tokPunct {
+		return Ref{}, fmt.Errorf("line %d: invalid inline ref on %s.%s", p.peek().line, from.Name, column)
+	}
+	r := Ref{From: from, FromColumns: []string{column}}
+	var parts []string
+	for _, t := range value[1:] {
+		if t.kind == tokWord || t.kind == tokQuoted {
+			parts = append(parts, t.text)
+		}
+	}
+	if len(parts) < 2 {
+		return Ref{}, fmt.Errorf("line %d: invalid inline ref on %s.%s", value[0].line, from.Name, column)
+	}
+	r.ToColumns = []string{parts[len(parts)-1]}
+	r.To = TableName{Name: parts[len(parts)-2]}
+	if len(parts) > 2 {
+		r.To.Schema = parts[len(parts)-3]
+	}
+	setRefOp(&r, value[0].text)
+	return r, nil
+}
+
+// setRefOp 按关系符号设置两端基数：> 多对一，< 一对多，- 一对一，<> 多对多；
+// < 的引用方在右侧，交换两端使 From 始终为引用方
+func setRefOp(r *Ref, op string) {
+	switch op {
+	case ">":
+		r.FromCardinality, r.ToCardinality = Many, One
+	case "<":
+		r.From, r.To = r.To, r.From
+		r.FromColumns, r.ToColumns = r.ToColumns, r.FromColumns
+		r.FromCardinality, r.ToCardinality = Many, One
+	case "<>":
+		r.FromCardinality, r.ToCardinality = Many, Many
+	default:
+		r.FromCardinality, r.ToCardinality = One, One
+	}
+}
+
+// indexes indexes { col [settings]  (a, b) [unique]  `expr` }
+func (p *dbmlParser) indexes(t *Table) error {
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.accept("}") {
+			return nil
+		}
+		var cols []string
+		expression := false
+		switch {
+		case p.accept("("):
+			for !p.accept(")") {
+				tok := p.next()
+				switch tok.kind {
+				case tokEOF:
+					return fmt.Errorf("line %d: unterminated index", tok.line)
+				case tokWord, tokQuoted:
+					cols = append(cols, tok.text)
+				case tokExpr:
+					expression = true
+				}
+			}
+		case p.peek().kind == tokExpr:
+			p.next()
+			expression = true
+		default:
+			name, err := p.name()
+			if err != nil {
+				return err
+			}
+			cols = append(cols, name)
+		}
+		items, err := p.settings()
+		if err != nil {
+			return err
+		}
+		pk, unique := false, false
+		for _, item := range items {
+			switch key, _ := settingKey(item); key {
+			case "pk", "primary key":
+				pk = true
+			case "unique":
+				unique = true
+			}
+		}
+		if expression && len(cols) == 0 {
+			continue
+		}
+		for i := range t.Columns {
+			for _, name := range cols {
+				if !strings.EqualFold(t.Columns[i].Name, name) {
+					continue
+				}
+				switch {
+				case pk:
+					t.Columns[i].PrimaryKey, t.Columns[i].Nullable = true, false
+				case unique && len(cols) == 1:
+					t.Columns[i].Unique = true
+				default:
+					t.Columns[i].Indexed = true
+				}
+			}
+		}
+	}
+}
+
+// ref Ref name: a.b > c.d [settings] 或 Ref name { ... }
+func (p *dbmlParser) ref() error {
+	p.next()
+	name := ""
+	if p.peek().kind == tokWord || p.peek().kind == tokQuoted {
+		name = p.next().text
+	}
+	if p.accept(":") {
+		return p.refLine(name)
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.accept("}") {
+			return nil
+		}
+		if err := p.refLine(name); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dbmlParser) refLine(name string) error {
+	from, fromCols, err := p.endpoint()
+	if err != nil {
+		return err
+	}
+	op := p.next()
+	if op.kind != tokPunct {
+		return fmt.Errorf("line %d: expected a relationship operator, found %q", op.line, op.text)
+	}
+	to, toCols, err := p.endpoint()
+	if err != nil {
+		return err
+	}
+	if _, err := p.settings(); err != nil {
+		return err
+	}
+	if len(fromCols) != len(toCols) {
+		return fmt.Errorf("line %d: ref %s -> %s has mismatched columns", op.line, from, to)
+	}
+	r := Ref{Name: name, From: from, FromColumns: fromCols, To: to, ToColumns: toCols}
+	setRefOp(&r, op.text)
+	p.file.Refs = append(p.file.Refs, r)
+	return nil
+}
+
+// endpoint schema.table.column、table.column 或 table.(a, b)
+func (p *dbmlParser) endpoint() (TableName, []string, error) {
+	var parts []string
+	for {
+		if p.accept("(") {
+			var cols []string
+			for !p.accept(")") {
+				tok := p.next()
+				switch tok.kind {
+				case tokEOF, tokNewline:
+					return TableName{}, nil, fmt.Errorf("line %d: unterminated column list", tok.line)
+				case tokWord, tokQuoted:
+					cols = append(cols, tok.text)
+				}
+			}
+			if len(parts) == 0 {
+				return TableName{}, nil, fmt.Errorf("line %d: ref endpoint has no table", p.peek().line)
+			}
+			return endpointTable(parts), cols, nil
+		}
+		name, err := p.name()
+		if err != nil {
+			return TableName{}, nil, err
+		}
+		parts = append(parts, name)
+		if !p.accept(".") {
+			break
+		}
+	}
+	if len(parts) < 2 {
+		return TableName{}, nil, fmt.Errorf("line %d: ref endpoint %s has no column", p.peek().line, strings.Join(parts, "."))
+	}
+	return endpointTable(parts[:len(parts)-1]), parts[len(parts)-1:], nil
+}
+
+func endpointTable(parts []string) TableName {
+	if len(parts) == 1 {
+		return TableName{Name: parts[0]}
+	}
+	return TableName{Schema: parts[len(parts)-2], Name: parts[len(parts)-1]}
+}
+
+// enum Enum name { value [note: '...'] }
+func (p *dbmlParser) enum() error {
+	p.next()
+	name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	var values []string
+	for {
+		p.skipNewlines()
+		if p.accept("}") {
+			break
+		}
+		v, err := p.name()
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+		if _, err := p.settings(); err != nil {
+			return err
+		}
+	}
+	p.enums[strings.ToLower(name.String())] = values
+	p.enums[strings.ToLower(name.Name)] = values
+	return nil
+}
+
+// enumType MySQL 的 enum 类型写法
+func enumType(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+	}
+	return "enum(" + strings.Join(quoted, ",") + ")"
+}
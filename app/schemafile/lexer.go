@@ -0,0 +1,237 @@
+package schemafile
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokWord    tokenKind = iota // 标识符、关键字、数字
+	tokString                   // 单引号字符串（DBML）或双引号字符串（Prisma）
+	tokQuoted                   // 双引号标识符（DBML）
+	tokExpr                     // 反引号表达式
+	tokPunct                    // 标点
+	tokNewline                  // 换行，DBML 与 Prisma 都按行书写字段
+	tokDoc                      // Prisma 的 /// 文档注释
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	line int
+}
+
+// lex 拆分 DBML 与 Prisma 共用的词法单元；doubleQuoteString 为 true 时双引号为字符串
+func lex(src string, doubleQuoteString bool) ([]token, error) {
+	var toks []token
+	line := 1
+	rs := []rune(src)
+	emit := func(kind tokenKind, text string) { toks = append(toks, token{kind: kind, text: text, line: line}) }
+	for i := 0; i < len(rs); {
+		c := rs[i]
+		switch {
+		case c == '\n':
+			emit(tokNewline, "\n")
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '/' && i+2 < len(rs) && rs[i+1] == '/' && rs[i+2] == '/':
+			end := i
+			for end < len(rs) && rs[end] != '\n' {
+				end++
+			}
+			emit(tokDoc, strings.TrimSpace(string(rs[i+3:end])))
+			i = end
+		case c == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(rs) && rs[i+1] == '*':
+			j := i + 2
+			for ; j+1 < len(rs) && !(rs[j] == '*' && rs[j+1] == '/'); j++ {
+				if rs[j] == '\n' {
+					line++
+				}
+			}
+			if j+1 >= len(rs) {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			i = j + 2
+		case c == '\'' && i+2 < len(rs) && rs[i+1] == '\'' && rs[i+2] == '\'':
+			j := i + 3
+			for ; j+2 < len(rs) && !(rs[j] == '\'' && rs[j+1] == '\'' && rs[j+2] == '\''); j++ {
+			}
+			if j+2 >= len(rs) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			text := string(rs[i+3 : j])
+			emit(tokString, trimMultiline(text))
+			line += strings.Count(text, "\n")
+			i = j + 3
+		case c == '\'' || c == '"' || c == '`':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(rs) && rs[j] != c; j++ {
+				if rs[j] == '\\' && j+1 < len(rs) {
+					j++
+					switch rs[j] {
+					case 'n':
+						b.WriteRune('\n')
+						continue
+					case 't':
+						b.WriteRune('\t')
+						continue
+					}
+				}
+				if rs[j] == '\n' {
+					line++
+				}
+				b.WriteRune(rs[j])
+			}
+			if j >= len(rs) {
+				return nil, fmt.Errorf("line %d: unterminated %c", line, c)
+			}
+			kind := tokString
+			switch {
+			case c == '`':
+				kind = tokExpr
+			case c == '"' && !doubleQuoteString:
+				kind = tokQuoted
+			}
+			emit(kind, b.String())
+			i = j + 1
+		case isWordRune(c) || (c == '#' && i+1 < len(rs) && isWordRune(rs[i+1])):
+			j := i + 1
+			for j < len(rs) && (isWordRune(rs[j]) || (rs[j] == '.' && j+1 < len(rs) && isDigit(rs[j+1]) && isDigit(c))) {
+				j++
+			}
+			emit(tokWord, string(rs[i:j]))
+			i = j
+		case c == '<' && i+1 < len(rs) && rs[i+1] == '>':
+			emit(tokPunct, "<>")
+			i += 2
+		default:
+			emit(tokPunct, string(c))
+			i++
+		}
+	}
+	toks = append(toks, token{kind: tokEOF, line: line})
+	return toks, nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == '$' || isDigit(r) || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 127
+}
+
+func isDigit(r rune) bool { return r >= '0' && r <= '9' }
+
+// trimMultiline 去掉多行字符串首尾的空行与公共缩进
+func trimMultiline(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	indent := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, l := range lines {
+		if len(l) >= indent && indent > 0 {
+			lines[i] = l[indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// parser 词法单元游标
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) peekAt(n int) token {
+	if p.pos+n < len(p.toks) {
+		return p.toks[p.pos+n]
+	}
+	return p.toks[len(p.toks)-1]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// skipNewlines 跳过空行；DBML 中的文档注释同样忽略
+func (p *parser) skipNewlines() {
+	for p.peek().kind == tokNewline {
+		p.pos++
+	}
+}
+
+func (p *parser) isPunct(text string) bool {
+	t := p.peek()
+	return t.kind == tokPunct && t.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.isPunct(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if p.accept(text) {
+		return nil
+	}
+	t := p.peek()
+	return fmt.Errorf("line %d: expected %q, found %q", t.line, text, t.text)
+}
+
+// name 读取标识符（可带双引号）
+func (p *parser) name() (string, error) {
+	t := p.peek()
+	if t.kind == tokWord || t.kind == tokQuoted {
+		p.pos++
+		return t.text, nil
+	}
+	return "", fmt.Errorf("line %d: expected a name, found %q", t.line, t.text)
+}
+
+// skipBlock 跳过从当前位置开始的一个花括号块（含嵌套）
+func (p *parser) skipBlock() error {
+	for !p.isPunct("{") {
+		if p.peek().kind == tokEOF {
+			return fmt.Errorf("line %d: expected a block", p.peek().line)
+		}
+		p.pos++
+	}
+	depth := 0
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return fmt.Errorf("line %d: unterminated block", t.line)
+		case t.kind == tokPunct && t.text == "{":
+			depth++
+		case t.kind == tokPunct && t.text == "}":
+			depth--
+			if depth == 0 {
+				return nil
+			}
+		}
+	}
+}
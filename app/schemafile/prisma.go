@@ -0,0 +1,592 @@
+package schemafile
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParsePrisma 解析 Prisma schema：datasource 的 provider 决定方言，model 转为表，
+// @relation 转为引用，/// 文档注释作为说明；view 与复合 type 将被跳过
+func ParsePrisma(src string) (File, error) {
+	f := File{Format: FormatPrisma}
+	toks, err := lex(src, true)
+	if err != nil {
+		return f, err
+	}
+	p := &prismaParser{parser: parser{toks: toks}, file: &f, enums: map[string][]string{}, enumMaps: map[string]map[string]string{}}
+	if err := p.parse(); err != nil {
+		return f, err
+	}
+	p.resolve()
+	return f, nil
+}
+
+type prismaParser struct {
+	parser
+	file     *File
+	enums    map[string][]string          // 枚举名 -> 数据库中的取值
+	enumMaps map[string]map[string]string // 枚举名 -> Prisma 取值 -> @map 后的取值
+	models   []prismaModel
+}
+
+// prismaModel model 解析的中间结果，字段类型在 datasource 确定后才能映射
+type prismaModel struct {
+	name    string // model 名称
+	table   string // @@map 后的表名
+	schema  string
+	note    string
+	fields  []prismaField
+	ids     []string // @@id
+	uniques [][]string
+	indexes [][]string
+}
+
+type prismaField struct {
+	name      string
+	column    string // @map 后的列名
+	typ       string
+	optional  bool
+	list      bool
+	native    string // @db.X(args)
+	id        bool
+	unique    bool
+	increment bool
+	def       string
+	note      string
+	relation  *prismaRelation
+}
+
+type prismaRelation struct {
+	fields     []string
+	references []string
+}
+
+func (p *prismaParser) parse() error {
+	var doc []string
+	for {
+		p.skipNewlines()
+		t := p.peek()
+		switch t.kind {
+		case tokEOF:
+			return nil
+		case tokDoc:
+			p.next()
+			doc = append(doc, t.text)
+			continue
+		case tokWord:
+		default:
+			return fmt.Errorf("line %d: unexpected %q", t.line, t.text)
+		}
+		var err error
+		switch t.text {
+		case "datasource":
+			err = p.datasource()
+		case "model":
+			err = p.model(strings.Join(doc, "\n"))
+		case "enum":
+			err = p.enum()
+		case "generator":
+			err = p.skipBlock()
+		default:
+			p.file.Warnings = append(p.file.Warnings, fmt.Sprintf("line %d: %s block skipped", t.line, t.text))
+			err = p.skipBlock()
+		}
+		if err != nil {
+			return err
+		}
+		doc = nil
+	}
+}
+
+// datasource datasource db { provider = "postgresql" }
+func (p *prismaParser) datasource() error {
+	p.next()
+	if _, err := p.name(); err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	for {
+		p.skipNewlines()
+		if p.accept("}") {
+			return nil
+		}
+		key := p.next()
+		if key.kind == tokEOF {
+			return fmt.Errorf("line %d: unterminated datasource", key.line)
+		}
+		if key.kind == tokWord && key.text == "provider" && p.accept("=") && p.peek().kind == tokString {
+			p.file.Dialect = dialectName(p.next().text)
+		}
+		for p.peek().kind != tokNewline && p.peek().kind != tokEOF && !p.isPunct("}") {
+			p.next()
+		}
+	}
+}
+
+// enum enum Role { USER ADMIN @map("admin") }
+func (p *prismaParser) enum() error {
+	p.next()
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	var values []string
+	mapped := map[string]string{}
+	for {
+		p.skipNewlines()
+		if p.accept("}") {
+			break
+		}
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return fmt.Errorf("line %d: unterminated enum %s", t.line, name)
+		case t.kind == tokWord:
+			values = append(values, t.text)
+			if p.accept("@") {
+				attr, args, err := p.attribute()
+				if err != nil {
+					return err
+				}
+				if attr == "map" && len(args) > 0 {
+					values[len(values)-1] = args[0].text
+				}
+			}
+			mapped[t.text] = values[len(values)-1]
+		case t.kind == tokPunct && t.text == "@":
+			// @@map 等枚举属性
+			if _, _, err := p.attribute(); err != nil {
+				return err
+			}
+		}
+	}
+	p.enums[name] = values
+	p.enumMaps[name] = mapped
+	return nil
+}
+
+// attribute 读取 @ 之后的属性名与括号内的参数，参数按词法单元返回（不含括号本身）
+func (p *prismaParser) attribute() (string, []token, error) {
+	p.accept("@")
+	var name strings.Builder
+	for {
+		n, err := p.name()
+		if err != nil {
+			return "", nil, err
+		}
+		name.WriteString(n)
+		if !p.accept(".") {
+			break
+		}
+		name.WriteString(".")
+	}
+	if !p.isPunct("(") {
+		return name.String(), nil, nil
+	}
+	p.next()
+	var args []token
+	depth := 0
+	for {
+		t := p.next()
+		switch {
+		case t.kind == tokEOF:
+			return "", nil, fmt.Errorf("line %d: unterminated attribute @%s", t.line, name.String())
+		case t.kind == tokNewline:
+			continue
+		case t.kind == tokPunct && (t.text == "(" || t.text == "["):
+			depth++
+		case t.kind == tokPunct && t.text == "]":
+			depth--
+		case t.kind == tokPunct && t.text == ")":
+			if depth == 0 {
+				return name.String(), args, nil
+			}
+			depth--
+		}
+		args = append(args, t)
+	}
+}
+
+// namedArg 取出 name: [..] 或 name: value 形式的参数；name 为空时取第一个位置参数
+func namedArg(args []token, name string) []token {
+	start := -1
+	if name == "" {
+		if len(args) > 1 && args[1].kind == tokPunct && args[1].text == ":" {
+			return nil
+		}
+		start = 0
+	} else {
+		for i := 0; i+1 < len(args); i++ {
+			if args[i].kind == tokWord && args[i].text == name && args[i+1].kind == tokPunct && args[i+1].text == ":" {
+				start = i + 2
+				break
+			}
+		}
+	}
+	if start < 0 || start >= len(args) {
+		return nil
+	}
+	depth := 0
+	for i := start; i < len(args); i++ {
+		t := args[i]
+		switch {
+		case t.kind == tokPunct && (t.text == "[" || t.text == "("):
+			depth++
+		case t.kind == tokPunct && (t.text == "]" || t.text == ")"):
+			depth--
+		case t.kind == tokPunct && t.text == "," && depth == 0:
+			return args[start:i]
+		}
+	}
+	return args[start:]
+}
+
+// argNames 参数中的字段名列表，如 [a, b(sort: Desc)] 取 a、b
+func argNames(value []token) []string {
+	var names []string
+	depth := 0
+	for _, t := range value {
+		switch {
+		case t.kind == tokPunct && t.text == "(":
+			depth++
+		case t.kind == tokPunct && t.text == ")":
+			depth--
+		case t.kind == tokWord && depth == 0:
+			names = append(names, t.text)
+		}
+	}
+	return names
+}
+
+// model model User { 字段 类型 属性  @@属性 }
+func (p *prismaParser) model(note string) error {
+	p.next()
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	m := prismaModel{name: name, table: name, note: note}
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	var doc []string
+	for {
+		p.skipNewlines()
+		if p.accept("}") {
+			break
+		}
+		t := p.peek()
+		switch {
+		case t.kind == tokEOF:
+			return fmt.Errorf("line %d: unterminated model %s", t.line, name)
+		case t.kind == tokDoc:
+			p.next()
+			doc = append(doc, t.text)
+			continue
+		case t.kind == tokPunct && t.text == "@" && p.peekAt(1).text == "@":
+			p.next()
+			p.next()
+			if err := p.modelAttribute(&m); err != nil {
+				return err
+			}
+		default:
+			f, err := p.field(strings.Join(doc, "\n"))
+			if err != nil {
+				return err
+			}
+			m.fields = append(m.fields, f)
+		}
+		doc = nil
+	}
+	p.models = append(p.models, m)
+	return nil
+}
+
+func (p *prismaParser) modelAttribute(m *prismaModel) error {
+	attr, args, err := p.attribute()
+	if err != nil {
+		return err
+	}
+	switch attr {
+	case "id":
+		m.ids = argNames(namedArg(args, "fields"))
+		if len(m.ids) == 0 {
+			m.ids = argNames(namedArg(args, ""))
+		}
+	case "unique", "index":
+		cols := argNames(namedArg(args, "fields"))
+		if len(cols) == 0 {
+			cols = argNames(namedArg(args, ""))
+		}
+		if attr == "unique" {
+			m.uniques = append(m.uniques, cols)
+		} else {
+			m.indexes = append(m.indexes, cols)
+		}
+	case "map":
+		if v := namedArg(args, ""); len(v) > 0 {
+			m.table = v[0].text
+		}
+	case "schema":
+		if v := namedArg(args, ""); len(v) > 0 {
+			m.schema = v[0].text
+		}
+	}
+	return nil
+}
+
+// field name Type? @id @default(...) @map("col") @db.VarChar(50) @relation(...)
+func (p *prismaParser) field(note string) (prismaField, error) {
+	name, err := p.name()
+	if err != nil {
+		return prismaField{}, err
+	}
+	f := prismaField{name: name, column: name, note: note}
+	typ, err := p.name()
+	if err != nil {
+		return f, err
+	}
+	f.typ = typ
+	if p.accept("(") {
+		// Unsupported("tsvector")
+		for !p.accept(")") {
+			t := p.next()
+			if t.kind == tokEOF {
+				return f, fmt.Errorf("line %d: unterminated type on %s", t.line, name)
+			}
+			if t.kind == tokString {
+				f.native = t.text
+			}
+		}
+	}
+	if p.accept("[") {
+		if err := p.expect("]"); err != nil {
+			return f, err
+		}
+		f.list = true
+	}
+	if p.accept("?") {
+		f.optional = true
+	}
+	for p.isPunct("@") {
+		attr, args, err := p.attribute()
+		if err != nil {
+			return f, err
+		}
+		switch {
+		case attr == "id":
+			f.id = true
+		case attr == "unique":
+			f.unique = true
+		case attr == "map":
+			if v := namedArg(args, ""); len(v) > 0 {
+				f.column = v[0].text
+			}
+		case attr == "default":
+			f.def, f.increment = prismaDefault(namedArg(args, ""))
+		case attr == "relation":
+			f.relation = &prismaRelation{fields: argNames(namedArg(args, "fields")), references: argNames(namedArg(args, "references"))}
+		case strings.HasPrefix(attr, "db."):
+			f.native = nativeType(strings.TrimPrefix(attr, "db."), args)
+		}
+	}
+	if t := p.peek(); t.kind != tokNewline && t.kind != tokEOF && !p.isPunct("}") {
+		return f, fmt.Errorf("line %d: unexpected %q after field %s", t.line, t.text, name)
+	}
+	return f, nil
+}
+
+// prismaDefault 将 @default 的参数写为 SQL 默认值，autoincrement() 返回自增标记；
+// cuid()、uuid() 等由 Prisma 客户端生成的值在数据库中没有默认值
+func prismaDefault(value []token) (string, bool) {
+	if len(value) == 0 {
+		return "", false
+	}
+	first := value[0]
+	if first.kind == tokWord && len(value) > 1 && value[1].kind == tokPunct && value[1].text == "(" {
+		switch first.text {
+		case "autoincrement":
+			return "", true
+		case "now":
+			return "CURRENT_TIMESTAMP", false
+		case "dbgenerated":
+			if len(value) > 2 && value[2].kind == tokString {
+				return value[2].text, false
+			}
+		}
+		return "", false
+	}
+	switch first.kind {
+	case tokString:
+		return "'" + strings.ReplaceAll(first.text, "'", "''") + "'", false
+	case tokWord:
+		return first.text, false
+	case tokPunct:
+		if first.text == "-" && len(value) > 1 {
+			return "-" + value[1].text, false
+		}
+	}
+	return "", false
+}
+
+// nativeType @db.VarChar(255) 写为 varchar(255)
+func nativeType(name string, args []token) string {
+	typ := strings.ToLower(name)
+	switch typ {
+	case "doubleprecision":
+		typ = "double precision"
+	case "timestamptz":
+		typ = "timestamp with time zone"
+	case "timetz":
+		typ = "time with time zone"
+	}
+	var parts []string
+	for _, a := range args {
+		if a.kind == tokWord {
+			parts = append(parts, strings.ToLower(a.text))
+		}
+	}
+	if len(parts) > 0 {
+		typ += "(" + strings.Join(parts, ",") + ")"
+	}
+	return typ
+}
+
+// prismaScalars Prisma 标量类型在各数据库中的默认映射
+var prismaScalars = map[string]map[string]string{
+	"postgresql": {"String": "text", "Int": "integer", "BigInt": "bigint", "Float": "double precision", "Decimal": "decimal(65,30)",
+		"Boolean": "boolean", "DateTime": "timestamp(3)", "Json": "jsonb", "Bytes": "bytea"},
+	"mysql": {"String": "varchar(191)", "Int": "int", "BigInt": "bigint", "Float": "double", "Decimal": "decimal(65,30)",
+		"Boolean": "tinyint(1)", "DateTime": "datetime(3)", "Json": "json", "Bytes": "longblob"},
+	"sqlserver": {"String": "nvarchar(1000)", "Int": "int", "BigInt": "bigint", "Float": "float", "Decimal": "decimal(32,16)",
+		"Boolean": "bit", "DateTime": "datetime2", "Json": "nvarchar(max)", "Bytes": "varbinary(max)"},
+}
+
+// columnType 字段在当前方言下的类型
+func (p *prismaParser) columnType(f prismaField) string {
+	if f.native != "" {
+		return f.native
+	}
+	dialect := p.file.Dialect
+	if dialect == "mariadb" {
+		dialect = "mysql"
+	}
+	if values, ok := p.enums[f.typ]; ok {
+		if dialect == "mysql" {
+			return enumType(values)
+		}
+		return f.typ
+	}
+	scalars, ok := prismaScalars[dialect]
+	if !ok {
+		scalars = prismaScalars["postgresql"]
+	}
+	if t, ok := scalars[f.typ]; ok {
+		return t
+	}
+	return f.typ
+}
+
+// resolve 将 model 转换为表与引用，关系字段改写为 @map 后的列名
+func (p *prismaParser) resolve() {
+	byName := map[string]*prismaModel{}
+	for i := range p.models {
+		byName[p.models[i].name] = &p.models[i]
+	}
+	columnOf := func(m *prismaModel, field string) string {
+		for _, f := range m.fields {
+			if f.name == field {
+				return f.column
+			}
+		}
+		return field
+	}
+	for i := range p.models {
+		m := &p.models[i]
+		t := Table{Schema: m.schema, Name: m.table, Note: m.note}
+		fk := map[string]bool{}
+		for _, f := range m.fields {
+			if f.relation != nil {
+				for _, name := range f.relation.fields {
+					fk[name] = true
+				}
+			}
+		}
+		for _, f := range m.fields {
+			if _, ok := byName[f.typ]; ok {
+				continue
+			}
+			if f.list && p.file.Dialect != "postgresql" && p.file.Dialect != "" {
+				p.file.Warnings = append(p.file.Warnings, fmt.Sprintf("%s.%s: scalar lists are only supported on PostgreSQL", m.name, f.name))
+			}
+			if mapped, ok := p.enumMaps[f.typ]; ok && f.def != "" && !strings.HasPrefix(f.def, "'") {
+				// 枚举默认值写作取值名，数据库中为字符串
+				value, ok := mapped[f.def]
+				if !ok {
+					value = f.def
+				}
+				f.def = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+			}
+			typ := p.columnType(f)
+			if f.list {
+				typ += "[]"
+			}
+			c := Column{Name: f.column, Type: typ, Nullable: f.optional, PrimaryKey: f.id, Unique: f.unique,
+				Indexed: fk[f.name], Increment: f.increment, Default: f.def, Note: f.note}
+			if contains(m.ids, f.name) {
+				c.PrimaryKey = true
+			}
+			for _, u := range m.uniques {
+				if len(u) == 1 && u[0] == f.name {
+					c.Unique = true
+				} else if contains(u, f.name) {
+					c.Indexed = true
+				}
+			}
+			for _, idx := range m.indexes {
+				if contains(idx, f.name) {
+					c.Indexed = true
+				}
+			}
+			t.Columns = append(t.Columns, c)
+		}
+		p.file.Tables = append(p.file.Tables, t)
+
+		for _, f := range m.fields {
+			target, ok := byName[f.typ]
+			if !ok || f.relation == nil || len(f.relation.fields) == 0 {
+				continue
+			}
+			r := Ref{From: TableName{Schema: m.schema, Name: m.table}, To: TableName{Schema: target.schema, Name: target.table},
+				FromCardinality: One, ToCardinality: One}
+			for _, name := range f.relation.fields {
+				r.FromColumns = append(r.FromColumns, columnOf(m, name))
+			}
+			for _, name := range f.relation.references {
+				r.ToColumns = append(r.ToColumns, columnOf(target, name))
+			}
+			// 对端的反向关系字段为列表时是多对一
+			for _, back := range target.fields {
+				if back.typ == m.name && back.list {
+					r.FromCardinality = Many
+					break
+				}
+			}
+			p.file.Refs = append(p.file.Refs, r)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package schemafile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// 支持的设计文件格式
+const (
+	FormatDBML   = "dbml"
+	FormatPrisma = "prisma"
+)
+
+// 关系两端的基数
+const (
+	One  = "one"
+	Many = "many"
+)
+
+// File 从设计文件中解析出的表结构
+type File struct {
+	Format   string
+	Name     string // DBML Project 名称
+	Dialect  string // database_type / provider 对应的连接类型，无法识别时为空
+	Note     string
+	Tables   []Table
+	Refs     []Ref
+	Warnings []string
+}
+
+// Table 设计文件中的表
+type Table struct {
+	Schema  string
+	Name    string
+	Note    string
+	Columns []Column
+}
+
+// Column 设计文件中的字段，Default 为可直接用于 DEFAULT 子句的表达式
+type Column struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	PrimaryKey bool
+	Unique     bool
+	Indexed    bool
+	Increment  bool
+	Default    string
+	Note       string
+}
+
+// TableName 关系端点引用的表，Schema 为空表示未限定
+type TableName struct {
+	Schema string
+	Name   string
+}
+
+func (t TableName) String() string {
+	if t.Schema == "" {
+		return t.Name
+	}
+	return t.Schema + "." + t.Name
+}
+
+// Ref 表之间的引用：From 的字段引用 To 的字段
+type Ref struct {
+	Name            string
+	From            TableName
+	FromColumns     []string
+	To              TableName
+	ToColumns       []string
+	FromCardinality string
+	ToCardinality   string
+}
+
+// ParseFile 按扩展名读取并解析 .dbml 或 .prisma 文件
+func ParseFile(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return File{}, fmt.Errorf("read %s failed: %w", path, err)
+	}
+	return Parse(filepath.Base(path), string(data))
+}
+
+var prismaBlock = regexp.MustCompile(`(?m)^\s*(model|datasource|generator)\s+\w+\s*\{`)
+
+// Parse 解析设计文件内容；扩展名无法判断格式时按内容识别
+func Parse(name, src string) (File, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".dbml":
+		return ParseDBML(src)
+	case ".prisma":
+		return ParsePrisma(src)
+	}
+	if prismaBlock.MatchString(src) {
+		return ParsePrisma(src)
+	}
+	return ParseDBML(src)
+}
+
+// dialectName 将设计文件中的数据库名称映射为连接类型
+func dialectName(s string) string {
+	switch strings.ToLower(strings.Join(strings.Fields(s), "")) {
+	case "postgresql", "postgres", "pg", "cockroachdb":
+		return "postgresql"
+	case "mysql":
+		return "mysql"
+	case "mariadb":
+		return "mariadb"
+	case "sqlserver", "mssql", "microsoftsqlserver":
+		return "sqlserver"
+	case "oracle":
+		return "oracle"
+	}
+	return ""
+}
+
+// FindTable 按名称查找表（忽略大小写）；未限定 Schema 时名称唯一才能匹配
+func (f File) FindTable(name TableName) (Table, bool) {
+	var found []Table
+	for _, t := range f.Tables {
+		if !strings.EqualFold(t.Name, name.Name) {
+			continue
+		}
+		if name.Schema == "" || strings.EqualFold(t.Schema, name.Schema) {
+			found = append(found, t)
+		}
+	}
+	if len(found) != 1 {
+		for _, t := range found {
+			// 未限定时优先匹配默认 Schema
+			if t.Schema == "" || strings.EqualFold(t.Schema, "public") {
+				return t, true
+			}
+		}
+		return Table{}, false
+	}
+	return found[0], true
+}
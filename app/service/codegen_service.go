@@ -0,0 +1,221 @@
+package service
+
+import (
+	"dbrun/app/codegen"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// 项目中保存自定义代码模板的配置键前缀，后接语言名
+const codeTemplateKeyPrefix = "codegen_template_"
+
+// GenerateCode 为选中的表生成 Go、TypeScript、Java、Kotlin 或 Python 模型代码；
+// 类型按表所属连接的方言映射，别名、注释与备注写入文档注释
+func GenerateCode(req models.CodeGenRequest) (models.CodeGenResult, error) {
+	result := models.CodeGenResult{Language: req.Language, OutputDir: req.OutputDir, Files: []models.CodeFile{}, Warnings: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	tables, err := manager.codegenTables(req.TableIDs, req.PageKey, req.Singular)
+	if err != nil {
+		return result, err
+	}
+	text := req.Template
+	if text == "" {
+		if text, _, err = manager.savedCodeTemplate(req.Language); err != nil {
+			return result, err
+		}
+	}
+	files, warnings, err := codegen.Generate(tables, codegen.Options{Language: req.Language, Package: req.Package, Template: text})
+	if err != nil {
+		return result, err
+	}
+	result.Tables = len(tables)
+	result.Warnings = append(result.Warnings, warnings...)
+	for _, f := range files {
+		result.Files = append(result.Files, models.CodeFile{Name: f.Name, Content: f.Content})
+		if req.OutputDir != "" {
+			if err := writeDocFile(filepath.Join(req.OutputDir, f.Name), []byte(f.Content)); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// ExportSchemas 将选中的表导出为 JSON Schema 文件或 OpenAPI components/schemas 文档
+func ExportSchemas(req models.SchemaExportRequest) (models.SchemaExportResult, error) {
+	result := models.SchemaExportResult{Format: req.Format, OutputDir: req.OutputDir, Files: []models.CodeFile{}, Warnings: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	tables, err := manager.codegenTables(req.TableIDs, req.PageKey, req.Singular)
+	if err != nil {
+		return result, err
+	}
+	files, warnings, err := codegen.GenerateSchemas(tables, req.Format, req.Title)
+	if err != nil {
+		return result, err
+	}
+	result.Tables = len(tables)
+	result.Warnings = append(result.Warnings, warnings...)
+	for _, f := range files {
+		result.Files = append(result.Files, models.CodeFile{Name: f.Name, Content: f.Content})
+		if req.OutputDir != "" {
+			if err := writeDocFile(filepath.Join(req.OutputDir, f.Name), []byte(f.Content)); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// codegenTables 收集选中的表与页面上的表及其字段，字段按显示顺序排列（含隐藏字段）；
+// singular 为 true 时模型名取表名的单数形式
+func (m *MetadataService) codegenTables(tableIDs []int64, pageKey string, singular bool) ([]codegen.Table, error) {
+	selected := map[int64]bool{}
+	for _, id := range tableIDs {
+		selected[id] = true
+	}
+	if pageKey != "" {
+		page, err := m.requirePage(pageKey)
+		if err != nil {
+			return nil, err
+		}
+		nodes, err := m.pages.ListNodes(page.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range nodes {
+			if n.ObjectKind != meta.PageObjectView {
+				selected[n.ObjectID] = true
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("no tables selected")
+	}
+	refs, err := m.rawStorage.GetRawObjectsByIDs(keysOf(selected), nil)
+	if err != nil {
+		return nil, err
+	}
+	configs := map[int64]bool{}
+	for _, r := range refs {
+		configs[r.ConfigID] = true
+	}
+
+	var tables []codegen.Table
+	for _, configID := range sortedIDs(configs) {
+		creds, err := m.GetCredentialsByID(configID)
+		if err != nil {
+			return nil, fmt.Errorf("get credentials failed: %w", err)
+		}
+		d, err := m.loadDictionary(models.DocOptions{ConfigID: configID})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range d.Tables {
+			if !selected[t.Info.ID] {
+				continue
+			}
+			raw, err := m.rawStorage.GetRawFieldsRows(t.Info.ID)
+			if err != nil {
+				return nil, fmt.Errorf("load fields failed: %w", err)
+			}
+			autoIncrement := map[int64]bool{}
+			for _, f := range raw {
+				autoIncrement[f.ID] = f.AutoIncrement
+			}
+			ct := codegen.Table{
+				Database: t.Database, Schema: t.Schema, Name: t.Info.Name, Dialect: connectionDialect(creds),
+				Comment: docLines(t.Info.Name, t.Info.Alias, t.Info.Comment, t.Info.Remark),
+			}
+			if singular {
+				ct.ModelName = singularize(toSnake(ct.Name), models.NamingRules{Pluralization: true})
+			}
+			for _, f := range t.Info.Fields {
+				ct.Columns = append(ct.Columns, codegen.Column{
+					Name: f.Name, Type: f.Type, Nullable: f.Nullable, PrimaryKey: strings.EqualFold(f.Key, "PRI"),
+					AutoIncrement: autoIncrement[f.ID], Default: f.DefaultValue,
+					Comment: docLines(f.Name, f.Alias, f.Comment, f.Remark),
+				})
+			}
+			tables = append(tables, ct)
+		}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("selected tables no longer exist")
+	}
+	return tables, nil
+}
+
+// docLines 合并别名、注释与备注为文档注释，去掉与名称或前文重复的内容
+func docLines(name string, parts ...string) string {
+	var lines []string
+	seen := map[string]bool{strings.ToLower(name): true}
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[strings.ToLower(p)] {
+			continue
+		}
+		seen[strings.ToLower(p)] = true
+		lines = append(lines, p)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// savedCodeTemplate 读取项目中保存的自定义模板，未保存时返回空字符串
+func (m *MetadataService) savedCodeTemplate(language string) (string, bool, error) {
+	value, ok, err := m.settings.Get(codeTemplateKeyPrefix + language)
+	if err != nil {
+		return "", false, fmt.Errorf("load code template failed: %w", err)
+	}
+	return value, ok && value != "", nil
+}
+
+// GetCodeTemplate 获取语言当前使用的模板：项目中保存的自定义模板或默认模板
+func GetCodeTemplate(language string) (models.CodeTemplate, error) {
+	result := models.CodeTemplate{Language: language}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	text, custom, err := manager.savedCodeTemplate(language)
+	if err != nil {
+		return result, err
+	}
+	if !custom {
+		if text, err = codegen.DefaultTemplate(language); err != nil {
+			return result, err
+		}
+	}
+	result.Template, result.Custom = text, custom
+	return result, nil
+}
+
+// SaveCodeTemplate 保存语言的自定义模板（text/template 语法），传入空模板时恢复默认模板
+func SaveCodeTemplate(tpl models.CodeTemplate) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	if _, err := codegen.DefaultTemplate(tpl.Language); err != nil {
+		return err
+	}
+	if strings.TrimSpace(tpl.Template) != "" {
+		if _, err := codegen.Parse(tpl.Language, tpl.Template); err != nil {
+			return err
+		}
+	}
+	return manager.settings.Set(codeTemplateKeyPrefix+tpl.Language, tpl.Template)
+}
+
+// ListCodeLanguages 支持生成代码的语言
+func ListCodeLanguages() []string {
+	return append([]string(nil), codegen.Languages...)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+    "dbrun/app/connect"
     meta "dbrun/app/sqlite/metadata"
 )
 
@@ -29,7 +30,11 @@ func UpdateCredentials(creds *meta.Credentials) error {
     if err != nil {
         return err
     }
-    return manager.UpdateCredentials(creds)
+    if err := manager.UpdateCredentials(creds); err != nil {
+        return err
+    }
+    // 配置（含只读开关）变更后丢弃旧连接，下次使用时按新配置重建
+    return connect.RemoveConnection(creds.ID)
 }
 
 // DeleteCredentialsByID 根据ID删除数据库连接凭证
@@ -38,5 +43,8 @@ func DeleteCredentialsByID(id int) error {
     if err != nil {
         return err
     }
-    return manager.DeleteCredentialsByID(id)
+    if err := manager.DeleteCredentialsByID(id); err != nil {
+        return err
+    }
+    return connect.RemoveConnection(int64(id))
 }
\ No newline at end of file
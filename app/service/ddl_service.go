@@ -0,0 +1,516 @@
+package service
+
+import (
+	"dbrun/app/connect"
+	"dbrun/app/ddl"
+	"dbrun/app/dialect"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GenerateDDL 根据原始元数据生成表与视图的建表脚本，对象按依赖顺序排列，可在空库上直接执行
+func GenerateDDL(req models.DDLRequest) (models.DDLResult, error) {
+	result := models.DDLResult{Warnings: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	src, err := manager.buildDDLSchema(req)
+	if err != nil {
+		return result, err
+	}
+	d, err := manager.ddlDialect(req.Dialect, src)
+	if err != nil {
+		return result, err
+	}
+	result.Dialect = d.Name()
+
+	script, warnings := ddl.Generate(d, src.schema, ddl.Options{Qualify: req.Qualify})
+	result.Script = script
+	result.Tables, result.Views = len(src.schema.Tables), len(src.schema.Views)
+	result.Warnings = append(append(result.Warnings, src.warnings...), warnings...)
+	return result, nil
+}
+
+// ConvertSchema 将表定义从所属连接的方言转换为目标方言：映射字段类型、改写默认值与自增列，
+// 生成目标方言的建表脚本并列出有损转换；视图不做转换
+func ConvertSchema(req models.ConversionRequest) (models.ConversionResult, error) {
+	result := models.ConversionResult{TargetDialect: req.TargetDialect, Lossy: []models.ConversionIssue{}, Warnings: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	target, err := dialect.Get(req.TargetDialect)
+	if err != nil {
+		return result, err
+	}
+	src, err := manager.buildDDLSchema(models.DDLRequest{TableIDs: req.TableIDs, PageKey: req.PageKey, IncludeDependencies: req.IncludeDependencies})
+	if err != nil {
+		return result, err
+	}
+	source, err := manager.ddlDialect("", src)
+	if err != nil {
+		return result, err
+	}
+	result.SourceDialect = source.Name()
+
+	converted, issues, err := ddl.Convert(src.schema, source.Name(), target.Name())
+	if err != nil {
+		return result, err
+	}
+	for _, i := range issues {
+		result.Lossy = append(result.Lossy, models.ConversionIssue{Table: i.Object, Column: i.Column, Kind: i.Kind, Source: i.Source, Target: i.Target, Message: i.Message})
+	}
+	script, warnings := ddl.Generate(target, converted, ddl.Options{Qualify: req.Qualify})
+	result.Script = script
+	result.Tables = len(converted.Tables)
+	result.Warnings = append(append(result.Warnings, src.warnings...), warnings...)
+	fmt.Printf("[DDL] converted schema: from=%s to=%s tables=%d lossy=%d\n", result.SourceDialect, result.TargetDialect, result.Tables, len(result.Lossy))
+	return result, nil
+}
+
+// ddlSource 从原始元数据构建的对象定义，以及它们引用到的对象名称
+type ddlSource struct {
+	schema    ddl.Schema
+	tableIDs  map[int64]bool
+	viewIDs   map[int64]bool
+	tableRefs map[int64]meta.RawObjectRef
+	viewRefs  map[int64]meta.RawObjectRef
+	warnings  []string
+}
+
+// ddlTableKeys 同步时从数据库获取的表键信息；refIDs 与 keys 一一对应，
+// 为外键引用表在原始元数据中的ID（未找到时为 0）
+type ddlTableKeys struct {
+	loaded bool
+	keys   []connect.KeyInfo
+	refIDs []int64
+}
+
+// buildDDLSchema 收集请求中的表与视图（可补充依赖对象），读取字段、外键与视图血缘构建定义
+func (m *MetadataService) buildDDLSchema(req models.DDLRequest) (ddlSource, error) {
+	result := ddlSource{}
+
+	tableIDs, viewIDs := map[int64]bool{}, map[int64]bool{}
+	for _, id := range req.TableIDs {
+		tableIDs[id] = true
+	}
+	for _, id := range req.ViewIDs {
+		viewIDs[id] = true
+	}
+	if req.PageKey != "" {
+		page, err := m.requirePage(req.PageKey)
+		if err != nil {
+			return result, err
+		}
+		nodes, err := m.pages.ListNodes(page.ID)
+		if err != nil {
+			return result, err
+		}
+		for _, n := range nodes {
+			if n.ObjectKind == meta.PageObjectView {
+				viewIDs[n.ObjectID] = true
+			} else {
+				tableIDs[n.ObjectID] = true
+			}
+		}
+	}
+	if len(tableIDs) == 0 && len(viewIDs) == 0 {
+		return result, fmt.Errorf("no tables or views selected")
+	}
+
+	foreignKeys, err := m.ddlForeignKeys()
+	if err != nil {
+		return result, err
+	}
+	viewSources := map[int64][]meta.ColumnLineage{}
+	loadSources := func() error {
+		for id := range viewIDs {
+			if _, ok := viewSources[id]; ok {
+				continue
+			}
+			edges, err := m.lineage.GetUpstreamEdges(id, "")
+			if err != nil {
+				return fmt.Errorf("load view lineage failed: %w", err)
+			}
+			viewSources[id] = edges
+		}
+		return nil
+	}
+	tableKeys := map[int64]ddlTableKeys{}
+	loadKeys := func() error {
+		for id := range tableIDs {
+			if _, ok := tableKeys[id]; ok {
+				continue
+			}
+			keys, err := m.ddlTableKeys(id)
+			if err != nil {
+				return err
+			}
+			tableKeys[id] = keys
+		}
+		return nil
+	}
+	if err := loadSources(); err != nil {
+		return result, err
+	}
+	if err := loadKeys(); err != nil {
+		return result, err
+	}
+	// 补充依赖对象直到不再变化
+	for changed := req.IncludeDependencies; changed; {
+		changed = false
+		for id := range tableIDs {
+			for _, r := range foreignKeys[id] {
+				if !tableIDs[r.TargetTableID] {
+					tableIDs[r.TargetTableID], changed = true, true
+				}
+			}
+			for _, refID := range tableKeys[id].refIDs {
+				if refID != 0 && !tableIDs[refID] {
+					tableIDs[refID], changed = true, true
+				}
+			}
+		}
+		for _, edges := range viewSources {
+			for _, e := range edges {
+				ids := tableIDs
+				if e.SourceKind == meta.LineageSourceView {
+					ids = viewIDs
+				}
+				if !ids[e.SourceID] {
+					ids[e.SourceID], changed = true, true
+				}
+			}
+		}
+		if err := loadSources(); err != nil {
+			return result, err
+		}
+		if err := loadKeys(); err != nil {
+			return result, err
+		}
+	}
+
+	// 外键与视图来源中可能引用了脚本以外的对象，一并查询名称
+	refTableIDs, refViewIDs := keysOf(tableIDs), keysOf(viewIDs)
+	for id := range tableIDs {
+		for _, r := range foreignKeys[id] {
+			refTableIDs = append(refTableIDs, r.TargetTableID)
+		}
+		refTableIDs = append(refTableIDs, tableKeys[id].refIDs...)
+	}
+	for _, edges := range viewSources {
+		for _, e := range edges {
+			if e.SourceKind == meta.LineageSourceView {
+				refViewIDs = append(refViewIDs, e.SourceID)
+			} else {
+				refTableIDs = append(refTableIDs, e.SourceID)
+			}
+		}
+	}
+	refs, err := m.rawStorage.GetRawObjectsByIDs(refTableIDs, refViewIDs)
+	if err != nil {
+		return result, err
+	}
+	tableRefs, viewRefs := map[int64]meta.RawObjectRef{}, map[int64]meta.RawObjectRef{}
+	for _, r := range refs {
+		if r.IsView {
+			viewRefs[r.ID] = r
+		} else {
+			tableRefs[r.ID] = r
+		}
+	}
+	refName := func(r meta.RawObjectRef) ddl.Name {
+		return ddl.Name{Database: r.DatabaseName, Schema: r.SchemaName, Name: r.Name}
+	}
+
+	result.tableIDs, result.viewIDs = tableIDs, viewIDs
+	result.tableRefs, result.viewRefs = tableRefs, viewRefs
+
+	schema := &result.schema
+	fieldsByTable := map[int64][]meta.RawFieldInfo{}
+	for _, id := range sortedIDs(tableIDs) {
+		if _, ok := tableRefs[id]; !ok {
+			result.warnings = append(result.warnings, fmt.Sprintf("table %d no longer exists", id))
+			continue
+		}
+		fields, err := m.rawStorage.GetRawFieldsRows(id)
+		if err != nil {
+			return result, fmt.Errorf("load fields failed: %w", err)
+		}
+		sort.Slice(fields, func(i, j int) bool { return fields[i].ID < fields[j].ID })
+		fieldsByTable[id] = fields
+	}
+	for _, id := range sortedIDs(tableIDs) {
+		ref, ok := tableRefs[id]
+		if !ok {
+			continue
+		}
+		t := ddl.Table{Name: refName(ref), Comment: ref.Comment}
+		for _, f := range fieldsByTable[id] {
+			t.Columns = append(t.Columns, ddl.Column{Name: f.Name, Type: f.Type, Nullable: f.Nullable, Default: f.DefaultValue, Comment: f.Comment, AutoIncrement: f.AutoIncrement})
+		}
+		keys := tableKeys[id]
+		if keys.loaded {
+			// 使用数据库中的约束与索引：保留名称与字段顺序，复合键不会被拆开
+			for i, k := range keys.keys {
+				switch k.Kind {
+				case connect.KeyPrimary:
+					t.PrimaryKey = k.Columns
+				case connect.KeyUnique, connect.KeyIndex:
+					t.Indexes = append(t.Indexes, ddl.Index{Name: k.Name, Columns: k.Columns, Unique: k.Kind == connect.KeyUnique})
+				case connect.KeyForeign:
+					target, ok := tableRefs[keys.refIDs[i]]
+					if !ok {
+						target = meta.RawObjectRef{SchemaName: k.RefSchema, Name: k.RefTable}
+					}
+					t.ForeignKeys = append(t.ForeignKeys, ddl.ForeignKey{Name: k.Name, Columns: k.Columns, RefTable: refName(target), RefColumns: k.RefColumns})
+				}
+			}
+		} else {
+			// 旧版本同步的表只有字段上的索引标记，无法还原复合唯一键与复合索引
+			for _, f := range fieldsByTable[id] {
+				switch strings.ToUpper(f.Key) {
+				case "PRI":
+					t.PrimaryKey = append(t.PrimaryKey, f.Name)
+				case "UNI":
+					t.Indexes = append(t.Indexes, ddl.Index{Columns: []string{f.Name}, Unique: true})
+				case "MUL", "IDX", "FOR":
+					t.Indexes = append(t.Indexes, ddl.Index{Columns: []string{f.Name}})
+				}
+			}
+			if len(t.PrimaryKey) == 0 && len(t.Indexes) == 0 {
+				result.warnings = append(result.warnings, fmt.Sprintf("table %s has no key information, primary keys and indexes are omitted; re-sync the table to capture them", t.Name))
+			} else {
+				result.warnings = append(result.warnings, fmt.Sprintf("table %s keys are derived from column flags, composite keys may be incomplete; re-sync the table to capture them", t.Name))
+			}
+		}
+		for _, r := range foreignKeys[id] {
+			target, ok := tableRefs[r.TargetTableID]
+			if !ok {
+				target = meta.RawObjectRef{Name: r.TargetTable}
+			}
+			fk := ddl.ForeignKey{RefTable: refName(target)}
+			for _, c := range r.Columns {
+				fk.Columns = append(fk.Columns, c.SourceField)
+				fk.RefColumns = append(fk.RefColumns, c.TargetField)
+			}
+			if hasForeignKey(t.ForeignKeys, fk) {
+				// 关系与数据库中已有的外键相同
+				continue
+			}
+			if fields, ok := fieldsByTable[r.TargetTableID]; ok && !referencesKey(fields, tableKeys[r.TargetTableID], fk.RefColumns) {
+				result.warnings = append(result.warnings, fmt.Sprintf("foreign key %s -> %s(%s): referenced columns are not a primary or unique key",
+					t.Name, fk.RefTable, strings.Join(fk.RefColumns, ", ")))
+			}
+			t.ForeignKeys = append(t.ForeignKeys, fk)
+		}
+		schema.Tables = append(schema.Tables, t)
+	}
+	for _, id := range sortedIDs(viewIDs) {
+		ref, ok := viewRefs[id]
+		if !ok {
+			result.warnings = append(result.warnings, fmt.Sprintf("view %d no longer exists", id))
+			continue
+		}
+		v := ddl.View{Name: refName(ref), Definition: ref.Definition}
+		seen := map[string]bool{}
+		for _, e := range viewSources[id] {
+			src, ok := tableRefs[e.SourceID]
+			if e.SourceKind == meta.LineageSourceView {
+				src, ok = viewRefs[e.SourceID]
+			}
+			key := fmt.Sprintf("%s:%d", e.SourceKind, e.SourceID)
+			if ok && !seen[key] && !(e.SourceKind == meta.LineageSourceView && e.SourceID == id) {
+				seen[key] = true
+				v.DependsOn = append(v.DependsOn, refName(src))
+			}
+		}
+		schema.Views = append(schema.Views, v)
+	}
+	return result, nil
+}
+
+// ddlForeignKeys 将项目中的关系按持有外键的表分组：多的一端持有外键，
+// 两端基数相同时以关系的源表为持有方；相同的关系只保留一条
+func (m *MetadataService) ddlForeignKeys() (map[int64][]models.RelationshipVO, error) {
+	rows, err := m.relations.List(meta.RelationshipFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("list relationships failed: %w", err)
+	}
+	rels, err := m.relationshipVOs(rows)
+	if err != nil {
+		return nil, err
+	}
+	out := map[int64][]models.RelationshipVO{}
+	seen := map[string]bool{}
+	for _, r := range rels {
+		if r.Broken || len(r.Columns) == 0 {
+			continue
+		}
+		if r.SourceCardinality == models.CardinalityOne && r.TargetCardinality == models.CardinalityMany {
+			r.SourceTableID, r.TargetTableID = r.TargetTableID, r.SourceTableID
+			r.SourceTable, r.TargetTable = r.TargetTable, r.SourceTable
+			cols := make([]models.RelationshipColumnVO, len(r.Columns))
+			for i, c := range r.Columns {
+				cols[i] = models.RelationshipColumnVO{SourceFieldID: c.TargetFieldID, SourceField: c.TargetField, TargetFieldID: c.SourceFieldID, TargetField: c.SourceField}
+			}
+			r.Columns = cols
+		}
+		sig := relationshipSignature(r)
+		if seen[sig] {
+			continue
+		}
+		seen[sig] = true
+		out[r.SourceTableID] = append(out[r.SourceTableID], r)
+	}
+	return out, nil
+}
+
+// ddlDialect 未指定方言时使用对象所属连接的类型，对象来自不同类型的连接时需明确指定
+func (m *MetadataService) ddlDialect(name string, src ddlSource) (dialect.Dialect, error) {
+	if name != "" {
+		return dialect.Get(name)
+	}
+	configs := map[int64]bool{}
+	for id := range src.tableIDs {
+		if r, ok := src.tableRefs[id]; ok {
+			configs[r.ConfigID] = true
+		}
+	}
+	for id := range src.viewIDs {
+		if r, ok := src.viewRefs[id]; ok {
+			configs[r.ConfigID] = true
+		}
+	}
+	types := map[string]bool{}
+	for _, configID := range sortedIDs(configs) {
+		creds, err := m.GetCredentialsByID(configID)
+		if err != nil {
+			return nil, fmt.Errorf("get credentials failed: %w", err)
+		}
+		name = connectionDialect(creds)
+		types[name] = true
+	}
+	if len(types) != 1 {
+		return nil, fmt.Errorf("objects belong to connections of different types, please choose a dialect")
+	}
+	return dialect.Get(name)
+}
+
+// ddlTableKeys 读取表的键信息，并在表所在的数据库中解析外键引用的表
+func (m *MetadataService) ddlTableKeys(tableID int64) (ddlTableKeys, error) {
+	keys, loaded, err := m.rawStorage.GetTableKeys(tableID)
+	if err != nil {
+		return ddlTableKeys{}, fmt.Errorf("load table keys failed: %w", err)
+	}
+	result := ddlTableKeys{loaded: loaded, keys: keys, refIDs: make([]int64, len(keys))}
+	if !loaded {
+		return result, nil
+	}
+	configID, _, _, _, databaseID, _, err := m.rawStorage.GetTableContextByID(tableID)
+	if err != nil {
+		return result, fmt.Errorf("resolve table context failed: %w", err)
+	}
+	for i, k := range keys {
+		if k.Kind != connect.KeyForeign {
+			continue
+		}
+		refs, err := m.rawStorage.FindRawObjectsByName(k.RefSchema, k.RefTable)
+		if err != nil {
+			return result, fmt.Errorf("resolve referenced table failed: %w", err)
+		}
+		// 同库优先；MySQL/MariaDB 的跨库引用以数据库名限定
+		for _, r := range refs {
+			if r.IsView || r.ConfigID != configID {
+				continue
+			}
+			if r.DatabaseID == databaseID {
+				result.refIDs[i] = r.ID
+				break
+			}
+			if result.refIDs[i] == 0 && strings.EqualFold(r.DatabaseName, k.RefSchema) {
+				result.refIDs[i] = r.ID
+			}
+		}
+	}
+	return result, nil
+}
+
+// hasForeignKey 是否已有字段与引用相同的外键
+func hasForeignKey(fks []ddl.ForeignKey, fk ddl.ForeignKey) bool {
+	for _, f := range fks {
+		if strings.EqualFold(f.RefTable.Name, fk.RefTable.Name) &&
+			strings.EqualFold(strings.Join(f.Columns, ","), strings.Join(fk.Columns, ",")) &&
+			strings.EqualFold(strings.Join(f.RefColumns, ","), strings.Join(fk.RefColumns, ",")) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesKey 被引用的字段是否构成主键或唯一键；有数据库键信息时按约束判断，否则按字段的索引标记判断
+func referencesKey(fields []meta.RawFieldInfo, tableKeys ddlTableKeys, columns []string) bool {
+	if tableKeys.loaded {
+		for _, k := range tableKeys.keys {
+			if (k.Kind == connect.KeyPrimary || k.Kind == connect.KeyUnique) && sameColumnSet(k.Columns, columns) {
+				return true
+			}
+		}
+		return false
+	}
+	keys := map[string]string{}
+	var pk []string
+	for _, f := range fields {
+		keys[strings.ToLower(f.Name)] = strings.ToUpper(f.Key)
+		if strings.EqualFold(f.Key, "PRI") {
+			pk = append(pk, strings.ToLower(f.Name))
+		}
+	}
+	if len(columns) == 1 && keys[strings.ToLower(columns[0])] == "UNI" {
+		return true
+	}
+	if len(columns) != len(pk) {
+		return false
+	}
+	for _, c := range columns {
+		if keys[strings.ToLower(c)] != "PRI" {
+			return false
+		}
+	}
+	return true
+}
+
+// sameColumnSet 两组字段是否相同（不区分顺序与大小写）
+func sameColumnSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := map[string]bool{}
+	for _, c := range a {
+		set[strings.ToLower(c)] = true
+	}
+	for _, c := range b {
+		if !set[strings.ToLower(c)] {
+			return false
+		}
+	}
+	return true
+}
+
+func keysOf(set map[int64]bool) []int64 {
+	out := make([]int64, 0, len(set))
+	for id := range set {
+		out = append(out, id)
+	}
+	return out
+}
+
+func sortedIDs(set map[int64]bool) []int64 {
+	out := keysOf(set)
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+	return out
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bytes"
+	"dbrun/app/docs"
+	"dbrun/app/models"
+	"fmt"
+	"io"
+)
+
+// diagramWriters 各文本格式的输出函数
+var diagramWriters = map[string]func(io.Writer, docs.Diagram) error{
+	models.DiagramMermaid:  docs.WriteMermaid,
+	models.DiagramPlantUML: docs.WritePlantUML,
+	models.DiagramDOT:      docs.WriteDOT,
+	models.DiagramDBML:     docs.WriteDBML,
+}
+
+// ExportPageDiagram 将页面导出为 Mermaid / PlantUML / Graphviz DOT / DBML 文本：
+// 表按字段排序输出显示的字段，使用表与字段的别名，关系带基数
+func ExportPageDiagram(req models.DiagramExportRequest) (models.DiagramExportResult, error) {
+	result := models.DiagramExportResult{Format: req.Format, OutputPath: req.OutputPath}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	write, ok := diagramWriters[req.Format]
+	if !ok {
+		return result, fmt.Errorf("invalid diagram format: %s", req.Format)
+	}
+	if req.PageKey == "" {
+		return result, fmt.Errorf("page is required")
+	}
+	diagram, _, err := manager.pageDiagram(req.PageKey, func(string, int64) (string, bool) { return "", false })
+	if err != nil {
+		return result, err
+	}
+	var buf bytes.Buffer
+	if err := write(&buf, diagram); err != nil {
+		return result, fmt.Errorf("write %s failed: %w", req.Format, err)
+	}
+	result.Text = buf.String()
+	result.Nodes, result.Relationships = len(diagram.Nodes), len(diagram.Edges)
+	if req.OutputPath != "" {
+		if err := writeDocFile(req.OutputPath, buf.Bytes()); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// RenderPageImage 在服务端将页面渲染为 SVG 或 PNG：表头颜色取节点或表的颜色，字段行带主键、外键、唯一键标记，
+// 连线两端画出基数；节点使用页面保存的位置，页面尚未布局或要求自动布局时按布局参数临时计算位置
+func RenderPageImage(req models.DiagramRenderRequest) (models.DiagramRenderResult, error) {
+	result := models.DiagramRenderResult{Format: req.Format, OutputPath: req.OutputPath}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if req.Format != models.DiagramSVG && req.Format != models.DiagramPNG {
+		return result, fmt.Errorf("invalid image format: %s", req.Format)
+	}
+	if req.PageKey == "" {
+		return result, fmt.Errorf("page is required")
+	}
+	diagram, _, err := manager.pageDiagram(req.PageKey, func(string, int64) (string, bool) { return "", false })
+	if err != nil {
+		return result, err
+	}
+	if req.AutoLayout || !hasStoredPositions(diagram) {
+		layoutDiagram(&diagram, req.Layout)
+		result.AutoLayout = true
+	}
+
+	var buf bytes.Buffer
+	result.Warnings = []string{}
+	if req.Format == models.DiagramSVG {
+		err = docs.WriteSVG(&buf, diagram)
+	} else {
+		var boxed int
+		boxed, err = docs.WritePNG(&buf, diagram, req.Scale)
+		if boxed > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%d non-ASCII characters are drawn as boxes in PNG, export SVG to keep the text", boxed))
+		}
+	}
+	if err != nil {
+		return result, fmt.Errorf("render %s failed: %w", req.Format, err)
+	}
+	if req.Format == models.DiagramSVG {
+		result.Text = buf.String()
+	} else {
+		result.Image = buf.Bytes()
+	}
+	_, _, result.Width, result.Height = diagram.Bounds()
+	result.Nodes, result.Relationships = len(diagram.Nodes), len(diagram.Edges)
+	if req.OutputPath != "" {
+		if err := writeDocFile(req.OutputPath, buf.Bytes()); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// hasStoredPositions 页面是否保存过节点位置；新建页面的节点都位于原点
+func hasStoredPositions(d docs.Diagram) bool {
+	for _, n := range d.Nodes {
+		if n.X != 0 || n.Y != 0 {
+			return true
+		}
+	}
+	return len(d.Nodes) <= 1
+}
+
+// layoutDiagram 按节点实际显示的高度计算自动布局，结果只用于本次渲染
+func layoutDiagram(d *docs.Diagram, opts models.LayoutOptions) {
+	index := map[string]int{}
+	nodes := make([]models.LayoutNode, len(d.Nodes))
+	for i, n := range d.Nodes {
+		index[n.Key] = i
+		width := n.Width
+		if width <= 0 {
+			width = docs.NodeWidth
+		}
+		nodes[i] = models.LayoutNode{Key: n.Key, Width: width, Height: n.Height()}
+	}
+	var edges []layoutEdge
+	for _, e := range d.Edges {
+		// 布局的边从被引用方指向引用方
+		edges = append(edges, layoutEdge{from: index[e.To], to: index[e.From]})
+	}
+	positions := map[string]models.LayoutNode{}
+	for _, n := range computeLayout(nodes, edges, opts).Nodes {
+		positions[n.Key] = n
+	}
+	for i := range d.Nodes {
+		p := positions[d.Nodes[i].Key]
+		d.Nodes[i].X, d.Nodes[i].Y = p.X, p.Y
+	}
+}
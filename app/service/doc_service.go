@@ -0,0 +1,530 @@
+package service
+
+import (
+	"bytes"
+	"dbrun/app/docs"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExportMarkdownDictionary 将连接（或页面上的表与视图）的元数据导出为 Markdown 数据字典
+func ExportMarkdownDictionary(opts models.DocOptions) (models.DocResult, error) {
+	result := models.DocResult{OutputPath: opts.OutputPath, Files: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if opts.OutputPath == "" {
+		return result, fmt.Errorf("output path is required")
+	}
+	d, err := manager.loadDictionary(opts)
+	if err != nil {
+		return result, err
+	}
+	result.Tables, result.Views = len(d.Tables), len(d.Views)
+
+	switch opts.Mode {
+	case "", models.DictionarySingleFile:
+		var buf bytes.Buffer
+		if err := docs.WriteMarkdown(&buf, d); err != nil {
+			return result, err
+		}
+		if err := writeDocFile(opts.OutputPath, buf.Bytes()); err != nil {
+			return result, err
+		}
+		result.Files = append(result.Files, filepath.Base(opts.OutputPath))
+	case models.DictionaryPerTable:
+		files := map[string]func(*bytes.Buffer) error{
+			"index.md": func(b *bytes.Buffer) error { return docs.WriteMarkdownIndex(b, d) },
+		}
+		for _, t := range d.Tables {
+			t := t
+			files[t.FileBase()+".md"] = func(b *bytes.Buffer) error { return docs.WriteMarkdownTable(b, t) }
+		}
+		for _, v := range d.Views {
+			v := v
+			files[v.FileBase()+".md"] = func(b *bytes.Buffer) error { return docs.WriteMarkdownView(b, v) }
+		}
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			var buf bytes.Buffer
+			if err := files[name](&buf); err != nil {
+				return result, err
+			}
+			if err := writeDocFile(filepath.Join(opts.OutputPath, name), buf.Bytes()); err != nil {
+				return result, err
+			}
+			result.Files = append(result.Files, name)
+		}
+	default:
+		return result, fmt.Errorf("invalid dictionary mode: %s", opts.Mode)
+	}
+	return result, nil
+}
+
+// loadDictionary 汇总文档所需的表与视图：VO 中的别名与备注叠加在原始元数据上
+func (m *MetadataService) loadDictionary(opts models.DocOptions) (docs.Dictionary, error) {
+	d := docs.Dictionary{Title: opts.Title}
+	var tableIDs, viewIDs map[int64]bool
+	configIDs := []int64{}
+	if opts.ConfigID != 0 {
+		configIDs = append(configIDs, opts.ConfigID)
+	}
+	if opts.PageKey != "" {
+		page, err := m.requirePage(opts.PageKey)
+		if err != nil {
+			return d, err
+		}
+		if d.Title == "" {
+			d.Title = page.Label
+		}
+		nodes, err := m.pages.ListNodes(page.ID)
+		if err != nil {
+			return d, err
+		}
+		tableIDs, viewIDs = map[int64]bool{}, map[int64]bool{}
+		var tIDs, vIDs []int64
+		for _, n := range nodes {
+			if n.ObjectKind == meta.PageObjectView {
+				viewIDs[n.ObjectID] = true
+				vIDs = append(vIDs, n.ObjectID)
+			} else {
+				tableIDs[n.ObjectID] = true
+				tIDs = append(tIDs, n.ObjectID)
+			}
+		}
+		if opts.ConfigID == 0 {
+			refs, err := m.rawStorage.GetRawObjectsByIDs(tIDs, vIDs)
+			if err != nil {
+				return d, err
+			}
+			seen := map[int64]bool{}
+			for _, r := range refs {
+				if !seen[r.ConfigID] {
+					seen[r.ConfigID] = true
+					configIDs = append(configIDs, r.ConfigID)
+				}
+			}
+			sort.Slice(configIDs, func(i, j int) bool { return configIDs[i] < configIDs[j] })
+		}
+	}
+	if len(configIDs) == 0 && opts.PageKey == "" {
+		return d, fmt.Errorf("connection or page is required")
+	}
+	if d.Title == "" {
+		d.Title = "数据字典"
+	}
+
+	for _, configID := range configIDs {
+		info, err := m.storedDBInfoVO(configID)
+		if err != nil {
+			return d, fmt.Errorf("load metadata failed: %w", err)
+		}
+		add := func(db, schema string, tables []models.TableInfoVO, views []models.ViewInfoVO) {
+			for _, t := range tables {
+				if tableIDs == nil || tableIDs[t.ID] {
+					d.Tables = append(d.Tables, docs.Table{Database: db, Schema: schema, Info: t})
+				}
+			}
+			for _, v := range views {
+				if viewIDs == nil || viewIDs[v.ID] {
+					d.Views = append(d.Views, docs.View{Database: db, Schema: schema, Info: v})
+				}
+			}
+		}
+		for _, db := range info.DBs {
+			add(db.Name, "", db.Tables, db.Views)
+			for _, s := range db.Schemas {
+				add(db.Name, s.Name, s.Tables, s.Views)
+			}
+		}
+	}
+	sort.SliceStable(d.Tables, func(i, j int) bool {
+		a, b := d.Tables[i], d.Tables[j]
+		return docLess(a.Database, a.Schema, a.Info.Name, b.Database, b.Schema, b.Info.Name)
+	})
+	sort.SliceStable(d.Views, func(i, j int) bool {
+		a, b := d.Views[i], d.Views[j]
+		return docLess(a.Database, a.Schema, a.Info.Name, b.Database, b.Schema, b.Info.Name)
+	})
+	docs.AssignFiles(&d)
+	return d, nil
+}
+
+// docLess 按库、Schema、名称排序
+func docLess(db1, schema1, name1, db2, schema2, name2 string) bool {
+	if db1 != db2 {
+		return db1 < db2
+	}
+	if schema1 != schema2 {
+		return schema1 < schema2
+	}
+	return name1 < name2
+}
+
+// writeDocFile 写出文档文件，自动创建目录
+func writeDocFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create directory failed: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("write %s failed: %w", path, err)
+	}
+	return nil
+}
+
+// ExportHTMLSite 生成可离线浏览的静态文档站点：可搜索的索引、每张表与视图的页面、页面 ER 图
+func ExportHTMLSite(opts models.DocOptions) (models.DocResult, error) {
+	result := models.DocResult{OutputPath: opts.OutputPath, Files: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if opts.OutputPath == "" {
+		return result, fmt.Errorf("output path is required")
+	}
+	d, err := manager.loadDictionary(opts)
+	if err != nil {
+		return result, err
+	}
+	site, err := manager.buildSite(d, opts.PageKey)
+	if err != nil {
+		return result, err
+	}
+	files, err := docs.RenderSite(site)
+	if err != nil {
+		return result, fmt.Errorf("render site failed: %w", err)
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeDocFile(filepath.Join(opts.OutputPath, filepath.FromSlash(name)), files[name]); err != nil {
+			return result, err
+		}
+	}
+	result.Files = names
+	result.Tables, result.Views = len(d.Tables), len(d.Views)
+	return result, nil
+}
+
+// siteLinker 为站点内外的表与视图生成链接，站点外的对象只显示名称
+type siteLinker struct {
+	manager *MetadataService
+	tables  map[int64]docs.Table
+	views   map[int64]docs.View
+	names   map[string]string // kind:id -> 名称缓存
+}
+
+func (l *siteLinker) link(kind string, id int64) docs.SiteLink {
+	if kind == meta.LineageSourceView {
+		if v, ok := l.views[id]; ok {
+			return docs.SiteLink{Name: v.QualifiedName(), Href: docs.ViewHref(v)}
+		}
+	} else if t, ok := l.tables[id]; ok {
+		return docs.SiteLink{Name: t.QualifiedName(), Href: docs.TableHref(t)}
+	}
+	key := fmt.Sprintf("%s:%d", kind, id)
+	if name, ok := l.names[key]; ok {
+		return docs.SiteLink{Name: name}
+	}
+	var tableIDs, viewIDs []int64
+	if kind == meta.LineageSourceView {
+		viewIDs = []int64{id}
+	} else {
+		tableIDs = []int64{id}
+	}
+	name := key
+	if refs, err := l.manager.rawStorage.GetRawObjectsByIDs(tableIDs, viewIDs); err == nil && len(refs) > 0 {
+		name = qualifyName(refs[0].SchemaName, refs[0].Name)
+	}
+	l.names[key] = name
+	return docs.SiteLink{Name: name}
+}
+
+// buildSite 汇总表页面的关系与血缘、视图页面的字段来源以及相关页面的 ER 图
+func (m *MetadataService) buildSite(d docs.Dictionary, pageKey string) (docs.Site, error) {
+	site := docs.Site{Title: d.Title}
+	linker := &siteLinker{manager: m, tables: map[int64]docs.Table{}, views: map[int64]docs.View{}, names: map[string]string{}}
+	for _, t := range d.Tables {
+		linker.tables[t.Info.ID] = t
+	}
+	for _, v := range d.Views {
+		linker.views[v.Info.ID] = v
+	}
+
+	rows, err := m.relations.List(meta.RelationshipFilter{})
+	if err != nil {
+		return site, err
+	}
+	rels, err := m.relationshipVOs(rows)
+	if err != nil {
+		return site, err
+	}
+	outgoing, incoming := map[int64][]docs.SiteRelation{}, map[int64][]docs.SiteRelation{}
+	seen := map[string]bool{}
+	for _, r := range rels {
+		sig := relationshipSignature(r)
+		if r.Broken || seen[sig] {
+			continue
+		}
+		seen[sig] = true
+		var pairs []string
+		for _, c := range r.Columns {
+			pairs = append(pairs, c.SourceField+" = "+c.TargetField)
+		}
+		rel := docs.SiteRelation{
+			Columns:     strings.Join(pairs, ", "),
+			Cardinality: cardinalityMark(r.SourceCardinality) + ":" + cardinalityMark(r.TargetCardinality),
+			Label:       r.Label,
+		}
+		if _, ok := linker.tables[r.SourceTableID]; ok {
+			rel.Other = linker.link(meta.LineageSourceTable, r.TargetTableID)
+			outgoing[r.SourceTableID] = append(outgoing[r.SourceTableID], rel)
+		}
+		if _, ok := linker.tables[r.TargetTableID]; ok {
+			rel.Other = linker.link(meta.LineageSourceTable, r.SourceTableID)
+			incoming[r.TargetTableID] = append(incoming[r.TargetTableID], rel)
+		}
+	}
+
+	for _, t := range d.Tables {
+		st := docs.SiteTable{Table: t, Outgoing: outgoing[t.Info.ID], Incoming: incoming[t.Info.ID]}
+		edges, err := m.lineage.GetDownstreamEdges(meta.LineageSourceTable, t.Info.ID, "")
+		if err != nil {
+			return site, err
+		}
+		viewSeen := map[int64]bool{}
+		for _, e := range edges {
+			view := linker.link(meta.LineageSourceView, e.ViewID)
+			if !viewSeen[e.ViewID] {
+				viewSeen[e.ViewID] = true
+				st.Views = append(st.Views, view)
+			}
+			st.Lineage = append(st.Lineage, docs.SiteLineage{Column: e.SourceColumn, Other: view, OtherColumn: e.ViewColumn, Transform: e.Transform})
+		}
+		site.Tables = append(site.Tables, st)
+	}
+	for _, v := range d.Views {
+		sv := docs.SiteView{View: v}
+		edges, err := m.lineage.GetUpstreamEdges(v.Info.ID, "")
+		if err != nil {
+			return site, err
+		}
+		for _, e := range edges {
+			sv.Lineage = append(sv.Lineage, docs.SiteLineage{
+				Column: e.ViewColumn, Other: linker.link(e.SourceKind, e.SourceID), OtherColumn: e.SourceColumn, Transform: e.Transform,
+			})
+		}
+		site.Views = append(site.Views, sv)
+	}
+
+	diagrams, err := m.siteDiagrams(linker, pageKey)
+	if err != nil {
+		return site, err
+	}
+	site.Diagrams = diagrams
+	return site, nil
+}
+
+// siteDiagrams 生成包含站点内对象的页面 ER 图；指定页面时只生成该页面
+func (m *MetadataService) siteDiagrams(linker *siteLinker, pageKey string) ([]docs.SiteDiagram, error) {
+	pages, err := m.pages.ListPages()
+	if err != nil {
+		return nil, err
+	}
+	var out []docs.SiteDiagram
+	for _, p := range pages {
+		if p.Type != meta.PageTypePage || (pageKey != "" && p.Key != pageKey) {
+			continue
+		}
+		diagram, relevant, err := m.pageDiagram(p.Key, func(kind string, id int64) (string, bool) {
+			l := linker.link(kind, id)
+			if l.Href == "" {
+				return "", false
+			}
+			return "../" + l.Href, true
+		})
+		if err != nil {
+			return nil, err
+		}
+		if relevant {
+			out = append(out, docs.SiteDiagram{Key: p.Key, Title: p.Label, Diagram: diagram})
+		}
+	}
+	return out, nil
+}
+
+// pageDiagram 将页面内容转换为 ER 图；link 返回对象的链接及其是否属于导出范围
+func (m *MetadataService) pageDiagram(key string, link func(kind string, id int64) (string, bool)) (docs.Diagram, bool, error) {
+	content, err := GetPageContent(key)
+	if err != nil {
+		return docs.Diagram{}, false, err
+	}
+	diagram := docs.Diagram{Title: content.Page.Label}
+	relevant := false
+	nodeOf := map[int64]string{}
+	var tableIDs, viewIDs []int64
+	for _, n := range content.Nodes {
+		if n.ObjectKind == meta.PageObjectView {
+			viewIDs = append(viewIDs, n.ObjectID)
+		} else {
+			tableIDs = append(tableIDs, n.ObjectID)
+		}
+	}
+	tableAliases, viewAliases, err := m.voStorage.GetAliasesByIDs(tableIDs, viewIDs)
+	if err != nil {
+		return diagram, false, fmt.Errorf("load aliases failed: %w", err)
+	}
+	tableColors, viewColors, err := m.voStorage.GetColorsByIDs(tableIDs, viewIDs)
+	if err != nil {
+		return diagram, false, fmt.Errorf("load colors failed: %w", err)
+	}
+	comments := map[int64]string{}
+	if refs, err := m.rawStorage.GetRawObjectsByIDs(tableIDs, nil); err == nil {
+		for _, r := range refs {
+			comments[r.ID] = r.Comment
+		}
+	}
+	for _, n := range content.Nodes {
+		node := docs.DiagramNode{
+			Key:       n.Key,
+			Title:     n.ObjectName,
+			Database:  n.DatabaseName,
+			Schema:    n.SchemaName,
+			Alias:     tableAliases[n.ObjectID],
+			Comment:   comments[n.ObjectID],
+			Color:     n.Style.Color,
+			View:      n.ObjectKind == meta.PageObjectView,
+			Missing:   n.Missing,
+			Collapsed: n.Style.Collapsed,
+			X:         n.X,
+			Y:         n.Y,
+			Width:     n.Width,
+		}
+		if node.View {
+			node.Alias, node.Comment = viewAliases[n.ObjectID], ""
+		}
+		// 节点未单独设置颜色时使用表或视图的颜色
+		if node.Color == "" {
+			if node.View {
+				node.Color = viewColors[n.ObjectID]
+			} else {
+				node.Color = tableColors[n.ObjectID]
+			}
+		}
+		if n.Missing {
+			node.Title = fmt.Sprintf("%s %d (missing)", n.ObjectKind, n.ObjectID)
+		} else if href, ok := link(n.ObjectKind, n.ObjectID); ok {
+			node.Link = href
+			relevant = true
+		}
+		node.Columns, node.Hidden = m.diagramColumns(n.ObjectKind, n.ObjectID)
+		if n.ObjectKind == meta.PageObjectTable {
+			if _, ok := nodeOf[n.ObjectID]; !ok {
+				nodeOf[n.ObjectID] = n.Key
+			}
+		}
+		diagram.Nodes = append(diagram.Nodes, node)
+	}
+	for _, r := range content.Relationships {
+		from, ok1 := nodeOf[r.SourceTableID]
+		to, ok2 := nodeOf[r.TargetTableID]
+		if !ok1 || !ok2 || len(r.Columns) == 0 {
+			continue
+		}
+		edge := docs.DiagramEdge{
+			From: from, To: to,
+			FromColumn: r.Columns[0].SourceField, ToColumn: r.Columns[0].TargetField,
+			FromCardinality: r.SourceCardinality, ToCardinality: r.TargetCardinality,
+			Label: r.Label,
+		}
+		for _, c := range r.Columns {
+			edge.FromColumns = append(edge.FromColumns, c.SourceField)
+			edge.ToColumns = append(edge.ToColumns, c.TargetField)
+		}
+		diagram.Edges = append(diagram.Edges, edge)
+	}
+	markForeignColumns(&diagram)
+	return diagram, relevant, nil
+}
+
+// markForeignColumns 将关系引用方的字段标记为外键
+func markForeignColumns(d *docs.Diagram) {
+	foreign := map[string]map[string]bool{}
+	for _, e := range d.Edges {
+		if foreign[e.From] == nil {
+			foreign[e.From] = map[string]bool{}
+		}
+		for _, c := range e.FromColumns {
+			foreign[e.From][c] = true
+		}
+	}
+	for i := range d.Nodes {
+		n := &d.Nodes[i]
+		for j := range n.Columns {
+			n.Columns[j].Foreign = foreign[n.Key][n.Columns[j].Name]
+		}
+		for j := range n.Hidden {
+			n.Hidden[j].Foreign = foreign[n.Key][n.Hidden[j].Name]
+		}
+	}
+}
+
+// diagramColumns 节点的字段行：表按字段排序取显示的字段（尚无业务配置时取全部原始字段），
+// 视图取解析出的字段；第二个返回值为未显示的字段
+func (m *MetadataService) diagramColumns(kind string, id int64) ([]docs.DiagramColumn, []docs.DiagramColumn) {
+	var cols, hidden []docs.DiagramColumn
+	if kind == meta.PageObjectView {
+		if parsed, err := ParseViewByID(id); err == nil {
+			for _, f := range parsed.Fields {
+				cols = append(cols, docs.DiagramColumn{Name: f.Name, Type: f.Type, Key: f.Key, Alias: f.Alias, Comment: f.Comment, Nullable: f.Nullable})
+			}
+		}
+		return cols, nil
+	}
+	fields, err := GetFieldsVOByTableID(id)
+	if err != nil || len(fields) == 0 {
+		rows, err := m.rawStorage.GetRawFieldsRows(id)
+		if err != nil {
+			return nil, nil
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+		fields = fields[:0]
+		for _, f := range rows {
+			fields = append(fields, models.FieldInfoVO{Name: f.Name, Type: f.Type, Key: f.Key, Comment: f.Comment, Nullable: f.Nullable, Display: true})
+		}
+	}
+	for _, f := range fields {
+		c := docs.DiagramColumn{Name: f.Name, Type: f.Type, Key: f.Key, Alias: f.Alias, Comment: f.Comment, Nullable: f.Nullable}
+		if f.Display {
+			cols = append(cols, c)
+		} else {
+			hidden = append(hidden, c)
+		}
+	}
+	return cols, hidden
+}
+
+// cardinalityMark 基数的简写
+func cardinalityMark(c string) string {
+	switch c {
+	case models.CardinalityOne:
+		return "1"
+	case models.CardinalityMany:
+		return "N"
+	default:
+		return "-"
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"dbrun/app/explain"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"dbrun/app/sqlparse"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExplainQuery 获取单条语句的执行计划（不执行语句本身），并与查询历史一起保存
+func ExplainQuery(configID int64, sqlText string) (models.ExplainResult, error) {
+	result := models.ExplainResult{}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	conn, creds, err := getQueryConnection(manager, configID)
+	if err != nil {
+		return result, err
+	}
+	stmts, err := sqlparse.Classify(creds.Type, sqlText)
+	if err != nil {
+		return result, err
+	}
+	if len(stmts) != 1 {
+		return result, fmt.Errorf("explain requires exactly one statement, got %d", len(stmts))
+	}
+	st := stmts[0]
+	if st.Kind != sqlparse.KindSelect && st.Kind != sqlparse.KindDML {
+		return result, fmt.Errorf("only SELECT and DML statements can be explained, got %s", st.Keyword)
+	}
+	result.SQL = st.SQL
+	result.Dialect = creds.Type
+
+	ctx := context.Background()
+	session, err := conn.OpenSession(ctx)
+	if err != nil {
+		return result, err
+	}
+	defer session.Close()
+
+	start := time.Now()
+	err = capturePlan(ctx, session, creds.Type, &result)
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	result.HistoryID = recordHistory(manager, configID, st.SQL, nil, models.QueryResult{
+		Kind:       string(sqlparse.KindExplain),
+		DurationMs: result.DurationMs,
+	}, err)
+	if err != nil {
+		return result, err
+	}
+	if result.HistoryID != 0 {
+		tree, _ := json.Marshal(result.Root)
+		plan := &meta.QueryPlan{
+			HistoryID: result.HistoryID,
+			Dialect:   result.Dialect,
+			Format:    result.Format,
+			Raw:       result.Raw,
+			Tree:      string(tree),
+		}
+		if err := manager.GetQueryStorage().SavePlan(plan); err != nil {
+			fmt.Printf("[Explain] save plan failed: historyID=%d err=%v\n", result.HistoryID, err)
+		}
+	}
+	return result, nil
+}
+
+// GetExplainPlan 读取与历史记录一起保存的执行计划
+func GetExplainPlan(historyID int64) (models.ExplainResult, error) {
+	result := models.ExplainResult{HistoryID: historyID}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	plan, err := manager.GetQueryStorage().GetPlanByHistoryID(historyID)
+	if err != nil {
+		return result, fmt.Errorf("get explain plan failed: %w", err)
+	}
+	var root models.PlanNode
+	if err := json.Unmarshal([]byte(plan.Tree), &root); err != nil {
+		return result, fmt.Errorf("invalid stored plan tree: %w", err)
+	}
+	result.Dialect = plan.Dialect
+	result.Format = plan.Format
+	result.Raw = plan.Raw
+	result.Root = &root
+	return result, nil
+}
+
+// capturePlan 按方言获取原始计划并解析为统一的计划树
+func capturePlan(ctx context.Context, session *sql.Conn, dbType string, result *models.ExplainResult) error {
+	var err error
+	switch dbType {
+	case "mysql", "mariadb", "postgresql":
+		cmd, _ := explain.Command(dbType, result.SQL)
+		result.Format = "json"
+		if err = session.QueryRowContext(ctx, cmd).Scan(&result.Raw); err != nil {
+			return fmt.Errorf("explain failed: %w", err)
+		}
+		if dbType == "postgresql" {
+			result.Root, err = explain.ParsePostgres(result.Raw)
+		} else {
+			result.Root, err = explain.ParseMySQL(result.Raw)
+		}
+	case "sqlserver":
+		result.Format = "xml"
+		if result.Raw, err = sqlServerShowplan(ctx, session, result.SQL); err != nil {
+			return err
+		}
+		result.Root, err = explain.ParseSQLServer(result.Raw)
+	case "oracle":
+		result.Format = "text"
+		result.Root, result.Raw, err = oraclePlan(ctx, session, result.SQL)
+	default:
+		return fmt.Errorf("explain is not supported for database type: %s", dbType)
+	}
+	return err
+}
+
+// sqlServerShowplan 在会话上开启 SHOWPLAN_XML 后提交语句，获取估算计划（语句不会被执行）
+func sqlServerShowplan(ctx context.Context, session *sql.Conn, sqlText string) (string, error) {
+	if _, err := session.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return "", fmt.Errorf("enable showplan failed: %w", err)
+	}
+	defer func() {
+		// 查询超时取消 ctx 后仍需关闭 SHOWPLAN；关闭失败时丢弃该连接，避免仍处于 SHOWPLAN 模式的连接回到连接池
+		resetCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := session.ExecContext(resetCtx, "SET SHOWPLAN_XML OFF"); err != nil {
+			fmt.Printf("[Explain] disable showplan failed, discarding connection: %v\n", err)
+			_ = session.Raw(func(any) error { return driver.ErrBadConn })
+			_ = session.Close()
+		}
+	}()
+	rows, err := session.QueryContext(ctx, sqlText)
+	if err != nil {
+		return "", fmt.Errorf("explain failed: %w", err)
+	}
+	defer rows.Close()
+	var plans []string
+	for {
+		for rows.Next() {
+			var plan string
+			if err := rows.Scan(&plan); err != nil {
+				return "", fmt.Errorf("failed to read showplan: %w", err)
+			}
+			plans = append(plans, plan)
+		}
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	if len(plans) == 0 {
+		return "", fmt.Errorf("sqlserver returned no showplan")
+	}
+	return plans[0], nil
+}
+
+// oraclePlan 通过 EXPLAIN PLAN 写入 PLAN_TABLE，读取结构化行与 DBMS_XPLAN 文本后清理
+func oraclePlan(ctx context.Context, session *sql.Conn, sqlText string) (*models.PlanNode, string, error) {
+	stmtID := explain.OracleStatementID(time.Now().UnixNano())
+	if _, err := session.ExecContext(ctx, fmt.Sprintf("EXPLAIN PLAN SET STATEMENT_ID = '%s' FOR %s", stmtID, sqlText)); err != nil {
+		return nil, "", fmt.Errorf("explain failed: %w", err)
+	}
+	defer func() {
+		if _, err := session.ExecContext(ctx, "DELETE FROM PLAN_TABLE WHERE STATEMENT_ID = :1", stmtID); err != nil {
+			fmt.Printf("[Explain] clean plan table failed: %v\n", err)
+		}
+	}()
+
+	rows, err := session.QueryContext(ctx, `SELECT ID, PARENT_ID, OPERATION, OPTIONS, OBJECT_OWNER, OBJECT_NAME,
+		CARDINALITY, COST, ACCESS_PREDICATES, FILTER_PREDICATES
+		FROM PLAN_TABLE WHERE STATEMENT_ID = :1 ORDER BY ID`, stmtID)
+	if err != nil {
+		return nil, "", fmt.Errorf("read plan table failed: %w", err)
+	}
+	var planRows []explain.OraclePlanRow
+	for rows.Next() {
+		var (
+			id                              int64
+			parentID                        sql.NullInt64
+			operation, options, owner, name sql.NullString
+			cardinality, cost               sql.NullFloat64
+			access, filter                  sql.NullString
+		)
+		if err := rows.Scan(&id, &parentID, &operation, &options, &owner, &name, &cardinality, &cost, &access, &filter); err != nil {
+			rows.Close()
+			return nil, "", fmt.Errorf("failed to scan plan row: %w", err)
+		}
+		r := explain.OraclePlanRow{
+			ID:          id,
+			Operation:   operation.String,
+			Options:     options.String,
+			ObjectOwner: owner.String,
+			ObjectName:  name.String,
+			Cardinality: cardinality.Float64,
+			Cost:        cost.Float64,
+			Access:      access.String,
+			Filter:      filter.String,
+		}
+		if parentID.Valid {
+			p := parentID.Int64
+			r.ParentID = &p
+		}
+		planRows = append(planRows, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+	root, err := explain.BuildOracle(planRows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var lines []string
+	textRows, err := session.QueryContext(ctx, "SELECT PLAN_TABLE_OUTPUT FROM TABLE(DBMS_XPLAN.DISPLAY('PLAN_TABLE', :1, 'TYPICAL'))", stmtID)
+	if err != nil {
+		fmt.Printf("[Explain] DBMS_XPLAN.DISPLAY failed: %v\n", err)
+		return root, "", nil
+	}
+	defer textRows.Close()
+	for textRows.Next() {
+		var line sql.NullString
+		if err := textRows.Scan(&line); err == nil {
+			lines = append(lines, line.String)
+		}
+	}
+	return root, strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"dbrun/app/connect"
+	"dbrun/app/dialect"
+	"dbrun/app/export"
+	"dbrun/app/models"
+	"dbrun/app/sqlparse"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// 进度事件的最小间隔
+const exportProgressInterval = 300 * time.Millisecond
+
+// ExportProgressFunc 导出进度回调
+type ExportProgressFunc func(models.ExportProgress)
+
+// 正在进行的导出任务，用于取消
+var (
+	exportJobs  = map[string]context.CancelFunc{}
+	exportMutex sync.Mutex
+)
+
+// CancelExport 取消正在进行的导出，返回任务是否存在
+func CancelExport(exportID string) bool {
+	exportMutex.Lock()
+	defer exportMutex.Unlock()
+	cancel, ok := exportJobs[exportID]
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// registerExport 登记导出任务，返回带取消能力的上下文与注销函数
+func registerExport(exportID string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if exportID == "" {
+		return ctx, cancel
+	}
+	exportMutex.Lock()
+	exportJobs[exportID] = cancel
+	exportMutex.Unlock()
+	return ctx, func() {
+		exportMutex.Lock()
+		delete(exportJobs, exportID)
+		exportMutex.Unlock()
+		cancel()
+	}
+}
+
+// ExportQuery 执行单条查询语句并将结果流式写入文件
+func ExportQuery(configID int64, sqlText string, opts models.ExportOptions, progress ExportProgressFunc) (models.ExportResult, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.ExportResult{}, err
+	}
+	conn, creds, err := getQueryConnection(manager, configID)
+	if err != nil {
+		return models.ExportResult{}, err
+	}
+	stmts, err := checkStatements(creds, sqlText)
+	if err != nil {
+		return models.ExportResult{}, err
+	}
+	if len(stmts) != 1 || !statementReturnsRows(stmts[0].Kind) {
+		return models.ExportResult{}, fmt.Errorf("export requires exactly one statement that returns rows")
+	}
+	return runExport(manager, conn, configID, stmts[0].SQL, nil, creds.Type, creds.IsReadOnly(), opts, progress)
+}
+
+// ExportTableData 按预览的过滤与排序条件导出整张表的数据
+func ExportTableData(tableID int64, filters []models.ColumnFilter, sorts []models.SortOrder, opts models.ExportOptions, progress ExportProgressFunc) (models.ExportResult, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.ExportResult{}, err
+	}
+	target, err := resolveTableTarget(manager, tableID)
+	if err != nil {
+		return models.ExportResult{}, err
+	}
+	conn, creds, err := getQueryConnection(manager, target.configID)
+	if err != nil {
+		return models.ExportResult{}, err
+	}
+	d, err := dialect.Get(creds.Type)
+	if err != nil {
+		return models.ExportResult{}, err
+	}
+	q, err := buildPreviewQuery(d, target, filters, sorts)
+	if err != nil {
+		return models.ExportResult{}, err
+	}
+	if opts.TableName == "" {
+		opts.TableName = target.tableName
+	}
+	sqlText := dialect.SelectAll(q.columns, q.from, q.where, q.orderBy)
+	return runExport(manager, conn, target.configID, sqlText, q.args, creds.Type, creds.IsReadOnly(), opts, progress)
+}
+
+// countingWriter 统计写出的字节数
+type countingWriter struct {
+	w     io.Writer
+	count int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.count += int64(n)
+	return n, err
+}
+
+// runExport 在独立会话中执行查询并逐行写出；先写入临时文件，成功后再重命名为目标文件
+func runExport(manager *MetadataService, conn connect.Connection, configID int64, sqlText string, args []interface{}, sourceType string, readOnly bool, opts models.ExportOptions, progress ExportProgressFunc) (models.ExportResult, error) {
+	result := models.ExportResult{ExportID: opts.ExportID, Format: opts.Format, Warnings: []string{}}
+	if opts.FilePath == "" {
+		return result, fmt.Errorf("export file path is required")
+	}
+	if filepath.Ext(opts.FilePath) == "" {
+		opts.FilePath += export.DefaultExtension(opts.Format)
+	}
+	result.FilePath = opts.FilePath
+	report := func(p models.ExportProgress) {
+		if progress != nil {
+			p.ExportID = opts.ExportID
+			progress(p)
+		}
+	}
+
+	ctx, done := registerExport(opts.ExportID)
+	defer done()
+
+	start := time.Now()
+	var rowCount int64
+	counter, err := exportToFile(ctx, conn, sqlText, args, sourceType, readOnly, opts, func(rows int64, bytes int64) {
+		report(models.ExportProgress{Rows: rows, Bytes: bytes})
+	}, &rowCount, &result.Warnings)
+	result.Rows = rowCount
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	recordHistory(manager, configID, sqlText, args, models.QueryResult{
+		Kind:       string(sqlparse.KindSelect),
+		RowCount:   int(rowCount),
+		DurationMs: result.DurationMs,
+	}, err)
+
+	final := models.ExportProgress{Rows: rowCount, Done: true}
+	if counter != nil {
+		final.Bytes = counter.count
+		result.Bytes = counter.count
+	}
+	if err != nil {
+		final.Error = err.Error()
+		report(final)
+		return result, err
+	}
+	report(final)
+	fmt.Printf("[Export] %d rows written to %s in %dms\n", rowCount, opts.FilePath, result.DurationMs)
+	for _, w := range result.Warnings {
+		fmt.Printf("[Export] warning: %s\n", w)
+	}
+	return result, nil
+}
+
+// exportToFile 执行查询并写出到临时文件，完成后原子替换目标文件
+func exportToFile(ctx context.Context, conn connect.Connection, sqlText string, args []interface{}, sourceType string, readOnly bool, opts models.ExportOptions, onProgress func(rows, bytes int64), rowCount *int64, warnings *[]string) (*countingWriter, error) {
+	if err := os.MkdirAll(filepath.Dir(opts.FilePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	tmpPath := opts.FilePath + ".part"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+	success := false
+	defer func() {
+		file.Close()
+		if !success {
+			os.Remove(tmpPath)
+		}
+	}()
+
+	counter := &countingWriter{w: file}
+	buf := bufio.NewWriterSize(counter, 256*1024)
+	writer, err := export.NewRowWriter(buf, opts, sourceType)
+	if err != nil {
+		return counter, err
+	}
+
+	session, err := conn.OpenSession(ctx)
+	if err != nil {
+		return counter, err
+	}
+	defer session.Close()
+	var exec sqlExecutor = session
+	if readOnly {
+		// 与 ExecuteQuery 相同，只读连接上的导出在只读事务中执行，结束后回滚
+		tx, err := conn.BeginReadOnly(ctx, session)
+		if err != nil {
+			return counter, err
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+	rows, err := exec.QueryContext(ctx, sqlText, args...)
+	if err != nil {
+		return counter, fmt.Errorf("export query failed: %w", err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return counter, fmt.Errorf("failed to read column types: %w", err)
+	}
+	columns := make([]export.Column, len(types))
+	for i, ct := range types {
+		columns[i] = export.Column{Name: ct.Name(), DatabaseType: ct.DatabaseTypeName()}
+	}
+	if err := writer.WriteHeader(columns); err != nil {
+		return counter, err
+	}
+
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	lastReport := time.Now()
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return counter, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := writer.WriteRow(values); err != nil {
+			return counter, fmt.Errorf("failed to write row: %w", err)
+		}
+		*rowCount++
+		if *rowCount%1000 == 0 && time.Since(lastReport) >= exportProgressInterval {
+			lastReport = time.Now()
+			onProgress(*rowCount, counter.count)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		if ctx.Err() != nil {
+			return counter, fmt.Errorf("export canceled")
+		}
+		return counter, err
+	}
+	if err := writer.Close(); err != nil {
+		return counter, err
+	}
+	if w, ok := writer.(export.WarningWriter); ok {
+		*warnings = append(*warnings, w.Warnings()...)
+	}
+	if err := buf.Flush(); err != nil {
+		return counter, err
+	}
+	if err := file.Close(); err != nil {
+		return counter, err
+	}
+	if err := os.Rename(tmpPath, opts.FilePath); err != nil {
+		return counter, fmt.Errorf("failed to move export file into place: %w", err)
+	}
+	success = true
+	return counter, nil
+}
@@ -0,0 +1,328 @@
+package service
+
+import (
+	"bufio"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 项目目录下保存SQL片段的子目录
+const snippetsDirName = "snippets"
+
+// 片段目录下记录上次导出文件清单的文件，导出时只清理清单中已不再对应片段的文件
+const snippetsManifestName = ".exported.json"
+
+// invalidFileNameChars Windows 文件名中不允许出现的字符
+const invalidFileNameChars = `:*?"<>|`
+
+// recordHistory 记录一次语句执行并返回记录ID；写入失败只打印日志（返回0），不影响查询结果
+func recordHistory(manager *MetadataService, configID int64, sqlText string, args []interface{}, res models.QueryResult, execErr error) int64 {
+	h := &meta.QueryHistory{
+		ConfigID:     configID,
+		SQL:          sqlText,
+		Kind:         res.Kind,
+		DurationMs:   res.DurationMs,
+		RowCount:     res.RowCount,
+		RowsAffected: res.RowsAffected,
+		ExecutedAt:   time.Now(),
+	}
+	if len(args) > 0 {
+		if b, err := json.Marshal(args); err == nil {
+			h.Params = string(b)
+		}
+	}
+	if execErr != nil {
+		h.Error = execErr.Error()
+	}
+	if err := manager.GetQueryStorage().AddHistory(h); err != nil {
+		fmt.Printf("[QueryHistory] save history failed: configID=%d err=%v\n", configID, err)
+		return 0
+	}
+	return h.ID
+}
+
+// SearchQueryHistory 检索查询历史
+func SearchQueryHistory(filter meta.HistoryFilter) (meta.HistoryPage, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return meta.HistoryPage{}, err
+	}
+	return manager.GetQueryStorage().SearchHistory(filter)
+}
+
+// DeleteQueryHistory 删除指定的历史记录
+func DeleteQueryHistory(ids []int64) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	return manager.GetQueryStorage().DeleteHistory(ids)
+}
+
+// ClearQueryHistory 清空历史记录，configID 为0时清空全部
+func ClearQueryHistory(configID int64) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	return manager.GetQueryStorage().ClearHistory(configID)
+}
+
+// SaveSnippet 保存SQL片段
+func SaveSnippet(snippet meta.QuerySnippet) (meta.QuerySnippet, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return snippet, err
+	}
+	snippet.Name = strings.TrimSpace(snippet.Name)
+	snippet.Folder = normalizeSnippetFolder(snippet.Folder)
+	if snippet.Name == "" {
+		return snippet, fmt.Errorf("snippet name is required")
+	}
+	if strings.ContainsAny(snippet.Name, `/\`) {
+		return snippet, fmt.Errorf("snippet name must not contain path separators: %s", snippet.Name)
+	}
+	if strings.ContainsAny(snippet.Name+snippet.Folder, invalidFileNameChars) {
+		return snippet, fmt.Errorf("snippet name and folder must not contain any of %s", invalidFileNameChars)
+	}
+	if err := manager.GetQueryStorage().SaveSnippet(&snippet); err != nil {
+		return snippet, fmt.Errorf("save snippet failed: %w", err)
+	}
+	return snippet, nil
+}
+
+// ListSnippets 列出SQL片段
+func ListSnippets(folder string, keyword string) ([]meta.QuerySnippet, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	return manager.GetQueryStorage().ListSnippets(folder, keyword)
+}
+
+// DeleteSnippet 删除SQL片段
+func DeleteSnippet(id int64) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	return manager.GetQueryStorage().DeleteSnippet(id)
+}
+
+// normalizeSnippetFolder 统一文件夹格式：使用 / 分隔，去除首尾分隔符与空段
+func normalizeSnippetFolder(folder string) string {
+	parts := strings.FieldsFunc(strings.ReplaceAll(folder, `\`, "/"), func(r rune) bool { return r == '/' })
+	clean := parts[:0]
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" && p != "." && p != ".." {
+			clean = append(clean, p)
+		}
+	}
+	return strings.Join(clean, "/")
+}
+
+// ExportSnippets 将全部片段导出为项目目录下 snippets/<folder>/<name>.sql 文件，返回导出数量
+// 上次导出写入、但已不再对应片段的文件会被删除，用户自行放入的文件保持不变
+func ExportSnippets() (int, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return 0, err
+	}
+	list, err := manager.GetQueryStorage().ListSnippets("", "")
+	if err != nil {
+		return 0, err
+	}
+	root := filepath.Join(manager.ProjectDir(), snippetsDirName)
+	previous, err := readSnippetManifest(root)
+	if err != nil {
+		return 0, err
+	}
+	written := map[string]bool{}
+	var files []string
+	for _, s := range list {
+		rel := snippetFilePath(s, written)
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return 0, fmt.Errorf("failed to create snippet directory %s: %w", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(formatSnippetFile(s)), 0644); err != nil {
+			return 0, fmt.Errorf("failed to write snippet %s: %w", path, err)
+		}
+		files = append(files, rel)
+	}
+	for _, rel := range previous {
+		// 清单被手工修改时不删除 snippets 目录以外的文件
+		if written[strings.ToLower(rel)] || !filepath.IsLocal(filepath.FromSlash(rel)) {
+			continue
+		}
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("failed to remove stale snippet %s: %w", path, err)
+		}
+	}
+	if err := writeSnippetManifest(root, files); err != nil {
+		return 0, err
+	}
+	fmt.Printf("[Snippets] exported %d snippets to %s\n", len(list), root)
+	return len(list), nil
+}
+
+// snippetFilePath 片段文件相对 snippets 目录的路径（/ 分隔）；
+// 文件名中 Windows 不允许的字符替换为 _，替换或大小写导致重名时追加序号
+func snippetFilePath(s meta.QuerySnippet, used map[string]bool) string {
+	var parts []string
+	if s.Folder != "" {
+		for _, p := range strings.Split(s.Folder, "/") {
+			parts = append(parts, safeFileName(p))
+		}
+	}
+	base := strings.Join(append(parts, safeFileName(s.Name)), "/")
+	rel := base + ".sql"
+	for i := 2; used[strings.ToLower(rel)]; i++ {
+		rel = fmt.Sprintf("%s (%d).sql", base, i)
+	}
+	used[strings.ToLower(rel)] = true
+	return rel
+}
+
+// safeFileName 将 Windows 文件名中不允许的字符与控制字符替换为 _
+func safeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(invalidFileNameChars, r) {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+// readSnippetManifest 读取上次导出的文件清单，清单不存在时返回空
+func readSnippetManifest(root string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(root, snippetsManifestName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snippet manifest: %w", err)
+	}
+	var files []string
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, fmt.Errorf("failed to parse snippet manifest: %w", err)
+	}
+	return files, nil
+}
+
+// writeSnippetManifest 保存本次导出的文件清单
+func writeSnippetManifest(root string, files []string) error {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return fmt.Errorf("failed to create snippet directory %s: %w", root, err)
+	}
+	data, err := json.MarshalIndent(files, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(root, snippetsManifestName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write snippet manifest: %w", err)
+	}
+	return nil
+}
+
+// ImportSnippets 从项目目录的 snippets 子目录导入片段，同一文件夹下同名片段会被覆盖
+func ImportSnippets() (int, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return 0, err
+	}
+	root := filepath.Join(manager.ProjectDir(), snippetsDirName)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return 0, nil
+	}
+	count := 0
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".sql") {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read snippet %s: %w", path, err)
+		}
+		rel, _ := filepath.Rel(root, filepath.Dir(path))
+		s := parseSnippetFile(string(data))
+		s.Folder = normalizeSnippetFolder(filepath.ToSlash(rel))
+		if s.Name == "" {
+			s.Name = strings.TrimSuffix(d.Name(), filepath.Ext(d.Name()))
+		}
+		if err := manager.GetQueryStorage().UpsertSnippetByPath(&s); err != nil {
+			return fmt.Errorf("failed to import snippet %s: %w", path, err)
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// 片段文件头部的元信息行
+const (
+	snippetHeaderName        = "-- name:"
+	snippetHeaderDescription = "-- description:"
+	snippetHeaderConnection  = "-- connection:"
+)
+
+// formatSnippetFile 生成片段文件内容：元信息注释头 + SQL
+func formatSnippetFile(s meta.QuerySnippet) string {
+	var b strings.Builder
+	b.WriteString(snippetHeaderName + " " + s.Name + "\n")
+	if s.Description != "" {
+		desc := strings.Join(strings.Fields(s.Description), " ")
+		b.WriteString(snippetHeaderDescription + " " + desc + "\n")
+	}
+	if s.ConfigID != nil {
+		b.WriteString(fmt.Sprintf("%s %d\n", snippetHeaderConnection, *s.ConfigID))
+	}
+	b.WriteString(strings.TrimRight(s.SQL, "\r\n"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// parseSnippetFile 解析片段文件，文件开头连续的元信息注释行之后为SQL
+func parseSnippetFile(content string) meta.QuerySnippet {
+	var s meta.QuerySnippet
+	var body []string
+	inHeader := true
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inHeader {
+			trimmed := strings.TrimSpace(line)
+			switch {
+			case strings.HasPrefix(trimmed, snippetHeaderName):
+				s.Name = strings.TrimSpace(strings.TrimPrefix(trimmed, snippetHeaderName))
+				continue
+			case strings.HasPrefix(trimmed, snippetHeaderDescription):
+				s.Description = strings.TrimSpace(strings.TrimPrefix(trimmed, snippetHeaderDescription))
+				continue
+			case strings.HasPrefix(trimmed, snippetHeaderConnection):
+				if id, err := strconv.ParseInt(strings.TrimSpace(strings.TrimPrefix(trimmed, snippetHeaderConnection)), 10, 64); err == nil {
+					s.ConfigID = &id
+				}
+				continue
+			}
+			inHeader = false
+		}
+		body = append(body, line)
+	}
+	s.SQL = strings.TrimSpace(strings.Join(body, "\n"))
+	return s
+}
@@ -0,0 +1,704 @@
+package service
+
+import (
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// 布局默认参数，节点尺寸与前端表节点一致（宽 16rem）
+const (
+	layoutNodeWidth     = 256.0
+	layoutHeaderHeight  = 40.0
+	layoutRowHeight     = 32.0
+	layoutMargin        = 40.0
+	defaultNodeSpacing  = 60.0
+	defaultRankSpacing  = 160.0
+	defaultLayoutSweeps = 24
+	defaultForceIters   = 300
+	defaultViewColumns  = 4   // 视图无法解析时按此列数估算高度
+	layoutGravity       = 1.0 // 力导向布局中指向中心的引力系数
+)
+
+// layoutEdge 布局用的有向边：from 为被引用方（一），to 为引用方（多）
+type layoutEdge struct {
+	from, to int
+}
+
+// LayoutPage 计算页面节点的自动布局；opts.Save 为 true 时写回节点位置
+func LayoutPage(key string, opts models.LayoutOptions) (models.LayoutResult, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.LayoutResult{}, err
+	}
+	page, err := manager.requirePage(key)
+	if err != nil {
+		return models.LayoutResult{}, err
+	}
+	rows, err := manager.pages.ListNodes(page.ID)
+	if err != nil {
+		return models.LayoutResult{}, fmt.Errorf("list page nodes failed: %w", err)
+	}
+	rels, err := manager.relations.List(meta.RelationshipFilter{PageID: &key})
+	if err != nil {
+		return models.LayoutResult{}, fmt.Errorf("list relationships failed: %w", err)
+	}
+	nodes := make([]models.LayoutNode, 0, len(rows))
+	for _, n := range rows {
+		nodes = append(nodes, models.LayoutNode{
+			Key:    n.NodeKey,
+			Width:  layoutNodeWidth,
+			Height: manager.nodeHeight(n.ObjectKind, n.ObjectID),
+		})
+	}
+	result := computeLayout(nodes, pageLayoutEdges(rows, rels), opts)
+	if !opts.Save {
+		return result, nil
+	}
+	positions := map[string]models.LayoutNode{}
+	for _, n := range result.Nodes {
+		positions[n.Key] = n
+	}
+	for i := range rows {
+		p := positions[rows[i].NodeKey]
+		rows[i].X, rows[i].Y, rows[i].Width, rows[i].Height = p.X, p.Y, p.Width, p.Height
+	}
+	if err := manager.pages.ReplaceNodes(page.ID, rows); err != nil {
+		return result, fmt.Errorf("save layout failed: %w", err)
+	}
+	return result, nil
+}
+
+// nodeHeight 按字段数估算节点高度
+func (m *MetadataService) nodeHeight(kind string, objectID int64) float64 {
+	count := defaultViewColumns
+	if kind == meta.PageObjectView {
+		if parsed, err := ParseViewByID(objectID); err == nil && len(parsed.Fields) > 0 {
+			count = len(parsed.Fields)
+		}
+	} else if fields, err := m.rawStorage.GetRawFieldsRows(objectID); err == nil {
+		count = len(fields)
+	}
+	return layoutHeaderHeight + float64(count)*layoutRowHeight
+}
+
+// pageLayoutEdges 将关系转换为节点间的有向边（同一张表出现多次时每个节点都连接）
+func pageLayoutEdges(rows []meta.PageNode, rels []meta.Relationship) []layoutEdge {
+	byTable := map[int64][]int{}
+	for i, n := range rows {
+		if n.ObjectKind == meta.PageObjectTable {
+			byTable[n.ObjectID] = append(byTable[n.ObjectID], i)
+		}
+	}
+	var edges []layoutEdge
+	for _, r := range rels {
+		for _, from := range byTable[r.TargetTableID] {
+			for _, to := range byTable[r.SourceTableID] {
+				edges = append(edges, layoutEdge{from: from, to: to})
+			}
+		}
+	}
+	return edges
+}
+
+// computeLayout 计算布局；节点按 Key 排序后处理，相同输入总是得到相同结果
+func computeLayout(nodes []models.LayoutNode, edges []layoutEdge, opts models.LayoutOptions) models.LayoutResult {
+	if opts.Algorithm == "" {
+		opts.Algorithm = models.LayoutLayered
+	}
+	if opts.Direction == "" {
+		opts.Direction = "LR"
+	}
+	if opts.NodeSpacing <= 0 {
+		opts.NodeSpacing = defaultNodeSpacing
+	}
+	if opts.RankSpacing <= 0 {
+		opts.RankSpacing = defaultRankSpacing
+	}
+
+	// 按 Key 排序并重映射边，去除自环与重复边
+	order := make([]int, len(nodes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return nodes[order[a]].Key < nodes[order[b]].Key })
+	index := make([]int, len(nodes))
+	sorted := make([]models.LayoutNode, len(nodes))
+	for pos, i := range order {
+		index[i] = pos
+		sorted[pos] = nodes[i]
+	}
+	seen := map[layoutEdge]bool{}
+	var graph []layoutEdge
+	for _, e := range edges {
+		ne := layoutEdge{from: index[e.from], to: index[e.to]}
+		if ne.from == ne.to || seen[ne] {
+			continue
+		}
+		seen[ne] = true
+		graph = append(graph, ne)
+	}
+	sort.Slice(graph, func(a, b int) bool {
+		if graph[a].from != graph[b].from {
+			return graph[a].from < graph[b].from
+		}
+		return graph[a].to < graph[b].to
+	})
+
+	switch opts.Algorithm {
+	case models.LayoutForce:
+		if opts.Iterations <= 0 {
+			opts.Iterations = defaultForceIters
+		}
+		forceLayout(sorted, graph, opts)
+	default:
+		opts.Algorithm = models.LayoutLayered
+		if opts.Iterations <= 0 {
+			opts.Iterations = defaultLayoutSweeps
+		}
+		layeredLayout(sorted, graph, opts)
+	}
+	return normalizeLayout(sorted, opts.Algorithm)
+}
+
+// normalizeLayout 平移到画布边距内并计算整体尺寸
+func normalizeLayout(nodes []models.LayoutNode, algorithm string) models.LayoutResult {
+	result := models.LayoutResult{Algorithm: algorithm, Nodes: nodes}
+	if len(nodes) == 0 {
+		result.Nodes = []models.LayoutNode{}
+		return result
+	}
+	minX, minY := math.Inf(1), math.Inf(1)
+	for _, n := range nodes {
+		minX, minY = math.Min(minX, n.X), math.Min(minY, n.Y)
+	}
+	for i := range nodes {
+		nodes[i].X = math.Round(nodes[i].X - minX + layoutMargin)
+		nodes[i].Y = math.Round(nodes[i].Y - minY + layoutMargin)
+		result.Width = math.Max(result.Width, nodes[i].X+nodes[i].Width+layoutMargin)
+		result.Height = math.Max(result.Height, nodes[i].Y+nodes[i].Height+layoutMargin)
+	}
+	return result
+}
+
+// layoutComponents 按连通分量分组，分量内节点升序；分量按规模降序、其次按最小节点排序
+func layoutComponents(n int, edges []layoutEdge) [][]int {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(x int) int {
+		for parent[x] != x {
+			parent[x] = parent[parent[x]]
+			x = parent[x]
+		}
+		return x
+	}
+	for _, e := range edges {
+		a, b := find(e.from), find(e.to)
+		if a < b {
+			parent[b] = a
+		} else if b < a {
+			parent[a] = b
+		}
+	}
+	groups := map[int][]int{}
+	var roots []int
+	for i := 0; i < n; i++ {
+		r := find(i)
+		if _, ok := groups[r]; !ok {
+			roots = append(roots, r)
+		}
+		groups[r] = append(groups[r], i)
+	}
+	out := make([][]int, 0, len(roots))
+	for _, r := range roots {
+		out = append(out, groups[r])
+	}
+	sort.SliceStable(out, func(a, b int) bool { return len(out[a]) > len(out[b]) })
+	return out
+}
+
+// layeredLayout 分层布局：去环、最长路径分层、插入虚拟节点、重心法减少交叉、按邻居位置对齐。
+// 先按 TB 方向计算（cross 为同层方向，rank 为层方向），最后按方向换算坐标
+func layeredLayout(nodes []models.LayoutNode, edges []layoutEdge, opts models.LayoutOptions) {
+	horizontal := opts.Direction == "LR" || opts.Direction == "RL"
+	breadth := func(n models.LayoutNode) float64 {
+		if horizontal {
+			return n.Height
+		}
+		return n.Width
+	}
+	depth := func(n models.LayoutNode) float64 {
+		if horizontal {
+			return n.Width
+		}
+		return n.Height
+	}
+	cross := make([]float64, len(nodes))
+	rankPos := make([]float64, len(nodes))
+
+	var isolated []int
+	offset := 0.0
+	for _, comp := range layoutComponents(len(nodes), edges) {
+		if len(comp) == 1 {
+			isolated = append(isolated, comp[0])
+			continue
+		}
+		span := layeredComponent(nodes, comp, edges, opts, breadth, depth, cross, rankPos, offset)
+		offset += span + opts.NodeSpacing*2
+	}
+	// 孤立节点排成网格，放在所有分量之后
+	if len(isolated) > 0 {
+		cols := int(math.Ceil(math.Sqrt(float64(len(isolated)))))
+		rowCross, rowDepth := offset, 0.0
+		rank := 0.0
+		for i, idx := range isolated {
+			if i > 0 && i%cols == 0 {
+				rank += rowDepth + opts.NodeSpacing
+				rowCross, rowDepth = offset, 0
+			}
+			cross[idx], rankPos[idx] = rowCross, rank
+			rowCross += breadth(nodes[idx]) + opts.NodeSpacing
+			rowDepth = math.Max(rowDepth, depth(nodes[idx]))
+		}
+	}
+
+	maxRank := 0.0
+	for i, n := range nodes {
+		maxRank = math.Max(maxRank, rankPos[i]+depth(n))
+	}
+	for i := range nodes {
+		r := rankPos[i]
+		if opts.Direction == "RL" || opts.Direction == "BT" {
+			r = maxRank - r - depth(nodes[i])
+		}
+		if horizontal {
+			nodes[i].X, nodes[i].Y = r, cross[i]
+		} else {
+			nodes[i].X, nodes[i].Y = cross[i], r
+		}
+	}
+}
+
+// layeredComponent 布局单个连通分量，返回其在同层方向上的跨度
+func layeredComponent(nodes []models.LayoutNode, comp []int, edges []layoutEdge, opts models.LayoutOptions,
+	breadth, depth func(models.LayoutNode) float64, cross, rankPos []float64, offset float64) float64 {
+	in := map[int]bool{}
+	for _, v := range comp {
+		in[v] = true
+	}
+	succ := map[int][]int{}
+	for _, e := range edges {
+		if in[e.from] {
+			succ[e.from] = append(succ[e.from], e.to)
+		}
+	}
+
+	// 去环：按节点顺序深度优先，反转回边
+	state := map[int]int{} // 0 未访问，1 在栈中，2 已完成
+	var dag []layoutEdge
+	inDag := map[layoutEdge]bool{}
+	addEdge := func(e layoutEdge) {
+		if !inDag[e] {
+			inDag[e] = true
+			dag = append(dag, e)
+		}
+	}
+	var visit func(int)
+	visit = func(v int) {
+		state[v] = 1
+		for _, w := range succ[v] {
+			switch state[w] {
+			case 0:
+				addEdge(layoutEdge{v, w})
+				visit(w)
+			case 1:
+				addEdge(layoutEdge{w, v})
+			default:
+				addEdge(layoutEdge{v, w})
+			}
+		}
+		state[v] = 2
+	}
+	for _, v := range comp {
+		if state[v] == 0 {
+			visit(v)
+		}
+	}
+
+	// 最长路径分层
+	preds, succs := map[int][]int{}, map[int][]int{}
+	indeg := map[int]int{}
+	for _, e := range dag {
+		succs[e.from] = append(succs[e.from], e.to)
+		preds[e.to] = append(preds[e.to], e.from)
+		indeg[e.to]++
+	}
+	rank := map[int]int{}
+	var queue, topo []int
+	for _, v := range comp {
+		if indeg[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+	for len(queue) > 0 {
+		sort.Ints(queue)
+		v := queue[0]
+		queue = queue[1:]
+		topo = append(topo, v)
+		for _, w := range succs[v] {
+			if rank[v]+1 > rank[w] {
+				rank[w] = rank[v] + 1
+			}
+			if indeg[w]--; indeg[w] == 0 {
+				queue = append(queue, w)
+			}
+		}
+	}
+	// 没有上游的节点下沉到最近的下游节点之前，避免长边
+	for i := len(topo) - 1; i >= 0; i-- {
+		v := topo[i]
+		if len(preds[v]) > 0 || len(succs[v]) == 0 {
+			continue
+		}
+		minRank := math.MaxInt32
+		for _, w := range succs[v] {
+			if rank[w] < minRank {
+				minRank = rank[w]
+			}
+		}
+		rank[v] = minRank - 1
+	}
+
+	// 插入虚拟节点，使每条边只跨一层；虚拟节点编号从 len(nodes) 开始
+	next := len(nodes)
+	up, down := map[int][]int{}, map[int][]int{}
+	layerOf := map[int]int{}
+	for _, v := range comp {
+		layerOf[v] = rank[v]
+	}
+	for _, e := range dag {
+		prev := e.from
+		for r := rank[e.from] + 1; r < rank[e.to]; r++ {
+			layerOf[next] = r
+			down[prev] = append(down[prev], next)
+			up[next] = append(up[next], prev)
+			prev = next
+			next++
+		}
+		down[prev] = append(down[prev], e.to)
+		up[e.to] = append(up[e.to], prev)
+	}
+
+	// 初始顺序：从首层节点深度优先
+	layerCount := 0
+	for _, r := range layerOf {
+		if r+1 > layerCount {
+			layerCount = r + 1
+		}
+	}
+	layers := make([][]int, layerCount)
+	placed := map[int]bool{}
+	var place func(int)
+	place = func(v int) {
+		if placed[v] {
+			return
+		}
+		placed[v] = true
+		layers[layerOf[v]] = append(layers[layerOf[v]], v)
+		for _, w := range down[v] {
+			place(w)
+		}
+	}
+	for _, v := range comp {
+		if len(up[v]) == 0 {
+			place(v)
+		}
+	}
+	for _, v := range comp {
+		place(v)
+	}
+
+	// 重心法交替上下扫描，保留交叉最少的顺序
+	best := copyLayers(layers)
+	bestCrossings := countCrossings(layers, down)
+	for iter := 0; iter < opts.Iterations && bestCrossings > 0; iter++ {
+		if iter%2 == 0 {
+			for l := 1; l < len(layers); l++ {
+				reorderLayer(layers[l], layers[l-1], up)
+			}
+		} else {
+			for l := len(layers) - 2; l >= 0; l-- {
+				reorderLayer(layers[l], layers[l+1], down)
+			}
+		}
+		if c := countCrossings(layers, down); c < bestCrossings {
+			best, bestCrossings = copyLayers(layers), c
+		}
+	}
+	layers = best
+
+	// 同层方向坐标：先紧密排列，再交替向上下游邻居的中心对齐
+	size := func(v int) float64 {
+		if v >= len(nodes) {
+			return 0
+		}
+		return breadth(nodes[v])
+	}
+	pos := map[int]float64{}
+	for _, layer := range layers {
+		x := 0.0
+		for _, v := range layer {
+			pos[v] = x
+			x += size(v) + opts.NodeSpacing
+		}
+	}
+	for pass := 0; pass < 4; pass++ {
+		if pass%2 == 0 {
+			for l := 1; l < len(layers); l++ {
+				alignLayer(layers[l], up, pos, size, opts.NodeSpacing)
+			}
+		} else {
+			for l := len(layers) - 2; l >= 0; l-- {
+				alignLayer(layers[l], down, pos, size, opts.NodeSpacing)
+			}
+		}
+	}
+
+	// 层方向坐标：每层深度取该层最大节点
+	rankStart := make([]float64, len(layers))
+	acc := 0.0
+	for l, layer := range layers {
+		rankStart[l] = acc
+		d := 0.0
+		for _, v := range layer {
+			if v < len(nodes) {
+				d = math.Max(d, depth(nodes[v]))
+			}
+		}
+		acc += d + opts.RankSpacing
+	}
+
+	minCross, maxCross := math.Inf(1), math.Inf(-1)
+	for _, v := range comp {
+		minCross = math.Min(minCross, pos[v])
+		maxCross = math.Max(maxCross, pos[v]+size(v))
+	}
+	for _, v := range comp {
+		cross[v] = pos[v] - minCross + offset
+		rankPos[v] = rankStart[layerOf[v]]
+	}
+	return maxCross - minCross
+}
+
+func copyLayers(layers [][]int) [][]int {
+	out := make([][]int, len(layers))
+	for i, l := range layers {
+		out[i] = append([]int(nil), l...)
+	}
+	return out
+}
+
+// reorderLayer 按相邻层邻居位置的重心排序；没有邻居的节点保持原位置
+func reorderLayer(layer, ref []int, neighbours map[int][]int) {
+	refPos := map[int]int{}
+	for i, v := range ref {
+		refPos[v] = i
+	}
+	bary := map[int]float64{}
+	for i, v := range layer {
+		sum, count := 0.0, 0
+		for _, w := range neighbours[v] {
+			if p, ok := refPos[w]; ok {
+				sum += float64(p)
+				count++
+			}
+		}
+		if count == 0 {
+			bary[v] = float64(i)
+		} else {
+			bary[v] = sum / float64(count)
+		}
+	}
+	sort.SliceStable(layer, func(a, b int) bool { return bary[layer[a]] < bary[layer[b]] })
+}
+
+// countCrossings 统计相邻层之间的连线交叉数
+func countCrossings(layers [][]int, down map[int][]int) int {
+	total := 0
+	for l := 0; l+1 < len(layers); l++ {
+		next := map[int]int{}
+		for i, v := range layers[l+1] {
+			next[v] = i
+		}
+		var pairs [][2]int
+		for i, v := range layers[l] {
+			for _, w := range down[v] {
+				if j, ok := next[w]; ok {
+					pairs = append(pairs, [2]int{i, j})
+				}
+			}
+		}
+		for a := 0; a < len(pairs); a++ {
+			for b := a + 1; b < len(pairs); b++ {
+				if (pairs[a][0]-pairs[b][0])*(pairs[a][1]-pairs[b][1]) < 0 {
+					total++
+				}
+			}
+		}
+	}
+	return total
+}
+
+// alignLayer 让同层节点尽量靠近邻居中心，同时保持顺序与最小间距（保序回归）
+func alignLayer(layer []int, neighbours map[int][]int, pos map[int]float64, size func(int) float64, spacing float64) {
+	if len(layer) == 0 {
+		return
+	}
+	// q_i = p_i - offset_i 需单调不减；目标为邻居中心对应的左边界
+	offsets := make([]float64, len(layer))
+	targets := make([]float64, len(layer))
+	acc := 0.0
+	for i, v := range layer {
+		offsets[i] = acc
+		acc += size(v) + spacing
+		desired := pos[v]
+		if ns := neighbours[v]; len(ns) > 0 {
+			sum := 0.0
+			for _, w := range ns {
+				sum += pos[w] + size(w)/2
+			}
+			desired = sum/float64(len(ns)) - size(v)/2
+		}
+		targets[i] = desired - offsets[i]
+	}
+	type block struct {
+		sum   float64
+		count int
+	}
+	var blocks []block
+	for _, t := range targets {
+		blocks = append(blocks, block{t, 1})
+		for len(blocks) > 1 {
+			a, b := blocks[len(blocks)-2], blocks[len(blocks)-1]
+			if a.sum/float64(a.count) <= b.sum/float64(b.count) {
+				break
+			}
+			blocks = append(blocks[:len(blocks)-2], block{a.sum + b.sum, a.count + b.count})
+		}
+	}
+	i := 0
+	for _, b := range blocks {
+		mean := b.sum / float64(b.count)
+		for k := 0; k < b.count; k++ {
+			pos[layer[i]] = mean + offsets[i]
+			i++
+		}
+	}
+}
+
+// forceLayout 力导向布局（Fruchterman-Reingold）：初始位置为按 Key 排列的网格，无随机因素；
+// 最后消除节点重叠
+func forceLayout(nodes []models.LayoutNode, edges []layoutEdge, opts models.LayoutOptions) {
+	n := len(nodes)
+	if n == 0 {
+		return
+	}
+	ideal := layoutNodeWidth + opts.RankSpacing
+	cols := int(math.Ceil(math.Sqrt(float64(n))))
+	cx, cy := make([]float64, n), make([]float64, n)
+	for i := range nodes {
+		cx[i] = float64(i%cols) * ideal
+		cy[i] = float64(i/cols) * ideal
+	}
+	temp := ideal * float64(cols) / 4
+	for iter := 0; iter < opts.Iterations; iter++ {
+		dx, dy := make([]float64, n), make([]float64, n)
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				vx, vy := cx[i]-cx[j], cy[i]-cy[j]
+				d := math.Hypot(vx, vy)
+				if d < 1 {
+					// 重合时按编号确定推开方向
+					vx, vy, d = float64(j-i), float64(i+j)/float64(n), 1
+				}
+				f := ideal * ideal / d
+				dx[i] += vx / d * f
+				dy[i] += vy / d * f
+				dx[j] -= vx / d * f
+				dy[j] -= vy / d * f
+			}
+		}
+		for _, e := range edges {
+			vx, vy := cx[e.from]-cx[e.to], cy[e.from]-cy[e.to]
+			d := math.Max(math.Hypot(vx, vy), 1)
+			f := d * d / ideal
+			dx[e.from] -= vx / d * f
+			dy[e.from] -= vy / d * f
+			dx[e.to] += vx / d * f
+			dy[e.to] += vy / d * f
+		}
+		// 向中心的引力，防止不连通的部分漂远
+		mx, my := 0.0, 0.0
+		for i := range nodes {
+			mx += cx[i] / float64(n)
+			my += cy[i] / float64(n)
+		}
+		for i := range nodes {
+			dx[i] -= (cx[i] - mx) * layoutGravity
+			dy[i] -= (cy[i] - my) * layoutGravity
+			d := math.Hypot(dx[i], dy[i])
+			if d > 0 {
+				step := math.Min(d, temp)
+				cx[i] += dx[i] / d * step
+				cy[i] += dy[i] / d * step
+			}
+		}
+		temp = math.Max(temp*0.97, 1)
+	}
+	for i := range nodes {
+		nodes[i].X = cx[i] - nodes[i].Width/2
+		nodes[i].Y = cy[i] - nodes[i].Height/2
+	}
+	removeOverlaps(nodes, opts.NodeSpacing)
+}
+
+// removeOverlaps 沿重叠较小的方向推开重叠节点，直到没有重叠或达到次数上限
+func removeOverlaps(nodes []models.LayoutNode, spacing float64) {
+	for pass := 0; pass < 100; pass++ {
+		moved := false
+		for i := range nodes {
+			for j := i + 1; j < len(nodes); j++ {
+				a, b := &nodes[i], &nodes[j]
+				ox := math.Min(a.X+a.Width, b.X+b.Width) - math.Max(a.X, b.X) + spacing
+				oy := math.Min(a.Y+a.Height, b.Y+b.Height) - math.Max(a.Y, b.Y) + spacing
+				if ox <= 0 || oy <= 0 {
+					continue
+				}
+				moved = true
+				if ox < oy {
+					shift := ox / 2
+					if a.X+a.Width/2 <= b.X+b.Width/2 {
+						a.X, b.X = a.X-shift, b.X+shift
+					} else {
+						a.X, b.X = a.X+shift, b.X-shift
+					}
+				} else {
+					shift := oy / 2
+					if a.Y+a.Height/2 <= b.Y+b.Height/2 {
+						a.Y, b.Y = a.Y-shift, b.Y+shift
+					} else {
+						a.Y, b.Y = a.Y+shift, b.Y-shift
+					}
+				}
+			}
+		}
+		if !moved {
+			return
+		}
+	}
+}
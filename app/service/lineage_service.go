@@ -0,0 +1,272 @@
+package service
+
+import (
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"dbrun/app/sqlparse"
+	"fmt"
+	"strings"
+)
+
+// 血缘遍历的默认最大层数
+const defaultLineageDepth = 16
+
+// RefreshViewLineage 解析数据库内全部视图的定义，重建其字段血缘
+func (m *MetadataService) RefreshViewLineage(databaseID int64) error {
+	views, err := m.rawStorage.GetRawViewsByDatabaseID(databaseID)
+	if err != nil {
+		return fmt.Errorf("load views failed: %w", err)
+	}
+	configID, _, err := m.rawStorage.GetDatabaseContextByID(databaseID)
+	if err != nil {
+		return err
+	}
+	dialect := sqlparse.DialectMySQL
+	if creds, err := m.GetCredentialsByID(configID); err == nil && connectionDialect(creds) != "" {
+		dialect = connectionDialect(creds)
+	}
+
+	var edges []meta.ColumnLineage
+	for _, v := range views {
+		if strings.TrimSpace(v.Definition) == "" {
+			continue
+		}
+		info, err := sqlparse.ParseSelect(dialect, v.Definition)
+		if err != nil {
+			fmt.Printf("[Lineage] parse view failed: view=%s err=%v\n", v.Name, err)
+			continue
+		}
+		resolver := newViewResolver(m, dialect, databaseID)
+		for i, col := range resolver.resolveSelect(info, 0) {
+			for _, o := range col.Origins {
+				edges = append(edges, meta.ColumnLineage{
+					DatabaseID:   databaseID,
+					ViewID:       v.ID,
+					ViewColumn:   col.Name,
+					Position:     i,
+					SourceKind:   o.Kind,
+					SourceID:     o.ObjectID,
+					SourceColumn: o.Column,
+					Transform:    col.Transform,
+					Expression:   col.Expression,
+				})
+			}
+		}
+	}
+	return m.lineage.ReplaceDatabaseLineage(databaseID, edges)
+}
+
+// refreshLineageQuietly 重建字段血缘，失败只记录日志，不影响元数据保存
+func (m *MetadataService) refreshLineageQuietly(databaseID int64) {
+	if err := m.RefreshViewLineage(databaseID); err != nil {
+		fmt.Printf("[Lineage] refresh failed: databaseID=%d err=%v\n", databaseID, err)
+	}
+}
+
+// refreshDownstreamLineage 重新同步单个数据库后，重建同一连接内引用了该库对象的其他库的视图血缘，
+// 避免其血缘边指向已删除或已变化的表/视图
+func (m *MetadataService) refreshDownstreamLineage(databaseID int64) {
+	ids, err := m.lineage.GetDownstreamDatabaseIDs(databaseID)
+	if err != nil {
+		fmt.Printf("[Lineage] load downstream databases failed: databaseID=%d err=%v\n", databaseID, err)
+		return
+	}
+	for _, id := range ids {
+		m.refreshLineageQuietly(id)
+	}
+}
+
+// RefreshViewLineage 重建指定数据库的视图字段血缘
+func RefreshViewLineage(databaseID int64) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	return manager.RefreshViewLineage(databaseID)
+}
+
+// GetColumnUpstream 获取视图字段的上游血缘，逐层穿过视图直到基础表；column 为空时取视图全部字段
+func GetColumnUpstream(viewID int64, column string, maxDepth int) (models.LineageGraph, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.LineageGraph{}, err
+	}
+	g := newLineageGraphBuilder(manager)
+	root := g.node(meta.LineageSourceView, viewID, column, 0)
+	g.graph.Root = root.ID
+
+	type item struct {
+		viewID int64
+		column string
+		depth  int
+	}
+	queue := []item{{viewID, column, 0}}
+	visited := map[string]bool{}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		key := lineageKey(meta.LineageSourceView, cur.viewID, cur.column)
+		if visited[key] || cur.depth >= lineageDepth(maxDepth) {
+			continue
+		}
+		visited[key] = true
+		edges, err := manager.lineage.GetUpstreamEdges(cur.viewID, cur.column)
+		if err != nil {
+			return g.graph, fmt.Errorf("get upstream lineage failed: %w", err)
+		}
+		for _, e := range edges {
+			to := g.node(meta.LineageSourceView, e.ViewID, e.ViewColumn, cur.depth)
+			from := g.node(e.SourceKind, e.SourceID, e.SourceColumn, cur.depth+1)
+			g.edge(from.ID, to.ID, e)
+			if e.SourceKind == meta.LineageSourceView {
+				queue = append(queue, item{e.SourceID, e.SourceColumn, cur.depth + 1})
+			}
+		}
+	}
+	return g.graph, nil
+}
+
+// GetColumnDownstream 获取表或视图字段的下游血缘，逐层查找引用它的视图；column 为空时取对象全部字段
+func GetColumnDownstream(sourceKind string, sourceID int64, column string, maxDepth int) (models.LineageGraph, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.LineageGraph{}, err
+	}
+	if sourceKind != meta.LineageSourceTable && sourceKind != meta.LineageSourceView {
+		return models.LineageGraph{}, fmt.Errorf("invalid lineage source kind: %s", sourceKind)
+	}
+	g := newLineageGraphBuilder(manager)
+	root := g.node(sourceKind, sourceID, column, 0)
+	g.graph.Root = root.ID
+
+	type item struct {
+		kind   string
+		id     int64
+		column string
+		depth  int
+	}
+	queue := []item{{sourceKind, sourceID, column, 0}}
+	visited := map[string]bool{}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		key := lineageKey(cur.kind, cur.id, cur.column)
+		if visited[key] || cur.depth >= lineageDepth(maxDepth) {
+			continue
+		}
+		visited[key] = true
+		edges, err := manager.lineage.GetDownstreamEdges(cur.kind, cur.id, cur.column)
+		if err != nil {
+			return g.graph, fmt.Errorf("get downstream lineage failed: %w", err)
+		}
+		for _, e := range edges {
+			from := g.node(e.SourceKind, e.SourceID, e.SourceColumn, cur.depth)
+			to := g.node(meta.LineageSourceView, e.ViewID, e.ViewColumn, cur.depth+1)
+			g.edge(from.ID, to.ID, e)
+			queue = append(queue, item{meta.LineageSourceView, e.ViewID, e.ViewColumn, cur.depth + 1})
+		}
+	}
+	return g.graph, nil
+}
+
+func lineageDepth(maxDepth int) int {
+	if maxDepth <= 0 {
+		return defaultLineageDepth
+	}
+	return maxDepth
+}
+
+func lineageKey(kind string, objectID int64, column string) string {
+	return fmt.Sprintf("%s:%d:%s", kind, objectID, strings.ToLower(column))
+}
+
+// lineageGraphBuilder 构建血缘图，缓存对象名称并对节点、边去重
+type lineageGraphBuilder struct {
+	manager *MetadataService
+	graph   models.LineageGraph
+	nodes   map[string]int
+	edges   map[string]bool
+	names   map[string][2]string // kind:id -> [对象名, 数据库名]
+}
+
+func newLineageGraphBuilder(manager *MetadataService) *lineageGraphBuilder {
+	return &lineageGraphBuilder{
+		manager: manager,
+		graph:   models.LineageGraph{Nodes: []models.LineageNode{}, Edges: []models.LineageEdge{}},
+		nodes:   map[string]int{},
+		edges:   map[string]bool{},
+		names:   map[string][2]string{},
+	}
+}
+
+// node 返回节点，不存在时创建；同一节点保留最小层数
+func (b *lineageGraphBuilder) node(kind string, objectID int64, column string, depth int) models.LineageNode {
+	key := lineageKey(kind, objectID, column)
+	if idx, ok := b.nodes[key]; ok {
+		if depth < b.graph.Nodes[idx].Depth {
+			b.graph.Nodes[idx].Depth = depth
+		}
+		return b.graph.Nodes[idx]
+	}
+	object, database := b.objectName(kind, objectID)
+	n := models.LineageNode{
+		ID:       key,
+		Kind:     kind,
+		ObjectID: objectID,
+		Object:   object,
+		Database: database,
+		Column:   column,
+		Depth:    depth,
+	}
+	b.nodes[key] = len(b.graph.Nodes)
+	b.graph.Nodes = append(b.graph.Nodes, n)
+	return n
+}
+
+func (b *lineageGraphBuilder) edge(from, to string, e meta.ColumnLineage) {
+	key := from + "->" + to
+	if b.edges[key] {
+		return
+	}
+	b.edges[key] = true
+	b.graph.Edges = append(b.graph.Edges, models.LineageEdge{
+		From:       from,
+		To:         to,
+		Transform:  e.Transform,
+		Expression: e.Expression,
+	})
+}
+
+// objectName 查询表或视图的名称（含 Schema）与所属数据库名
+func (b *lineageGraphBuilder) objectName(kind string, objectID int64) (string, string) {
+	cacheKey := fmt.Sprintf("%s:%d", kind, objectID)
+	if n, ok := b.names[cacheKey]; ok {
+		return n[0], n[1]
+	}
+	raw := b.manager.GetRawStorage()
+	var object, database string
+	if kind == meta.LineageSourceTable {
+		if _, dbName, schemaName, tableName, _, _, err := raw.GetTableContextByID(objectID); err == nil {
+			object, database = qualifyName(schemaName, tableName), dbName
+		}
+	} else if v, err := raw.GetRawViewByID(objectID); err == nil {
+		object = v.Name
+		if v.SchemaID != nil {
+			if _, _, schemaName, _, err := raw.GetSchemaContextByID(*v.SchemaID); err == nil {
+				object = qualifyName(schemaName, v.Name)
+			}
+		}
+		if _, dbName, err := raw.GetDatabaseContextByID(v.DatabaseID); err == nil {
+			database = dbName
+		}
+	}
+	b.names[cacheKey] = [2]string{object, database}
+	return object, database
+}
+
+func qualifyName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
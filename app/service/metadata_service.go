@@ -12,22 +12,32 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
-	"vitess.io/vitess/go/vt/sqlparser"
 )
 
 // MetadataService 将原始与VO存储整合到 service 层，替代 sqlite/metadata/manager.go
 type MetadataService struct {
-	db         *gorm.DB
-	rawStorage *meta.RawMetadataStorage
-	voStorage  *meta.VOMetadataStorage
+	db           *gorm.DB
+	projectDir   string
+	rawStorage   *meta.RawMetadataStorage
+	voStorage    *meta.VOMetadataStorage
+	queryStorage *meta.QueryStorage
+	lineage      *meta.LineageStorage
+	settings     *meta.SettingStorage
+	relations    *meta.RelationshipStorage
+	pages        *meta.PageStorage
 }
 
 // NewMetadataService 创建 service 层的元数据管理器
 func NewMetadataService(db *gorm.DB) *MetadataService {
 	return &MetadataService{
-		db:         db,
-		rawStorage: meta.NewRawMetadataStorage(db),
-		voStorage:  meta.NewVOMetadataStorage(db),
+		db:           db,
+		rawStorage:   meta.NewRawMetadataStorage(db),
+		voStorage:    meta.NewVOMetadataStorage(db),
+		queryStorage: meta.NewQueryStorage(db),
+		lineage:      meta.NewLineageStorage(db),
+		settings:     meta.NewSettingStorage(db),
+		relations:    meta.NewRelationshipStorage(db),
+		pages:        meta.NewPageStorage(db),
 	}
 }
 
@@ -60,6 +70,7 @@ func InitMetadataStorageService(cacheDir string) error {
 	_ = gdb.Exec("PRAGMA foreign_keys = ON;")
 
 	service := NewMetadataService(gdb)
+	service.projectDir = cacheDir
 	if err := service.InitTables(); err != nil {
 		return fmt.Errorf("failed to initialize metadata tables: %w", err)
 	}
@@ -96,6 +107,7 @@ func (m *MetadataService) InitTables() error {
 		&meta.RawSchemaInfo{},
 		&meta.RawTableInfo{},
 		&meta.RawFieldInfo{},
+		&meta.RawTableKey{},
 		&meta.RawViewInfo{},
 	); err != nil {
 		return err
@@ -117,13 +129,32 @@ func (m *MetadataService) InitTables() error {
 	if err := m.db.AutoMigrate(&meta.Credentials{}); err != nil {
 		return err
 	}
+
+	// 自动迁移查询历史、SQL片段与执行计划表
+	if err := m.db.AutoMigrate(&meta.QueryHistory{}, &meta.QuerySnippet{}, &meta.QueryPlan{}); err != nil {
+		return err
+	}
+	if err := m.db.AutoMigrate(&meta.ColumnLineage{}, &meta.ProjectSetting{}); err != nil {
+		return err
+	}
+	if err := m.db.AutoMigrate(&meta.Relationship{}, &meta.RelationshipColumn{}); err != nil {
+		return err
+	}
+	if err := m.db.AutoMigrate(&meta.DiagramPage{}, &meta.PageNode{}); err != nil {
+		return err
+	}
 	return nil
 }
 
-// UpdateRawDatabase 更新原始数据库信息
+// UpdateRawDatabase 更新原始数据库信息，并重建该库以及同一连接内引用该库的其他库的视图字段血缘
 func (m *MetadataService) UpdateRawDatabase(configID int64, database connect.DatabaseInfo) error {
-	_, err := m.rawStorage.SaveDatabaseInfo(configID, database)
-	return err
+	rawDB, err := m.rawStorage.SaveDatabaseInfo(configID, database)
+	if err != nil {
+		return err
+	}
+	m.refreshLineageQuietly(rawDB.ID)
+	m.refreshDownstreamLineage(rawDB.ID)
+	return nil
 }
 
 // GetRawStorage 获取原始存储实例
@@ -132,6 +163,24 @@ func (m *MetadataService) GetRawStorage() *meta.RawMetadataStorage { return m.ra
 // GetVOStorage 获取VO存储实例
 func (m *MetadataService) GetVOStorage() *meta.VOMetadataStorage { return m.voStorage }
 
+// GetLineageStorage 获取字段血缘存储实例
+func (m *MetadataService) GetLineageStorage() *meta.LineageStorage { return m.lineage }
+
+// GetSettingStorage 获取项目配置存储实例
+func (m *MetadataService) GetSettingStorage() *meta.SettingStorage { return m.settings }
+
+// GetRelationshipStorage 获取表关系存储实例
+func (m *MetadataService) GetRelationshipStorage() *meta.RelationshipStorage { return m.relations }
+
+// GetPageStorage 获取页面存储
+func (m *MetadataService) GetPageStorage() *meta.PageStorage { return m.pages }
+
+// GetQueryStorage 获取查询历史与片段存储实例
+func (m *MetadataService) GetQueryStorage() *meta.QueryStorage { return m.queryStorage }
+
+// ProjectDir 当前项目目录（relation.db 所在目录）
+func (m *MetadataService) ProjectDir() string { return m.projectDir }
+
 // ConvertRawToVO 将原始数据转换为VO数据（保留原始ID）
 func (m *MetadataService) ConvertRawToVO(configID int64) (*models.DBInfoVO, error) {
 	rawDatabases, err := m.rawStorage.GetDatabasesByConfigID(configID)
@@ -293,8 +342,21 @@ func (m *MetadataService) ConvertRawToVO(configID int64) (*models.DBInfoVO, erro
 
 // ===== 凭证操作（迁移自 sqlite/metadata/credentials.go） =====
 
+// InsertCredentials 插入凭证；未指定只读开关时按只读保存，写入后回读校验只读标记
 func (m *MetadataService) InsertCredentials(creds *meta.Credentials) error {
-	return m.db.Create(creds).Error
+	readOnly := creds.IsReadOnly()
+	creds.ReadOnly = &readOnly
+	if err := m.db.Create(creds).Error; err != nil {
+		return err
+	}
+	stored, err := m.GetCredentialsByID(creds.ID)
+	if err != nil {
+		return fmt.Errorf("read back credentials failed: %w", err)
+	}
+	if stored.IsReadOnly() != readOnly {
+		return fmt.Errorf("credentials %d stored read_only=%v, expected %v", creds.ID, stored.IsReadOnly(), readOnly)
+	}
+	return nil
 }
 
 func (m *MetadataService) GetAllCredentials() ([]meta.Credentials, error) {
@@ -307,15 +369,16 @@ func (m *MetadataService) UpdateCredentials(creds *meta.Credentials) error {
 	return m.db.Model(&meta.Credentials{}).
 		Where("id = ?", creds.ID).
 		Updates(map[string]interface{}{
-			"type":     creds.Type,
-			"label":    creds.Label,
-			"username": creds.Username,
-			"password": creds.Password,
-			"host":     creds.Host,
-			"port":     creds.Port,
-			"database": creds.Database,
-			"instance": creds.Instance,
-			"options":  creds.Options,
+			"type":      creds.Type,
+			"label":     creds.Label,
+			"username":  creds.Username,
+			"password":  creds.Password,
+			"host":      creds.Host,
+			"port":      creds.Port,
+			"database":  creds.Database,
+			"instance":  creds.Instance,
+			"options":   creds.Options,
+			"read_only": creds.IsReadOnly(),
 		}).Error
 }
 
@@ -351,16 +414,7 @@ func ListDatabasesByConfig(config connect.Config) (models.DBInfoVO, error) {
 	// 1) 先尝试读取原始元数据
 	rawStored, err := manager.rawStorage.GetDatabasesByConfigID(int64(config.ID))
 	if err == nil && len(rawStored) > 0 {
-		baseVO, err := manager.ConvertRawToVO(int64(config.ID))
-		if err != nil {
-			return models.DBInfoVO{}, err
-		}
-		overlayVO, _ := manager.voStorage.GetDatabasesVOByConfigID(int64(config.ID))
-		if overlayVO != nil {
-			merged := mergeVOOverlay(*baseVO, models.DBInfoVO{DBs: overlayVO, Display: baseVO.Display})
-			return merged, nil
-		}
-		return *baseVO, nil
+		return manager.storedDBInfoVO(int64(config.ID))
 	}
 
 	// 2) 若原始存储没有，则从数据库拉取并仅保存原始数据，并初始化VO以提供稳定ID
@@ -373,16 +427,28 @@ func ListDatabasesByConfig(config connect.Config) (models.DBInfoVO, error) {
 		return models.DBInfoVO{}, err
 	}
 	rs := manager.rawStorage
+	var savedIDs []int64
 	for _, db := range rawFetched {
-		if _, err := rs.SaveDatabaseInfo(int64(config.ID), db); err != nil {
+		saved, err := rs.SaveDatabaseInfo(int64(config.ID), db)
+		if err != nil {
 			return models.DBInfoVO{}, err
 		}
+		savedIDs = append(savedIDs, saved.ID)
 	}
-	baseVO, err := manager.ConvertRawToVO(int64(config.ID))
+	// 所有库保存完成后再解析视图，跨库引用才能被解析
+	for _, id := range savedIDs {
+		manager.refreshLineageQuietly(id)
+	}
+	return manager.storedDBInfoVO(int64(config.ID))
+}
+
+// storedDBInfoVO 由已保存的原始元数据生成VO，并叠加业务配置
+func (m *MetadataService) storedDBInfoVO(configID int64) (models.DBInfoVO, error) {
+	baseVO, err := m.ConvertRawToVO(configID)
 	if err != nil {
 		return models.DBInfoVO{}, err
 	}
-	overlayVO, _ := manager.voStorage.GetDatabasesVOByConfigID(int64(config.ID))
+	overlayVO, _ := m.voStorage.GetDatabasesVOByConfigID(configID)
 	if overlayVO != nil {
 		merged := mergeVOOverlay(*baseVO, models.DBInfoVO{DBs: overlayVO, Display: baseVO.Display})
 		return merged, nil
@@ -494,39 +560,21 @@ func GetTableVOCacheByTableID(tableID int64) TableCacheVO {
 	return TableCacheVO{Remark: remark, Version: 0, FieldMap: fmap}
 }
 
-// ParseViewSQL 解析视图SQL，获取字段列表
-func ParseViewSQL(sql string) ([]models.FieldInfoVO, error) {
-	parser := sqlparser.NewTestParser()
-	stmt, err := parser.Parse(sql)
+// ParseViewSQL 按视图所在数据库的连接方言解析视图SQL，返回字段列表与部分无法解析时的警告；引用的对象限定在该连接内查找
+func ParseViewSQL(databaseID int64, sql string) (models.ViewParseResult, error) {
+	manager, err := getMgr()
 	if err != nil {
-		return nil, fmt.Errorf("解析SQL失败: %w", err)
-	}
-	selectStmt, ok := stmt.(*sqlparser.Select)
-	if !ok {
-		return nil, fmt.Errorf("无法解析非SELECT语句，当前语句类型: %T", stmt)
-	}
-	var fields []models.FieldInfoVO
-	for _, se := range selectStmt.SelectExprs {
-		switch expr := se.(type) {
-		case *sqlparser.AliasedExpr:
-			var fieldName string
-			if expr.As.IsEmpty() {
-				if cn, ok := expr.Expr.(*sqlparser.ColName); ok {
-					fieldName = cn.Name.String()
-				} else {
-					fieldName = sqlparser.String(expr.Expr)
-				}
-			} else {
-				fieldName = expr.As.String()
-			}
-			fields = append(fields, models.FieldInfoVO{Name: fieldName, Type: "VARCHAR", Display: true, Nullable: true, Key: ""})
-		case *sqlparser.StarExpr:
-			fields = append(fields, models.FieldInfoVO{Name: "*", Type: "UNKNOWN", Display: true, Nullable: true, Key: "", Comment: "星号表达式需要访问数据库才能获取具体字段"})
-		default:
-			fields = append(fields, models.FieldInfoVO{Name: sqlparser.String(expr), Type: "UNKNOWN", Display: true, Nullable: true, Key: ""})
-		}
+		return models.ViewParseResult{}, err
+	}
+	configID, _, err := manager.rawStorage.GetDatabaseContextByID(databaseID)
+	if err != nil {
+		return models.ViewParseResult{}, fmt.Errorf("database %d not found: %w", databaseID, err)
 	}
-	return fields, nil
+	creds, err := manager.GetCredentialsByID(configID)
+	if err != nil {
+		return models.ViewParseResult{}, fmt.Errorf("get credentials failed: %w", err)
+	}
+	return parseView(manager, connectionDialect(creds), databaseID, sql)
 }
 
 // mergeVOOverlay 将VO中的业务配置（备注/显示等）叠加到原始转换后的VO
@@ -601,7 +649,7 @@ func mergeTablesOverlay(base *[]models.TableInfoVO, over []models.TableInfoVO) {
             for _, bf := range bt.Fields {
                 bm[bf.Name] = bf
             }
-            // 按 VO 字段顺序重建字段列表，并同步 Display/Remark/Sort
+            // 按 VO 字段顺序重建字段列表，并同步 Display/Remark/Alias/Sort
             newFields := make([]models.FieldInfoVO, 0, len(bt.Fields))
             for _, of := range ot.Fields {
                 if bf, ok := bm[of.Name]; ok {
@@ -610,6 +658,9 @@ func mergeTablesOverlay(base *[]models.TableInfoVO, over []models.TableInfoVO) {
                     if of.Remark != "" {
                         bf.Remark = of.Remark
                     }
+                    if of.Alias != "" {
+                        bf.Alias = of.Alias
+                    }
                     bf.Sort = of.Sort
                     newFields = append(newFields, bf)
                     delete(bm, of.Name)
@@ -685,11 +736,9 @@ func fetchRawDatabase(conn connect.Connection, dbName string) (connect.DatabaseI
 				return info, fmt.Errorf("failed to get tables for schema %s in %s: %w", s.Name, dbName, err)
 			}
 			for i := range tables {
-				fields, err := conn.GetTableFields(connect.QueryParams{Database: dbName, Schema: s.Name, Table: tables[i].Name})
-				if err != nil {
-					return info, fmt.Errorf("failed to get fields for table %s: %w", tables[i].Name, err)
+				if err := fetchTableDetails(conn, connect.QueryParams{Database: dbName, Schema: s.Name, Table: tables[i].Name}, &tables[i]); err != nil {
+					return info, err
 				}
-				tables[i].Fields = fields
 			}
 			views, err := conn.GetViews(connect.QueryParams{Database: dbName, Schema: s.Name})
 			if err != nil {
@@ -705,11 +754,9 @@ func fetchRawDatabase(conn connect.Connection, dbName string) (connect.DatabaseI
 			return info, fmt.Errorf("failed to get tables for database %s: %w", dbName, err)
 		}
 		for i := range tables {
-			fields, err := conn.GetTableFields(connect.QueryParams{Database: dbName, Table: tables[i].Name})
-			if err != nil {
-				return info, fmt.Errorf("failed to get fields for table %s: %w", tables[i].Name, err)
+			if err := fetchTableDetails(conn, connect.QueryParams{Database: dbName, Table: tables[i].Name}, &tables[i]); err != nil {
+				return info, err
 			}
-			tables[i].Fields = fields
 		}
 		views, err := conn.GetViews(connect.QueryParams{Database: dbName})
 		if err != nil {
@@ -720,3 +767,21 @@ func fetchRawDatabase(conn connect.Connection, dbName string) (connect.DatabaseI
 	}
 	return info, nil
 }
+
+// fetchTableDetails 读取表的字段与键信息，并按键信息补全字段的索引标记；
+// 键信息读取失败（如缺少系统视图权限）时只记录日志，表的键信息保持未获取
+func fetchTableDetails(conn connect.Connection, params connect.QueryParams, table *connect.TableInfo) error {
+	fields, err := conn.GetTableFields(params)
+	if err != nil {
+		return fmt.Errorf("failed to get fields for table %s: %w", params.Table, err)
+	}
+	keys, err := conn.GetTableKeys(params)
+	if err != nil {
+		fmt.Printf("[MetadataSync] get keys failed: table=%s err=%v\n", params.Table, err)
+	} else {
+		connect.ApplyKeyFlags(fields, keys)
+		table.Keys = keys
+	}
+	table.Fields = fields
+	return nil
+}
@@ -52,6 +52,7 @@ func SyncTableFieldsByTableID(tableID int64) error {
 		Database:  creds.Database,
 		Instance:  creds.Instance,
 		Options:   creds.Options,
+		ReadOnly:  creds.IsReadOnly(),
 		CreatedAt: creds.CreatedAt,
 	}
 
@@ -115,6 +116,7 @@ func SyncSchemaByID(schemaID int64) error {
 		Database:  creds.Database,
 		Instance:  creds.Instance,
 		Options:   creds.Options,
+		ReadOnly:  creds.IsReadOnly(),
 		CreatedAt: creds.CreatedAt,
 	}
 
@@ -177,6 +179,7 @@ func SyncDatabaseByID(databaseID int64) error {
         Database:  creds.Database,
         Instance:  creds.Instance,
         Options:   creds.Options,
+        ReadOnly:  creds.IsReadOnly(),
         CreatedAt: creds.CreatedAt,
     }
 
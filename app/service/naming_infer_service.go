@@ -0,0 +1,419 @@
+package service
+
+import (
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 命名规则在项目配置中的键
+const namingRulesKey = "naming_rules"
+
+// DefaultNamingRules 默认的命名推断规则
+func DefaultNamingRules() models.NamingRules {
+	return models.NamingRules{
+		ForeignKeyPatterns: []string{"{table}_{pk}", "{table}_id", "fk_{table}", "{table}_fk", "id_{table}"},
+		PrimaryKeyNames:    []string{"id", "{table}_id"},
+		TablePrefixes:      []string{"tbl_", "t_"},
+		Pluralization:      true,
+		RoleNames:          true,
+		CrossSchema:        true,
+		RequireTypeMatch:   true,
+		MinConfidence:      0.3,
+	}
+}
+
+// GetNamingRules 读取当前项目的命名规则，未配置时返回默认规则
+func GetNamingRules() (models.NamingRules, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.NamingRules{}, err
+	}
+	value, ok, err := manager.settings.Get(namingRulesKey)
+	if err != nil {
+		return models.NamingRules{}, fmt.Errorf("load naming rules failed: %w", err)
+	}
+	rules := DefaultNamingRules()
+	if !ok {
+		return rules, nil
+	}
+	if err := json.Unmarshal([]byte(value), &rules); err != nil {
+		return DefaultNamingRules(), fmt.Errorf("invalid naming rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SaveNamingRules 保存当前项目的命名规则
+func SaveNamingRules(rules models.NamingRules) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	for _, p := range rules.ForeignKeyPatterns {
+		if !strings.Contains(p, "{table}") {
+			return fmt.Errorf("foreign key pattern must contain {table}: %s", p)
+		}
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	return manager.settings.Set(namingRulesKey, string(data))
+}
+
+// namingTable 参与匹配的表
+type namingTable struct {
+	id     int64
+	name   string
+	schema string
+	stem   string // 去前缀并转为下划线形式的表名
+	fields []meta.RawFieldInfo
+	pk     *meta.RawFieldInfo
+}
+
+// namingPattern 编译后的外键列名模式
+type namingPattern struct {
+	source string
+	re     *regexp.Regexp
+	usesPK bool
+}
+
+// InferNamingRelationships 按项目命名规则推断数据库内表之间的候选关系，结果仅作建议
+func InferNamingRelationships(databaseID int64) ([]models.RelationshipCandidate, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	rules, err := GetNamingRules()
+	if err != nil {
+		return nil, err
+	}
+	tables, err := loadNamingTables(manager, databaseID, rules)
+	if err != nil {
+		return nil, err
+	}
+	patterns := compileNamingPatterns(rules.ForeignKeyPatterns)
+
+	// 表名词干（原形与单数）-> 表，同名词干可能出现在多个 Schema
+	byStem := map[string][]*namingTable{}
+	for _, t := range tables {
+		for _, key := range uniqueStrings(t.stem, singularize(t.stem, rules)) {
+			byStem[key] = append(byStem[key], t)
+		}
+	}
+
+	var out []models.RelationshipCandidate
+	for _, src := range tables {
+		for _, f := range src.fields {
+			if c, ok := matchNamingColumn(src, f, patterns, byStem, rules); ok {
+				out = append(out, c)
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Confidence > out[j].Confidence })
+	return out, nil
+}
+
+// loadNamingTables 读取数据库内所有表（含各 Schema）及其字段与主键
+func loadNamingTables(manager *MetadataService, databaseID int64, rules models.NamingRules) ([]*namingTable, error) {
+	rs := manager.rawStorage
+	var tables []*namingTable
+	add := func(rows []meta.RawTableInfo, schema string) error {
+		for _, rt := range rows {
+			fields, err := rs.GetRawFieldsRows(rt.ID)
+			if err != nil {
+				return err
+			}
+			t := &namingTable{id: rt.ID, name: rt.Name, schema: schema, stem: tableStem(rt.Name, rules), fields: fields}
+			t.pk = primaryKeyOf(t, rules)
+			tables = append(tables, t)
+		}
+		return nil
+	}
+	rows, err := rs.GetRawTablesRows(databaseID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := add(rows, ""); err != nil {
+		return nil, err
+	}
+	schemas, err := rs.GetRawSchemasRows(databaseID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range schemas {
+		sid := s.ID
+		rows, err := rs.GetRawTablesRows(databaseID, &sid)
+		if err != nil {
+			return nil, err
+		}
+		if err := add(rows, s.Name); err != nil {
+			return nil, err
+		}
+	}
+	return tables, nil
+}
+
+// primaryKeyOf 返回单列主键；无主键标记时按规则中的主键列名查找
+func primaryKeyOf(t *namingTable, rules models.NamingRules) *meta.RawFieldInfo {
+	var pk *meta.RawFieldInfo
+	for i := range t.fields {
+		if strings.EqualFold(t.fields[i].Key, "PRI") {
+			if pk != nil {
+				return nil // 复合主键不作为被引用方
+			}
+			pk = &t.fields[i]
+		}
+	}
+	if pk != nil {
+		return pk
+	}
+	for _, name := range rules.PrimaryKeyNames {
+		for i := range t.fields {
+			col := toSnake(t.fields[i].Name)
+			if strings.Contains(name, "{table}") {
+				for _, stem := range uniqueStrings(t.stem, singularize(t.stem, rules), toSnake(t.name)) {
+					if col == strings.ReplaceAll(strings.ToLower(name), "{table}", stem) {
+						return &t.fields[i]
+					}
+				}
+			} else if col == strings.ToLower(name) {
+				return &t.fields[i]
+			}
+		}
+	}
+	return nil
+}
+
+// compileNamingPatterns 将 {table}_id 形式的模式编译为正则，{table} 捕获表名部分
+func compileNamingPatterns(patterns []string) []namingPattern {
+	var out []namingPattern
+	for _, p := range patterns {
+		snake := strings.ToLower(p)
+		expr := regexp.QuoteMeta(snake)
+		expr = strings.Replace(expr, regexp.QuoteMeta("{table}"), "(?P<table>[a-z0-9_]+?)", 1)
+		usesPK := strings.Contains(expr, regexp.QuoteMeta("{pk}"))
+		expr = strings.ReplaceAll(expr, regexp.QuoteMeta("{pk}"), "(?P<pk>[a-z0-9_]+)")
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			fmt.Printf("[NamingInfer] invalid pattern: %s err=%v\n", p, err)
+			continue
+		}
+		out = append(out, namingPattern{source: p, re: re, usesPK: usesPK})
+	}
+	return out
+}
+
+// matchNamingColumn 按模式匹配列名并查找被引用表，返回置信度最高的候选
+func matchNamingColumn(src *namingTable, f meta.RawFieldInfo, patterns []namingPattern, byStem map[string][]*namingTable, rules models.NamingRules) (models.RelationshipCandidate, bool) {
+	col := toSnake(f.Name)
+	var best models.RelationshipCandidate
+	found := false
+	for _, p := range patterns {
+		m := p.re.FindStringSubmatch(col)
+		if m == nil {
+			continue
+		}
+		stem := m[p.re.SubexpIndex("table")]
+		pkName := ""
+		if p.usesPK {
+			pkName = m[p.re.SubexpIndex("pk")]
+		}
+		for _, cand := range stemCandidates(stem, rules) {
+			for _, target := range lookupStem(byStem, cand.stem, rules) {
+				if target.pk == nil || (target.id == src.id && strings.EqualFold(target.pk.Name, f.Name)) {
+					continue
+				}
+				if pkName != "" && toSnake(target.pk.Name) != pkName {
+					continue
+				}
+				if target.schema != src.schema && !rules.CrossSchema {
+					continue
+				}
+				mismatch := !typesCompatible(f.Type, target.pk.Type)
+				if mismatch && rules.RequireTypeMatch {
+					continue
+				}
+				score := namingConfidence(src, target, cand.role, mismatch)
+				if score < rules.MinConfidence || (found && score <= best.Confidence) {
+					continue
+				}
+				found = true
+				best = models.RelationshipCandidate{
+					Key:          fmt.Sprintf("%d.%s=%d.%s", src.id, strings.ToLower(f.Name), target.id, strings.ToLower(target.pk.Name)),
+					FromTableID:  src.id,
+					FromTable:    qualifyName(src.schema, src.name),
+					FromColumn:   f.Name,
+					ToTableID:    target.id,
+					ToTable:      qualifyName(target.schema, target.name),
+					ToColumn:     target.pk.Name,
+					Confidence:   score,
+					TypeMismatch: mismatch,
+					Evidence: []models.RelationshipEvidence{{
+						Kind:       "naming",
+						Name:       p.source,
+						Expression: fmt.Sprintf("%s.%s -> %s.%s", src.name, f.Name, target.name, target.pk.Name),
+					}},
+				}
+			}
+		}
+	}
+	return best, found
+}
+
+// stemCandidate 列名中可能表示表名的部分；role 表示去掉了角色前缀
+type stemCandidate struct {
+	stem string
+	role bool
+}
+
+// stemCandidates 返回完整词干，启用角色名时再依次去掉前面的单词
+func stemCandidates(stem string, rules models.NamingRules) []stemCandidate {
+	out := []stemCandidate{{stem: stem}}
+	if !rules.RoleNames {
+		return out
+	}
+	parts := strings.Split(stem, "_")
+	for i := 1; i < len(parts); i++ {
+		out = append(out, stemCandidate{stem: strings.Join(parts[i:], "_"), role: true})
+	}
+	return out
+}
+
+// namingConfidence 同 Schema、有主键标记时提高，角色名、跨 Schema、类型不兼容时降低
+func namingConfidence(src, target *namingTable, role, mismatch bool) float64 {
+	score := 0.6
+	if strings.EqualFold(target.pk.Key, "PRI") {
+		score += 0.1
+	}
+	if target.schema == src.schema {
+		score += 0.1
+	} else {
+		score -= 0.1
+	}
+	if role {
+		score -= 0.15
+	}
+	if target.id == src.id {
+		score -= 0.05
+	}
+	if mismatch {
+		score -= 0.3
+	}
+	score = math.Max(0.05, math.Min(score, 0.95))
+	return math.Round(score*100) / 100
+}
+
+// lookupStem 按列名中的词干查找表，启用单复数规则时同时尝试单数与复数形式
+func lookupStem(byStem map[string][]*namingTable, stem string, rules models.NamingRules) []*namingTable {
+	keys := []string{stem}
+	if rules.Pluralization {
+		keys = uniqueStrings(stem, singularize(stem, rules), pluralize(stem))
+	}
+	var out []*namingTable
+	seen := map[int64]bool{}
+	for _, key := range keys {
+		for _, t := range byStem[key] {
+			if !seen[t.id] {
+				seen[t.id] = true
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+func uniqueStrings(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		dup := false
+		for _, o := range out {
+			dup = dup || o == v
+		}
+		if !dup {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// tableStem 表名去前缀并转为下划线形式
+func tableStem(name string, rules models.NamingRules) string {
+	s := toSnake(name)
+	for _, p := range rules.TablePrefixes {
+		p = strings.ToLower(p)
+		if p != "" && strings.HasPrefix(s, p) && len(s) > len(p) {
+			s = s[len(p):]
+			break
+		}
+	}
+	return s
+}
+
+var camelBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// toSnake 将驼峰或混合大小写名称转为小写下划线形式，如 customerId -> customer_id
+func toSnake(name string) string {
+	s := camelBoundary.ReplaceAllString(name, "${1}_${2}")
+	s = strings.ReplaceAll(strings.ReplaceAll(s, "-", "_"), " ", "_")
+	return strings.ToLower(s)
+}
+
+// 不规则复数
+var irregularPlurals = map[string]string{
+	"people": "person", "men": "man", "women": "woman", "children": "child", "mice": "mouse",
+	"geese": "goose", "feet": "foot", "teeth": "tooth", "data": "datum", "indices": "index",
+}
+
+// singularize 取名称最后一个单词的单数形式；未启用单复数规则时原样返回
+func singularize(s string, rules models.NamingRules) string {
+	if !rules.Pluralization {
+		return s
+	}
+	head, word := "", s
+	if i := strings.LastIndex(s, "_"); i >= 0 {
+		head, word = s[:i+1], s[i+1:]
+	}
+	if w, ok := irregularPlurals[word]; ok {
+		return head + w
+	}
+	switch {
+	case len(word) > 3 && strings.HasSuffix(word, "ies"):
+		word = word[:len(word)-3] + "y"
+	case len(word) > 4 && (strings.HasSuffix(word, "sses") || strings.HasSuffix(word, "xes") || strings.HasSuffix(word, "zes") ||
+		strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes")):
+		word = word[:len(word)-2]
+	case len(word) > 1 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		word = word[:len(word)-1]
+	}
+	return head + word
+}
+
+// pluralize 取名称最后一个单词的复数形式
+func pluralize(s string) string {
+	head, word := "", s
+	if i := strings.LastIndex(s, "_"); i >= 0 {
+		head, word = s[:i+1], s[i+1:]
+	}
+	for plural, single := range irregularPlurals {
+		if word == single {
+			return head + plural
+		}
+	}
+	switch {
+	case len(word) > 1 && strings.HasSuffix(word, "y") && !strings.ContainsAny(word[len(word)-2:len(word)-1], "aeiou"):
+		word = word[:len(word)-1] + "ies"
+	case strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x") || strings.HasSuffix(word, "z") ||
+		strings.HasSuffix(word, "ch") || strings.HasSuffix(word, "sh"):
+		word += "es"
+	default:
+		word += "s"
+	}
+	return head + word
+}
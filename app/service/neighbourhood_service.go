@@ -0,0 +1,332 @@
+package service
+
+import (
+	"dbrun/app/connect"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// 邻域页面默认参数
+const (
+	defaultNeighbourhoodDepth = 1
+	defaultNeighbourhoodLimit = 200
+)
+
+// GeneratePageFromNeighbourhood 从起始表出发沿关系与数据库外键遍历指定跳数，新建包含这些表、关系与自动布局的页面；
+// 生成过程中出错时删除已创建的页面
+func GeneratePageFromNeighbourhood(req models.NeighbourhoodRequest) (result models.NeighbourhoodResult, err error) {
+	result = models.NeighbourhoodResult{Excluded: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if req.Depth <= 0 {
+		req.Depth = defaultNeighbourhoodDepth
+	}
+	if req.MaxTables <= 0 {
+		req.MaxTables = defaultNeighbourhoodLimit
+	}
+	switch req.Direction {
+	case "":
+		req.Direction = models.NeighbourhoodBoth
+	case models.NeighbourhoodOut, models.NeighbourhoodIn, models.NeighbourhoodBoth:
+	default:
+		return result, fmt.Errorf("invalid direction: %s", req.Direction)
+	}
+	patterns := make([]string, 0, len(req.Exclude))
+	for _, p := range req.Exclude {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" {
+			continue
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return result, fmt.Errorf("invalid exclude pattern %q: %w", p, err)
+		}
+		patterns = append(patterns, p)
+	}
+
+	start, err := manager.rawStorage.GetRawObjectsByIDs([]int64{req.TableID}, nil)
+	if err != nil {
+		return result, err
+	}
+	if len(start) == 0 {
+		return result, fmt.Errorf("table %d not found", req.TableID)
+	}
+
+	// 所有页面上的关系构成关系图，相同两端与字段的关系只保留一条
+	rows, err := manager.relations.List(meta.RelationshipFilter{})
+	if err != nil {
+		return result, fmt.Errorf("list relationships failed: %w", err)
+	}
+	rels, err := manager.relationshipVOs(rows)
+	if err != nil {
+		return result, err
+	}
+	// 表名统一为 schema.table 形式，与关系两端的名称一致
+	fks := newForeignKeyLoader(manager)
+	names := map[int64]string{req.TableID: fks.tableName(req.TableID)}
+	adjacent := map[int64][]models.RelationshipVO{}
+	seen := map[string]bool{}
+	var edges []models.RelationshipVO
+	addEdge := func(r models.RelationshipVO) {
+		sig := relationshipSignature(r)
+		if r.Broken || r.SourceTableID == r.TargetTableID || seen[sig] {
+			return
+		}
+		seen[sig] = true
+		edges = append(edges, r)
+		names[r.SourceTableID], names[r.TargetTableID] = r.SourceTable, r.TargetTable
+		if req.Direction != models.NeighbourhoodIn {
+			adjacent[r.SourceTableID] = append(adjacent[r.SourceTableID], r)
+		}
+		if req.Direction != models.NeighbourhoodOut {
+			adjacent[r.TargetTableID] = append(adjacent[r.TargetTableID], r)
+		}
+	}
+	for _, r := range rels {
+		addEdge(r)
+	}
+
+	// 数据库外键在展开表时按需读取：引用方的外键在两个方向上都需要，被引用方只在逆引用方向上查找
+	expanded := map[int64]bool{}
+	expand := func(id int64) error {
+		if expanded[id] {
+			return nil
+		}
+		expanded[id] = true
+		out, err := fks.outgoing(id)
+		if err != nil {
+			return err
+		}
+		for _, r := range out {
+			addEdge(r)
+		}
+		if req.Direction == models.NeighbourhoodOut {
+			return nil
+		}
+		in, err := fks.incoming(id)
+		if err != nil {
+			return err
+		}
+		for _, r := range in {
+			addEdge(r)
+		}
+		return nil
+	}
+
+	// 广度优先遍历，被排除的表不加入也不继续展开
+	included := map[int64]bool{req.TableID: true}
+	excluded := map[int64]bool{}
+	order := []int64{req.TableID}
+	frontier := []int64{req.TableID}
+	for hop := 0; hop < req.Depth && len(frontier) > 0 && !result.Truncated; hop++ {
+		var next []int64
+		for _, id := range frontier {
+			if err := expand(id); err != nil {
+				return result, err
+			}
+			for _, r := range adjacent[id] {
+				other := r.TargetTableID
+				if other == id {
+					other = r.SourceTableID
+				}
+				if included[other] || excluded[other] {
+					continue
+				}
+				if matchesAny(patterns, names[other]) {
+					excluded[other] = true
+					result.Excluded = append(result.Excluded, names[other])
+					continue
+				}
+				if len(order) >= req.MaxTables {
+					result.Truncated = true
+					break
+				}
+				included[other] = true
+				order = append(order, other)
+				next = append(next, other)
+			}
+		}
+		frontier = next
+	}
+	sort.Strings(result.Excluded)
+	// 页面上表之间的外键都要复制，未展开的表（最后一跳）补充读取其外键
+	for _, id := range order {
+		out, err := fks.outgoing(id)
+		if err != nil {
+			return result, err
+		}
+		for _, r := range out {
+			addEdge(r)
+		}
+	}
+
+	page, err := CreatePage(models.PageVO{
+		ParentKey: req.ParentKey,
+		Type:      meta.PageTypePage,
+		Label:     defaultString(req.Label, start[0].Name),
+	})
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if delErr := DeletePage(page.Key); delErr != nil {
+			fmt.Printf("[Neighbourhood] remove incomplete page failed: key=%s err=%v\n", page.Key, delErr)
+		}
+	}()
+	nodes := make([]models.PageNodeVO, 0, len(order))
+	for _, id := range order {
+		nodes = append(nodes, models.PageNodeVO{ObjectKind: meta.PageObjectTable, ObjectID: id, ObjectName: bareTableName(names[id])})
+	}
+	saved, err := SavePageNodes(page.Key, nodes)
+	if err != nil {
+		return result, err
+	}
+	nodeKeys := map[int64]string{}
+	for _, n := range saved {
+		nodeKeys[n.ObjectID] = n.Key
+	}
+
+	// 复制两端都在页面上的关系与外键
+	for _, r := range edges {
+		sig := relationshipSignature(r)
+		if !seen[sig] || !included[r.SourceTableID] || !included[r.TargetTableID] {
+			continue
+		}
+		seen[sig] = false
+		copied := r
+		copied.ID = 0
+		copied.PageID = page.Key
+		rel, cols, err := manager.relationshipFromVO(copied)
+		if err != nil {
+			return result, err
+		}
+		rel.EdgeKey = flowEdgeKey(nodeKeys[r.SourceTableID], r.Columns[0].SourceField, nodeKeys[r.TargetTableID], r.Columns[0].TargetField)
+		if err := manager.relations.Create(rel, cols); err != nil {
+			return result, fmt.Errorf("create relationship failed: %w", err)
+		}
+	}
+
+	req.Layout.Save = true
+	if _, err := LayoutPage(page.Key, req.Layout); err != nil {
+		return result, err
+	}
+	if result.Content, err = GetPageContent(page.Key); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// foreignKeyLoader 将同步时获取的数据库外键转为关系，按表缓存
+type foreignKeyLoader struct {
+	manager *MetadataService
+	out     map[int64][]models.RelationshipVO
+}
+
+func newForeignKeyLoader(manager *MetadataService) *foreignKeyLoader {
+	return &foreignKeyLoader{manager: manager, out: map[int64][]models.RelationshipVO{}}
+}
+
+// outgoing 表自身的外键；引用的表未同步或字段已不存在的外键跳过
+func (l *foreignKeyLoader) outgoing(tableID int64) ([]models.RelationshipVO, error) {
+	if rels, ok := l.out[tableID]; ok {
+		return rels, nil
+	}
+	keys, err := l.manager.ddlTableKeys(tableID)
+	if err != nil {
+		return nil, err
+	}
+	rels := []models.RelationshipVO{}
+	for i, k := range keys.keys {
+		if k.Kind != connect.KeyForeign || keys.refIDs[i] == 0 || len(k.Columns) != len(k.RefColumns) {
+			continue
+		}
+		vo := models.RelationshipVO{SourceTableID: tableID, TargetTableID: keys.refIDs[i], Label: k.Name,
+			Origin: meta.RelationshipOriginForeignKey}
+		for j := range k.Columns {
+			vo.Columns = append(vo.Columns, models.RelationshipColumnVO{SourceField: k.Columns[j], TargetField: k.RefColumns[j]})
+		}
+		_, cols, err := l.manager.relationshipFromVO(vo)
+		if err != nil {
+			continue
+		}
+		for j, c := range cols {
+			vo.Columns[j].SourceFieldID, vo.Columns[j].TargetFieldID = c.SourceFieldID, c.TargetFieldID
+		}
+		vo.SourceTable, vo.TargetTable = l.tableName(vo.SourceTableID), l.tableName(vo.TargetTableID)
+		rels = append(rels, vo)
+	}
+	l.out[tableID] = rels
+	return rels, nil
+}
+
+// incoming 其他表引用该表的外键
+func (l *foreignKeyLoader) incoming(tableID int64) ([]models.RelationshipVO, error) {
+	_, _, _, name, _, _, err := l.manager.rawStorage.GetTableContextByID(tableID)
+	if err != nil {
+		return nil, fmt.Errorf("table %d not found: %w", tableID, err)
+	}
+	ids, err := l.manager.rawStorage.FindForeignKeyTableIDs(name)
+	if err != nil {
+		return nil, fmt.Errorf("load foreign keys failed: %w", err)
+	}
+	var rels []models.RelationshipVO
+	for _, id := range ids {
+		out, err := l.outgoing(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range out {
+			if r.TargetTableID == tableID {
+				rels = append(rels, r)
+			}
+		}
+	}
+	return rels, nil
+}
+
+func (l *foreignKeyLoader) tableName(tableID int64) string {
+	_, _, schemaName, tableName, _, _, _ := l.manager.rawStorage.GetTableContextByID(tableID)
+	return qualifyName(schemaName, tableName)
+}
+
+// relationshipSignature 按两端表与字段对识别重复关系
+func relationshipSignature(r models.RelationshipVO) string {
+	parts := []string{fmt.Sprintf("%d>%d", r.SourceTableID, r.TargetTableID)}
+	for _, c := range r.Columns {
+		parts = append(parts, fmt.Sprintf("%d=%d", c.SourceFieldID, c.TargetFieldID))
+	}
+	return strings.Join(parts, ",")
+}
+
+// flowEdgeKey 生成与前端 vue-flow 一致的连线ID
+func flowEdgeKey(source, sourceField, target, targetField string) string {
+	return fmt.Sprintf("vueflow__edge-%ssr-%s-%stl-%s", source, sourceField, target, targetField)
+}
+
+// matchesAny 表名是否匹配任一通配符（忽略大小写）；带 schema 的名称同时按 schema.table 与表名本身匹配
+func matchesAny(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	bare := bareTableName(name)
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, bare); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bareTableName 去掉 schema.table 中的 schema 部分
+func bareTableName(name string) string {
+	return name[strings.LastIndex(name, ".")+1:]
+}
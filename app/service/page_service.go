@@ -0,0 +1,838 @@
+package service
+
+import (
+	"crypto/rand"
+	"dbrun/app/cache"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 项目配置键
+const (
+	pageTabsKey             = "page_tabs"           // 打开的页面标签
+	boltPagesImportedKey    = "bolt_pages_imported" // 标记旧版页面树已导入
+	pageMoveBefore          = "before"
+	pageMoveAfter           = "after"
+	pageMoveInside          = "inside"
+	pageIssueMissingObject  = "missing_object"
+	pageIssueBrokenRelation = "broken_relationship"
+	pageIssueDanglingRel    = "dangling_relationship"
+)
+
+// GetPageTree 返回页面树
+func GetPageTree() ([]models.PageVO, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	pages, err := manager.pages.ListPages()
+	if err != nil {
+		return nil, fmt.Errorf("list pages failed: %w", err)
+	}
+	nodes, err := manager.pages.ListAllNodes()
+	if err != nil {
+		return nil, fmt.Errorf("list page nodes failed: %w", err)
+	}
+	counts := map[int64]int{}
+	for _, n := range nodes {
+		counts[n.PageID]++
+	}
+	return buildPageTree(pages, counts), nil
+}
+
+// CreatePage 新建页面或分组，追加到所属分组末尾；未指定 Key 时自动生成
+func CreatePage(vo models.PageVO) (models.PageVO, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return vo, err
+	}
+	if vo.Type == "" {
+		vo.Type = meta.PageTypePage
+	}
+	if vo.Type != meta.PageTypePage && vo.Type != meta.PageTypeGroup {
+		return vo, fmt.Errorf("invalid page type: %s", vo.Type)
+	}
+	if strings.TrimSpace(vo.Label) == "" {
+		return vo, fmt.Errorf("page label is required")
+	}
+	if vo.Key == "" {
+		vo.Key = newPageKey()
+	} else if existing, err := manager.pages.GetPageByKey(vo.Key); err != nil {
+		return vo, err
+	} else if existing != nil {
+		return vo, fmt.Errorf("page already exists: %s", vo.Key)
+	}
+	parentID, err := manager.pageParentID(vo.ParentKey)
+	if err != nil {
+		return vo, err
+	}
+	pages, err := manager.pages.ListPages()
+	if err != nil {
+		return vo, err
+	}
+	sort := 0
+	for _, p := range pages {
+		if sameParent(p.ParentID, parentID) && p.Sort >= sort {
+			sort = p.Sort + 1
+		}
+	}
+	row := &meta.DiagramPage{
+		Key:      vo.Key,
+		ParentID: parentID,
+		Type:     vo.Type,
+		Label:    vo.Label,
+		Icon:     vo.Icon,
+		Sort:     sort,
+		Expanded: vo.Expanded,
+		Style:    encodeStyle(vo.Style),
+	}
+	if err := manager.pages.SavePage(row); err != nil {
+		return vo, fmt.Errorf("create page failed: %w", err)
+	}
+	return pageVO(*row, vo.ParentKey, 0), nil
+}
+
+// UpdatePage 更新页面名称、图标、展开状态与样式；调整位置使用 MovePage
+func UpdatePage(vo models.PageVO) (models.PageVO, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return vo, err
+	}
+	row, err := manager.requirePage(vo.Key)
+	if err != nil {
+		return vo, err
+	}
+	if strings.TrimSpace(vo.Label) == "" {
+		return vo, fmt.Errorf("page label is required")
+	}
+	row.Label = vo.Label
+	row.Icon = vo.Icon
+	row.Expanded = vo.Expanded
+	row.Style = encodeStyle(vo.Style)
+	if err := manager.pages.SavePage(row); err != nil {
+		return vo, fmt.Errorf("update page failed: %w", err)
+	}
+	return manager.pageVOByKey(row.Key)
+}
+
+// MovePage 移动页面：position 为 before / after 时放在目标同级前后，为 inside 时放入目标分组末尾（目标为空表示根级）
+func MovePage(key, targetKey, position string) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	pages, err := manager.pages.ListPages()
+	if err != nil {
+		return err
+	}
+	byKey := map[string]*meta.DiagramPage{}
+	byID := map[int64]*meta.DiagramPage{}
+	for i := range pages {
+		byKey[pages[i].Key] = &pages[i]
+		byID[pages[i].ID] = &pages[i]
+	}
+	src := byKey[key]
+	if src == nil {
+		return fmt.Errorf("page not found: %s", key)
+	}
+	var target *meta.DiagramPage
+	if targetKey != "" {
+		if target = byKey[targetKey]; target == nil {
+			return fmt.Errorf("page not found: %s", targetKey)
+		}
+	}
+
+	var parentID *int64
+	switch position {
+	case pageMoveInside:
+		if target != nil {
+			if target.Type != meta.PageTypeGroup {
+				return fmt.Errorf("target is not a group: %s", targetKey)
+			}
+			parentID = &target.ID
+		}
+	case pageMoveBefore, pageMoveAfter:
+		if target == nil {
+			return fmt.Errorf("target page is required for %s", position)
+		}
+		if target.ID == src.ID {
+			return nil
+		}
+		parentID = target.ParentID
+	default:
+		return fmt.Errorf("invalid move position: %s", position)
+	}
+	// 不能移动到自身或其子孙分组内
+	for p := parentID; p != nil && byID[*p] != nil; p = byID[*p].ParentID {
+		if *p == src.ID {
+			return fmt.Errorf("cannot move a group into itself")
+		}
+	}
+
+	siblings := make([]meta.DiagramPage, 0)
+	for _, p := range pages {
+		if p.ID != src.ID && sameParent(p.ParentID, parentID) {
+			siblings = append(siblings, p)
+		}
+	}
+	moved := *src
+	moved.ParentID = parentID
+	idx := len(siblings)
+	if position != pageMoveInside {
+		for i, p := range siblings {
+			if p.ID == target.ID {
+				idx = i
+				if position == pageMoveAfter {
+					idx++
+				}
+				break
+			}
+		}
+	}
+	ordered := append(append(append([]meta.DiagramPage{}, siblings[:idx]...), moved), siblings[idx:]...)
+	for i := range ordered {
+		ordered[i].Sort = i
+	}
+	if err := manager.pages.UpdateSorts(ordered); err != nil {
+		return fmt.Errorf("move page failed: %w", err)
+	}
+	return nil
+}
+
+// DeletePage 删除页面或分组（含所有子页面）及其节点与关系
+func DeletePage(key string) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	row, err := manager.requirePage(key)
+	if err != nil {
+		return err
+	}
+	pages, err := manager.pages.ListPages()
+	if err != nil {
+		return err
+	}
+	ids := []int64{row.ID}
+	keys := []string{row.Key}
+	for i := 0; i < len(ids); i++ {
+		for _, p := range pages {
+			if p.ParentID != nil && *p.ParentID == ids[i] {
+				ids = append(ids, p.ID)
+				keys = append(keys, p.Key)
+			}
+		}
+	}
+	if err := manager.pages.DeletePages(ids, keys); err != nil {
+		return fmt.Errorf("delete page failed: %w", err)
+	}
+	manager.removePageTabs(keys)
+	return nil
+}
+
+// GetPageContent 返回页面上的节点与关系，节点补充对象名称并标记已不存在的对象
+func GetPageContent(key string) (models.PageContent, error) {
+	var content models.PageContent
+	manager, err := getMgr()
+	if err != nil {
+		return content, err
+	}
+	row, err := manager.requirePage(key)
+	if err != nil {
+		return content, err
+	}
+	nodes, err := manager.pages.ListNodes(row.ID)
+	if err != nil {
+		return content, fmt.Errorf("list page nodes failed: %w", err)
+	}
+	if content.Page, err = manager.pageVOByKey(key); err != nil {
+		return content, err
+	}
+	if content.Nodes, err = manager.pageNodeVOs(nodes); err != nil {
+		return content, err
+	}
+	rels, err := manager.relations.List(meta.RelationshipFilter{PageID: &key})
+	if err != nil {
+		return content, fmt.Errorf("list relationships failed: %w", err)
+	}
+	if content.Relationships, err = manager.relationshipVOs(rels); err != nil {
+		return content, err
+	}
+	return content, nil
+}
+
+// SavePageNodes 保存页面上的全部节点（整体替换），返回补全后的节点
+func SavePageNodes(key string, nodes []models.PageNodeVO) ([]models.PageNodeVO, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	row, err := manager.requirePage(key)
+	if err != nil {
+		return nil, err
+	}
+	if row.Type != meta.PageTypePage {
+		return nil, fmt.Errorf("cannot place nodes on a group: %s", key)
+	}
+	rows := make([]meta.PageNode, 0, len(nodes))
+	seen := map[string]bool{}
+	now := time.Now().UnixMilli()
+	for i, n := range nodes {
+		if n.ObjectKind != meta.PageObjectTable && n.ObjectKind != meta.PageObjectView {
+			return nil, fmt.Errorf("invalid node object kind: %s", n.ObjectKind)
+		}
+		if n.ObjectID == 0 {
+			return nil, fmt.Errorf("node %s has no object id", n.Key)
+		}
+		if n.Key == "" {
+			n.Key = fmt.Sprintf("%s_%d", defaultString(n.ObjectName, n.ObjectKind), now+int64(i))
+		}
+		if seen[n.Key] {
+			return nil, fmt.Errorf("duplicate node key: %s", n.Key)
+		}
+		seen[n.Key] = true
+		rows = append(rows, meta.PageNode{
+			NodeKey:    n.Key,
+			ObjectKind: n.ObjectKind,
+			ObjectID:   n.ObjectID,
+			X:          n.X,
+			Y:          n.Y,
+			Width:      n.Width,
+			Height:     n.Height,
+			Style:      encodeStyle(n.Style),
+		})
+	}
+	if err := manager.pages.ReplaceNodes(row.ID, rows); err != nil {
+		return nil, fmt.Errorf("save page nodes failed: %w", err)
+	}
+	return manager.pageNodeVOs(rows)
+}
+
+// ValidatePages 校验页面引用：节点对象是否存在、关系是否损坏、关系两端是否在页面上；key 为空时校验所有页面
+func ValidatePages(key string) ([]models.PageIssue, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	pages, err := manager.pages.ListPages()
+	if err != nil {
+		return nil, err
+	}
+	issues := []models.PageIssue{}
+	for _, p := range pages {
+		if p.Type != meta.PageTypePage || (key != "" && p.Key != key) {
+			continue
+		}
+		content, err := GetPageContent(p.Key)
+		if err != nil {
+			return nil, err
+		}
+		onPage := map[int64]bool{}
+		for _, n := range content.Nodes {
+			if n.Missing {
+				issues = append(issues, models.PageIssue{
+					PageKey: p.Key, PageLabel: p.Label, Kind: pageIssueMissingObject, NodeKey: n.Key,
+					Message: fmt.Sprintf("%s %d no longer exists", n.ObjectKind, n.ObjectID),
+				})
+				continue
+			}
+			if n.ObjectKind == meta.PageObjectTable {
+				onPage[n.ObjectID] = true
+			}
+		}
+		for _, r := range content.Relationships {
+			switch {
+			case r.Broken:
+				issues = append(issues, models.PageIssue{
+					PageKey: p.Key, PageLabel: p.Label, Kind: pageIssueBrokenRelation, RelationshipID: r.ID,
+					Message: fmt.Sprintf("relationship %s -> %s references a missing table or column", r.SourceTable, r.TargetTable),
+				})
+			case !onPage[r.SourceTableID] || !onPage[r.TargetTableID]:
+				issues = append(issues, models.PageIssue{
+					PageKey: p.Key, PageLabel: p.Label, Kind: pageIssueDanglingRel, RelationshipID: r.ID,
+					Message: fmt.Sprintf("relationship %s -> %s connects a table not placed on the page", r.SourceTable, r.TargetTable),
+				})
+			}
+		}
+	}
+	return issues, nil
+}
+
+// SearchPages 按名称搜索页面，以及页面上的表和视图（忽略大小写）
+func SearchPages(keyword string) ([]models.PageSearchHit, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	keyword = strings.ToLower(strings.TrimSpace(keyword))
+	hits := []models.PageSearchHit{}
+	if keyword == "" {
+		return hits, nil
+	}
+	pages, err := manager.pages.ListPages()
+	if err != nil {
+		return nil, err
+	}
+	byID := map[int64]meta.DiagramPage{}
+	for _, p := range pages {
+		byID[p.ID] = p
+	}
+	path := func(p meta.DiagramPage) string {
+		var parts []string
+		for id := p.ParentID; id != nil; {
+			parent, ok := byID[*id]
+			if !ok {
+				break
+			}
+			parts = append([]string{parent.Label}, parts...)
+			id = parent.ParentID
+		}
+		return strings.Join(parts, "/")
+	}
+	for _, p := range pages {
+		if strings.Contains(strings.ToLower(p.Label), keyword) {
+			hits = append(hits, models.PageSearchHit{PageKey: p.Key, PageLabel: p.Label, Path: path(p), Match: p.Type})
+		}
+	}
+	nodes, err := manager.pages.ListAllNodes()
+	if err != nil {
+		return nil, err
+	}
+	vos, err := manager.pageNodeVOs(nodes)
+	if err != nil {
+		return nil, err
+	}
+	for i, n := range vos {
+		p := byID[nodes[i].PageID]
+		if n.Missing || !strings.Contains(strings.ToLower(n.ObjectName), keyword) {
+			continue
+		}
+		hits = append(hits, models.PageSearchHit{
+			PageKey: p.Key, PageLabel: p.Label, Path: path(p), Match: n.ObjectKind,
+			NodeKey: n.Key, ObjectName: qualifyName(n.SchemaName, n.ObjectName),
+		})
+	}
+	return hits, nil
+}
+
+// GetPageTabs 返回打开的页面标签
+func GetPageTabs() (models.PageTabState, error) {
+	state := models.PageTabState{OpenTabs: []models.PageTab{}}
+	manager, err := getMgr()
+	if err != nil {
+		return state, err
+	}
+	value, ok, err := manager.settings.Get(pageTabsKey)
+	if err != nil || !ok {
+		return state, err
+	}
+	if err := json.Unmarshal([]byte(value), &state); err != nil {
+		return state, fmt.Errorf("invalid page tabs: %w", err)
+	}
+	return state, nil
+}
+
+// SavePageTabs 保存打开的页面标签
+func SavePageTabs(state models.PageTabState) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	if state.OpenTabs == nil {
+		state.OpenTabs = []models.PageTab{}
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	return manager.settings.Set(pageTabsKey, string(data))
+}
+
+// removePageTabs 关闭已删除页面的标签
+func (m *MetadataService) removePageTabs(keys []string) {
+	state, err := GetPageTabs()
+	if err != nil {
+		return
+	}
+	removed := map[string]bool{}
+	for _, k := range keys {
+		removed[k] = true
+	}
+	tabs := state.OpenTabs[:0]
+	for _, t := range state.OpenTabs {
+		if !removed[t.Key] {
+			tabs = append(tabs, t)
+		}
+	}
+	state.OpenTabs = tabs
+	if removed[state.ActiveTab] {
+		state.ActiveTab = ""
+		if len(tabs) > 0 {
+			state.ActiveTab = tabs[len(tabs)-1].Key
+		}
+	}
+	if err := SavePageTabs(state); err != nil {
+		fmt.Printf("[Page] update tabs failed: %v\n", err)
+	}
+}
+
+// legacyPage 旧版页面缓存（pageStore）中的页面树节点
+type legacyPage struct {
+	Key      string       `json:"key"`
+	Label    string       `json:"label"`
+	Icon     string       `json:"icon"`
+	Type     string       `json:"type"`
+	Expanded bool         `json:"expanded"`
+	Children []legacyPage `json:"children"`
+}
+
+// ImportBoltPages 将页面缓存（pageStore 与 flow_node_{pageId}）中的页面树、节点与标签导入；已存在的页面跳过
+func ImportBoltPages() (models.PageImportResult, error) {
+	result := models.PageImportResult{Failed: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if cache.GetBoltCache() == nil {
+		return result, fmt.Errorf("app cache not initialized")
+	}
+	var stateJSON string
+	cache.Get("pageStore", &stateJSON)
+	if stateJSON == "" {
+		return result, nil
+	}
+	var state struct {
+		Pages     []legacyPage     `json:"pages"`
+		OpenTabs  []models.PageTab `json:"openTabs"`
+		ActiveTab string           `json:"activeTab"`
+	}
+	if err := json.Unmarshal([]byte(stateJSON), &state); err != nil {
+		return result, fmt.Errorf("invalid page store: %w", err)
+	}
+	if err := manager.importLegacyPages(state.Pages, nil, &result); err != nil {
+		return result, err
+	}
+	if _, ok, err := manager.settings.Get(pageTabsKey); err == nil && !ok {
+		if err := SavePageTabs(models.PageTabState{OpenTabs: state.OpenTabs, ActiveTab: state.ActiveTab}); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// importLegacyPages 递归导入页面树
+func (m *MetadataService) importLegacyPages(pages []legacyPage, parentID *int64, result *models.PageImportResult) error {
+	for i, lp := range pages {
+		if lp.Key == "" {
+			continue
+		}
+		if lp.Type == "" {
+			lp.Type = meta.PageTypePage
+			if lp.Children != nil {
+				lp.Type = meta.PageTypeGroup
+			}
+		}
+		row, err := m.pages.GetPageByKey(lp.Key)
+		if err != nil {
+			return err
+		}
+		if row != nil {
+			result.Skipped++
+		} else {
+			row = &meta.DiagramPage{
+				Key:      lp.Key,
+				ParentID: parentID,
+				Type:     lp.Type,
+				Label:    defaultString(lp.Label, lp.Key),
+				Icon:     lp.Icon,
+				Sort:     i,
+				Expanded: lp.Expanded,
+				Style:    encodeStyle(models.PageStyle{}),
+			}
+			if err := m.pages.SavePage(row); err != nil {
+				return fmt.Errorf("import page %s failed: %w", lp.Key, err)
+			}
+			if row.Type == meta.PageTypeGroup {
+				result.Groups++
+			} else {
+				result.Pages++
+				if err := m.importLegacyNodes(row, result); err != nil {
+					result.Failed = append(result.Failed, fmt.Sprintf("page %s: %v", lp.Key, err))
+				}
+			}
+		}
+		if err := m.importLegacyPages(lp.Children, &row.ID, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importLegacyNodes 导入单个页面的节点；data.table 含 definition 的节点为视图
+func (m *MetadataService) importLegacyNodes(page *meta.DiagramPage, result *models.PageImportResult) error {
+	var nodesJSON string
+	cache.Get("flow_node_"+page.Key, &nodesJSON)
+	if nodesJSON == "" {
+		return nil
+	}
+	var nodes []map[string]interface{}
+	if err := json.Unmarshal([]byte(nodesJSON), &nodes); err != nil {
+		return fmt.Errorf("invalid page nodes: %w", err)
+	}
+	rows := make([]meta.PageNode, 0, len(nodes))
+	for _, n := range nodes {
+		key, _ := n["id"].(string)
+		kind := meta.PageObjectTable
+		objectID := nodeTableID(n)
+		if data, ok := n["data"].(map[string]interface{}); ok {
+			if t, ok := data["table"].(map[string]interface{}); ok {
+				if _, isView := t["definition"]; isView {
+					kind = meta.PageObjectView
+					id, _ := t["id"].(float64)
+					objectID = int64(id)
+				}
+			}
+		}
+		if key == "" || objectID == 0 {
+			result.Failed = append(result.Failed, fmt.Sprintf("page %s node %s: object id not found", page.Key, key))
+			continue
+		}
+		row := meta.PageNode{NodeKey: key, ObjectKind: kind, ObjectID: objectID, Style: encodeStyle(models.NodeStyle{})}
+		if pos, ok := n["position"].(map[string]interface{}); ok {
+			row.X, _ = pos["x"].(float64)
+			row.Y, _ = pos["y"].(float64)
+		}
+		if dim, ok := n["dimensions"].(map[string]interface{}); ok {
+			row.Width, _ = dim["width"].(float64)
+			row.Height, _ = dim["height"].(float64)
+		}
+		rows = append(rows, row)
+	}
+	if err := m.pages.ReplaceNodes(page.ID, rows); err != nil {
+		return err
+	}
+	result.Nodes += len(rows)
+	return nil
+}
+
+// ImportLegacyCacheOnce 打开项目时各导入一次旧版页面树与页面连线，失败只记录日志
+func ImportLegacyCacheOnce(projectDir string) {
+	manager, err := getMgr()
+	if err != nil {
+		return
+	}
+	pending := func(key string) bool {
+		_, done, err := manager.settings.Get(key)
+		return err == nil && !done
+	}
+	importPages, importEdges := pending(boltPagesImportedKey), pending(boltRelationshipsImportedKey)
+	if !importPages && !importEdges {
+		return
+	}
+	// 页面缓存与 relation.db 位于同一项目目录
+	if err := cache.InitAppCache(projectDir); err != nil {
+		fmt.Printf("[Page] open app cache failed: %v\n", err)
+		return
+	}
+	if importPages {
+		result, err := ImportBoltPages()
+		if err != nil {
+			fmt.Printf("[Page] import pages failed: %v\n", err)
+		} else {
+			fmt.Printf("[Page] imported pages: pages=%d groups=%d nodes=%d skipped=%d failed=%d\n",
+				result.Pages, result.Groups, result.Nodes, result.Skipped, len(result.Failed))
+			if err := manager.settings.Set(boltPagesImportedKey, "true"); err != nil {
+				fmt.Printf("[Page] mark import done failed: %v\n", err)
+			}
+		}
+	}
+	if importEdges {
+		result, err := ImportBoltRelationships("")
+		if err != nil {
+			fmt.Printf("[Relationship] import page edges failed: %v\n", err)
+			return
+		}
+		fmt.Printf("[Relationship] imported page edges: pages=%d imported=%d skipped=%d failed=%d\n",
+			result.Pages, result.Imported, result.Skipped, len(result.Failed))
+		if err := manager.settings.Set(boltRelationshipsImportedKey, "true"); err != nil {
+			fmt.Printf("[Relationship] mark import done failed: %v\n", err)
+		}
+	}
+}
+
+// requirePage 按键获取页面，不存在时返回错误
+func (m *MetadataService) requirePage(key string) (*meta.DiagramPage, error) {
+	row, err := m.pages.GetPageByKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if row == nil {
+		return nil, fmt.Errorf("page not found: %s", key)
+	}
+	return row, nil
+}
+
+// pageParentID 解析所属分组，键为空表示根级
+func (m *MetadataService) pageParentID(parentKey string) (*int64, error) {
+	if parentKey == "" {
+		return nil, nil
+	}
+	parent, err := m.requirePage(parentKey)
+	if err != nil {
+		return nil, err
+	}
+	if parent.Type != meta.PageTypeGroup {
+		return nil, fmt.Errorf("parent is not a group: %s", parentKey)
+	}
+	return &parent.ID, nil
+}
+
+// pageVOByKey 返回单个页面（不含子节点）
+func (m *MetadataService) pageVOByKey(key string) (models.PageVO, error) {
+	row, err := m.requirePage(key)
+	if err != nil {
+		return models.PageVO{}, err
+	}
+	parentKey := ""
+	if row.ParentID != nil {
+		pages, err := m.pages.ListPages()
+		if err != nil {
+			return models.PageVO{}, err
+		}
+		for _, p := range pages {
+			if p.ID == *row.ParentID {
+				parentKey = p.Key
+			}
+		}
+	}
+	nodes, err := m.pages.ListNodes(row.ID)
+	if err != nil {
+		return models.PageVO{}, err
+	}
+	return pageVO(*row, parentKey, len(nodes)), nil
+}
+
+// pageNodeVOs 组装节点VO，补充对象名称与所在位置
+func (m *MetadataService) pageNodeVOs(nodes []meta.PageNode) ([]models.PageNodeVO, error) {
+	var tableIDs, viewIDs []int64
+	for _, n := range nodes {
+		if n.ObjectKind == meta.PageObjectView {
+			viewIDs = append(viewIDs, n.ObjectID)
+		} else {
+			tableIDs = append(tableIDs, n.ObjectID)
+		}
+	}
+	refs, err := m.rawStorage.GetRawObjectsByIDs(tableIDs, viewIDs)
+	if err != nil {
+		return nil, fmt.Errorf("load page objects failed: %w", err)
+	}
+	objects := map[string]meta.RawObjectRef{}
+	for _, r := range refs {
+		kind := meta.PageObjectTable
+		if r.IsView {
+			kind = meta.PageObjectView
+		}
+		objects[fmt.Sprintf("%s:%d", kind, r.ID)] = r
+	}
+	out := make([]models.PageNodeVO, 0, len(nodes))
+	for _, n := range nodes {
+		vo := models.PageNodeVO{
+			Key:        n.NodeKey,
+			ObjectKind: n.ObjectKind,
+			ObjectID:   n.ObjectID,
+			X:          n.X,
+			Y:          n.Y,
+			Width:      n.Width,
+			Height:     n.Height,
+		}
+		decodeStyle(n.Style, &vo.Style)
+		if r, ok := objects[fmt.Sprintf("%s:%d", n.ObjectKind, n.ObjectID)]; ok {
+			vo.ObjectName = r.Name
+			vo.DatabaseID = r.DatabaseID
+			vo.DatabaseName = r.DatabaseName
+			vo.SchemaName = r.SchemaName
+		} else {
+			vo.Missing = true
+		}
+		out = append(out, vo)
+	}
+	return out, nil
+}
+
+// buildPageTree 按所属分组组装页面树
+func buildPageTree(pages []meta.DiagramPage, counts map[int64]int) []models.PageVO {
+	children := map[int64][]meta.DiagramPage{}
+	keys := map[int64]string{}
+	var roots []meta.DiagramPage
+	for _, p := range pages {
+		keys[p.ID] = p.Key
+	}
+	for _, p := range pages {
+		if p.ParentID == nil || keys[*p.ParentID] == "" {
+			roots = append(roots, p)
+			continue
+		}
+		children[*p.ParentID] = append(children[*p.ParentID], p)
+	}
+	var build func(rows []meta.DiagramPage, parentKey string) []models.PageVO
+	build = func(rows []meta.DiagramPage, parentKey string) []models.PageVO {
+		out := make([]models.PageVO, 0, len(rows))
+		for _, p := range rows {
+			vo := pageVO(p, parentKey, counts[p.ID])
+			vo.Children = build(children[p.ID], p.Key)
+			out = append(out, vo)
+		}
+		return out
+	}
+	return build(roots, "")
+}
+
+func pageVO(p meta.DiagramPage, parentKey string, nodeCount int) models.PageVO {
+	vo := models.PageVO{
+		Key:       p.Key,
+		ParentKey: parentKey,
+		Type:      p.Type,
+		Label:     p.Label,
+		Icon:      p.Icon,
+		Expanded:  p.Expanded,
+		NodeCount: nodeCount,
+		Children:  []models.PageVO{},
+		UpdatedAt: p.UpdatedAt,
+	}
+	decodeStyle(p.Style, &vo.Style)
+	return vo
+}
+
+func sameParent(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func encodeStyle(style interface{}) string {
+	data, err := json.Marshal(style)
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+func decodeStyle(text string, dest interface{}) {
+	if text != "" {
+		_ = json.Unmarshal([]byte(text), dest)
+	}
+}
+
+// newPageKey 生成与前端一致的 UUID v4 页面键
+func newPageKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"dbrun/app/dialect"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"dbrun/app/sqlparse"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPreviewPageSize = 100
+	maxPreviewPageSize     = 1000
+)
+
+// previewQuery 预览查询的各组成部分，分页与否由调用方决定
+type previewQuery struct {
+	columns []string
+	from    string
+	where   string
+	orderBy string
+	args    []interface{}
+	fields  []meta.RawFieldInfo
+}
+
+// tableTarget 通过表ID解析出的查询目标
+type tableTarget struct {
+	configID   int64
+	dbName     string
+	schemaName string
+	tableName  string
+	fields     []meta.RawFieldInfo
+}
+
+// resolveTableTarget 通过 GetTableContextByID 解析表所在的连接与限定名，并加载字段
+func resolveTableTarget(manager *MetadataService, tableID int64) (*tableTarget, error) {
+	rs := manager.GetRawStorage()
+	configID, dbName, schemaName, tableName, _, _, err := rs.GetTableContextByID(tableID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve table context failed: %w", err)
+	}
+	fields, err := rs.GetRawFieldsRows(tableID)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("table %s has no synchronized fields, please sync it first", tableName)
+	}
+	return &tableTarget{configID: configID, dbName: dbName, schemaName: schemaName, tableName: tableName, fields: fields}, nil
+}
+
+// PreviewTableData 分页预览表数据，过滤与排序在服务端执行
+func PreviewTableData(tableID int64, filters []models.ColumnFilter, sorts []models.SortOrder, page models.PageRequest) (models.TablePreview, error) {
+	preview := models.TablePreview{TableID: tableID}
+	manager, err := getMgr()
+	if err != nil {
+		return preview, err
+	}
+	target, err := resolveTableTarget(manager, tableID)
+	if err != nil {
+		return preview, err
+	}
+	conn, creds, err := getQueryConnection(manager, target.configID)
+	if err != nil {
+		return preview, err
+	}
+	d, err := dialect.Get(creds.Type)
+	if err != nil {
+		return preview, err
+	}
+	q, err := buildPreviewQuery(d, target, filters, sorts)
+	if err != nil {
+		return preview, err
+	}
+
+	pageNo, pageSize := page.Page, page.PageSize
+	if pageNo <= 0 {
+		pageNo = 1
+	}
+	if pageSize <= 0 {
+		pageSize = defaultPreviewPageSize
+	}
+	if pageSize > maxPreviewPageSize {
+		pageSize = maxPreviewPageSize
+	}
+	// 多取一行用于判断是否存在下一页
+	sqlText := d.SelectPage(q.columns, q.from, q.where, q.orderBy, pageSize+1, (pageNo-1)*pageSize)
+	preview.SQL = sqlText
+	preview.Page = pageNo
+	preview.PageSize = pageSize
+
+	ctx := context.Background()
+	session, err := conn.OpenSession(ctx)
+	if err != nil {
+		return preview, err
+	}
+	defer session.Close()
+
+	start := time.Now()
+	res := models.QueryResult{SQL: sqlText, Kind: string(sqlparse.KindSelect)}
+	rows, err := session.QueryContext(ctx, sqlText, q.args...)
+	if err != nil {
+		recordHistory(manager, target.configID, sqlText, q.args, res, err)
+		return preview, fmt.Errorf("preview query failed: %w", err)
+	}
+	defer rows.Close()
+	err = readResultSet(rows, pageSize, &res)
+	res.DurationMs = time.Since(start).Milliseconds()
+	recordHistory(manager, target.configID, sqlText, q.args, res, err)
+	if err != nil {
+		return preview, err
+	}
+	preview.DurationMs = res.DurationMs
+	preview.Rows = res.Rows
+	preview.HasMore = res.Truncated
+	preview.Columns = make([]models.PreviewColumn, len(q.fields))
+	for i, f := range q.fields {
+		col := models.PreviewColumn{Name: f.Name, Type: f.Type, Nullable: f.Nullable, Key: f.Key}
+		if i < len(res.Columns) {
+			col.DatabaseType = res.Columns[i].DatabaseType
+		}
+		preview.Columns[i] = col
+	}
+	return preview, nil
+}
+
+// buildPreviewQuery 构建预览查询；列名只接受元数据中存在的字段，值全部通过绑定参数传递
+func buildPreviewQuery(d dialect.Dialect, target *tableTarget, filters []models.ColumnFilter, sorts []models.SortOrder) (*previewQuery, error) {
+	q := &previewQuery{
+		from:   d.QualifiedName(target.dbName, target.schemaName, target.tableName),
+		fields: target.fields,
+	}
+	for _, f := range target.fields {
+		q.columns = append(q.columns, d.QuoteIdent(f.Name))
+	}
+
+	var conds []string
+	for _, f := range filters {
+		field, ok := findRawField(target.fields, f.Column)
+		if !ok {
+			return nil, fmt.Errorf("unknown filter column: %s", f.Column)
+		}
+		cond, args, err := buildFilterCondition(d, d.QuoteIdent(field.Name), f, len(q.args))
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+		q.args = append(q.args, args...)
+	}
+	q.where = strings.Join(conds, " AND ")
+
+	var orders []string
+	for _, s := range sorts {
+		field, ok := findRawField(target.fields, s.Column)
+		if !ok {
+			return nil, fmt.Errorf("unknown sort column: %s", s.Column)
+		}
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		orders = append(orders, d.QuoteIdent(field.Name)+" "+dir)
+	}
+	if len(orders) == 0 {
+		// 未指定排序时按主键排序，保证分页结果稳定；没有主键的表由方言决定（SQL Server 为 (SELECT NULL)）
+		for _, f := range target.fields {
+			if f.Key == "PRI" {
+				orders = append(orders, d.QuoteIdent(f.Name)+" ASC")
+			}
+		}
+	}
+	q.orderBy = strings.Join(orders, ", ")
+	return q, nil
+}
+
+// findRawField 按名称查找字段（优先精确匹配，其次忽略大小写）
+func findRawField(fields []meta.RawFieldInfo, name string) (meta.RawFieldInfo, bool) {
+	for _, f := range fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return meta.RawFieldInfo{}, false
+}
+
+// likeEscaper 转义 LIKE 模式中的通配符，统一使用 ! 作为转义字符以兼容各方言
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// buildFilterCondition 生成单列过滤条件，argBase 为已有参数个数
+func buildFilterCondition(d dialect.Dialect, col string, f models.ColumnFilter, argBase int) (string, []interface{}, error) {
+	ph := func(i int) string { return d.Placeholder(argBase + i) }
+	op := strings.ToLower(strings.TrimSpace(f.Operator))
+	switch op {
+	case "", "=", "!=", "<>", ">", ">=", "<", "<=":
+		if op == "" {
+			op = "="
+		}
+		if op == "!=" {
+			op = "<>"
+		}
+		if f.Value == nil {
+			return "", nil, fmt.Errorf("filter on %s requires a value", col)
+		}
+		return fmt.Sprintf("%s %s %s", col, op, ph(1)), []interface{}{f.Value}, nil
+	case "like", "not like":
+		return fmt.Sprintf("%s %s %s", col, strings.ToUpper(op), ph(1)), []interface{}{fmt.Sprint(f.Value)}, nil
+	case "contains", "starts_with", "ends_with":
+		pattern := likeEscaper.Replace(fmt.Sprint(f.Value))
+		switch op {
+		case "contains":
+			pattern = "%" + pattern + "%"
+		case "starts_with":
+			pattern = pattern + "%"
+		case "ends_with":
+			pattern = "%" + pattern
+		}
+		return fmt.Sprintf("%s LIKE %s ESCAPE '!'", col, ph(1)), []interface{}{pattern}, nil
+	case "in", "not in":
+		if len(f.Values) == 0 {
+			return "", nil, fmt.Errorf("filter %s on %s requires at least one value", op, col)
+		}
+		phs := make([]string, len(f.Values))
+		for i := range f.Values {
+			phs[i] = ph(i + 1)
+		}
+		return fmt.Sprintf("%s %s (%s)", col, strings.ToUpper(op), strings.Join(phs, ", ")), f.Values, nil
+	case "between":
+		if len(f.Values) != 2 {
+			return "", nil, fmt.Errorf("filter between on %s requires exactly two values", col)
+		}
+		return fmt.Sprintf("%s BETWEEN %s AND %s", col, ph(1), ph(2)), f.Values, nil
+	case "is null", "is not null":
+		return fmt.Sprintf("%s %s", col, strings.ToUpper(op)), nil, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported filter operator: %s", f.Operator)
+	}
+}
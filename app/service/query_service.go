@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"dbrun/app/connect"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"dbrun/app/sqlparse"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+	"unicode/utf8"
+)
+
+// 查询结果默认返回的最大行数
+const defaultMaxRows = 1000
+
+// configFromCredentials 由存储的凭证构建连接配置
+func configFromCredentials(creds *meta.Credentials) connect.Config {
+	return connect.Config{
+		ID:        creds.ID,
+		Type:      creds.Type,
+		Label:     creds.Label,
+		Username:  creds.Username,
+		Password:  creds.Password,
+		Host:      creds.Host,
+		Port:      creds.Port,
+		Database:  creds.Database,
+		Instance:  creds.Instance,
+		Options:   creds.Options,
+		ReadOnly:  creds.IsReadOnly(),
+		CreatedAt: creds.CreatedAt,
+	}
+}
+
+// getQueryConnection 按配置ID获取用于执行查询的连接
+// 只读标记以存储的凭证为准：连接池中的连接若与之不一致（例如由前端传入的配置创建），则丢弃后重建
+func getQueryConnection(manager *MetadataService, configID int64) (connect.Connection, *meta.Credentials, error) {
+	creds, err := manager.GetCredentialsByID(configID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get credentials failed: %w", err)
+	}
+	if creds.Type == "" {
+		return nil, nil, fmt.Errorf("missing database type in credentials for id %d", configID)
+	}
+	if pooled, ok := connect.GetConnectionFromPool(configID); ok {
+		if pooled.GetConfig().ReadOnly == creds.IsReadOnly() {
+			return pooled, creds, nil
+		}
+		if err := connect.RemoveConnection(configID); err != nil {
+			fmt.Printf("[QueryService] close stale connection failed: configID=%d err=%v\n", configID, err)
+		}
+	}
+	conn, err := connect.GetConnection(configFromCredentials(creds))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	return conn, creds, nil
+}
+
+// ClassifySQL 按连接的方言对SQL进行分类，供前端在执行前提示
+func ClassifySQL(configID int64, sqlText string) ([]sqlparse.Statement, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	creds, err := manager.GetCredentialsByID(configID)
+	if err != nil {
+		return nil, fmt.Errorf("get credentials failed: %w", err)
+	}
+	return sqlparse.Classify(creds.Type, sqlText)
+}
+
+// checkStatements 分类SQL；只读连接上出现非只读语句时直接拒绝
+func checkStatements(creds *meta.Credentials, sqlText string) ([]sqlparse.Statement, error) {
+	if creds.IsReadOnly() {
+		return sqlparse.CheckReadOnly(creds.Type, sqlText)
+	}
+	return sqlparse.Classify(creds.Type, sqlText)
+}
+
+// ExecuteQuery 在指定连接上执行SQL（可包含多条语句），每条语句返回一个结果
+// 只读连接会先经过语句分类校验，再在只读事务中执行并始终回滚
+func ExecuteQuery(configID int64, sqlText string, maxRows int) ([]models.QueryResult, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	conn, creds, err := getQueryConnection(manager, configID)
+	if err != nil {
+		return nil, err
+	}
+	stmts, err := checkStatements(creds, sqlText)
+	if err != nil {
+		return nil, err
+	}
+	if maxRows <= 0 {
+		maxRows = defaultMaxRows
+	}
+
+	ctx := context.Background()
+	session, err := conn.OpenSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer session.Close()
+
+	var exec sqlExecutor = session
+	if creds.IsReadOnly() {
+		// 语句分类只是执行前的提示，真正的保护是只读事务：执行完毕后始终回滚
+		tx, err := conn.BeginReadOnly(ctx, session)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+		exec = tx
+	}
+
+	results := make([]models.QueryResult, 0, len(stmts))
+	for _, st := range stmts {
+		res, err := executeStatement(ctx, exec, st, maxRows)
+		recordHistory(manager, configID, st.SQL, nil, res, err)
+		if err != nil {
+			return results, fmt.Errorf("execute %s statement failed: %w", st.Keyword, err)
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// statementReturnsRows 判断语句是否应以查询方式执行
+func statementReturnsRows(kind sqlparse.StatementKind) bool {
+	switch kind {
+	case sqlparse.KindSelect, sqlparse.KindShow, sqlparse.KindExplain, sqlparse.KindProcedure, sqlparse.KindUnknown:
+		return true
+	}
+	return false
+}
+
+// sqlExecutor 可执行语句的会话或事务
+type sqlExecutor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func executeStatement(ctx context.Context, session sqlExecutor, st sqlparse.Statement, maxRows int) (models.QueryResult, error) {
+	start := time.Now()
+	res := models.QueryResult{SQL: st.SQL, Kind: string(st.Kind)}
+	if !statementReturnsRows(st.Kind) {
+		r, err := session.ExecContext(ctx, st.SQL)
+		if err != nil {
+			return res, err
+		}
+		res.RowsAffected, _ = r.RowsAffected()
+		res.DurationMs = time.Since(start).Milliseconds()
+		return res, nil
+	}
+	rows, err := session.QueryContext(ctx, st.SQL)
+	if err != nil {
+		return res, err
+	}
+	defer rows.Close()
+	if err := readResultSet(rows, maxRows, &res); err != nil {
+		return res, err
+	}
+	res.DurationMs = time.Since(start).Milliseconds()
+	return res, nil
+}
+
+// readResultSet 读取结果集的列与最多 maxRows 行数据
+func readResultSet(rows *sql.Rows, maxRows int, res *models.QueryResult) error {
+	columns, err := resultColumns(rows)
+	if err != nil {
+		return err
+	}
+	res.Columns = columns
+	res.Rows = make([][]interface{}, 0)
+	for rows.Next() {
+		if res.RowCount >= maxRows {
+			res.Truncated = true
+			break
+		}
+		row, err := scanRow(rows, len(columns))
+		if err != nil {
+			return err
+		}
+		res.Rows = append(res.Rows, row)
+		res.RowCount++
+	}
+	return rows.Err()
+}
+
+// resultColumns 读取结果集的列信息
+func resultColumns(rows *sql.Rows) ([]models.QueryColumn, error) {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read column types: %w", err)
+	}
+	columns := make([]models.QueryColumn, len(types))
+	for i, ct := range types {
+		columns[i] = models.QueryColumn{Name: ct.Name(), DatabaseType: ct.DatabaseTypeName()}
+		if nullable, ok := ct.Nullable(); ok {
+			n := nullable
+			columns[i].Nullable = &n
+		}
+	}
+	return columns, nil
+}
+
+// scanRow 扫描当前行并转换为可JSON序列化的值
+func scanRow(rows *sql.Rows, n int) ([]interface{}, error) {
+	values := make([]interface{}, n)
+	ptrs := make([]interface{}, n)
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+	for i, v := range values {
+		values[i] = normalizeValue(v)
+	}
+	return values, nil
+}
+
+// JavaScript 可安全表示的最大整数
+const maxSafeInteger = 1<<53 - 1
+
+// normalizeValue 将驱动返回的值转换为前端可直接展示的值
+// []byte 按文本返回（非UTF-8内容以十六进制表示），超出JS安全范围的整数转为字符串
+func normalizeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		if utf8.Valid(val) {
+			return string(val)
+		}
+		return "0x" + hex.EncodeToString(val)
+	case int64:
+		if val > maxSafeInteger || val < -maxSafeInteger {
+			return fmt.Sprintf("%d", val)
+		}
+	case uint64:
+		if val > maxSafeInteger {
+			return fmt.Sprintf("%d", val)
+		}
+	case float64:
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			return fmt.Sprintf("%v", val)
+		}
+	case time.Time:
+		return val.Format("2006-01-02 15:04:05.999999999Z07:00")
+	}
+	return v
+}
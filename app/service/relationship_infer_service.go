@@ -0,0 +1,437 @@
+package service
+
+import (
+	"dbrun/app/cache"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"dbrun/app/sqlparse"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 参与推断的查询历史条数上限
+const inferHistoryLimit = 500
+
+// joinSide 连接条件一侧解析到的基础表字段
+type joinSide struct {
+	TableID int64
+	Column  string
+}
+
+// joinInference 汇总视图与查询中的等值连接，生成候选关系
+type joinInference struct {
+	manager    *MetadataService
+	databaseID int64
+	pairs      map[string]*joinPair
+	order      []string
+	fields     map[int64][]meta.RawFieldInfo
+}
+
+// joinPair 一对字段的连接及其依据
+type joinPair struct {
+	a, b     joinSide
+	evidence []models.RelationshipEvidence
+}
+
+// InferJoinRelationships 扫描数据库内视图定义（可选包括查询历史）中的等值连接，推断候选关系
+func InferJoinRelationships(databaseID int64, includeHistory bool) ([]models.RelationshipCandidate, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	configID, _, err := manager.rawStorage.GetDatabaseContextByID(databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("database not found: %w", err)
+	}
+	dialect := sqlparse.DialectMySQL
+	if creds, err := manager.GetCredentialsByID(configID); err == nil && connectionDialect(creds) != "" {
+		dialect = connectionDialect(creds)
+	}
+	inf := &joinInference{
+		manager:    manager,
+		databaseID: databaseID,
+		pairs:      map[string]*joinPair{},
+		fields:     map[int64][]meta.RawFieldInfo{},
+	}
+
+	views, err := manager.rawStorage.GetRawViewsByDatabaseID(databaseID)
+	if err != nil {
+		return nil, fmt.Errorf("load views failed: %w", err)
+	}
+	for _, v := range views {
+		if strings.TrimSpace(v.Definition) == "" {
+			continue
+		}
+		info, err := sqlparse.ParseSelect(dialect, v.Definition)
+		if err != nil {
+			fmt.Printf("[RelInfer] parse view failed: view=%s err=%v\n", v.Name, err)
+			continue
+		}
+		inf.scan(newViewResolver(manager, dialect, databaseID), info, 0,
+			models.RelationshipEvidence{Kind: meta.LineageSourceView, ID: v.ID, Name: v.Name})
+	}
+
+	if includeHistory {
+		page, err := manager.queryStorage.SearchHistory(meta.HistoryFilter{ConfigID: configID, Limit: inferHistoryLimit})
+		if err != nil {
+			return nil, fmt.Errorf("load query history failed: %w", err)
+		}
+		for _, h := range page.Items {
+			if h.Error != "" || h.Kind != string(sqlparse.KindSelect) {
+				continue
+			}
+			info, err := sqlparse.ParseSelect(dialect, h.SQL)
+			if err != nil {
+				continue
+			}
+			inf.scan(newViewResolver(manager, dialect, databaseID), info, 0,
+				models.RelationshipEvidence{Kind: "query", ID: h.ID, Name: summarizeSQL(h.SQL)})
+		}
+	}
+	return inf.candidates(), nil
+}
+
+// scan 收集查询中的等值连接：JOIN ... ON/USING 与 WHERE 中的隐式连接，并递归进入派生表与集合运算
+func (inf *joinInference) scan(r *viewResolver, info *sqlparse.SelectInfo, depth int, ev models.RelationshipEvidence) {
+	if info == nil || depth >= maxViewDepth {
+		return
+	}
+	sources := r.resolveSources(info, depth)
+	for i := range info.Tables {
+		t := &info.Tables[i]
+		for _, eq := range equalities(t.On) {
+			inf.addExpr(sources, eq, ev, false)
+		}
+		for _, col := range t.Using {
+			left, _, okL := findColumn(sources[:i], "", col)
+			right, _, okR := findColumn(sources[i:i+1], "", col)
+			if okL && okR {
+				e := ev
+				e.Expression = fmt.Sprintf("USING (%s)", col)
+				inf.add(left, right, e)
+			}
+		}
+		if t.Subquery != nil {
+			inf.scan(r, t.Subquery, depth+1, ev)
+		}
+	}
+	for _, eq := range equalities(info.Where) {
+		inf.addExpr(sources, eq, ev, true)
+	}
+	for _, u := range info.Unions {
+		inf.scan(r, u, depth+1, ev)
+	}
+}
+
+// equalities 拆分 AND 连接的条件，返回两侧均为列引用的等值比较
+func equalities(e *sqlparse.Expr) []*sqlparse.Expr {
+	if e == nil || e.Kind != sqlparse.ExprCompare {
+		return nil
+	}
+	switch e.Name {
+	case "AND":
+		var out []*sqlparse.Expr
+		for _, a := range e.Args {
+			out = append(out, equalities(a)...)
+		}
+		return out
+	case "=":
+		if len(e.Args) == 2 && e.Args[0].Kind == sqlparse.ExprColumn && e.Args[1].Kind == sqlparse.ExprColumn {
+			return []*sqlparse.Expr{e}
+		}
+	}
+	return nil
+}
+
+func (inf *joinInference) addExpr(sources []viewSource, eq *sqlparse.Expr, ev models.RelationshipEvidence, implicit bool) {
+	l, ls, okL := findColumn(sources, eq.Args[0].Qualifier, eq.Args[0].Name)
+	r, rs, okR := findColumn(sources, eq.Args[1].Qualifier, eq.Args[1].Name)
+	// 同一来源内的比较不是连接
+	if !okL || !okR || ls == rs {
+		return
+	}
+	ev.Expression = eq.Text
+	ev.Implicit = implicit
+	inf.add(l, r, ev)
+}
+
+// add 将两侧列追溯到基础表字段后记录
+func (inf *joinInference) add(l, r viewColumn, ev models.RelationshipEvidence) {
+	a, okA := inf.baseColumn(l)
+	b, okB := inf.baseColumn(r)
+	if !okA || !okB || (a.TableID == b.TableID && strings.EqualFold(a.Column, b.Column)) {
+		return
+	}
+	if a.TableID > b.TableID || (a.TableID == b.TableID && strings.ToLower(a.Column) > strings.ToLower(b.Column)) {
+		a, b = b, a
+	}
+	key := fmt.Sprintf("%d.%s=%d.%s", a.TableID, strings.ToLower(a.Column), b.TableID, strings.ToLower(b.Column))
+	p, ok := inf.pairs[key]
+	if !ok {
+		p = &joinPair{a: a, b: b}
+		inf.pairs[key] = p
+		inf.order = append(inf.order, key)
+	}
+	for _, e := range p.evidence {
+		if e.Kind == ev.Kind && e.ID == ev.ID && e.Expression == ev.Expression {
+			return
+		}
+	}
+	p.evidence = append(p.evidence, ev)
+}
+
+// baseColumn 将直接引用的列追溯到基础表字段；经过视图时沿字段血缘向上查找
+func (inf *joinInference) baseColumn(c viewColumn) (joinSide, bool) {
+	if len(c.Origins) != 1 || (c.Transform != "" && c.Transform != "direct") {
+		return joinSide{}, false
+	}
+	o := c.Origins[0]
+	for depth := 0; depth < maxViewDepth; depth++ {
+		if o.Kind == meta.LineageSourceTable {
+			return joinSide{TableID: o.ObjectID, Column: o.Column}, true
+		}
+		edges, err := inf.manager.lineage.GetUpstreamEdges(o.ObjectID, o.Column)
+		if err != nil || len(edges) != 1 || edges[0].Transform != "direct" {
+			return joinSide{}, false
+		}
+		o = columnOrigin{Kind: edges[0].SourceKind, ObjectID: edges[0].SourceID, Column: edges[0].SourceColumn}
+	}
+	return joinSide{}, false
+}
+
+// candidates 生成候选关系：确定引用方向并计算置信度，按置信度降序
+func (inf *joinInference) candidates() []models.RelationshipCandidate {
+	out := make([]models.RelationshipCandidate, 0, len(inf.order))
+	for _, key := range inf.order {
+		p := inf.pairs[key]
+		fa, okA := inf.field(p.a)
+		fb, okB := inf.field(p.b)
+		if !okA || !okB {
+			continue
+		}
+		// 被引用方（一）：主键/唯一键优先，其次名为 id 的字段
+		from, to, fromField, toField := p.a, p.b, fa, fb
+		if referenceRank(fa) > referenceRank(fb) {
+			from, to, fromField, toField = p.b, p.a, fb, fa
+		}
+		// 与当前数据库无关的关系不返回
+		if !inf.inDatabase(from.TableID) && !inf.inDatabase(to.TableID) {
+			continue
+		}
+		c := models.RelationshipCandidate{
+			Key:          key,
+			FromTableID:  from.TableID,
+			FromTable:    inf.tableName(from.TableID),
+			FromColumn:   fromField.Name,
+			ToTableID:    to.TableID,
+			ToTable:      inf.tableName(to.TableID),
+			ToColumn:     toField.Name,
+			TypeMismatch: !typesCompatible(fromField.Type, toField.Type),
+			Evidence:     p.evidence,
+		}
+		c.Confidence = joinConfidence(p.evidence, referenceRank(toField), c.TypeMismatch)
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Confidence > out[j].Confidence })
+	return out
+}
+
+// joinConfidence 显式连接的依据强于隐式连接，多处依据、被引用方为键时提高，类型不兼容时降低
+func joinConfidence(evidence []models.RelationshipEvidence, toRank int, mismatch bool) float64 {
+	score := 0.45
+	sources := map[string]bool{}
+	for _, e := range evidence {
+		if !e.Implicit {
+			score = 0.6
+		}
+		sources[fmt.Sprintf("%s:%d", e.Kind, e.ID)] = true
+	}
+	score += math.Min(float64(len(sources)-1)*0.1, 0.2)
+	switch toRank {
+	case 2:
+		score += 0.15
+	case 1:
+		score += 0.05
+	}
+	if mismatch {
+		score -= 0.3
+	}
+	score = math.Max(0.05, math.Min(score, 0.99))
+	return math.Round(score*100) / 100
+}
+
+// referenceRank 字段作为被引用方的可能性：2 主键/唯一键，1 名为 id，0 其他
+func referenceRank(f meta.RawFieldInfo) int {
+	switch strings.ToUpper(f.Key) {
+	case "PRI", "UNI":
+		return 2
+	}
+	if strings.EqualFold(f.Name, "id") {
+		return 1
+	}
+	return 0
+}
+
+// typesCompatible 判断两侧字段类型是否属于同一类别
+func typesCompatible(a, b string) bool {
+	fa, fb := typeFamily(a), typeFamily(b)
+	if fa == "unknown" || fb == "unknown" {
+		return true
+	}
+	numeric := func(f string) bool { return f == "int" || f == "decimal" }
+	return fa == fb || (numeric(fa) && numeric(fb))
+}
+
+func (inf *joinInference) field(s joinSide) (meta.RawFieldInfo, bool) {
+	rows, ok := inf.fields[s.TableID]
+	if !ok {
+		rows, _ = inf.manager.rawStorage.GetRawFieldsRows(s.TableID)
+		inf.fields[s.TableID] = rows
+	}
+	for _, f := range rows {
+		if strings.EqualFold(f.Name, s.Column) {
+			return f, true
+		}
+	}
+	return meta.RawFieldInfo{}, false
+}
+
+func (inf *joinInference) inDatabase(tableID int64) bool {
+	_, _, _, _, databaseID, _, err := inf.manager.rawStorage.GetTableContextByID(tableID)
+	return err == nil && databaseID == inf.databaseID
+}
+
+func (inf *joinInference) tableName(tableID int64) string {
+	_, _, schemaName, tableName, _, _, err := inf.manager.rawStorage.GetTableContextByID(tableID)
+	if err != nil {
+		return ""
+	}
+	return qualifyName(schemaName, tableName)
+}
+
+// summarizeSQL 压缩空白并截断，作为依据的展示名称
+func summarizeSQL(sql string) string {
+	s := strings.Join(strings.Fields(sql), " ")
+	if r := []rune(s); len(r) > 80 {
+		return string(r[:80]) + "..."
+	}
+	return s
+}
+
+// AcceptRelationshipCandidate 将候选关系作为连线添加到页面并保存为关系，两张表都需已在页面上
+func AcceptRelationshipCandidate(pageID string, c models.RelationshipCandidate) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	if cache.GetBoltCache() == nil {
+		return fmt.Errorf("app cache not initialized")
+	}
+	var nodesJSON, edgesJSON string
+	cache.Get("flow_node_"+pageID, &nodesJSON)
+	cache.Get("flow_edge_"+pageID, &edgesJSON)
+	var nodes, edges []map[string]interface{}
+	if nodesJSON != "" {
+		if err := json.Unmarshal([]byte(nodesJSON), &nodes); err != nil {
+			return fmt.Errorf("invalid page nodes: %w", err)
+		}
+	}
+	if edgesJSON != "" {
+		if err := json.Unmarshal([]byte(edgesJSON), &edges); err != nil {
+			return fmt.Errorf("invalid page edges: %w", err)
+		}
+	}
+
+	from, fromX, ok := findPageNode(nodes, c.FromTableID)
+	if !ok {
+		return fmt.Errorf("table %s is not on the page", c.FromTable)
+	}
+	to, toX, ok := findPageNode(nodes, c.ToTableID)
+	if !ok {
+		return fmt.Errorf("table %s is not on the page", c.ToTable)
+	}
+	// 与拖动节点后的连接点规则一致：来源在左侧时从右侧连出
+	sourceHandle, targetHandle := "sr-"+c.FromColumn, "tl-"+c.ToColumn
+	if fromX >= toX {
+		sourceHandle, targetHandle = "sl-"+c.FromColumn, "tr-"+c.ToColumn
+	}
+	for _, e := range edges {
+		if e["source"] == from && e["target"] == to &&
+			handleField(e["sourceHandle"]) == c.FromColumn && handleField(e["targetHandle"]) == c.ToColumn {
+			return nil
+		}
+	}
+	edgeID := fmt.Sprintf("vueflow__edge-%s%s-%s%s", from, sourceHandle, to, targetHandle)
+	rel, cols, err := manager.relationshipFromVO(models.RelationshipVO{
+		PageID:            pageID,
+		SourceTableID:     c.FromTableID,
+		TargetTableID:     c.ToTableID,
+		Columns:           []models.RelationshipColumnVO{{SourceField: c.FromColumn, TargetField: c.ToColumn}},
+		SourceCardinality: models.CardinalityMany,
+		TargetCardinality: models.CardinalityOne,
+		Notes:             candidateNotes(c),
+		Origin:            meta.RelationshipOriginInferred,
+	})
+	if err != nil {
+		return err
+	}
+	rel.EdgeKey = edgeID
+	if err := manager.relations.Create(rel, cols); err != nil {
+		return fmt.Errorf("save relationship failed: %w", err)
+	}
+
+	edges = append(edges, map[string]interface{}{
+		"id":           edgeID,
+		"type":         "button",
+		"source":       from,
+		"target":       to,
+		"sourceHandle": sourceHandle,
+		"targetHandle": targetHandle,
+		"data": map[string]interface{}{
+			"relationship": map[string]string{"source": "many", "target": "one"},
+		},
+	})
+	data, err := json.Marshal(edges)
+	if err != nil {
+		return err
+	}
+	return cache.Set("flow_edge_"+pageID, string(data))
+}
+
+// candidateNotes 记录候选关系的置信度与依据
+func candidateNotes(c models.RelationshipCandidate) string {
+	lines := []string{fmt.Sprintf("confidence %.2f", c.Confidence)}
+	for _, e := range c.Evidence {
+		lines = append(lines, fmt.Sprintf("%s %s: %s", e.Kind, e.Name, e.Expression))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 连接点ID前缀（sl/sr/tl/tr-字段名）
+var handlePrefix = regexp.MustCompile(`^(sl|sr|tl|tr)-`)
+
+// handleField 从连接点ID中取出字段名
+func handleField(handle interface{}) string {
+	s, _ := handle.(string)
+	return handlePrefix.ReplaceAllString(s, "")
+}
+
+// findPageNode 按表ID查找页面节点，返回节点ID与横坐标
+func findPageNode(nodes []map[string]interface{}, tableID int64) (string, float64, bool) {
+	for _, n := range nodes {
+		if nodeTableID(n) != tableID {
+			continue
+		}
+		x := 0.0
+		if pos, ok := n["position"].(map[string]interface{}); ok {
+			x, _ = pos["x"].(float64)
+		}
+		nodeID, _ := n["id"].(string)
+		return nodeID, x, true
+	}
+	return "", 0, false
+}
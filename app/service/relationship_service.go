@@ -0,0 +1,354 @@
+package service
+
+import (
+	"dbrun/app/cache"
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// 标记旧版页面连线已导入的项目配置键
+const boltRelationshipsImportedKey = "bolt_relationships_imported"
+
+// ListRelationships 列出关系；pageID 为 "*" 时不限页面，tableID 非0时仅返回涉及该表的关系
+func ListRelationships(pageID string, tableID int64) ([]models.RelationshipVO, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return nil, err
+	}
+	filter := meta.RelationshipFilter{TableID: tableID}
+	if pageID != "*" {
+		filter.PageID = &pageID
+	}
+	rows, err := manager.relations.List(filter)
+	if err != nil {
+		return nil, fmt.Errorf("list relationships failed: %w", err)
+	}
+	return manager.relationshipVOs(rows)
+}
+
+// GetRelationship 获取单个关系
+func GetRelationship(id int64) (models.RelationshipVO, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.RelationshipVO{}, err
+	}
+	rel, err := manager.relations.GetByID(id)
+	if err != nil {
+		return models.RelationshipVO{}, fmt.Errorf("relationship not found: %w", err)
+	}
+	vos, err := manager.relationshipVOs([]meta.Relationship{*rel})
+	if err != nil {
+		return models.RelationshipVO{}, err
+	}
+	return vos[0], nil
+}
+
+// CreateRelationship 新增关系，字段须属于两端的表
+func CreateRelationship(vo models.RelationshipVO) (models.RelationshipVO, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.RelationshipVO{}, err
+	}
+	if vo.Origin == "" {
+		vo.Origin = meta.RelationshipOriginManual
+	}
+	rel, cols, err := manager.relationshipFromVO(vo)
+	if err != nil {
+		return models.RelationshipVO{}, err
+	}
+	rel.ID = 0
+	if err := manager.relations.Create(rel, cols); err != nil {
+		return models.RelationshipVO{}, fmt.Errorf("create relationship failed: %w", err)
+	}
+	return GetRelationship(rel.ID)
+}
+
+// UpdateRelationship 更新关系的两端、字段、基数与说明
+func UpdateRelationship(vo models.RelationshipVO) (models.RelationshipVO, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.RelationshipVO{}, err
+	}
+	existing, err := manager.relations.GetByID(vo.ID)
+	if err != nil {
+		return models.RelationshipVO{}, fmt.Errorf("relationship not found: %w", err)
+	}
+	if vo.Origin == "" {
+		vo.Origin = existing.Origin
+	}
+	rel, cols, err := manager.relationshipFromVO(vo)
+	if err != nil {
+		return models.RelationshipVO{}, err
+	}
+	rel.CreatedAt = existing.CreatedAt
+	rel.EdgeKey = existing.EdgeKey
+	if err := manager.relations.Update(rel, cols); err != nil {
+		return models.RelationshipVO{}, fmt.Errorf("update relationship failed: %w", err)
+	}
+	return GetRelationship(rel.ID)
+}
+
+// DeleteRelationship 删除关系
+func DeleteRelationship(id int64) error {
+	manager, err := getMgr()
+	if err != nil {
+		return err
+	}
+	return manager.relations.Delete(id)
+}
+
+// relationshipFromVO 校验表、字段与基数，按名称补全字段ID
+func (m *MetadataService) relationshipFromVO(vo models.RelationshipVO) (*meta.Relationship, []meta.RelationshipColumn, error) {
+	if len(vo.Columns) == 0 {
+		return nil, nil, fmt.Errorf("relationship requires at least one column pair")
+	}
+	for _, c := range []string{vo.SourceCardinality, vo.TargetCardinality} {
+		switch c {
+		case "", models.CardinalityOne, models.CardinalityMany, models.CardinalityNone:
+		default:
+			return nil, nil, fmt.Errorf("invalid cardinality: %s", c)
+		}
+	}
+	srcFields, err := m.tableFields(vo.SourceTableID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tgtFields, err := m.tableFields(vo.TargetTableID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cols := make([]meta.RelationshipColumn, 0, len(vo.Columns))
+	for _, c := range vo.Columns {
+		src, ok := matchField(srcFields, c.SourceFieldID, c.SourceField)
+		if !ok {
+			return nil, nil, fmt.Errorf("source field not found: %d %s", c.SourceFieldID, c.SourceField)
+		}
+		tgt, ok := matchField(tgtFields, c.TargetFieldID, c.TargetField)
+		if !ok {
+			return nil, nil, fmt.Errorf("target field not found: %d %s", c.TargetFieldID, c.TargetField)
+		}
+		cols = append(cols, meta.RelationshipColumn{SourceFieldID: src.ID, TargetFieldID: tgt.ID})
+	}
+	rel := &meta.Relationship{
+		ID:                vo.ID,
+		PageID:            vo.PageID,
+		SourceTableID:     vo.SourceTableID,
+		TargetTableID:     vo.TargetTableID,
+		SourceCardinality: defaultString(vo.SourceCardinality, models.CardinalityMany),
+		TargetCardinality: defaultString(vo.TargetCardinality, models.CardinalityOne),
+		Label:             vo.Label,
+		Notes:             vo.Notes,
+		Origin:            vo.Origin,
+	}
+	return rel, cols, nil
+}
+
+func (m *MetadataService) tableFields(tableID int64) ([]meta.RawFieldInfo, error) {
+	if _, _, _, _, _, _, err := m.rawStorage.GetTableContextByID(tableID); err != nil {
+		return nil, fmt.Errorf("table %d not found: %w", tableID, err)
+	}
+	return m.rawStorage.GetRawFieldsRows(tableID)
+}
+
+// matchField 按ID查找字段，ID为0时按名称查找
+func matchField(fields []meta.RawFieldInfo, id int64, name string) (meta.RawFieldInfo, bool) {
+	if id != 0 {
+		for _, f := range fields {
+			if f.ID == id {
+				return f, true
+			}
+		}
+		return meta.RawFieldInfo{}, false
+	}
+	return findRawField(fields, name)
+}
+
+func defaultString(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// relationshipVOs 组装关系VO，补充表名与字段名，引用已不存在时标记为 Broken
+func (m *MetadataService) relationshipVOs(rows []meta.Relationship) ([]models.RelationshipVO, error) {
+	ids := make([]int64, 0, len(rows))
+	for _, r := range rows {
+		ids = append(ids, r.ID)
+	}
+	colsByRel, err := m.relations.GetColumns(ids)
+	if err != nil {
+		return nil, err
+	}
+	names := map[int64]string{}
+	fields := map[int64]map[int64]string{}
+	lookup := func(tableID int64) (string, map[int64]string, bool) {
+		if _, ok := fields[tableID]; !ok {
+			fields[tableID] = map[int64]string{}
+			if _, _, schemaName, tableName, _, _, err := m.rawStorage.GetTableContextByID(tableID); err == nil {
+				names[tableID] = qualifyName(schemaName, tableName)
+				rows, _ := m.rawStorage.GetRawFieldsRows(tableID)
+				for _, f := range rows {
+					fields[tableID][f.ID] = f.Name
+				}
+			}
+		}
+		name, ok := names[tableID]
+		return name, fields[tableID], ok
+	}
+
+	out := make([]models.RelationshipVO, 0, len(rows))
+	for _, r := range rows {
+		vo := models.RelationshipVO{
+			ID:                r.ID,
+			PageID:            r.PageID,
+			SourceTableID:     r.SourceTableID,
+			TargetTableID:     r.TargetTableID,
+			SourceCardinality: r.SourceCardinality,
+			TargetCardinality: r.TargetCardinality,
+			Label:             r.Label,
+			Notes:             r.Notes,
+			Origin:            r.Origin,
+			UpdatedAt:         r.UpdatedAt,
+			Columns:           []models.RelationshipColumnVO{},
+		}
+		srcName, srcFields, okS := lookup(r.SourceTableID)
+		tgtName, tgtFields, okT := lookup(r.TargetTableID)
+		vo.SourceTable, vo.TargetTable = srcName, tgtName
+		vo.Broken = !okS || !okT
+		for _, c := range colsByRel[r.ID] {
+			cvo := models.RelationshipColumnVO{
+				SourceFieldID: c.SourceFieldID,
+				SourceField:   srcFields[c.SourceFieldID],
+				TargetFieldID: c.TargetFieldID,
+				TargetField:   tgtFields[c.TargetFieldID],
+			}
+			if cvo.SourceField == "" || cvo.TargetField == "" {
+				vo.Broken = true
+			}
+			vo.Columns = append(vo.Columns, cvo)
+		}
+		out = append(out, vo)
+	}
+	return out, nil
+}
+
+// ImportBoltRelationships 将页面缓存（flow_edge_{pageId}）中的连线导入为关系；pageID 为空时导入所有页面。
+// 连线按前端节点ID关联表，按连接点中的字段名关联字段；已导入的连线跳过
+func ImportBoltRelationships(pageID string) (models.RelationshipImportResult, error) {
+	result := models.RelationshipImportResult{Failed: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if cache.GetBoltCache() == nil {
+		return result, fmt.Errorf("app cache not initialized")
+	}
+	var pages []string
+	if pageID != "" {
+		pages = []string{pageID}
+	} else {
+		for _, key := range cache.Keys() {
+			if strings.HasPrefix(key, "flow_edge_") {
+				pages = append(pages, strings.TrimPrefix(key, "flow_edge_"))
+			}
+		}
+	}
+	for _, p := range pages {
+		if err := manager.importPageEdges(p, &result); err != nil {
+			result.Failed = append(result.Failed, fmt.Sprintf("page %s: %v", p, err))
+			continue
+		}
+		result.Pages++
+	}
+	return result, nil
+}
+
+// importPageEdges 导入单个页面的连线
+func (m *MetadataService) importPageEdges(pageID string, result *models.RelationshipImportResult) error {
+	var nodesJSON, edgesJSON string
+	cache.Get("flow_node_"+pageID, &nodesJSON)
+	cache.Get("flow_edge_"+pageID, &edgesJSON)
+	if edgesJSON == "" {
+		return nil
+	}
+	var nodes, edges []map[string]interface{}
+	if nodesJSON != "" {
+		if err := json.Unmarshal([]byte(nodesJSON), &nodes); err != nil {
+			return fmt.Errorf("invalid page nodes: %w", err)
+		}
+	}
+	if err := json.Unmarshal([]byte(edgesJSON), &edges); err != nil {
+		return fmt.Errorf("invalid page edges: %w", err)
+	}
+
+	// 节点ID -> 表ID
+	tables := map[string]int64{}
+	for _, n := range nodes {
+		id, _ := n["id"].(string)
+		if tableID := nodeTableID(n); id != "" && tableID != 0 {
+			tables[id] = tableID
+		}
+	}
+	for _, e := range edges {
+		edgeID, _ := e["id"].(string)
+		if existing, err := m.relations.FindByEdgeKey(pageID, edgeID); err != nil {
+			return err
+		} else if existing != nil {
+			result.Skipped++
+			continue
+		}
+		source, _ := e["source"].(string)
+		target, _ := e["target"].(string)
+		vo := models.RelationshipVO{
+			PageID:        pageID,
+			SourceTableID: tables[source],
+			TargetTableID: tables[target],
+			Columns: []models.RelationshipColumnVO{{
+				SourceField: handleField(e["sourceHandle"]),
+				TargetField: handleField(e["targetHandle"]),
+			}},
+			Origin: meta.RelationshipOriginImport,
+		}
+		if data, ok := e["data"].(map[string]interface{}); ok {
+			if rel, ok := data["relationship"].(map[string]interface{}); ok {
+				vo.SourceCardinality, _ = rel["source"].(string)
+				vo.TargetCardinality, _ = rel["target"].(string)
+			}
+			vo.Label, _ = data["text"].(string)
+		}
+		if vo.SourceTableID == 0 || vo.TargetTableID == 0 {
+			result.Failed = append(result.Failed, fmt.Sprintf("page %s edge %s: node table not found", pageID, edgeID))
+			continue
+		}
+		rel, cols, err := m.relationshipFromVO(vo)
+		if err != nil {
+			result.Failed = append(result.Failed, fmt.Sprintf("page %s edge %s: %v", pageID, edgeID, err))
+			continue
+		}
+		rel.EdgeKey = edgeID
+		if err := m.relations.Create(rel, cols); err != nil {
+			return err
+		}
+		result.Imported++
+	}
+	return nil
+}
+
+// nodeTableID 读取页面节点引用的表ID（优先 tableId，其次 table.id）
+func nodeTableID(n map[string]interface{}) int64 {
+	data, _ := n["data"].(map[string]interface{})
+	if data == nil {
+		return 0
+	}
+	id, _ := data["tableId"].(float64)
+	if id == 0 {
+		if t, ok := data["table"].(map[string]interface{}); ok {
+			id, _ = t["id"].(float64)
+		}
+	}
+	return int64(id)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"dbrun/app/dialect"
+	"dbrun/app/models"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// 单条统计语句的默认超时
+const defaultVerifyTimeout = 2 * time.Minute
+
+// VerifyRelationship 在真实连接上统计孤儿行、空值率与实际基数，并与连线声明的基数比较
+func VerifyRelationship(req models.RelationshipCheckRequest) (models.RelationshipCheckResult, error) {
+	result := models.RelationshipCheckResult{Request: req}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if len(req.SourceColumns) == 0 || len(req.SourceColumns) != len(req.TargetColumns) {
+		return result, fmt.Errorf("source and target columns must be non-empty and of equal length")
+	}
+	src, err := resolveTableTarget(manager, req.SourceTableID)
+	if err != nil {
+		return result, err
+	}
+	tgt, err := resolveTableTarget(manager, req.TargetTableID)
+	if err != nil {
+		return result, err
+	}
+	if src.configID != tgt.configID {
+		return result, fmt.Errorf("tables belong to different connections, cannot verify with one query")
+	}
+	conn, creds, err := getQueryConnection(manager, src.configID)
+	if err != nil {
+		return result, err
+	}
+	if creds.Type == "postgresql" && src.dbName != tgt.dbName {
+		return result, fmt.Errorf("postgresql does not support cross-database queries")
+	}
+	d, err := dialect.Get(creds.Type)
+	if err != nil {
+		return result, err
+	}
+	srcCols, err := quoteFields(d, src, req.SourceColumns)
+	if err != nil {
+		return result, err
+	}
+	tgtCols, err := quoteFields(d, tgt, req.TargetColumns)
+	if err != nil {
+		return result, err
+	}
+	q := buildVerifyQueries(d.QualifiedName(src.dbName, src.schemaName, src.tableName), srcCols,
+		d.QualifiedName(tgt.dbName, tgt.schemaName, tgt.tableName), tgtCols)
+
+	timeout := defaultVerifyTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = time.Duration(req.TimeoutSeconds) * time.Second
+	}
+	session, err := conn.OpenSession(context.Background())
+	if err != nil {
+		return result, err
+	}
+	defer session.Close()
+
+	start := time.Now()
+	steps := []struct {
+		sql  string
+		dest []*int64
+	}{
+		{q.totals, []*int64{&result.SourceRows, &result.NullRows}},
+		{q.orphans, []*int64{&result.OrphanRows}},
+		{q.maxChildren, []*int64{&result.MaxChildrenPerParent}},
+		{q.maxTarget, []*int64{&result.MaxTargetRowsPerKey}},
+	}
+	for _, step := range steps {
+		result.SQL = append(result.SQL, step.sql)
+		if err := scanCounts(session, timeout, step.sql, step.dest...); err != nil {
+			result.DurationMs = time.Since(start).Milliseconds()
+			return result, fmt.Errorf("verify query failed: %w", err)
+		}
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	evaluateRelationship(&result)
+	return result, nil
+}
+
+// verifyQueries 关系校验所需的统计语句
+type verifyQueries struct {
+	totals      string // 总行数与引用列为空的行数
+	orphans     string // 孤儿行数
+	maxChildren string // 同一引用值的最大行数
+	maxTarget   string // 目标表同一键值的最大行数
+}
+
+// buildVerifyQueries 生成统计语句；列名均来自元数据并已加引号，各方言通用
+func buildVerifyQueries(srcTable string, srcCols []string, tgtTable string, tgtCols []string) verifyQueries {
+	var anyNull, notNull, match, srcNotNull, tgtNotNull []string
+	for i, c := range srcCols {
+		anyNull = append(anyNull, c+" IS NULL")
+		notNull = append(notNull, "s."+c+" IS NOT NULL")
+		match = append(match, fmt.Sprintf("t.%s = s.%s", tgtCols[i], c))
+		srcNotNull = append(srcNotNull, c+" IS NOT NULL")
+		tgtNotNull = append(tgtNotNull, tgtCols[i]+" IS NOT NULL")
+	}
+	return verifyQueries{
+		totals: fmt.Sprintf("SELECT COUNT(*), SUM(CASE WHEN %s THEN 1 ELSE 0 END) FROM %s",
+			strings.Join(anyNull, " OR "), srcTable),
+		orphans: fmt.Sprintf("SELECT COUNT(*) FROM %s s WHERE %s AND NOT EXISTS (SELECT 1 FROM %s t WHERE %s)",
+			srcTable, strings.Join(notNull, " AND "), tgtTable, strings.Join(match, " AND ")),
+		maxChildren: fmt.Sprintf("SELECT MAX(cnt) FROM (SELECT COUNT(*) AS cnt FROM %s WHERE %s GROUP BY %s) g",
+			srcTable, strings.Join(srcNotNull, " AND "), strings.Join(srcCols, ", ")),
+		maxTarget: fmt.Sprintf("SELECT MAX(cnt) FROM (SELECT COUNT(*) AS cnt FROM %s WHERE %s GROUP BY %s) g",
+			tgtTable, strings.Join(tgtNotNull, " AND "), strings.Join(tgtCols, ", ")),
+	}
+}
+
+// quoteFields 校验列名存在于元数据中并加引号
+func quoteFields(d dialect.Dialect, target *tableTarget, names []string) ([]string, error) {
+	out := make([]string, 0, len(names))
+	for _, n := range names {
+		f, ok := findRawField(target.fields, n)
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s in table %s", n, target.tableName)
+		}
+		out = append(out, d.QuoteIdent(f.Name))
+	}
+	return out, nil
+}
+
+// scanCounts 执行返回单行计数的语句，NULL 视为 0
+func scanCounts(session *sql.Conn, timeout time.Duration, sqlText string, dest ...*int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	values := make([]sql.NullInt64, len(dest))
+	ptrs := make([]interface{}, len(dest))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+	if err := session.QueryRowContext(ctx, sqlText).Scan(ptrs...); err != nil {
+		return err
+	}
+	for i, v := range values {
+		*dest[i] = v.Int64
+	}
+	return nil
+}
+
+// evaluateRelationship 计算比例与实际基数，并与声明比较
+func evaluateRelationship(r *models.RelationshipCheckResult) {
+	if r.SourceRows > 0 {
+		r.NullRate = ratio(r.NullRows, r.SourceRows)
+	}
+	if nonNull := r.SourceRows - r.NullRows; nonNull > 0 {
+		r.OrphanRate = ratio(r.OrphanRows, nonNull)
+	}
+	r.ObservedSource = models.CardinalityOne
+	if r.MaxChildrenPerParent > 1 {
+		r.ObservedSource = models.CardinalityMany
+	}
+	r.ObservedTarget = models.CardinalityOne
+	if r.MaxTargetRowsPerKey > 1 {
+		r.ObservedTarget = models.CardinalityMany
+	}
+
+	r.Issues = []string{}
+	if r.OrphanRows > 0 {
+		r.Issues = append(r.Issues, fmt.Sprintf("%d source rows reference values missing from the target table", r.OrphanRows))
+	}
+	if r.ObservedTarget == models.CardinalityMany {
+		r.Issues = append(r.Issues, fmt.Sprintf("target key is not unique: up to %d rows share a value", r.MaxTargetRowsPerKey))
+	}
+	check := func(side, declared, observed string) {
+		if declared == "" || declared == models.CardinalityNone || declared == observed {
+			return
+		}
+		r.Mismatch = true
+		if declared == models.CardinalityOne {
+			r.Issues = append(r.Issues, fmt.Sprintf("%s declared as one but data shows many", side))
+		} else {
+			r.Issues = append(r.Issues, fmt.Sprintf("%s declared as many but data shows at most one", side))
+		}
+	}
+	check("source", r.Request.DeclaredSource, r.ObservedSource)
+	check("target", r.Request.DeclaredTarget, r.ObservedTarget)
+}
+
+func ratio(part, total int64) float64 {
+	return math.Round(float64(part)/float64(total)*10000) / 10000
+}
@@ -0,0 +1,566 @@
+package service
+
+import (
+	"dbrun/app/connect"
+	"dbrun/app/ddl"
+	"dbrun/app/models"
+	"dbrun/app/schemafile"
+	meta "dbrun/app/sqlite/metadata"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 由设计文件导入的虚拟连接类型，只有元数据，不能建立连接
+const virtualConnectionType = "virtual"
+
+// virtualOptions 虚拟连接保存在 Credentials.Options 中的来源信息
+type virtualOptions struct {
+	Format  string `json:"format"`
+	Path    string `json:"path"`
+	Dialect string `json:"dialect"`
+}
+
+// connectionDialect 连接对应的 SQL 方言，虚拟连接取设计文件声明的数据库类型
+func connectionDialect(creds *meta.Credentials) string {
+	if creds.Type != virtualConnectionType {
+		return creds.Type
+	}
+	var opts virtualOptions
+	if err := json.Unmarshal([]byte(creds.Options), &opts); err != nil {
+		return ""
+	}
+	return opts.Dialect
+}
+
+// ImportSchemaFile 将 DBML / Prisma 设计文件导入为只读的虚拟连接：表、字段与说明写入原始元数据，
+// 引用转为关系；指定 ConfigID 时重新导入到已有的虚拟连接
+func ImportSchemaFile(req models.SchemaFileImportRequest) (models.SchemaFileImportResult, error) {
+	result := models.SchemaFileImportResult{Skipped: []string{}, Warnings: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if req.Path == "" {
+		return result, fmt.Errorf("input path is required")
+	}
+	file, err := schemafile.ParseFile(req.Path)
+	if err != nil {
+		return result, fmt.Errorf("parse schema file failed: %w", err)
+	}
+	if len(file.Tables) == 0 {
+		return result, fmt.Errorf("no tables found in %s", filepath.Base(req.Path))
+	}
+	if req.Dialect != "" {
+		file.Dialect = req.Dialect
+	}
+	result.Format, result.Dialect = file.Format, file.Dialect
+	result.Warnings = append(result.Warnings, file.Warnings...)
+	if file.Dialect == "" {
+		result.Warnings = append(result.Warnings, "database type is not declared in the file, types are kept as written")
+	}
+
+	name := file.Name
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(req.Path), filepath.Ext(req.Path))
+	}
+	label := req.Label
+	if label == "" {
+		label = name
+	}
+	opts, err := json.Marshal(virtualOptions{Format: file.Format, Path: req.Path, Dialect: file.Dialect})
+	if err != nil {
+		return result, err
+	}
+	creds := &meta.Credentials{Type: virtualConnectionType, Label: label, Database: name, Options: string(opts)}
+	if req.ConfigID != 0 {
+		existing, err := manager.GetCredentialsByID(req.ConfigID)
+		if err != nil {
+			return result, fmt.Errorf("get credentials failed: %w", err)
+		}
+		if existing.Type != virtualConnectionType {
+			return result, fmt.Errorf("connection %d is not imported from a schema file", req.ConfigID)
+		}
+		creds.ID, creds.CreatedAt = existing.ID, existing.CreatedAt
+		if req.Label == "" {
+			creds.Label = existing.Label
+		}
+		if err := manager.UpdateCredentials(creds); err != nil {
+			return result, fmt.Errorf("update credentials failed: %w", err)
+		}
+	} else if err := manager.InsertCredentials(creds); err != nil {
+		return result, fmt.Errorf("create connection failed: %w", err)
+	}
+	result.ConfigID = creds.ID
+
+	db, defaultSchema := schemaFileDatabase(file, name)
+	if err := manager.UpdateRawDatabase(creds.ID, db); err != nil {
+		return result, fmt.Errorf("save metadata failed: %w", err)
+	}
+	removed, err := manager.pruneSchemaFileImport(creds.ID, db)
+	if err != nil {
+		return result, err
+	}
+	if err := manager.SyncRawToVO(creds.ID); err != nil {
+		return result, fmt.Errorf("sync metadata failed: %w", err)
+	}
+	for _, t := range file.Tables {
+		result.Tables++
+		result.Columns += len(t.Columns)
+	}
+
+	tableIDs, err := manager.schemaFileTableIDs(creds.ID)
+	if err != nil {
+		return result, err
+	}
+	created, staleRefs, skipped, err := manager.importSchemaFileRefs(file, tableIDs, defaultSchema)
+	if err != nil {
+		return result, err
+	}
+	result.Relationships = created
+	result.Skipped = append(result.Skipped, skipped...)
+	fmt.Printf("[ImportSchemaFile] imported: configID=%d format=%s dialect=%q tables=%d relationships=%d removedTables=%d removedRelationships=%d\n",
+		creds.ID, file.Format, file.Dialect, result.Tables, created, removed, staleRefs)
+	return result, nil
+}
+
+// schemaFileDatabase 将设计文件转为原始元数据；表带 Schema 或方言以 Schema 组织对象时，
+// 未限定的表放入默认 Schema，返回该默认 Schema（不使用 Schema 时为空）
+func schemaFileDatabase(file schemafile.File, name string) (connect.DatabaseInfo, string) {
+	db := connect.DatabaseInfo{Name: name, Comment: file.Note}
+	// 引用方的字段即使没有索引也标记为 MUL，与外键在元数据中的表示一致
+	refColumns := map[string]bool{}
+	for _, r := range file.Refs {
+		if t, ok := file.FindTable(r.From); ok {
+			for _, c := range r.FromColumns {
+				refColumns[strings.ToLower(t.Schema+"."+t.Name+"."+c)] = true
+			}
+		}
+	}
+	// 键信息来自字段上的设置：主键字段合为一个（可能是复合）主键，唯一与索引为单字段键；
+	// 引用由关系表示，不作为外键写入
+	tableInfo := func(t schemafile.Table) connect.TableInfo {
+		info := connect.TableInfo{Name: t.Name, Comment: t.Note, Keys: []connect.KeyInfo{}}
+		var pk []string
+		for _, c := range t.Columns {
+			f := connect.FieldInfo{Name: c.Name, Type: c.Type, Nullable: c.Nullable && !c.PrimaryKey, Comment: c.Note,
+				DefaultValue: c.Default, AutoIncrement: c.Increment}
+			switch {
+			case c.PrimaryKey:
+				f.Key = "PRI"
+				pk = append(pk, c.Name)
+			case c.Unique:
+				f.Key = "UNI"
+				info.Keys = append(info.Keys, connect.KeyInfo{Kind: connect.KeyUnique, Columns: []string{c.Name}})
+			case c.Indexed || refColumns[strings.ToLower(t.Schema+"."+t.Name+"."+c.Name)]:
+				f.Key = "MUL"
+				if c.Indexed {
+					info.Keys = append(info.Keys, connect.KeyInfo{Kind: connect.KeyIndex, Columns: []string{c.Name}})
+				}
+			}
+			info.Fields = append(info.Fields, f)
+		}
+		if len(pk) > 0 {
+			info.Keys = append([]connect.KeyInfo{{Kind: connect.KeyPrimary, Columns: pk}}, info.Keys...)
+		}
+		return info
+	}
+
+	defaultSchema := ""
+	switch file.Dialect {
+	case "postgresql":
+		defaultSchema = "public"
+	case "sqlserver":
+		defaultSchema = "dbo"
+	}
+	for _, t := range file.Tables {
+		if t.Schema != "" && defaultSchema == "" {
+			defaultSchema = "public"
+		}
+	}
+	if defaultSchema == "" {
+		for _, t := range file.Tables {
+			db.Tables = append(db.Tables, tableInfo(t))
+		}
+		return db, ""
+	}
+	index := map[string]int{}
+	for _, t := range file.Tables {
+		schema := t.Schema
+		if schema == "" {
+			schema = defaultSchema
+		}
+		i, ok := index[strings.ToLower(schema)]
+		if !ok {
+			i = len(db.Schemas)
+			index[strings.ToLower(schema)] = i
+			db.Schemas = append(db.Schemas, connect.Schema{Name: schema})
+		}
+		db.Schemas[i].Tables = append(db.Schemas[i].Tables, tableInfo(t))
+	}
+	return db, defaultSchema
+}
+
+// schemaFileTableIDs 按 "schema.table"（小写，无 Schema 时 Schema 部分为空）索引连接下的原始表ID
+func (m *MetadataService) schemaFileTableIDs(configID int64) (map[string]int64, error) {
+	ids := map[string]int64{}
+	dbs, err := m.rawStorage.GetRawDatabasesRows(configID)
+	if err != nil {
+		return nil, fmt.Errorf("load databases failed: %w", err)
+	}
+	for _, db := range dbs {
+		tables, err := m.rawStorage.GetRawTablesRows(db.ID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("load tables failed: %w", err)
+		}
+		for _, t := range tables {
+			ids["."+strings.ToLower(t.Name)] = t.ID
+		}
+		schemas, err := m.rawStorage.GetRawSchemasRows(db.ID)
+		if err != nil {
+			return nil, fmt.Errorf("load schemas failed: %w", err)
+		}
+		for _, s := range schemas {
+			tables, err := m.rawStorage.GetRawTablesRows(db.ID, &s.ID)
+			if err != nil {
+				return nil, fmt.Errorf("load tables failed: %w", err)
+			}
+			for _, t := range tables {
+				ids[strings.ToLower(s.Name+"."+t.Name)] = t.ID
+			}
+		}
+	}
+	return ids, nil
+}
+
+// importSchemaFileRefs 将设计文件的引用创建为关系，已存在相同表与字段的关系时跳过；
+// 连接内由设计文件导入、但文件中已不存在的关系随之删除。返回新建数、删除数与跳过的引用
+func (m *MetadataService) importSchemaFileRefs(file schemafile.File, tableIDs map[string]int64, defaultSchema string) (int, int, []string, error) {
+	var skipped []string
+	lookup := func(name schemafile.TableName) (int64, bool) {
+		t, ok := file.FindTable(name)
+		if !ok {
+			return 0, false
+		}
+		schema := t.Schema
+		if schema == "" {
+			schema = defaultSchema
+		}
+		id, ok := tableIDs[strings.ToLower(schema+"."+t.Name)]
+		return id, ok
+	}
+	rows, err := m.relations.List(meta.RelationshipFilter{})
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("list relationships failed: %w", err)
+	}
+	existing, err := m.relationshipVOs(rows)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	seen := map[string]bool{}
+	for _, r := range existing {
+		seen[relationshipSignature(r)] = true
+	}
+	inFile := map[string]bool{}
+
+	created := 0
+	for _, r := range file.Refs {
+		desc := fmt.Sprintf("%s(%s) -> %s(%s)", r.From, strings.Join(r.FromColumns, ", "), r.To, strings.Join(r.ToColumns, ", "))
+		source, ok := lookup(r.From)
+		if !ok {
+			skipped = append(skipped, desc+": table "+r.From.String()+" not found")
+			continue
+		}
+		target, ok := lookup(r.To)
+		if !ok {
+			skipped = append(skipped, desc+": table "+r.To.String()+" not found")
+			continue
+		}
+		vo := models.RelationshipVO{
+			SourceTableID:     source,
+			TargetTableID:     target,
+			SourceCardinality: r.FromCardinality,
+			TargetCardinality: r.ToCardinality,
+			Label:             r.Name,
+			Origin:            meta.RelationshipOriginSchemaFile,
+		}
+		for i := range r.FromColumns {
+			vo.Columns = append(vo.Columns, models.RelationshipColumnVO{SourceField: r.FromColumns[i], TargetField: r.ToColumns[i]})
+		}
+		rel, cols, err := m.relationshipFromVO(vo)
+		if err != nil {
+			skipped = append(skipped, desc+": "+err.Error())
+			continue
+		}
+		for i, c := range cols {
+			vo.Columns[i].SourceFieldID, vo.Columns[i].TargetFieldID = c.SourceFieldID, c.TargetFieldID
+		}
+		sig := relationshipSignature(vo)
+		inFile[sig] = true
+		if seen[sig] {
+			continue
+		}
+		seen[sig] = true
+		if err := m.relations.Create(rel, cols); err != nil {
+			return created, 0, skipped, fmt.Errorf("create relationship failed: %w", err)
+		}
+		created++
+	}
+
+	inConnection := map[int64]bool{}
+	for _, id := range tableIDs {
+		inConnection[id] = true
+	}
+	removed := 0
+	for _, r := range existing {
+		if r.Origin != meta.RelationshipOriginSchemaFile || !inConnection[r.SourceTableID] || inFile[relationshipSignature(r)] {
+			continue
+		}
+		if err := m.relations.Delete(r.ID); err != nil {
+			return created, removed, skipped, fmt.Errorf("delete relationship failed: %w", err)
+		}
+		removed++
+	}
+	return created, removed, skipped, nil
+}
+
+// pruneSchemaFileImport 重新导入时删除设计文件中已不存在的数据库、Schema 与表（含涉及这些表的关系），
+// 字段在保存表时已按名称清理。返回删除的表数
+func (m *MetadataService) pruneSchemaFileImport(configID int64, db connect.DatabaseInfo) (int, error) {
+	dbs, err := m.rawStorage.GetRawDatabasesRows(configID)
+	if err != nil {
+		return 0, fmt.Errorf("load databases failed: %w", err)
+	}
+	removed := 0
+	for _, rd := range dbs {
+		var keep map[string]map[string]bool
+		if rd.Name == db.Name {
+			// 库下直属的表记在空 Schema 名下
+			keep = map[string]map[string]bool{"": {}}
+			for _, t := range db.Tables {
+				keep[""][t.Name] = true
+			}
+			for _, s := range db.Schemas {
+				keep[s.Name] = map[string]bool{}
+				for _, t := range s.Tables {
+					keep[s.Name][t.Name] = true
+				}
+			}
+		}
+		var stale []int64
+		collect := func(schemaID *int64, names map[string]bool) error {
+			tables, err := m.rawStorage.GetRawTablesRows(rd.ID, schemaID)
+			if err != nil {
+				return fmt.Errorf("load tables failed: %w", err)
+			}
+			for _, t := range tables {
+				if !names[t.Name] {
+					stale = append(stale, t.ID)
+				}
+			}
+			return nil
+		}
+		if err := collect(nil, keep[""]); err != nil {
+			return removed, err
+		}
+		schemas, err := m.rawStorage.GetRawSchemasRows(rd.ID)
+		if err != nil {
+			return removed, fmt.Errorf("load schemas failed: %w", err)
+		}
+		var staleSchemas []int64
+		for _, s := range schemas {
+			names, ok := keep[s.Name]
+			if !ok {
+				staleSchemas = append(staleSchemas, s.ID)
+			}
+			if err := collect(&s.ID, names); err != nil {
+				return removed, err
+			}
+		}
+		removed += len(stale)
+
+		if keep == nil {
+			if err := m.voStorage.DeleteVODatabaseByID(rd.ID); err != nil {
+				return removed, fmt.Errorf("delete database failed: %w", err)
+			}
+			if err := m.rawStorage.DeleteDatabaseByID(rd.ID); err != nil {
+				return removed, fmt.Errorf("delete database failed: %w", err)
+			}
+			continue
+		}
+		if err := m.voStorage.DeleteVOTablesByIDs(stale); err != nil {
+			return removed, fmt.Errorf("delete tables failed: %w", err)
+		}
+		if err := m.rawStorage.DeleteTablesByIDs(stale); err != nil {
+			return removed, fmt.Errorf("delete tables failed: %w", err)
+		}
+		if err := m.voStorage.DeleteVOSchemasByIDs(staleSchemas); err != nil {
+			return removed, fmt.Errorf("delete schemas failed: %w", err)
+		}
+		if err := m.rawStorage.DeleteSchemasByIDs(staleSchemas); err != nil {
+			return removed, fmt.Errorf("delete schemas failed: %w", err)
+		}
+	}
+	return removed, nil
+}
+
+// diffTable 参与比较的表，Schema 为空表示库下直属的表
+type diffTable struct {
+	schema string
+	info   connect.TableInfo
+}
+
+// DiffConnectionSchemas 比较两个连接的原始元数据：表与字段按名称匹配（忽略大小写），
+// 列出缺失或多出的表与字段，以及类型、可空与主键的差异；类型按两侧方言换算后比较
+func DiffConnectionSchemas(req models.SchemaDiffRequest) (models.SchemaDiffResult, error) {
+	result := models.SchemaDiffResult{Items: []models.SchemaDiffItem{}, Warnings: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	load := func(configID int64) (string, []diffTable, error) {
+		creds, err := manager.GetCredentialsByID(configID)
+		if err != nil {
+			return "", nil, fmt.Errorf("get credentials failed: %w", err)
+		}
+		dbs, err := manager.rawStorage.GetDatabasesByConfigID(configID)
+		if err != nil {
+			return "", nil, fmt.Errorf("load metadata failed: %w", err)
+		}
+		var tables []diffTable
+		for _, db := range dbs {
+			for _, t := range db.Tables {
+				tables = append(tables, diffTable{info: t})
+			}
+			for _, s := range db.Schemas {
+				for _, t := range s.Tables {
+					tables = append(tables, diffTable{schema: s.Name, info: t})
+				}
+			}
+		}
+		return connectionDialect(creds), tables, nil
+	}
+	var source, target []diffTable
+	if result.SourceDialect, source, err = load(req.SourceConfigID); err != nil {
+		return result, err
+	}
+	if result.TargetDialect, target, err = load(req.TargetConfigID); err != nil {
+		return result, err
+	}
+	// 未声明数据库类型的一侧按另一侧的方言理解类型
+	if result.SourceDialect == "" {
+		result.SourceDialect = result.TargetDialect
+	}
+	if result.TargetDialect == "" {
+		result.TargetDialect = result.SourceDialect
+	}
+
+	sourceKeys, targetKeys := diffTableKeys(source), diffTableKeys(target)
+	targetByKey := map[string]diffTable{}
+	for i, t := range target {
+		targetByKey[targetKeys[i]] = t
+	}
+	matched := map[string]bool{}
+	for i, s := range source {
+		key := sourceKeys[i]
+		t, ok := targetByKey[key]
+		if !ok {
+			result.Items = append(result.Items, models.SchemaDiffItem{Kind: models.SchemaDiffMissingTable, Table: key, Source: s.info.Name})
+			continue
+		}
+		matched[key] = true
+		result.Matched++
+		comparePK := hasKeyInfo(s.info) && hasKeyInfo(t.info)
+		if !comparePK {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("table %s has no key information on one side, primary keys were not compared", key))
+		}
+		result.Items = append(result.Items, diffColumns(key, s.info, t.info, result.SourceDialect, result.TargetDialect, comparePK)...)
+	}
+	for i, t := range target {
+		if !matched[targetKeys[i]] {
+			result.Items = append(result.Items, models.SchemaDiffItem{Kind: models.SchemaDiffExtraTable, Table: targetKeys[i], Target: t.info.Name})
+		}
+	}
+	return result, nil
+}
+
+// diffTableKeys 表的匹配键：表名在两侧通常唯一，只有同名表出现在多个 Schema 时才带 Schema
+func diffTableKeys(tables []diffTable) []string {
+	count := map[string]int{}
+	for _, t := range tables {
+		count[strings.ToLower(t.info.Name)]++
+	}
+	keys := make([]string, len(tables))
+	for i, t := range tables {
+		keys[i] = strings.ToLower(t.info.Name)
+		if count[keys[i]] > 1 && t.schema != "" {
+			keys[i] = strings.ToLower(t.schema) + "." + keys[i]
+		}
+	}
+	return keys
+}
+
+// diffColumns 比较同名表的字段，comparePK 为 false 时不比较主键
+func diffColumns(table string, source, target connect.TableInfo, sourceDialect, targetDialect string, comparePK bool) []models.SchemaDiffItem {
+	var items []models.SchemaDiffItem
+	targetFields := map[string]connect.FieldInfo{}
+	for _, f := range target.Fields {
+		targetFields[strings.ToLower(f.Name)] = f
+	}
+	seen := map[string]bool{}
+	for _, s := range source.Fields {
+		name := strings.ToLower(s.Name)
+		seen[name] = true
+		t, ok := targetFields[name]
+		if !ok {
+			items = append(items, models.SchemaDiffItem{Kind: models.SchemaDiffMissingColumn, Table: table, Column: s.Name, Source: s.Type})
+			continue
+		}
+		if !ddl.SameType(sourceDialect, s.Type, targetDialect, t.Type) {
+			items = append(items, models.SchemaDiffItem{Kind: models.SchemaDiffType, Table: table, Column: s.Name, Source: s.Type, Target: t.Type})
+		}
+		if s.Nullable != t.Nullable {
+			items = append(items, models.SchemaDiffItem{Kind: models.SchemaDiffNullable, Table: table, Column: s.Name,
+				Source: nullability(s.Nullable), Target: nullability(t.Nullable)})
+		}
+		if sp, tp := strings.EqualFold(s.Key, "PRI"), strings.EqualFold(t.Key, "PRI"); comparePK && sp != tp {
+			items = append(items, models.SchemaDiffItem{Kind: models.SchemaDiffPrimaryKey, Table: table, Column: s.Name,
+				Source: fmt.Sprint(sp), Target: fmt.Sprint(tp)})
+		}
+	}
+	var extra []string
+	for name := range targetFields {
+		if !seen[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		t := targetFields[name]
+		items = append(items, models.SchemaDiffItem{Kind: models.SchemaDiffExtraColumn, Table: table, Column: t.Name, Target: t.Type})
+	}
+	return items
+}
+
+// hasKeyInfo 表的元数据是否包含键信息：同步时获取过键，或字段上带有主键标记；
+// 两侧都有键信息时才能判断主键差异
+func hasKeyInfo(t connect.TableInfo) bool {
+	if t.Keys != nil {
+		return true
+	}
+	for _, f := range t.Fields {
+		if strings.EqualFold(f.Key, "PRI") {
+			return true
+		}
+	}
+	return false
+}
+
+func nullability(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
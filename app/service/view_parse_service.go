@@ -0,0 +1,595 @@
+package service
+
+import (
+	"dbrun/app/models"
+	meta "dbrun/app/sqlite/metadata"
+	"dbrun/app/sqlparse"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 视图嵌套解析的最大深度，防止循环引用
+const maxViewDepth = 8
+
+// 无法推断时使用的类型
+const unknownType = "UNKNOWN"
+
+// columnOrigin 输出列所引用的来源字段（表或视图）
+type columnOrigin struct {
+	Kind     string // meta.LineageSourceTable / meta.LineageSourceView
+	ObjectID int64
+	Column   string
+}
+
+// viewColumn 解析得到的输出列
+type viewColumn struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	Comment    string
+	Origins    []columnOrigin // 来源字段
+	Transform  string         // 来源到该列的转换方式：direct 或表达式类别
+	Expression string         // 非直接引用时的表达式文本
+}
+
+// viewSource 已解析列信息的数据来源
+type viewSource struct {
+	table   *sqlparse.TableSource
+	columns []viewColumn
+}
+
+// viewResolver 基于原始元数据解析视图的输出列
+type viewResolver struct {
+	manager    *MetadataService
+	raw        *meta.RawMetadataStorage
+	dialect    string
+	configID   int64            // 视图所在的连接，只在该连接内查找引用的对象
+	databaseID int64            // 已确定的数据库范围，用于在同名表中优先选择
+	dialects   map[int64]string // 连接配置ID -> 方言
+	warnings   []string
+}
+
+// newViewResolver 创建解析器；databaseID 不为 0 时引用的对象限定在其所属连接内查找
+func newViewResolver(manager *MetadataService, dialect string, databaseID int64) *viewResolver {
+	r := &viewResolver{
+		manager:    manager,
+		raw:        manager.GetRawStorage(),
+		dialect:    dialect,
+		databaseID: databaseID,
+		dialects:   map[int64]string{},
+	}
+	if databaseID != 0 {
+		if configID, _, err := r.raw.GetDatabaseContextByID(databaseID); err == nil {
+			r.configID = configID
+		}
+	}
+	return r
+}
+
+func (r *viewResolver) warn(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	for _, w := range r.warnings {
+		if w == msg {
+			return
+		}
+	}
+	r.warnings = append(r.warnings, msg)
+}
+
+// dialectFor 返回连接配置对应的方言，未知时沿用当前方言
+func (r *viewResolver) dialectFor(configID int64) string {
+	if d, ok := r.dialects[configID]; ok {
+		return d
+	}
+	d := r.dialect
+	if creds, err := r.manager.GetCredentialsByID(configID); err == nil {
+		if name := connectionDialect(creds); name != "" {
+			d = name
+		}
+	}
+	r.dialects[configID] = d
+	return d
+}
+
+// parseView 解析视图定义并解析输出列；dialect 为空时按 MySQL 处理
+func parseView(manager *MetadataService, dialect string, databaseID int64, definition string) (models.ViewParseResult, error) {
+	if dialect == "" {
+		dialect = sqlparse.DialectMySQL
+	}
+	result := models.ViewParseResult{Dialect: dialect}
+	info, err := sqlparse.ParseSelect(dialect, definition)
+	if err != nil {
+		return result, err
+	}
+	resolver := newViewResolver(manager, dialect, databaseID)
+	result.Fields = toFieldVOs(resolver.resolveSelect(info, 0))
+	result.Warnings = resolver.warnings
+	return result, nil
+}
+
+// ParseViewByID 按视图所在连接的方言解析已保存的视图定义
+func ParseViewByID(viewID int64) (models.ViewParseResult, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return models.ViewParseResult{}, err
+	}
+	view, err := manager.GetRawStorage().GetRawViewByID(viewID)
+	if err != nil {
+		return models.ViewParseResult{}, fmt.Errorf("view not found: %w", err)
+	}
+	configID, _, err := manager.GetRawStorage().GetDatabaseContextByID(view.DatabaseID)
+	if err != nil {
+		return models.ViewParseResult{}, err
+	}
+	dialect := ""
+	if creds, err := manager.GetCredentialsByID(configID); err == nil {
+		dialect = connectionDialect(creds)
+	}
+	result, err := parseView(manager, dialect, view.DatabaseID, view.Definition)
+	result.ViewID = viewID
+	return result, err
+}
+
+// resolveSelect 解析查询的输出列：展开 * 与 t.*，并推断各列的类型与可空性
+func (r *viewResolver) resolveSelect(info *sqlparse.SelectInfo, depth int) []viewColumn {
+	for _, w := range info.Warnings {
+		r.warn("%s", w)
+	}
+	sources := r.resolveSources(info, depth)
+
+	var out []viewColumn
+	for _, col := range info.Columns {
+		if !col.Star {
+			c := r.inferExpr(col.Expr, sources, depth)
+			c.Name = col.Name
+			if col.Expr == nil || col.Expr.Kind != sqlparse.ExprColumn {
+				c.Origins = exprOrigins(col.Expr, sources)
+				c.Transform = string(exprKind(col.Expr))
+				c.Expression = exprText(col.Expr)
+			}
+			out = append(out, c)
+			continue
+		}
+		expanded := false
+		for _, s := range sources {
+			if col.StarQualifier != "" && !s.table.Matches(col.StarQualifier) {
+				continue
+			}
+			for _, c := range s.columns {
+				c.Nullable = c.Nullable || s.table.Nullable
+				out = append(out, c)
+				expanded = true
+			}
+		}
+		if !expanded {
+			r.warn("cannot expand %s", col.Name)
+			out = append(out, viewColumn{Name: col.Name, Type: unknownType, Nullable: true, Comment: "星号表达式需要访问数据库才能获取具体字段"})
+		}
+	}
+
+	// 集合运算：列类型取第一个可推断的分支，任一分支可空则结果可空
+	for _, u := range info.Unions {
+		branch := r.resolveSelect(u, depth+1)
+		for i := range out {
+			if i >= len(branch) {
+				break
+			}
+			out[i].Nullable = out[i].Nullable || branch[i].Nullable
+			if out[i].Type == unknownType {
+				out[i].Type = branch[i].Type
+			}
+			out[i].Origins = mergeOrigins(out[i].Origins, branch[i].Origins)
+		}
+	}
+	return applyAliases(out, info.Aliases)
+}
+
+// resolveSources 解析查询中各数据来源的列
+func (r *viewResolver) resolveSources(info *sqlparse.SelectInfo, depth int) []viewSource {
+	sources := make([]viewSource, 0, len(info.Tables))
+	for i := range info.Tables {
+		t := &info.Tables[i]
+		cols, _ := r.resolveSource(t, depth)
+		sources = append(sources, viewSource{table: t, columns: cols})
+	}
+	return sources
+}
+
+// applyAliases 按位置重命名列
+func applyAliases(cols []viewColumn, aliases []string) []viewColumn {
+	for i := range cols {
+		if i < len(aliases) && aliases[i] != "" {
+			cols[i].Name = aliases[i]
+		}
+	}
+	return cols
+}
+
+// resolveSource 获取数据来源的列：派生表递归解析，表从原始元数据读取，视图解析其定义
+func (r *viewResolver) resolveSource(t *sqlparse.TableSource, depth int) ([]viewColumn, bool) {
+	if t.Subquery != nil {
+		if depth >= maxViewDepth {
+			r.warn("query nesting too deep at %s", t.RefName())
+			return nil, false
+		}
+		return applyAliases(r.resolveSelect(t.Subquery, depth+1), t.Columns), true
+	}
+	if t.Name == "" {
+		return nil, false
+	}
+	qualifier := t.Schema
+	if qualifier == "" {
+		qualifier = t.Database
+	}
+	refs, err := r.raw.FindRawObjectsByName(qualifier, t.Name)
+	if err == nil && len(refs) == 0 && qualifier != "" {
+		// 默认 Schema（如 dbo、public）可能未单独保存
+		refs, err = r.raw.FindRawObjectsByName("", t.Name)
+	}
+	if err != nil {
+		r.warn("lookup %s failed: %v", t.Name, err)
+		return nil, false
+	}
+	if len(refs) == 0 {
+		r.warn("table not found: %s", t.Name)
+		return nil, false
+	}
+	ref, ok := r.pickRef(refs)
+	if !ok {
+		r.warn("%s is ambiguous or not found in this connection, left unresolved", t.Name)
+		return nil, false
+	}
+	if r.databaseID == 0 {
+		r.databaseID = ref.DatabaseID
+	}
+
+	if !ref.IsView {
+		rows, err := r.raw.GetRawFieldsRows(ref.ID)
+		if err != nil {
+			r.warn("load fields of %s failed: %v", t.Name, err)
+			return nil, false
+		}
+		cols := make([]viewColumn, 0, len(rows))
+		for _, f := range rows {
+			cols = append(cols, viewColumn{
+				Name:      f.Name,
+				Type:      f.Type,
+				Nullable:  f.Nullable,
+				Comment:   f.Comment,
+				Origins:   []columnOrigin{{Kind: meta.LineageSourceTable, ObjectID: ref.ID, Column: f.Name}},
+				Transform: "direct",
+			})
+		}
+		return cols, true
+	}
+
+	if depth >= maxViewDepth {
+		r.warn("view nesting too deep at %s", t.Name)
+		return nil, false
+	}
+	sub, err := sqlparse.ParseSelect(r.dialectFor(ref.ConfigID), ref.Definition)
+	if err != nil {
+		r.warn("parse view %s failed: %v", t.Name, err)
+		return nil, false
+	}
+	cols := r.resolveSelect(sub, depth+1)
+	// 血缘只追溯到视图本身的字段，视图内部的来源由视图自己的血缘记录
+	for i := range cols {
+		cols[i].Origins = []columnOrigin{{Kind: meta.LineageSourceView, ObjectID: ref.ID, Column: cols[i].Name}}
+		cols[i].Transform = "direct"
+		cols[i].Expression = ""
+	}
+	return applyAliases(cols, t.Columns), true
+}
+
+// pickRef 在视图所在连接的同名对象中优先选择已确定数据库内的对象，其次选择唯一的对象；
+// 存在多个候选或连接内没有该对象时返回 false，不跨连接猜测
+func (r *viewResolver) pickRef(refs []meta.RawObjectRef) (meta.RawObjectRef, bool) {
+	var candidates []meta.RawObjectRef
+	for _, ref := range refs {
+		if r.configID != 0 && ref.ConfigID != r.configID {
+			continue
+		}
+		if r.databaseID != 0 && ref.DatabaseID == r.databaseID {
+			return ref, true
+		}
+		candidates = append(candidates, ref)
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	return meta.RawObjectRef{}, false
+}
+
+// findColumn 在数据来源中查找列，返回列与所属来源
+func findColumn(sources []viewSource, qualifier, name string) (viewColumn, *viewSource, bool) {
+	for i := range sources {
+		s := &sources[i]
+		if qualifier != "" && !s.table.Matches(qualifier) {
+			continue
+		}
+		for _, c := range s.columns {
+			if strings.EqualFold(c.Name, name) {
+				return c, s, true
+			}
+		}
+	}
+	return viewColumn{}, nil, false
+}
+
+// inferExpr 推断表达式的类型、可空性与注释
+func (r *viewResolver) inferExpr(e *sqlparse.Expr, sources []viewSource, depth int) viewColumn {
+	if e == nil {
+		return viewColumn{Type: unknownType, Nullable: true}
+	}
+	switch e.Kind {
+	case sqlparse.ExprColumn:
+		c, s, ok := findColumn(sources, e.Qualifier, e.Name)
+		if !ok {
+			return viewColumn{Type: unknownType, Nullable: true}
+		}
+		c.Nullable = c.Nullable || s.table.Nullable
+		return c
+	case sqlparse.ExprLiteral:
+		if e.Type == "NULL" {
+			return viewColumn{Type: unknownType, Nullable: true}
+		}
+		return viewColumn{Type: e.Type}
+	case sqlparse.ExprCast:
+		return viewColumn{Type: e.Type, Nullable: r.anyNullable(e.Args, sources, depth)}
+	case sqlparse.ExprCase:
+		out := viewColumn{Type: unknownType, Nullable: !e.HasElse}
+		for _, a := range e.Args {
+			c := r.inferExpr(a, sources, depth)
+			if out.Type == unknownType && !isNullLiteral(a) {
+				out.Type = c.Type
+			}
+			out.Nullable = out.Nullable || c.Nullable
+		}
+		return out
+	case sqlparse.ExprArith:
+		return r.inferArith(e, sources, depth)
+	case sqlparse.ExprConcat:
+		return viewColumn{Type: "VARCHAR", Nullable: r.anyNullable(e.Args, sources, depth)}
+	case sqlparse.ExprCompare:
+		return viewColumn{Type: "BOOLEAN", Nullable: r.anyNullable(e.Args, sources, depth)}
+	case sqlparse.ExprSubquery:
+		if e.Subquery != nil && depth < maxViewDepth {
+			if cols := r.resolveSelect(e.Subquery, depth+1); len(cols) > 0 {
+				return viewColumn{Type: cols[0].Type, Nullable: true}
+			}
+		}
+		return viewColumn{Type: unknownType, Nullable: true}
+	case sqlparse.ExprFunc:
+		return r.inferFunc(e, sources, depth)
+	}
+	return viewColumn{Type: unknownType, Nullable: true}
+}
+
+// exprOrigins 收集表达式所引用列的来源字段
+func exprOrigins(e *sqlparse.Expr, sources []viewSource) []columnOrigin {
+	var origins []columnOrigin
+	for _, ref := range e.ColumnRefs() {
+		if c, _, ok := findColumn(sources, ref.Qualifier, ref.Name); ok {
+			origins = mergeOrigins(origins, c.Origins)
+		}
+	}
+	return origins
+}
+
+// mergeOrigins 合并来源字段并去重
+func mergeOrigins(dst, src []columnOrigin) []columnOrigin {
+	for _, o := range src {
+		dup := false
+		for _, d := range dst {
+			if d.Kind == o.Kind && d.ObjectID == o.ObjectID && strings.EqualFold(d.Column, o.Column) {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			dst = append(dst, o)
+		}
+	}
+	return dst
+}
+
+func exprKind(e *sqlparse.Expr) sqlparse.ExprKind {
+	if e == nil {
+		return sqlparse.ExprOther
+	}
+	return e.Kind
+}
+
+func exprText(e *sqlparse.Expr) string {
+	if e == nil {
+		return ""
+	}
+	return e.Text
+}
+
+func isNullLiteral(e *sqlparse.Expr) bool {
+	return e != nil && e.Kind == sqlparse.ExprLiteral && e.Type == "NULL"
+}
+
+func (r *viewResolver) anyNullable(args []*sqlparse.Expr, sources []viewSource, depth int) bool {
+	for _, a := range args {
+		if r.inferExpr(a, sources, depth).Nullable {
+			return true
+		}
+	}
+	return false
+}
+
+// inferArith 根据操作数类型推断算术运算结果
+func (r *viewResolver) inferArith(e *sqlparse.Expr, sources []viewSource, depth int) viewColumn {
+	out := viewColumn{Type: "BIGINT"}
+	for _, a := range e.Args {
+		c := r.inferExpr(a, sources, depth)
+		out.Nullable = out.Nullable || c.Nullable
+		switch typeFamily(c.Type) {
+		case "float":
+			out.Type = "DOUBLE"
+		case "decimal", "unknown", "string":
+			if out.Type != "DOUBLE" {
+				out.Type = "DECIMAL"
+			}
+		}
+	}
+	// SQL Server 使用 + 拼接字符串
+	if e.Name == "+" && r.dialect == sqlparse.DialectSQLServer {
+		for _, a := range e.Args {
+			if typeFamily(r.inferExpr(a, sources, depth).Type) == "string" {
+				out.Type = "VARCHAR"
+				return out
+			}
+		}
+	}
+	switch e.Name {
+	case "/":
+		if out.Type == "BIGINT" {
+			out.Type = "DECIMAL"
+		}
+	case "&", "|", "^", "<<", ">>":
+		out.Type = "BIGINT UNSIGNED"
+	case "DIV":
+		out.Type = "BIGINT"
+	}
+	return out
+}
+
+// 结果类型固定的函数
+var funcResultTypes = map[string]string{
+	"COUNT": "BIGINT", "ROW_NUMBER": "BIGINT", "RANK": "BIGINT", "DENSE_RANK": "BIGINT", "NTILE": "BIGINT",
+	"SUM": "DECIMAL", "AVG": "DECIMAL",
+	"LENGTH": "BIGINT", "CHAR_LENGTH": "BIGINT", "CHARACTER_LENGTH": "BIGINT", "LEN": "BIGINT", "DATALENGTH": "BIGINT",
+	"LOCATE": "BIGINT", "INSTR": "BIGINT", "POSITION": "BIGINT", "CHARINDEX": "BIGINT",
+	"YEAR": "BIGINT", "MONTH": "BIGINT", "DAY": "BIGINT", "DAYOFMONTH": "BIGINT", "DAYOFWEEK": "BIGINT",
+	"DAYOFYEAR": "BIGINT", "WEEK": "BIGINT", "QUARTER": "BIGINT", "HOUR": "BIGINT", "MINUTE": "BIGINT",
+	"SECOND": "BIGINT", "DATEDIFF": "BIGINT", "TIMESTAMPDIFF": "BIGINT", "UNIX_TIMESTAMP": "BIGINT",
+	"CEIL": "BIGINT", "CEILING": "BIGINT", "FLOOR": "BIGINT", "SIGN": "BIGINT",
+	"RAND": "DOUBLE", "SQRT": "DOUBLE", "POW": "DOUBLE", "POWER": "DOUBLE", "EXP": "DOUBLE", "LN": "DOUBLE",
+	"LOG": "DOUBLE", "LOG10": "DOUBLE", "LOG2": "DOUBLE", "PI": "DOUBLE",
+	"CONCAT": "VARCHAR", "CONCAT_WS": "VARCHAR", "SUBSTRING": "VARCHAR", "SUBSTR": "VARCHAR", "LEFT": "VARCHAR",
+	"RIGHT": "VARCHAR", "UPPER": "VARCHAR", "LOWER": "VARCHAR", "UCASE": "VARCHAR", "LCASE": "VARCHAR",
+	"TRIM": "VARCHAR", "LTRIM": "VARCHAR", "RTRIM": "VARCHAR", "REPLACE": "VARCHAR", "LPAD": "VARCHAR",
+	"RPAD": "VARCHAR", "REVERSE": "VARCHAR", "REPEAT": "VARCHAR", "GROUP_CONCAT": "VARCHAR", "STRING_AGG": "VARCHAR",
+	"LISTAGG": "VARCHAR", "DATE_FORMAT": "VARCHAR", "FORMAT": "VARCHAR", "TO_CHAR": "VARCHAR", "HEX": "VARCHAR",
+	"MD5": "VARCHAR", "SHA1": "VARCHAR", "SHA2": "VARCHAR", "UUID": "VARCHAR", "JSON_UNQUOTE": "VARCHAR",
+	"CURRENT_USER": "VARCHAR", "SESSION_USER": "VARCHAR", "USER": "VARCHAR", "EXTRACT": "BIGINT",
+	"DATEPART": "BIGINT", "DATE_PART": "DECIMAL", "ROWNUM": "BIGINT", "LEVEL": "BIGINT",
+	"NOW": "DATETIME", "CURRENT_TIMESTAMP": "DATETIME", "SYSDATE": "DATETIME", "LOCALTIME": "DATETIME",
+	"LOCALTIMESTAMP": "DATETIME", "UTC_TIMESTAMP": "DATETIME", "GETDATE": "DATETIME", "SYSDATETIME": "DATETIME",
+	"DATE_ADD": "DATETIME", "DATE_SUB": "DATETIME", "ADDDATE": "DATETIME", "SUBDATE": "DATETIME",
+	"STR_TO_DATE": "DATETIME", "FROM_UNIXTIME": "DATETIME", "TIMESTAMP": "DATETIME", "TIMESTAMPADD": "DATETIME",
+	"DATEADD": "DATETIME", "TO_TIMESTAMP": "DATETIME", "SYSTIMESTAMP": "DATETIME", "DATE_TRUNC": "DATETIME",
+	"CURDATE": "DATE", "CURRENT_DATE": "DATE", "UTC_DATE": "DATE", "DATE": "DATE", "LAST_DAY": "DATE",
+	"TO_DATE": "DATE", "MAKEDATE": "DATE",
+	"CURTIME": "TIME", "CURRENT_TIME": "TIME", "UTC_TIME": "TIME", "TIME": "TIME", "SEC_TO_TIME": "TIME",
+	"MAKETIME":     "TIME",
+	"JSON_EXTRACT": "JSON", "JSON_OBJECT": "JSON", "JSON_ARRAY": "JSON", "JSON_ARRAYAGG": "JSON", "JSON_OBJECTAGG": "JSON",
+}
+
+// 结果非空的函数（不受参数影响）
+var funcNotNull = map[string]bool{
+	"COUNT": true, "ROW_NUMBER": true, "RANK": true, "DENSE_RANK": true, "NTILE": true,
+	"NOW": true, "CURRENT_TIMESTAMP": true, "SYSDATE": true, "LOCALTIME": true, "LOCALTIMESTAMP": true,
+	"UTC_TIMESTAMP": true, "GETDATE": true, "SYSDATETIME": true, "CURDATE": true, "CURRENT_DATE": true,
+	"UTC_DATE": true, "SYSTIMESTAMP": true, "ROWNUM": true, "LEVEL": true, "CURTIME": true, "CURRENT_TIME": true, "UTC_TIME": true, "UUID": true, "RAND": true, "PI": true,
+}
+
+// 聚合函数在空分组上返回 NULL
+var nullableAggregates = map[string]bool{
+	"SUM": true, "AVG": true, "MIN": true, "MAX": true, "GROUP_CONCAT": true, "STRING_AGG": true,
+	"LISTAGG": true, "JSON_ARRAYAGG": true, "JSON_OBJECTAGG": true,
+}
+
+// inferFunc 推断函数调用的类型与可空性
+func (r *viewResolver) inferFunc(e *sqlparse.Expr, sources []viewSource, depth int) viewColumn {
+	args := make([]viewColumn, len(e.Args))
+	anyNull := false
+	for i, a := range e.Args {
+		args[i] = r.inferExpr(a, sources, depth)
+		anyNull = anyNull || args[i].Nullable
+	}
+	firstTyped := func(from int) string {
+		for i := from; i < len(args); i++ {
+			if args[i].Type != unknownType {
+				return args[i].Type
+			}
+		}
+		return unknownType
+	}
+
+	out := viewColumn{Type: unknownType, Nullable: anyNull}
+	switch e.Name {
+	case "COALESCE", "IFNULL", "NVL", "ISNULL":
+		out.Type = firstTyped(0)
+		out.Nullable = len(args) > 0
+		for _, a := range args {
+			out.Nullable = out.Nullable && a.Nullable
+		}
+	case "IF", "IIF":
+		out.Type = firstTyped(1)
+		out.Nullable = false
+		for i := 1; i < len(args); i++ {
+			out.Nullable = out.Nullable || args[i].Nullable
+		}
+	case "NULLIF":
+		out.Type = firstTyped(0)
+		out.Nullable = true
+	case "MIN", "MAX", "ABS", "ROUND", "TRUNCATE", "MOD", "GREATEST", "LEAST", "ANY_VALUE",
+		"FIRST_VALUE", "LAST_VALUE", "LAG", "LEAD":
+		out.Type = firstTyped(0)
+	default:
+		if t, ok := funcResultTypes[e.Name]; ok {
+			out.Type = t
+		}
+	}
+	if nullableAggregates[e.Name] || e.Name == "LAG" || e.Name == "LEAD" {
+		out.Nullable = true
+	}
+	if funcNotNull[e.Name] {
+		out.Nullable = false
+	}
+	return out
+}
+
+// 类型名中的长度/精度部分
+var typeArgsPattern = regexp.MustCompile(`\(.*\)`)
+
+// typeFamily 将数据库类型归类，用于运算结果推断
+func typeFamily(t string) string {
+	base := strings.TrimSpace(typeArgsPattern.ReplaceAllString(strings.ToUpper(t), ""))
+	base = strings.TrimSuffix(base, " UNSIGNED")
+	switch base {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT", "INT2", "INT4", "INT8",
+		"SERIAL", "BIGSERIAL", "SMALLSERIAL", "BIT", "YEAR":
+		return "int"
+	case "DECIMAL", "NUMERIC", "NUMBER", "MONEY", "SMALLMONEY", "DEC", "FIXED":
+		return "decimal"
+	case "FLOAT", "DOUBLE", "REAL", "DOUBLE PRECISION", "FLOAT4", "FLOAT8", "BINARY_FLOAT", "BINARY_DOUBLE":
+		return "float"
+	case "":
+		return "unknown"
+	case unknownType:
+		return "unknown"
+	}
+	return "string"
+}
+
+// toFieldVOs 将解析结果转换为字段VO
+func toFieldVOs(cols []viewColumn) []models.FieldInfoVO {
+	fields := make([]models.FieldInfoVO, 0, len(cols))
+	for _, c := range cols {
+		fields = append(fields, models.FieldInfoVO{
+			Name:     c.Name,
+			Type:     c.Type,
+			Display:  true,
+			Nullable: c.Nullable,
+			Comment:  c.Comment,
+		})
+	}
+	return fields
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"bytes"
+	"dbrun/app/docs"
+	"dbrun/app/models"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ExportXLSXDictionary 将连接（或页面上的表）的数据字典导出为工作簿，每个库或 Schema 一个工作表
+func ExportXLSXDictionary(opts models.DocOptions) (models.DocResult, error) {
+	result := models.DocResult{OutputPath: opts.OutputPath, Files: []string{}}
+	manager, err := getMgr()
+	if err != nil {
+		return result, err
+	}
+	if opts.OutputPath == "" {
+		return result, fmt.Errorf("output path is required")
+	}
+	d, err := manager.loadDictionary(opts)
+	if err != nil {
+		return result, err
+	}
+	var buf bytes.Buffer
+	if err := docs.WriteXLSX(&buf, d); err != nil {
+		return result, fmt.Errorf("write workbook failed: %w", err)
+	}
+	if err := writeDocFile(opts.OutputPath, buf.Bytes()); err != nil {
+		return result, err
+	}
+	result.Files = append(result.Files, filepath.Base(opts.OutputPath))
+	result.Tables = len(d.Tables)
+	return result, nil
+}
+
+// PreviewXLSXDictionaryImport 读取编辑后的工作簿，按库、Schema、表名与字段名匹配，列出备注与别名的修改（不写入）
+func PreviewXLSXDictionaryImport(req models.DictionaryImportRequest) (models.DictionaryImportPreview, error) {
+	preview := models.DictionaryImportPreview{Changes: []models.DictionaryChange{}, Issues: []models.DictionaryImportIssue{}}
+	manager, err := getMgr()
+	if err != nil {
+		return preview, err
+	}
+	if req.InputPath == "" {
+		return preview, fmt.Errorf("input path is required")
+	}
+	rows, err := docs.ReadXLSX(req.InputPath)
+	if err != nil {
+		return preview, err
+	}
+	preview.Rows = len(rows)
+	d, err := manager.loadDictionary(models.DocOptions{ConfigID: req.ConfigID, PageKey: req.PageKey})
+	if err != nil {
+		return preview, err
+	}
+
+	// 名称匹配忽略大小写
+	tables := map[string]*docs.Table{}
+	for i := range d.Tables {
+		t := &d.Tables[i]
+		tables[strings.ToLower(t.QualifiedName())] = t
+	}
+	type target struct {
+		line  int
+		sheet string
+		value string
+	}
+	changed := map[string]target{}
+	issue := func(r docs.WorkbookRow, format string, args ...interface{}) {
+		preview.Issues = append(preview.Issues, models.DictionaryImportIssue{Sheet: r.Sheet, Line: r.Line, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, r := range rows {
+		name := docs.Table{Database: r.Database, Schema: r.Schema, Info: models.TableInfoVO{Name: r.Table}}.QualifiedName()
+		t := tables[strings.ToLower(name)]
+		if t == nil {
+			issue(r, "table %s not found", name)
+			continue
+		}
+		name = t.QualifiedName()
+		change := models.DictionaryChange{TableID: t.Info.ID, Database: t.Database, Schema: t.Schema, Table: t.Info.Name}
+		oldRemark, oldAlias := t.Info.Remark, t.Info.Alias
+		if r.Column != "" {
+			var field *models.FieldInfoVO
+			for i := range t.Info.Fields {
+				if strings.EqualFold(t.Info.Fields[i].Name, r.Column) {
+					field = &t.Info.Fields[i]
+					break
+				}
+			}
+			if field == nil {
+				issue(r, "column %s.%s not found", name, r.Column)
+				continue
+			}
+			change.FieldID, change.Column = field.ID, field.Name
+			name += "." + field.Name
+			oldRemark, oldAlias = field.Remark, field.Alias
+		}
+
+		for _, attr := range []struct {
+			name     string
+			old      string
+			newValue *string
+		}{
+			{models.DictionaryRemark, oldRemark, r.Remark},
+			{models.DictionaryAlias, oldAlias, r.Alias},
+		} {
+			if attr.newValue == nil {
+				continue
+			}
+			key := fmt.Sprintf("%d/%d/%s", change.TableID, change.FieldID, attr.name)
+			if prev, ok := changed[key]; ok {
+				if prev.value != *attr.newValue {
+					issue(r, "conflicting %s for %s, keeping the value from %s line %d", attr.name, name, prev.sheet, prev.line)
+				}
+				continue
+			}
+			changed[key] = target{line: r.Line, sheet: r.Sheet, value: *attr.newValue}
+			if strings.TrimSpace(attr.old) == *attr.newValue {
+				continue
+			}
+			c := change
+			c.Attribute, c.OldValue, c.NewValue = attr.name, attr.old, *attr.newValue
+			preview.Changes = append(preview.Changes, c)
+		}
+	}
+	return preview, nil
+}
+
+// ApplyDictionaryChanges 提交预览中确认的修改，返回写入的修改数
+func ApplyDictionaryChanges(changes []models.DictionaryChange) (int, error) {
+	manager, err := getMgr()
+	if err != nil {
+		return 0, err
+	}
+	tables := map[int64]map[string]interface{}{}
+	fields := map[int64]map[string]interface{}{}
+	for _, c := range changes {
+		if c.Attribute != models.DictionaryRemark && c.Attribute != models.DictionaryAlias {
+			return 0, fmt.Errorf("invalid dictionary attribute: %s", c.Attribute)
+		}
+		target, id := tables, c.TableID
+		if c.FieldID != 0 {
+			target, id = fields, c.FieldID
+		}
+		if target[id] == nil {
+			target[id] = map[string]interface{}{}
+		}
+		target[id][c.Attribute] = c.NewValue
+	}
+	if err := manager.voStorage.UpdateDictionaryVO(tables, fields); err != nil {
+		return 0, fmt.Errorf("apply dictionary changes failed: %w", err)
+	}
+	fmt.Printf("[Dictionary] applied changes: changes=%d tables=%d fields=%d\n", len(changes), len(tables), len(fields))
+	return len(changes), nil
+}
@@ -16,7 +16,11 @@ type Credentials struct {
     Database  string    `json:"database"`
     Instance  string    `json:"instance"`
     Options   string    `json:"options"`
+    ReadOnly  *bool     `json:"read_only" gorm:"default:true"` // 只读连接（未指定时默认开启，拒绝执行DML/DDL）；指针使 false 不被列默认值替换
     CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 }
 
 func (Credentials) TableName() string { return "table_credentials" }
+
+// IsReadOnly 是否为只读连接，未指定时视为只读
+func (c Credentials) IsReadOnly() bool { return c.ReadOnly == nil || *c.ReadOnly }
@@ -0,0 +1,73 @@
+package metadata
+
+import (
+	"gorm.io/gorm"
+)
+
+// LineageStorage 字段血缘存储
+type LineageStorage struct {
+	db *gorm.DB
+}
+
+// NewLineageStorage 创建字段血缘存储
+func NewLineageStorage(db *gorm.DB) *LineageStorage {
+	return &LineageStorage{db: db}
+}
+
+// ReplaceDatabaseLineage 替换数据库内所有视图的血缘边
+func (l *LineageStorage) ReplaceDatabaseLineage(databaseID int64, edges []ColumnLineage) error {
+	return l.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("database_id = ?", databaseID).Delete(&ColumnLineage{}).Error; err != nil {
+			return err
+		}
+		if len(edges) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(edges, 200).Error
+	})
+}
+
+// GetUpstreamEdges 获取视图字段的来源边；column 为空时返回视图所有字段的来源
+func (l *LineageStorage) GetUpstreamEdges(viewID int64, column string) ([]ColumnLineage, error) {
+	var rows []ColumnLineage
+	q := l.db.Where("view_id = ?", viewID)
+	if column != "" {
+		q = q.Where("LOWER(view_column) = LOWER(?)", column)
+	}
+	if err := q.Order("position, id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetDownstreamEdges 获取引用了指定表/视图字段的血缘边；column 为空时返回引用该对象任意字段的边
+func (l *LineageStorage) GetDownstreamEdges(sourceKind string, sourceID int64, column string) ([]ColumnLineage, error) {
+	var rows []ColumnLineage
+	q := l.db.Where("source_kind = ? AND source_id = ?", sourceKind, sourceID)
+	if column != "" {
+		q = q.Where("LOWER(source_column) = LOWER(?)", column)
+	}
+	if err := q.Order("view_id, position, id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// DeleteByDatabaseID 删除数据库内视图的血缘边
+func (l *LineageStorage) DeleteByDatabaseID(databaseID int64) error {
+	return l.db.Where("database_id = ?", databaseID).Delete(&ColumnLineage{}).Error
+}
+
+// GetDownstreamDatabaseIDs 同一连接内其他数据库中，来源指向该库的表/视图或指向已不存在对象的血缘边所在的数据库
+func (l *LineageStorage) GetDownstreamDatabaseIDs(databaseID int64) ([]int64, error) {
+	var ids []int64
+	sameConfig := "SELECT id FROM raw_database_info WHERE config_id = (SELECT config_id FROM raw_database_info WHERE id = ?)"
+	err := l.db.Model(&ColumnLineage{}).Distinct("database_id").
+		Where("database_id <> ? AND database_id IN ("+sameConfig+")", databaseID, databaseID).
+		Where(l.db.Where("source_kind = ? AND (source_id IN (SELECT id FROM raw_table_info WHERE database_id = ?) OR source_id NOT IN (SELECT id FROM raw_table_info))",
+			LineageSourceTable, databaseID).
+			Or("source_kind = ? AND (source_id IN (SELECT id FROM raw_view_info WHERE database_id = ?) OR source_id NOT IN (SELECT id FROM raw_view_info))",
+				LineageSourceView, databaseID)).
+		Pluck("database_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,30 @@
+package metadata
+
+import (
+	"time"
+)
+
+// 血缘来源类型
+const (
+	LineageSourceTable = "table"
+	LineageSourceView  = "view"
+)
+
+// ColumnLineage 视图字段的血缘边：来源表/视图的字段 -> 视图字段
+type ColumnLineage struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	DatabaseID   int64     `gorm:"not null;index" json:"database_id"`                               // 视图所在数据库ID
+	ViewID       int64     `gorm:"not null;index:idx_lineage_view" json:"view_id"`                  // 原始视图ID
+	ViewColumn   string    `gorm:"not null;size:255;index:idx_lineage_view" json:"view_column"`     // 视图字段名
+	Position     int       `json:"position"`                                                        // 视图字段序号（从0开始）
+	SourceKind   string    `gorm:"not null;size:20;index:idx_lineage_source" json:"source_kind"`    // table / view
+	SourceID     int64     `gorm:"not null;index:idx_lineage_source" json:"source_id"`              // 原始表或视图ID
+	SourceColumn string    `gorm:"not null;size:255;index:idx_lineage_source" json:"source_column"` // 来源字段名
+	Transform    string    `gorm:"size:50" json:"transform"`                                        // direct / func / cast / case / arith ...
+	Expression   string    `gorm:"type:text" json:"expression"`                                     // 视图字段的表达式，直接引用时为空
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ColumnLineage) TableName() string {
+	return "column_lineage"
+}
@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"gorm.io/gorm"
+)
+
+// PageStorage 页面与页面节点存储
+type PageStorage struct {
+	db *gorm.DB
+}
+
+// NewPageStorage 创建页面存储
+func NewPageStorage(db *gorm.DB) *PageStorage {
+	return &PageStorage{db: db}
+}
+
+// ListPages 返回所有页面与分组，按同级排序
+func (s *PageStorage) ListPages() ([]DiagramPage, error) {
+	var rows []DiagramPage
+	err := s.db.Order("sort, id").Find(&rows).Error
+	return rows, err
+}
+
+// GetPageByKey 按页面键获取页面，不存在时返回 nil
+func (s *PageStorage) GetPageByKey(key string) (*DiagramPage, error) {
+	var rows []DiagramPage
+	if err := s.db.Where("key = ?", key).Limit(1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// SavePage 新增或更新页面
+func (s *PageStorage) SavePage(p *DiagramPage) error {
+	if p.ID == 0 {
+		return s.db.Create(p).Error
+	}
+	return s.db.Save(p).Error
+}
+
+// UpdateSorts 批量更新同级排序与所属分组
+func (s *PageStorage) UpdateSorts(pages []DiagramPage) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for _, p := range pages {
+			if err := tx.Model(&DiagramPage{}).Where("id = ?", p.ID).
+				Updates(map[string]interface{}{"sort": p.Sort, "parent_id": p.ParentID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeletePages 删除页面及其节点、关系
+func (s *PageStorage) DeletePages(ids []int64, keys []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("page_id IN ?", ids).Delete(&PageNode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("relationship_id IN (SELECT id FROM relationship WHERE page_id IN ?)", keys).Delete(&RelationshipColumn{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("page_id IN ?", keys).Delete(&Relationship{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&DiagramPage{}).Error
+	})
+}
+
+// ListNodes 返回页面上的节点
+func (s *PageStorage) ListNodes(pageID int64) ([]PageNode, error) {
+	var rows []PageNode
+	err := s.db.Where("page_id = ?", pageID).Order("id").Find(&rows).Error
+	return rows, err
+}
+
+// ListAllNodes 返回所有页面的节点
+func (s *PageStorage) ListAllNodes() ([]PageNode, error) {
+	var rows []PageNode
+	err := s.db.Order("page_id, id").Find(&rows).Error
+	return rows, err
+}
+
+// ReplaceNodes 替换页面上的全部节点
+func (s *PageStorage) ReplaceNodes(pageID int64, nodes []PageNode) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("page_id = ?", pageID).Delete(&PageNode{}).Error; err != nil {
+			return err
+		}
+		for i := range nodes {
+			nodes[i].ID = 0
+			nodes[i].PageID = pageID
+		}
+		if len(nodes) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(nodes, 200).Error
+	})
+}
@@ -0,0 +1,56 @@
+package metadata
+
+import (
+	"time"
+)
+
+// 页面树节点类型
+const (
+	PageTypePage  = "page"
+	PageTypeGroup = "group"
+)
+
+// 页面节点引用的对象类型
+const (
+	PageObjectTable = "table"
+	PageObjectView  = "view"
+)
+
+// DiagramPage 页面或分组，分组通过 ParentID 组成树
+type DiagramPage struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Key       string    `gorm:"not null;size:100;uniqueIndex" json:"key"` // 前端使用的页面键
+	ParentID  *int64    `gorm:"index" json:"parent_id"`                   // 所属分组，为空表示根级
+	Type      string    `gorm:"not null;size:10;default:page" json:"type"`
+	Label     string    `gorm:"not null;size:255" json:"label"`
+	Icon      string    `gorm:"size:100" json:"icon"`
+	Sort      int       `gorm:"default:0" json:"sort"` // 同级排序（升序）
+	Expanded  bool      `json:"expanded"`
+	Style     string    `gorm:"type:text" json:"style"` // 页面样式（JSON）
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// PageNode 页面上的表或视图节点
+type PageNode struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	PageID     int64     `gorm:"not null;index" json:"page_id"`
+	NodeKey    string    `gorm:"not null;size:255" json:"node_key"` // 前端节点ID
+	ObjectKind string    `gorm:"not null;size:10;index:idx_page_node_object" json:"object_kind"`
+	ObjectID   int64     `gorm:"not null;index:idx_page_node_object" json:"object_id"` // 原始表或视图ID
+	X          float64   `json:"x"`
+	Y          float64   `json:"y"`
+	Width      float64   `json:"width"`
+	Height     float64   `json:"height"`
+	Style      string    `gorm:"type:text" json:"style"` // 节点样式（JSON）
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (DiagramPage) TableName() string {
+	return "diagram_page"
+}
+
+func (PageNode) TableName() string {
+	return "page_node"
+}
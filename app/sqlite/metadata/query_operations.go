@@ -0,0 +1,163 @@
+package metadata
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// QueryStorage 查询历史与SQL片段存储
+type QueryStorage struct {
+	db *gorm.DB
+}
+
+// NewQueryStorage 创建查询历史与片段存储
+func NewQueryStorage(db *gorm.DB) *QueryStorage {
+	return &QueryStorage{db: db}
+}
+
+// HistoryFilter 历史记录检索条件
+type HistoryFilter struct {
+	ConfigID   int64  `json:"configId"`   // 为0时不限连接
+	Keyword    string `json:"keyword"`    // 匹配SQL文本
+	OnlyErrors bool   `json:"onlyErrors"` // 仅返回执行失败的记录
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+}
+
+// HistoryPage 历史记录分页结果
+type HistoryPage struct {
+	Items []QueryHistory `json:"items"`
+	Total int64          `json:"total"`
+}
+
+// AddHistory 写入一条历史记录
+func (q *QueryStorage) AddHistory(h *QueryHistory) error {
+	return q.db.Create(h).Error
+}
+
+// SearchHistory 按条件检索历史记录，按执行时间倒序
+func (q *QueryStorage) SearchHistory(filter HistoryFilter) (HistoryPage, error) {
+	tx := q.db.Model(&QueryHistory{})
+	if filter.ConfigID != 0 {
+		tx = tx.Where("config_id = ?", filter.ConfigID)
+	}
+	if kw := strings.TrimSpace(filter.Keyword); kw != "" {
+		tx = tx.Where("sql LIKE ? ESCAPE '\\'", "%"+escapeLike(kw)+"%")
+	}
+	if filter.OnlyErrors {
+		tx = tx.Where("error <> ''")
+	}
+	var page HistoryPage
+	if err := tx.Count(&page.Total).Error; err != nil {
+		return page, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	err := tx.Order("executed_at DESC, id DESC").Limit(limit).Offset(filter.Offset).Find(&page.Items).Error
+	return page, err
+}
+
+// DeleteHistory 删除指定的历史记录
+func (q *QueryStorage) DeleteHistory(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := q.db.Where("history_id IN ?", ids).Delete(&QueryPlan{}).Error; err != nil {
+		return err
+	}
+	return q.db.Where("id IN ?", ids).Delete(&QueryHistory{}).Error
+}
+
+// ClearHistory 清空历史记录，configID 为0时清空全部
+func (q *QueryStorage) ClearHistory(configID int64) error {
+	tx := q.db.Session(&gorm.Session{AllowGlobalUpdate: true})
+	if configID != 0 {
+		sub := q.db.Model(&QueryHistory{}).Select("id").Where("config_id = ?", configID)
+		if err := q.db.Where("history_id IN (?)", sub).Delete(&QueryPlan{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("config_id = ?", configID).Delete(&QueryHistory{}).Error
+	}
+	if err := tx.Delete(&QueryPlan{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&QueryHistory{}).Error
+}
+
+// SavePlan 保存执行计划
+func (q *QueryStorage) SavePlan(p *QueryPlan) error {
+	return q.db.Create(p).Error
+}
+
+// GetPlanByHistoryID 获取历史记录对应的执行计划
+func (q *QueryStorage) GetPlanByHistoryID(historyID int64) (*QueryPlan, error) {
+	var p QueryPlan
+	if err := q.db.Where("history_id = ?", historyID).First(&p).Error; err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SaveSnippet 新增或更新片段（ID为0时新增）
+func (q *QueryStorage) SaveSnippet(s *QuerySnippet) error {
+	if s.ID == 0 {
+		return q.db.Create(s).Error
+	}
+	return q.db.Model(&QuerySnippet{}).Where("id = ?", s.ID).Updates(map[string]interface{}{
+		"name":        s.Name,
+		"folder":      s.Folder,
+		"config_id":   s.ConfigID,
+		"sql":         s.SQL,
+		"description": s.Description,
+	}).Error
+}
+
+// UpsertSnippetByPath 按 文件夹+名称 新增或覆盖片段，用于从项目目录导入
+func (q *QueryStorage) UpsertSnippetByPath(s *QuerySnippet) error {
+	var existing []QuerySnippet
+	if err := q.db.Where("folder = ? AND name = ?", s.Folder, s.Name).Limit(1).Find(&existing).Error; err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return q.db.Create(s).Error
+	}
+	s.ID = existing[0].ID
+	return q.SaveSnippet(s)
+}
+
+// GetSnippetByID 获取单个片段
+func (q *QueryStorage) GetSnippetByID(id int64) (*QuerySnippet, error) {
+	var s QuerySnippet
+	if err := q.db.Where("id = ?", id).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ListSnippets 列出片段，folder 非空时仅返回该文件夹及其子文件夹下的片段
+func (q *QueryStorage) ListSnippets(folder string, keyword string) ([]QuerySnippet, error) {
+	tx := q.db.Model(&QuerySnippet{})
+	if folder = strings.Trim(folder, "/"); folder != "" {
+		tx = tx.Where("folder = ? OR folder LIKE ? ESCAPE '\\'", folder, escapeLike(folder)+"/%")
+	}
+	if kw := strings.TrimSpace(keyword); kw != "" {
+		like := "%" + escapeLike(kw) + "%"
+		tx = tx.Where("name LIKE ? ESCAPE '\\' OR sql LIKE ? ESCAPE '\\' OR description LIKE ? ESCAPE '\\'", like, like, like)
+	}
+	var list []QuerySnippet
+	err := tx.Order("folder ASC, name ASC").Find(&list).Error
+	return list, err
+}
+
+// DeleteSnippet 删除片段
+func (q *QueryStorage) DeleteSnippet(id int64) error {
+	return q.db.Where("id = ?", id).Delete(&QuerySnippet{}).Error
+}
+
+// escapeLike 转义 LIKE 模式中的通配符（转义字符为反斜杠）
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,54 @@
+package metadata
+
+import (
+	"time"
+)
+
+// QueryHistory 已执行语句的历史记录
+type QueryHistory struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ConfigID     int64     `gorm:"not null;index" json:"config_id"` // 执行所用的连接配置ID
+	SQL          string    `gorm:"type:text;not null" json:"sql"`   // 执行的语句
+	Params       string    `gorm:"type:text" json:"params"`         // 绑定参数（JSON数组）
+	Kind         string    `gorm:"size:50" json:"kind"`             // 语句类别
+	DurationMs   int64     `json:"duration_ms"`                     // 耗时（毫秒）
+	RowCount     int       `json:"row_count"`                       // 返回行数
+	RowsAffected int64     `json:"rows_affected"`                   // 影响行数
+	Error        string    `gorm:"type:text" json:"error"`          // 执行错误，成功时为空
+	ExecutedAt   time.Time `gorm:"index" json:"executed_at"`        // 执行时间
+}
+
+// QuerySnippet 命名保存的SQL片段
+type QuerySnippet struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name        string    `gorm:"not null;size:255;uniqueIndex:idx_snippet_folder_name" json:"name"` // 片段名称
+	Folder      string    `gorm:"size:255;uniqueIndex:idx_snippet_folder_name" json:"folder"`        // 所在文件夹，使用 / 分隔层级
+	ConfigID    *int64    `gorm:"index" json:"config_id"`                                            // 默认执行的连接（可为空）
+	SQL         string    `gorm:"type:text;not null" json:"sql"`
+	Description string    `gorm:"size:1000" json:"description"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// QueryPlan 与历史记录关联的执行计划
+type QueryPlan struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	HistoryID int64     `gorm:"not null;uniqueIndex" json:"history_id"` // 关联的历史记录ID
+	Dialect   string    `gorm:"size:50" json:"dialect"`
+	Format    string    `gorm:"size:20" json:"format"` // 原始计划格式：json / xml / text
+	Raw       string    `gorm:"type:text" json:"raw"`  // 原始计划
+	Tree      string    `gorm:"type:text" json:"tree"` // 统一计划树（JSON）
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (QueryHistory) TableName() string {
+	return "query_history"
+}
+
+func (QuerySnippet) TableName() string {
+	return "query_snippet"
+}
+
+func (QueryPlan) TableName() string {
+	return "query_plan"
+}
@@ -2,6 +2,7 @@ package metadata
 
 import (
 	"dbrun/app/connect"
+	"encoding/json"
 	"gorm.io/gorm"
 )
 
@@ -149,23 +150,27 @@ func (r *RawMetadataStorage) SaveTableInfo(databaseID int64, schemaID *int64, ta
 		return nil, err
 	}
 
-	// 先删除该表的所有现有字段记录
-	if err := r.db.Where("table_id = ?", rawTable.ID).Delete(&RawFieldInfo{}).Error; err != nil {
+	// 按名称更新或新增字段，保持已有字段ID不变（关系与VO扩展信息引用字段ID）
+	keep := make([]int64, 0, len(table.Fields))
+	for _, field := range table.Fields {
+		rawField, err := r.SaveFieldInfo(rawTable.ID, field)
+		if err != nil {
+			return nil, err
+		}
+		keep = append(keep, rawField.ID)
+	}
+
+	// 删除已不存在的字段
+	del := r.db.Where("table_id = ?", rawTable.ID)
+	if len(keep) > 0 {
+		del = del.Where("id NOT IN ?", keep)
+	}
+	if err := del.Delete(&RawFieldInfo{}).Error; err != nil {
 		return nil, err
 	}
 
-	// 然后重新插入所有字段
-	for _, field := range table.Fields {
-		rawField := &RawFieldInfo{
-			TableID:      rawTable.ID,
-			Name:         field.Name,
-			Type:         field.Type,
-			Nullable:     field.Nullable,
-			Key:          field.Key,
-			DefaultValue: field.DefaultValue,
-			Comment:      field.Comment,
-		}
-		if err := r.db.Create(rawField).Error; err != nil {
+	if table.Keys != nil {
+		if err := r.replaceTableKeys(rawTable, table.Keys); err != nil {
 			return nil, err
 		}
 	}
@@ -173,16 +178,69 @@ func (r *RawMetadataStorage) SaveTableInfo(databaseID int64, schemaID *int64, ta
 	return rawTable, nil
 }
 
+// replaceTableKeys 用本次同步获取的键信息替换表的已有记录，并标记该表已获取键信息
+func (r *RawMetadataStorage) replaceTableKeys(table *RawTableInfo, keys []connect.KeyInfo) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("table_id = ?", table.ID).Delete(&RawTableKey{}).Error; err != nil {
+			return err
+		}
+		for _, k := range keys {
+			columns, _ := json.Marshal(k.Columns)
+			refColumns, _ := json.Marshal(k.RefColumns)
+			row := RawTableKey{TableID: table.ID, Name: k.Name, Kind: k.Kind, Columns: string(columns),
+				RefSchema: k.RefSchema, RefTable: k.RefTable, RefColumns: string(refColumns)}
+			if err := tx.Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		table.KeysLoaded = true
+		return tx.Model(table).Update("keys_loaded", true).Error
+	})
+}
+
+// GetTableKeys 返回表的键信息；loaded 为 false 表示同步时未获取过键信息（旧版本同步或无权限）
+func (r *RawMetadataStorage) GetTableKeys(tableID int64) (keys []connect.KeyInfo, loaded bool, err error) {
+	var table RawTableInfo
+	if err := r.db.Select("id", "keys_loaded").Where("id = ?", tableID).First(&table).Error; err != nil {
+		return nil, false, err
+	}
+	if !table.KeysLoaded {
+		return nil, false, nil
+	}
+	var rows []RawTableKey
+	if err := r.db.Where("table_id = ?", tableID).Order("id").Find(&rows).Error; err != nil {
+		return nil, false, err
+	}
+	keys = make([]connect.KeyInfo, 0, len(rows))
+	for _, row := range rows {
+		k := connect.KeyInfo{Name: row.Name, Kind: row.Kind, RefSchema: row.RefSchema, RefTable: row.RefTable}
+		_ = json.Unmarshal([]byte(row.Columns), &k.Columns)
+		_ = json.Unmarshal([]byte(row.RefColumns), &k.RefColumns)
+		keys = append(keys, k)
+	}
+	return keys, true, nil
+}
+
+// FindForeignKeyTableIDs 返回有外键引用指定表名（忽略大小写）的表ID，引用的 Schema 由调用方再行核对
+func (r *RawMetadataStorage) FindForeignKeyTableIDs(tableName string) ([]int64, error) {
+	var ids []int64
+	err := r.db.Model(&RawTableKey{}).Distinct("table_id").
+		Where("kind = ? AND LOWER(ref_table) = LOWER(?)", connect.KeyForeign, tableName).
+		Pluck("table_id", &ids).Error
+	return ids, err
+}
+
 // SaveFieldInfo 保存字段信息
 func (r *RawMetadataStorage) SaveFieldInfo(tableID int64, field connect.FieldInfo) (*RawFieldInfo, error) {
 	rawField := &RawFieldInfo{
-		TableID:      tableID,
-		Name:         field.Name,
-		Type:         field.Type,
-		Nullable:     field.Nullable,
-		Key:          field.Key,
-		Comment:      field.Comment,
-		DefaultValue: field.DefaultValue,
+		TableID:       tableID,
+		Name:          field.Name,
+		Type:          field.Type,
+		Nullable:      field.Nullable,
+		Key:           field.Key,
+		Comment:       field.Comment,
+		DefaultValue:  field.DefaultValue,
+		AutoIncrement: field.AutoIncrement,
 	}
 
 	// 先查找是否已存在
@@ -195,14 +253,25 @@ func (r *RawMetadataStorage) SaveFieldInfo(tableID int64, field connect.FieldInf
 		existing.Key = field.Key
 		existing.Comment = field.Comment
 		existing.DefaultValue = field.DefaultValue
+		existing.AutoIncrement = field.AutoIncrement
 		err = r.db.Save(&existing).Error
 		if err != nil {
 			return nil, err
 		}
 		rawField = &existing
 	} else if err == gorm.ErrRecordNotFound {
-		// 创建新记录
-		err = r.db.Create(rawField).Error
+		// 创建新记录；Nullable 带默认值 true，GORM 创建时会忽略零值 false 并回填默认值，
+		// 因此不可为空的字段在同一事务内单独写入 false
+		err = r.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(rawField).Error; err != nil {
+				return err
+			}
+			if field.Nullable {
+				return nil
+			}
+			rawField.Nullable = false
+			return tx.Model(rawField).Update("nullable", false).Error
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -358,6 +427,15 @@ func (r *RawMetadataStorage) GetTablesByDatabaseID(databaseID int64, schemaID *i
 		}
 		table.Fields = fields
 
+		// 同步时获取过键信息的表附带键信息，未获取过的保持为 nil
+		if rawTable.KeysLoaded {
+			keys, _, err := r.GetTableKeys(rawTable.ID)
+			if err != nil {
+				return nil, err
+			}
+			table.Keys = keys
+		}
+
 		tables = append(tables, table)
 	}
 
@@ -438,8 +516,24 @@ func (r *RawMetadataStorage) DeleteByConfigID(configID int64) error {
 
 // DeleteByDatabaseID 根据数据库ID删除所有相关数据
 func (r *RawMetadataStorage) DeleteByDatabaseID(databaseID int64) error {
+	// 删除涉及库内表的关系
+	tablesInDB := "SELECT id FROM raw_table_info WHERE database_id = ?"
+	err := r.db.Where("relationship_id IN (SELECT id FROM relationship WHERE source_table_id IN ("+tablesInDB+") OR target_table_id IN ("+tablesInDB+"))",
+		databaseID, databaseID).Delete(&RelationshipColumn{}).Error
+	if err != nil {
+		return err
+	}
+	err = r.db.Where("source_table_id IN ("+tablesInDB+") OR target_table_id IN ("+tablesInDB+")", databaseID, databaseID).Delete(&Relationship{}).Error
+	if err != nil {
+		return err
+	}
+
 	// 删除字段信息
-	err := r.db.Where("table_id IN (SELECT id FROM raw_table_info WHERE database_id = ?)", databaseID).Delete(&RawFieldInfo{}).Error
+	err = r.db.Where("table_id IN (SELECT id FROM raw_table_info WHERE database_id = ?)", databaseID).Delete(&RawFieldInfo{}).Error
+	if err != nil {
+		return err
+	}
+	err = r.db.Where("table_id IN (SELECT id FROM raw_table_info WHERE database_id = ?)", databaseID).Delete(&RawTableKey{}).Error
 	if err != nil {
 		return err
 	}
@@ -450,16 +544,58 @@ func (r *RawMetadataStorage) DeleteByDatabaseID(databaseID int64) error {
 		return err
 	}
 
-	// 删除视图信息
+	// 删除视图信息及其字段血缘
 	err = r.db.Where("database_id = ?", databaseID).Delete(&RawViewInfo{}).Error
 	if err != nil {
 		return err
 	}
+	if err := r.db.Where("database_id = ?", databaseID).Delete(&ColumnLineage{}).Error; err != nil {
+		return err
+	}
 
 	// 删除Schema信息
     return r.db.Where("database_id = ?", databaseID).Delete(&RawSchemaInfo{}).Error
 }
 
+// DeleteDatabaseByID 删除数据库及其下的全部数据
+func (r *RawMetadataStorage) DeleteDatabaseByID(databaseID int64) error {
+	if err := r.DeleteByDatabaseID(databaseID); err != nil {
+		return err
+	}
+	return r.db.Where("id = ?", databaseID).Delete(&RawDatabaseInfo{}).Error
+}
+
+// DeleteTablesByIDs 删除指定的表及其字段、键信息和涉及这些表的关系
+func (r *RawMetadataStorage) DeleteTablesByIDs(tableIDs []int64) error {
+	if len(tableIDs) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		rels := "SELECT id FROM relationship WHERE source_table_id IN ? OR target_table_id IN ?"
+		if err := tx.Where("relationship_id IN ("+rels+")", tableIDs, tableIDs).Delete(&RelationshipColumn{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("source_table_id IN ? OR target_table_id IN ?", tableIDs, tableIDs).Delete(&Relationship{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("table_id IN ?", tableIDs).Delete(&RawFieldInfo{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("table_id IN ?", tableIDs).Delete(&RawTableKey{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", tableIDs).Delete(&RawTableInfo{}).Error
+	})
+}
+
+// DeleteSchemasByIDs 删除指定的Schema，调用方需先删除其下的表
+func (r *RawMetadataStorage) DeleteSchemasByIDs(schemaIDs []int64) error {
+	if len(schemaIDs) == 0 {
+		return nil
+	}
+	return r.db.Where("id IN ?", schemaIDs).Delete(&RawSchemaInfo{}).Error
+}
+
 // GetTableContextByID 通过原始表ID获取其上下文信息（configID、数据库/Schema/表名称及相关ID）
 func (r *RawMetadataStorage) GetTableContextByID(tableID int64) (configID int64, dbName string, schemaName string, tableName string, databaseID int64, schemaID *int64, err error) {
     var rt RawTableInfo
@@ -559,4 +695,91 @@ func (r *RawMetadataStorage) GetRawFieldsRows(tableID int64) ([]RawFieldInfo, er
         return nil, err
     }
     return rows, nil
-}
\ No newline at end of file
+}
+// RawObjectRef 按名称查找到的原始表或视图及其所在位置
+type RawObjectRef struct {
+	ID           int64  `json:"id"`
+	ConfigID     int64  `json:"config_id"`
+	DatabaseID   int64  `json:"database_id"`
+	SchemaID     *int64 `json:"schema_id"`
+	DatabaseName string `json:"database_name"`
+	SchemaName   string `json:"schema_name"`
+	Name         string `json:"name"`
+	Comment      string `json:"comment"`
+	Definition   string `json:"definition"` // 仅视图有值
+	IsView       bool   `json:"is_view"`
+}
+
+// FindRawObjectsByName 按名称（忽略大小写）查找原始表与视图；qualifier 为数据库名或Schema名，为空时不限定
+func (r *RawMetadataStorage) FindRawObjectsByName(qualifier string, name string) ([]RawObjectRef, error) {
+	var tables []RawObjectRef
+	q := r.db.Table("raw_table_info AS t").
+		Select("t.id, d.config_id, t.database_id, t.schema_id, d.name AS database_name, s.name AS schema_name, t.name, t.comment").
+		Joins("JOIN raw_database_info AS d ON d.id = t.database_id").
+		Joins("LEFT JOIN raw_schema_info AS s ON s.id = t.schema_id").
+		Where("LOWER(t.name) = LOWER(?)", name)
+	if qualifier != "" {
+		q = q.Where("(LOWER(d.name) = LOWER(?) OR LOWER(s.name) = LOWER(?))", qualifier, qualifier)
+	}
+	if err := q.Order("t.id").Scan(&tables).Error; err != nil {
+		return nil, err
+	}
+
+	var views []RawObjectRef
+	q = r.db.Table("raw_view_info AS v").
+		Select("v.id, d.config_id, v.database_id, v.schema_id, d.name AS database_name, s.name AS schema_name, v.name, v.definition, 1 AS is_view").
+		Joins("JOIN raw_database_info AS d ON d.id = v.database_id").
+		Joins("LEFT JOIN raw_schema_info AS s ON s.id = v.schema_id").
+		Where("LOWER(v.name) = LOWER(?)", name)
+	if qualifier != "" {
+		q = q.Where("(LOWER(d.name) = LOWER(?) OR LOWER(s.name) = LOWER(?))", qualifier, qualifier)
+	}
+	if err := q.Order("v.id").Scan(&views).Error; err != nil {
+		return nil, err
+	}
+	return append(tables, views...), nil
+}
+
+// GetRawObjectsByIDs 按ID批量获取原始表与视图及其所在位置
+func (r *RawMetadataStorage) GetRawObjectsByIDs(tableIDs []int64, viewIDs []int64) ([]RawObjectRef, error) {
+	var tables, views []RawObjectRef
+	if len(tableIDs) > 0 {
+		err := r.db.Table("raw_table_info AS t").
+			Select("t.id, d.config_id, t.database_id, t.schema_id, d.name AS database_name, s.name AS schema_name, t.name, t.comment").
+			Joins("JOIN raw_database_info AS d ON d.id = t.database_id").
+			Joins("LEFT JOIN raw_schema_info AS s ON s.id = t.schema_id").
+			Where("t.id IN ?", tableIDs).Order("t.id").Scan(&tables).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(viewIDs) > 0 {
+		err := r.db.Table("raw_view_info AS v").
+			Select("v.id, d.config_id, v.database_id, v.schema_id, d.name AS database_name, s.name AS schema_name, v.name, v.definition, 1 AS is_view").
+			Joins("JOIN raw_database_info AS d ON d.id = v.database_id").
+			Joins("LEFT JOIN raw_schema_info AS s ON s.id = v.schema_id").
+			Where("v.id IN ?", viewIDs).Order("v.id").Scan(&views).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(tables, views...), nil
+}
+
+// GetRawViewByID 根据ID获取原始视图
+func (r *RawMetadataStorage) GetRawViewByID(viewID int64) (*RawViewInfo, error) {
+	var v RawViewInfo
+	if err := r.db.Where("id = ?", viewID).First(&v).Error; err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// GetRawViewsByDatabaseID 返回数据库内的全部原始视图（含各Schema）
+func (r *RawMetadataStorage) GetRawViewsByDatabaseID(databaseID int64) ([]RawViewInfo, error) {
+	var rows []RawViewInfo
+	if err := r.db.Where("database_id = ?", databaseID).Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
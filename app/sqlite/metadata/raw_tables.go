@@ -30,22 +30,36 @@ type RawTableInfo struct {
 	SchemaID   *int64    `gorm:"index" json:"schema_id"`                 // 关联的Schema ID（可为空）
 	Name       string    `gorm:"not null;size:255" json:"name"`          // 表名
 	Comment    string    `gorm:"size:1000" json:"comment"`               // 表注释
+	KeysLoaded bool      `json:"keys_loaded"`                            // 是否已从数据库获取键信息（见 RawTableKey）
 	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// RawTableKey 原始表的主键、唯一键、索引与外键，同步时按表整体替换
+type RawTableKey struct {
+	ID         int64  `gorm:"primaryKey;autoIncrement" json:"id"`
+	TableID    int64  `gorm:"not null;index" json:"table_id"`  // 关联的表ID
+	Name       string `gorm:"size:255" json:"name"`            // 约束或索引名称
+	Kind       string `gorm:"size:20" json:"kind"`             // primary / unique / index / foreign
+	Columns    string `gorm:"type:text" json:"columns"`        // 按顺序排列的字段名（JSON 数组）
+	RefSchema  string `gorm:"size:255" json:"ref_schema"`      // 外键引用表所在的 Schema 或数据库
+	RefTable   string `gorm:"size:255" json:"ref_table"`       // 外键引用的表
+	RefColumns string `gorm:"type:text" json:"ref_columns"`    // 外键引用的字段（JSON 数组）
+}
+
 // RawFieldInfo 原始字段信息表
 type RawFieldInfo struct {
-	ID           int64     `gorm:"primaryKey;autoIncrement" json:"id"`
-	TableID      int64     `gorm:"not null;index" json:"table_id"`        // 关联的表ID
-	Name         string    `gorm:"not null;size:255" json:"name"`         // 字段名称
-	Type         string    `gorm:"not null;size:100" json:"type"`         // 字段类型
-	Nullable     bool      `gorm:"default:true" json:"nullable"`          // 是否可为空
-	Key          string    `gorm:"size:50" json:"key"`                    // 字段索引类型
-	Comment      string    `gorm:"size:1000" json:"comment"`              // 字段注释
-	DefaultValue string    `gorm:"size:500" json:"default_value"`         // 默认值
-	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID            int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	TableID       int64     `gorm:"not null;index" json:"table_id"`      // 关联的表ID
+	Name          string    `gorm:"not null;size:255" json:"name"`       // 字段名称
+	Type          string    `gorm:"not null;size:100" json:"type"`       // 字段类型
+	Nullable      bool      `gorm:"default:true" json:"nullable"`        // 是否可为空
+	Key           string    `gorm:"size:50" json:"key"`                  // 字段索引类型
+	Comment       string    `gorm:"size:1000" json:"comment"`            // 字段注释
+	DefaultValue  string    `gorm:"size:500" json:"default_value"`       // 默认值
+	AutoIncrement bool      `gorm:"default:false" json:"auto_increment"` // 自增/标识列
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 // RawViewInfo 原始视图信息表
@@ -76,6 +90,10 @@ func (RawFieldInfo) TableName() string {
 	return "raw_field_info"
 }
 
+func (RawTableKey) TableName() string {
+	return "raw_table_key"
+}
+
 func (RawViewInfo) TableName() string {
 	return "raw_view_info"
 }
\ No newline at end of file
@@ -0,0 +1,117 @@
+package metadata
+
+import (
+	"gorm.io/gorm"
+)
+
+// RelationshipStorage 表关系存储
+type RelationshipStorage struct {
+	db *gorm.DB
+}
+
+// NewRelationshipStorage 创建表关系存储
+func NewRelationshipStorage(db *gorm.DB) *RelationshipStorage {
+	return &RelationshipStorage{db: db}
+}
+
+// RelationshipFilter 关系检索条件
+type RelationshipFilter struct {
+	PageID  *string // 非空时仅返回该页面的关系（空字符串表示不属于页面的关系）
+	TableID int64   // 非0时返回一端为该表的关系
+}
+
+// Create 新增关系及其字段对
+func (s *RelationshipStorage) Create(rel *Relationship, cols []RelationshipColumn) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rel).Error; err != nil {
+			return err
+		}
+		return saveRelationshipColumns(tx, rel.ID, cols)
+	})
+}
+
+// Update 更新关系并替换其字段对
+func (s *RelationshipStorage) Update(rel *Relationship, cols []RelationshipColumn) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(rel).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("relationship_id = ?", rel.ID).Delete(&RelationshipColumn{}).Error; err != nil {
+			return err
+		}
+		return saveRelationshipColumns(tx, rel.ID, cols)
+	})
+}
+
+func saveRelationshipColumns(tx *gorm.DB, relationshipID int64, cols []RelationshipColumn) error {
+	for i := range cols {
+		cols[i].ID = 0
+		cols[i].RelationshipID = relationshipID
+		cols[i].Position = i
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+	return tx.Create(&cols).Error
+}
+
+// Delete 删除关系及其字段对
+func (s *RelationshipStorage) Delete(id int64) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("relationship_id = ?", id).Delete(&RelationshipColumn{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&Relationship{}).Error
+	})
+}
+
+// GetByID 获取单个关系
+func (s *RelationshipStorage) GetByID(id int64) (*Relationship, error) {
+	var rel Relationship
+	if err := s.db.Where("id = ?", id).First(&rel).Error; err != nil {
+		return nil, err
+	}
+	return &rel, nil
+}
+
+// List 按条件列出关系
+func (s *RelationshipStorage) List(filter RelationshipFilter) ([]Relationship, error) {
+	tx := s.db.Model(&Relationship{})
+	if filter.PageID != nil {
+		tx = tx.Where("page_id = ?", *filter.PageID)
+	}
+	if filter.TableID != 0 {
+		tx = tx.Where("source_table_id = ? OR target_table_id = ?", filter.TableID, filter.TableID)
+	}
+	var rows []Relationship
+	err := tx.Order("id").Find(&rows).Error
+	return rows, err
+}
+
+// GetColumns 获取多个关系的字段对，按关系ID分组
+func (s *RelationshipStorage) GetColumns(relationshipIDs []int64) (map[int64][]RelationshipColumn, error) {
+	out := map[int64][]RelationshipColumn{}
+	if len(relationshipIDs) == 0 {
+		return out, nil
+	}
+	var rows []RelationshipColumn
+	if err := s.db.Where("relationship_id IN ?", relationshipIDs).Order("relationship_id, position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range rows {
+		out[c.RelationshipID] = append(out[c.RelationshipID], c)
+	}
+	return out, nil
+}
+
+// FindByEdgeKey 按页面与前端连线ID查找已导入的关系
+func (s *RelationshipStorage) FindByEdgeKey(pageID, edgeKey string) (*Relationship, error) {
+	var rows []Relationship
+	if err := s.db.Where("page_id = ? AND edge_key = ?", pageID, edgeKey).Limit(1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
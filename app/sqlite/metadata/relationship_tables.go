@@ -0,0 +1,47 @@
+package metadata
+
+import (
+	"time"
+)
+
+// 关系的来源
+const (
+	RelationshipOriginManual     = "manual"      // 手工绘制或创建
+	RelationshipOriginImport     = "import"      // 从旧版页面连线导入
+	RelationshipOriginInferred   = "inferred"    // 接受的推断结果
+	RelationshipOriginSchemaFile = "schema_file" // 从 DBML / Prisma 设计文件导入
+	RelationshipOriginForeignKey = "foreign_key" // 数据库中的外键
+)
+
+// Relationship 表之间的关系：Source 的字段引用 Target 的字段
+type Relationship struct {
+	ID                int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	PageID            string    `gorm:"size:100;index" json:"page_id"`                  // 所在页面，为空表示不属于具体页面
+	SourceTableID     int64     `gorm:"not null;index" json:"source_table_id"`          // 原始表ID（引用方）
+	TargetTableID     int64     `gorm:"not null;index" json:"target_table_id"`          // 原始表ID（被引用方）
+	SourceCardinality string    `gorm:"size:10;default:many" json:"source_cardinality"` // one / many / none
+	TargetCardinality string    `gorm:"size:10;default:one" json:"target_cardinality"`
+	Label             string    `gorm:"size:255" json:"label"`
+	Notes             string    `gorm:"type:text" json:"notes"`
+	Origin            string    `gorm:"size:20" json:"origin"`
+	EdgeKey           string    `gorm:"size:255;index" json:"edge_key"` // 导入时对应的前端连线ID
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// RelationshipColumn 关系中的一对字段，复合关系按 Position 排列
+type RelationshipColumn struct {
+	ID             int64 `gorm:"primaryKey;autoIncrement" json:"id"`
+	RelationshipID int64 `gorm:"not null;index" json:"relationship_id"`
+	Position       int   `json:"position"`
+	SourceFieldID  int64 `gorm:"not null;index" json:"source_field_id"` // 原始字段ID
+	TargetFieldID  int64 `gorm:"not null;index" json:"target_field_id"`
+}
+
+func (Relationship) TableName() string {
+	return "relationship"
+}
+
+func (RelationshipColumn) TableName() string {
+	return "relationship_column"
+}
@@ -0,0 +1,38 @@
+package metadata
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// SettingStorage 项目配置存储
+type SettingStorage struct {
+	db *gorm.DB
+}
+
+// NewSettingStorage 创建项目配置存储
+func NewSettingStorage(db *gorm.DB) *SettingStorage {
+	return &SettingStorage{db: db}
+}
+
+// Get 读取配置项，不存在时返回 false
+func (s *SettingStorage) Get(key string) (string, bool, error) {
+	var row ProjectSetting
+	err := s.db.Where("key = ?", key).Limit(1).Find(&row).Error
+	if err != nil {
+		return "", false, err
+	}
+	if row.Key == "" {
+		return "", false, nil
+	}
+	return row.Value, true, nil
+}
+
+// Set 写入配置项（存在则覆盖）
+func (s *SettingStorage) Set(key, value string) error {
+	if key == "" {
+		return errors.New("setting key is required")
+	}
+	return s.db.Save(&ProjectSetting{Key: key, Value: value}).Error
+}
@@ -0,0 +1,16 @@
+package metadata
+
+import (
+	"time"
+)
+
+// ProjectSetting 项目级配置项，值为 JSON 文本
+type ProjectSetting struct {
+	Key       string    `gorm:"primaryKey;size:100" json:"key"`
+	Value     string    `gorm:"type:text" json:"value"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (ProjectSetting) TableName() string {
+	return "project_setting"
+}
@@ -564,7 +564,32 @@ func (v *VOMetadataStorage) DeleteVOByDatabaseID(databaseID int64) error {
 	return v.db.Where("database_id = ?", databaseID).Delete(&VOSchemaInfo{}).Error
 }
 
+// DeleteVODatabaseByID 删除数据库的VO数据及数据库记录
+func (v *VOMetadataStorage) DeleteVODatabaseByID(databaseID int64) error {
+	if err := v.DeleteVOByDatabaseID(databaseID); err != nil {
+		return err
+	}
+	return v.db.Where("id = ?", databaseID).Delete(&VODatabaseInfo{}).Error
+}
 
+// DeleteVOTablesByIDs 删除指定表及其字段的VO数据
+func (v *VOMetadataStorage) DeleteVOTablesByIDs(tableIDs []int64) error {
+	if len(tableIDs) == 0 {
+		return nil
+	}
+	if err := v.db.Where("table_id IN ?", tableIDs).Delete(&VOFieldInfo{}).Error; err != nil {
+		return err
+	}
+	return v.db.Where("id IN ?", tableIDs).Delete(&VOTableInfo{}).Error
+}
+
+// DeleteVOSchemasByIDs 删除指定Schema的VO数据
+func (v *VOMetadataStorage) DeleteVOSchemasByIDs(schemaIDs []int64) error {
+	if len(schemaIDs) == 0 {
+		return nil
+	}
+	return v.db.Where("id IN ?", schemaIDs).Delete(&VOSchemaInfo{}).Error
+}
 
 // UpdateTableRemarkByID 根据表ID更新备注
 func (v *VOMetadataStorage) UpdateTableRemarkByID(tableID int64, remark string) error {
@@ -671,6 +696,54 @@ func (v *VOMetadataStorage) GetTableRemarkByID(tableID int64) (string, error) {
     return t.Remark, nil
 }
 
+// GetAliasesByIDs 批量获取表与视图的别名，未设置别名的对象不在结果中
+func (v *VOMetadataStorage) GetAliasesByIDs(tableIDs []int64, viewIDs []int64) (map[int64]string, map[int64]string, error) {
+    tables, views := map[int64]string{}, map[int64]string{}
+    if len(tableIDs) > 0 {
+        var rows []VOTableInfo
+        if err := v.db.Select("id", "alias").Where("id IN ? AND alias <> ''", tableIDs).Find(&rows).Error; err != nil {
+            return nil, nil, err
+        }
+        for _, r := range rows {
+            tables[r.ID] = r.Alias
+        }
+    }
+    if len(viewIDs) > 0 {
+        var rows []VOViewInfo
+        if err := v.db.Select("id", "alias").Where("id IN ? AND alias <> ''", viewIDs).Find(&rows).Error; err != nil {
+            return nil, nil, err
+        }
+        for _, r := range rows {
+            views[r.ID] = r.Alias
+        }
+    }
+    return tables, views, nil
+}
+
+// GetColorsByIDs 批量获取表与视图设置的颜色，未设置颜色的对象不在结果中
+func (v *VOMetadataStorage) GetColorsByIDs(tableIDs []int64, viewIDs []int64) (map[int64]string, map[int64]string, error) {
+    tables, views := map[int64]string{}, map[int64]string{}
+    if len(tableIDs) > 0 {
+        var rows []VOTableInfo
+        if err := v.db.Select("id", "color").Where("id IN ? AND color <> ''", tableIDs).Find(&rows).Error; err != nil {
+            return nil, nil, err
+        }
+        for _, r := range rows {
+            tables[r.ID] = r.Color
+        }
+    }
+    if len(viewIDs) > 0 {
+        var rows []VOViewInfo
+        if err := v.db.Select("id", "color").Where("id IN ? AND color <> ''", viewIDs).Find(&rows).Error; err != nil {
+            return nil, nil, err
+        }
+        for _, r := range rows {
+            views[r.ID] = r.Color
+        }
+    }
+    return tables, views, nil
+}
+
 // （已移除）通过名称组合解析表ID的方法，前端与API均改为ID直连
 
 // UpdateFieldRemarkByTableIDName 根据表ID与字段名更新字段备注
@@ -726,4 +799,58 @@ func (v *VOMetadataStorage) GetDisplayVOByConfigID(configID int64) (*models.Disp
 
     // 已移除 VOStyle，样式返回空映射
     return display, nil
-}
\ No newline at end of file
+}
+
+// UpdateDictionaryVO 在同一事务中批量更新表与字段的 VO 扩展属性（如 remark、alias），
+// 键为表ID或字段ID；VO 记录不存在时按 Raw 信息懒创建，Raw 也不存在的对象将被忽略
+func (v *VOMetadataStorage) UpdateDictionaryVO(tables, fields map[int64]map[string]interface{}) error {
+    return v.db.Transaction(func(tx *gorm.DB) error {
+        for tableID, values := range tables {
+            res := tx.Model(&VOTableInfo{}).Where("id = ?", tableID).Updates(values)
+            if res.Error != nil {
+                return res.Error
+            }
+            if res.RowsAffected > 0 {
+                continue
+            }
+            var rt RawTableInfo
+            if err := tx.Where("id = ?", tableID).First(&rt).Error; err != nil {
+                if err == gorm.ErrRecordNotFound {
+                    continue
+                }
+                return err
+            }
+            vo := VOTableInfo{ID: tableID, DatabaseID: rt.DatabaseID, SchemaID: rt.SchemaID}
+            if err := tx.Create(&vo).Error; err != nil {
+                return err
+            }
+            if err := tx.Model(&VOTableInfo{}).Where("id = ?", tableID).Updates(values).Error; err != nil {
+                return err
+            }
+        }
+        for fieldID, values := range fields {
+            res := tx.Model(&VOFieldInfo{}).Where("id = ?", fieldID).Updates(values)
+            if res.Error != nil {
+                return res.Error
+            }
+            if res.RowsAffected > 0 {
+                continue
+            }
+            var rf RawFieldInfo
+            if err := tx.Where("id = ?", fieldID).First(&rf).Error; err != nil {
+                if err == gorm.ErrRecordNotFound {
+                    continue
+                }
+                return err
+            }
+            vo := VOFieldInfo{ID: fieldID, TableID: rf.TableID}
+            if err := tx.Create(&vo).Error; err != nil {
+                return err
+            }
+            if err := tx.Model(&VOFieldInfo{}).Where("id = ?", fieldID).Updates(values).Error; err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
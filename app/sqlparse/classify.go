@@ -0,0 +1,314 @@
+package sqlparse
+
+import (
+	"fmt"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// StatementKind 语句类别
+type StatementKind string
+
+const (
+	KindSelect      StatementKind = "select"      // 查询
+	KindShow        StatementKind = "show"        // SHOW/DESCRIBE 等元数据查询
+	KindExplain     StatementKind = "explain"     // 执行计划
+	KindDML         StatementKind = "dml"         // 数据修改
+	KindDDL         StatementKind = "ddl"         // 结构变更
+	KindDCL         StatementKind = "dcl"         // 权限控制
+	KindTransaction StatementKind = "transaction" // 事务控制
+	KindSession     StatementKind = "session"     // 会话设置（SET/USE）
+	KindProcedure   StatementKind = "procedure"   // 存储过程调用或匿名块
+	KindUnknown     StatementKind = "unknown"     // 无法识别
+)
+
+// Statement 单条语句的分类结果
+type Statement struct {
+	SQL      string        `json:"sql"`
+	Kind     StatementKind `json:"kind"`
+	Keyword  string        `json:"keyword"`  // 语句的主关键字，如 SELECT、UPDATE
+	ReadOnly bool          `json:"readOnly"` // 是否可在只读连接上执行
+	Reason   string        `json:"reason,omitempty"`
+}
+
+// ReadOnlyViolationError 只读连接上出现写语句时返回的错误
+type ReadOnlyViolationError struct {
+	Statement Statement
+}
+
+func (e *ReadOnlyViolationError) Error() string {
+	reason := e.Statement.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("%s statement is not allowed", e.Statement.Kind)
+	}
+	return fmt.Sprintf("read-only connection rejected %s statement: %s", e.Statement.Keyword, reason)
+}
+
+// Classify 按方言拆分并分类SQL中的每条语句
+// MySQL/MariaDB 使用 vitess sqlparser 解析，其余方言使用词法分析识别
+func Classify(dialect, sql string) ([]Statement, error) {
+	pieces := Split(dialect, sql)
+	if len(pieces) == 0 {
+		return nil, fmt.Errorf("empty SQL statement")
+	}
+	stmts := make([]Statement, 0, len(pieces))
+	for _, p := range pieces {
+		var st Statement
+		if dialect == DialectMySQL || dialect == DialectMariaDB {
+			st = classifyMySQL(p)
+		} else {
+			st = classifyTokens(dialect, p.Tokens)
+		}
+		st.SQL = p.SQL
+		stmts = append(stmts, st)
+	}
+	return stmts, nil
+}
+
+// CheckReadOnly 校验SQL是否全部为只读语句，否则返回 *ReadOnlyViolationError
+// 词法分类无法识别有副作用的函数（如 set_config），仅作为执行前的提示，不能代替只读事务
+func CheckReadOnly(dialect, sql string) ([]Statement, error) {
+	stmts, err := Classify(dialect, sql)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range stmts {
+		if !st.ReadOnly {
+			return stmts, &ReadOnlyViolationError{Statement: st}
+		}
+	}
+	return stmts, nil
+}
+
+// classifyMySQL 使用 vitess 解析 MySQL 语句，解析失败时退回词法分类
+func classifyMySQL(p Piece) Statement {
+	parser := sqlparser.NewTestParser()
+	stmt, err := parser.Parse(p.SQL)
+	if err != nil {
+		return classifyTokens(DialectMySQL, p.Tokens)
+	}
+	return classifyAST(stmt, p.Tokens)
+}
+
+func classifyAST(stmt sqlparser.Statement, tokens []Token) Statement {
+	keyword := ""
+	if len(tokens) > 0 {
+		keyword = tokens[0].Upper()
+	}
+	st := Statement{Keyword: keyword}
+	switch node := stmt.(type) {
+	case *sqlparser.Select:
+		st.Kind = KindSelect
+		st.ReadOnly = true
+		if node.Into != nil {
+			st.Kind, st.ReadOnly, st.Reason = KindDML, false, "SELECT ... INTO writes data"
+		} else if node.Lock != sqlparser.NoLock {
+			st.ReadOnly, st.Reason = false, "locking read (FOR UPDATE / LOCK IN SHARE MODE)"
+		}
+		return st
+	case *sqlparser.Union:
+		st.Kind = KindSelect
+		st.ReadOnly = true
+		if node.Into != nil {
+			st.Kind, st.ReadOnly, st.Reason = KindDML, false, "SELECT ... INTO writes data"
+		} else if node.Lock != sqlparser.NoLock {
+			st.ReadOnly, st.Reason = false, "locking read (FOR UPDATE / LOCK IN SHARE MODE)"
+		}
+		return st
+	case *sqlparser.ExplainStmt:
+		if node.Type == sqlparser.AnalyzeType {
+			// EXPLAIN ANALYZE 会真正执行语句，按内部语句判断
+			inner := classifyAST(node.Statement, tokens[1:])
+			inner.Keyword = keyword
+			if !inner.ReadOnly {
+				inner.Reason = "EXPLAIN ANALYZE executes the statement"
+			}
+			inner.Kind = KindExplain
+			return inner
+		}
+		st.Kind, st.ReadOnly = KindExplain, true
+		return st
+	}
+	switch sqlparser.ASTToStatementType(stmt) {
+	case sqlparser.StmtShow, sqlparser.StmtShowMigrationLogs:
+		st.Kind, st.ReadOnly = KindShow, true
+	case sqlparser.StmtExplain:
+		st.Kind, st.ReadOnly = KindExplain, true
+	case sqlparser.StmtUse:
+		st.Kind, st.ReadOnly = KindSession, true
+	case sqlparser.StmtCommentOnly:
+		st.Kind, st.ReadOnly = KindSelect, true
+	case sqlparser.StmtInsert, sqlparser.StmtReplace, sqlparser.StmtUpdate, sqlparser.StmtDelete:
+		st.Kind = KindDML
+	case sqlparser.StmtDDL, sqlparser.StmtRevert, sqlparser.StmtAnalyze, sqlparser.StmtFlush:
+		st.Kind = KindDDL
+	case sqlparser.StmtPriv:
+		st.Kind = KindDCL
+	case sqlparser.StmtBegin, sqlparser.StmtCommit, sqlparser.StmtRollback, sqlparser.StmtSavepoint,
+		sqlparser.StmtSRollback, sqlparser.StmtRelease, sqlparser.StmtLockTables, sqlparser.StmtUnlockTables:
+		st.Kind = KindTransaction
+	case sqlparser.StmtSet:
+		st.Kind, st.Reason = KindSession, "SET may change the session's read-only mode"
+	case sqlparser.StmtCallProc, sqlparser.StmtPrepare, sqlparser.StmtExecute, sqlparser.StmtDeallocate:
+		st.Kind, st.Reason = KindProcedure, "procedure calls and prepared statements cannot be verified"
+	default:
+		// vitess 能解析但无法归类的语句，交给词法分类兜底
+		return classifyTokens(DialectMySQL, tokens)
+	}
+	return st
+}
+
+// classifyTokens 基于首个关键字与少量上下文对语句进行分类（PostgreSQL/SQL Server/Oracle）
+func classifyTokens(dialect string, tokens []Token) Statement {
+	if len(tokens) == 0 {
+		return Statement{Kind: KindUnknown}
+	}
+	// SQL Server 习惯写法 ";WITH"，分词后首个单元可能是左括号
+	first := 0
+	for first < len(tokens) && tokens[first].IsPunct("(") {
+		first++
+	}
+	if first >= len(tokens) {
+		return Statement{Kind: KindUnknown, Keyword: tokens[0].Text}
+	}
+	head := tokens[first]
+	kw := head.Upper()
+	st := Statement{Keyword: kw}
+	switch kw {
+	case "SELECT":
+		return classifySelect(dialect, tokens[first:], st)
+	case "VALUES", "TABLE":
+		st.Kind, st.ReadOnly = KindSelect, true
+	case "WITH":
+		return classifyWith(dialect, tokens[first:], st)
+	case "SHOW", "DESCRIBE", "DESC":
+		st.Kind, st.ReadOnly = KindShow, true
+	case "EXPLAIN":
+		return classifyExplain(dialect, tokens[first:], st)
+	case "INSERT", "UPDATE", "DELETE", "MERGE", "UPSERT", "REPLACE", "COPY", "LOAD", "LOCK":
+		st.Kind = KindDML
+	case "CREATE", "ALTER", "DROP", "RENAME", "TRUNCATE", "COMMENT", "REINDEX", "CLUSTER",
+		"VACUUM", "ANALYZE", "REFRESH", "PURGE", "FLASHBACK", "IMPORT", "SECURITY":
+		st.Kind = KindDDL
+	case "GRANT", "REVOKE", "DENY":
+		st.Kind = KindDCL
+	case "BEGIN", "START", "COMMIT", "ROLLBACK", "SAVEPOINT", "RELEASE", "END", "ABORT", "PREPARE":
+		if kw == "BEGIN" && dialect == DialectOracle {
+			st.Kind, st.Reason = KindProcedure, "anonymous PL/SQL blocks cannot be verified"
+			break
+		}
+		st.Kind = KindTransaction
+	case "SET", "RESET", "DISCARD":
+		st.Kind, st.Reason = KindSession, "SET may change the session's read-only mode"
+	case "USE":
+		st.Kind, st.ReadOnly = KindSession, true
+	case "CALL", "EXEC", "EXECUTE", "DO", "DECLARE":
+		st.Kind, st.Reason = KindProcedure, "procedure calls and anonymous blocks cannot be verified"
+	default:
+		st.Kind, st.Reason = KindUnknown, "unrecognized statement"
+	}
+	return st
+}
+
+// classifySelect 识别 SELECT ... INTO（建表）与锁定读
+func classifySelect(dialect string, tokens []Token, st Statement) Statement {
+	st.Kind, st.ReadOnly = KindSelect, true
+	depth := 0
+	seenFrom := false
+	for i, t := range tokens {
+		switch {
+		case t.IsPunct("("):
+			depth++
+		case t.IsPunct(")"):
+			depth--
+		case depth != 0:
+		case t.IsWord("FROM"):
+			seenFrom = true
+		case t.IsWord("INTO") && !seenFrom && dialect != DialectOracle:
+			st.Kind, st.ReadOnly, st.Reason = KindDDL, false, "SELECT ... INTO writes data"
+			return st
+		case t.IsWord("FOR") && i+1 < len(tokens) && tokens[i+1].IsWord("UPDATE", "SHARE", "NO", "KEY"):
+			st.ReadOnly, st.Reason = false, "locking read (FOR UPDATE / FOR SHARE)"
+			return st
+		case dialect == DialectSQLServer && t.IsWord("WITH") && i+2 < len(tokens) && tokens[i+1].IsPunct("(") &&
+			tokens[i+2].IsWord("UPDLOCK", "XLOCK", "TABLOCKX", "HOLDLOCK"):
+			st.ReadOnly, st.Reason = false, "locking table hint"
+			return st
+		}
+	}
+	return st
+}
+
+// classifyWith 识别 CTE 的主语句，并检查 CTE 中是否包含数据修改语句
+func classifyWith(dialect string, tokens []Token, st Statement) Statement {
+	depth := 0
+	for i := 1; i < len(tokens); i++ {
+		t := tokens[i]
+		switch {
+		case t.IsPunct("("):
+			depth++
+			if i+1 < len(tokens) && tokens[i+1].IsWord("INSERT", "UPDATE", "DELETE", "MERGE") {
+				st.Kind, st.ReadOnly, st.Reason = KindDML, false, "data-modifying statement inside WITH"
+				return st
+			}
+		case t.IsPunct(")"):
+			depth--
+		case depth == 0 && t.IsWord("SELECT", "VALUES", "TABLE"):
+			sel := classifySelect(dialect, tokens[i:], st)
+			sel.Keyword = st.Keyword
+			return sel
+		case depth == 0 && t.IsWord("INSERT", "UPDATE", "DELETE", "MERGE"):
+			st.Kind, st.Reason = KindDML, "data-modifying statement after WITH"
+			return st
+		}
+	}
+	st.Kind, st.Reason = KindUnknown, "unable to find the main statement of WITH"
+	return st
+}
+
+// classifyExplain 处理 EXPLAIN；EXPLAIN ANALYZE 会执行语句，需要按内部语句判断
+func classifyExplain(dialect string, tokens []Token, st Statement) Statement {
+	st.Kind, st.ReadOnly = KindExplain, true
+	i := 1
+	analyze := false
+	if i < len(tokens) && tokens[i].IsPunct("(") {
+		depth := 0
+		for ; i < len(tokens); i++ {
+			if tokens[i].IsPunct("(") {
+				depth++
+			} else if tokens[i].IsPunct(")") {
+				depth--
+				if depth == 0 {
+					i++
+					break
+				}
+			} else if tokens[i].IsWord("ANALYZE", "ANALYSE") {
+				// EXPLAIN (ANALYZE false) 不执行语句
+				analyze = !(i+1 < len(tokens) && tokens[i+1].IsWord("FALSE", "OFF"))
+			}
+		}
+	}
+	for i < len(tokens) && tokens[i].IsWord("ANALYZE", "ANALYSE", "VERBOSE", "PLAN", "FOR", "SET", "STATEMENT_ID") {
+		if tokens[i].IsWord("ANALYZE", "ANALYSE") {
+			analyze = true
+		}
+		if tokens[i].IsWord("SET") {
+			// Oracle: EXPLAIN PLAN SET STATEMENT_ID = 'x' FOR ...
+			for i < len(tokens) && !tokens[i].IsWord("FOR") {
+				i++
+			}
+			continue
+		}
+		i++
+	}
+	if !analyze || i >= len(tokens) {
+		return st
+	}
+	inner := classifyTokens(dialect, tokens[i:])
+	if !inner.ReadOnly {
+		st.ReadOnly = false
+		st.Reason = "EXPLAIN ANALYZE executes the statement: " + strings.ToLower(inner.Keyword)
+	}
+	return st
+}
@@ -0,0 +1,349 @@
+package sqlparse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 方言名称，与 connect.Config.Type 保持一致
+const (
+	DialectMySQL      = "mysql"
+	DialectMariaDB    = "mariadb"
+	DialectPostgreSQL = "postgresql"
+	DialectSQLServer  = "sqlserver"
+	DialectOracle     = "oracle"
+)
+
+// TokenKind 词法单元类型
+type TokenKind int
+
+const (
+	TokenWord        TokenKind = iota // 关键字或未加引号的标识符
+	TokenQuotedIdent                  // 加引号的标识符（"a"、`a`、[a]）
+	TokenString                       // 字符串字面量（含 $$ 与 q'[]'）
+	TokenNumber                       // 数字
+	TokenParam                        // 参数占位符（?、$1、:name、@p1）
+	TokenPunct                        // 标点与运算符
+)
+
+// Token 词法单元，Start/End 为原始SQL中的字节偏移
+type Token struct {
+	Kind  TokenKind
+	Text  string
+	Start int
+	End   int
+}
+
+// Upper 返回大写文本，便于关键字比较
+func (t Token) Upper() string {
+	return strings.ToUpper(t.Text)
+}
+
+// IsWord 判断是否为指定关键字（大小写不敏感）
+func (t Token) IsWord(words ...string) bool {
+	if t.Kind != TokenWord {
+		return false
+	}
+	for _, w := range words {
+		if strings.EqualFold(t.Text, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPunct 判断是否为指定标点
+func (t Token) IsPunct(p string) bool {
+	return t.Kind == TokenPunct && t.Text == p
+}
+
+// Ident 返回标识符的实际名称（去掉引号）
+func (t Token) Ident() string {
+	if t.Kind != TokenQuotedIdent || len(t.Text) < 2 {
+		return t.Text
+	}
+	inner := t.Text[1 : len(t.Text)-1]
+	switch t.Text[0] {
+	case '"':
+		return strings.ReplaceAll(inner, `""`, `"`)
+	case '`':
+		return strings.ReplaceAll(inner, "``", "`")
+	case '[':
+		return strings.ReplaceAll(inner, "]]", "]")
+	}
+	return inner
+}
+
+// Tokenize 按方言对SQL进行分词，注释会被丢弃
+func Tokenize(dialect, sql string) []Token {
+	lx := &lexer{dialect: dialect, src: sql}
+	return lx.run()
+}
+
+type lexer struct {
+	dialect string
+	src     string
+	pos     int
+	tokens  []Token
+}
+
+func (l *lexer) isMySQL() bool {
+	return l.dialect == DialectMySQL || l.dialect == DialectMariaDB
+}
+
+func (l *lexer) peek(off int) byte {
+	if l.pos+off < len(l.src) {
+		return l.src[l.pos+off]
+	}
+	return 0
+}
+
+func (l *lexer) emit(kind TokenKind, start int) {
+	l.tokens = append(l.tokens, Token{Kind: kind, Text: l.src[start:l.pos], Start: start, End: l.pos})
+}
+
+func (l *lexer) run() []Token {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			l.pos++
+		case c == '-' && l.peek(1) == '-':
+			l.skipLine()
+		case c == '#' && l.isMySQL():
+			l.skipLine()
+		case c == '/' && l.peek(1) == '*':
+			l.skipBlockComment()
+		case c == '\'':
+			l.readQuoted('\'', TokenString)
+		case (c == 'N' || c == 'n' || c == 'E' || c == 'e' || c == 'B' || c == 'b' || c == 'X' || c == 'x') && l.peek(1) == '\'':
+			start := l.pos
+			l.pos++
+			l.readQuoted('\'', TokenString)
+			l.tokens[len(l.tokens)-1].Start = start
+			l.tokens[len(l.tokens)-1].Text = l.src[start:l.pos]
+		case (c == 'q' || c == 'Q') && l.peek(1) == '\'' && l.dialect == DialectOracle:
+			l.readOracleQ()
+		case c == '"':
+			if l.isMySQL() {
+				l.readQuoted('"', TokenString)
+			} else {
+				l.readQuoted('"', TokenQuotedIdent)
+			}
+		case c == '`':
+			l.readQuoted('`', TokenQuotedIdent)
+		case c == '[' && l.dialect == DialectSQLServer:
+			l.readQuoted(']', TokenQuotedIdent)
+		case c == '$' && l.dialect == DialectPostgreSQL:
+			l.readDollar()
+		case c >= '0' && c <= '9', c == '.' && l.peek(1) >= '0' && l.peek(1) <= '9':
+			l.readNumber()
+		case c == '?':
+			start := l.pos
+			l.pos++
+			l.emit(TokenParam, start)
+		case c == ':' && isIdentStart(l.peek(1)) && l.dialect == DialectOracle:
+			start := l.pos
+			l.pos++
+			l.readIdentRest()
+			l.emit(TokenParam, start)
+		case c == '@' && l.dialect == DialectSQLServer && isIdentStart(l.peek(1)):
+			start := l.pos
+			l.pos++
+			l.readIdentRest()
+			l.emit(TokenParam, start)
+		case isIdentStart(c) || c >= utf8.RuneSelf:
+			start := l.pos
+			l.readIdentRest()
+			if l.pos == start {
+				// 非标识符的多字节字符，按单个标点处理，避免死循环
+				_, size := utf8.DecodeRuneInString(l.src[l.pos:])
+				l.pos += size
+				l.emit(TokenPunct, start)
+			} else {
+				l.emit(TokenWord, start)
+			}
+		default:
+			l.readPunct()
+		}
+	}
+	return l.tokens
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func (l *lexer) readIdentRest() {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c < utf8.RuneSelf {
+			if isIdentStart(c) || (c >= '0' && c <= '9') || c == '$' || (c == '#' && l.dialect == DialectOracle) {
+				l.pos++
+				continue
+			}
+			return
+		}
+		r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return
+		}
+		l.pos += size
+	}
+}
+
+func (l *lexer) skipLine() {
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.pos++
+	}
+}
+
+func (l *lexer) skipBlockComment() {
+	depth := 0
+	for l.pos < len(l.src) {
+		if l.src[l.pos] == '/' && l.peek(1) == '*' {
+			depth++
+			l.pos += 2
+			// 只有 PostgreSQL 支持嵌套注释
+			if l.dialect != DialectPostgreSQL && depth > 1 {
+				depth = 1
+			}
+			continue
+		}
+		if l.src[l.pos] == '*' && l.peek(1) == '/' {
+			depth--
+			l.pos += 2
+			if depth == 0 {
+				return
+			}
+			continue
+		}
+		l.pos++
+	}
+}
+
+// readQuoted 读取以 quote 结束的引号内容，支持重复引号转义；MySQL 字符串还支持反斜杠转义
+func (l *lexer) readQuoted(closer byte, kind TokenKind) {
+	start := l.pos
+	l.pos++
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '\\' && kind == TokenString && l.isMySQL() {
+			l.pos += 2
+			continue
+		}
+		if c == closer {
+			if l.peek(1) == closer {
+				l.pos += 2
+				continue
+			}
+			l.pos++
+			break
+		}
+		l.pos++
+	}
+	if l.pos > len(l.src) {
+		l.pos = len(l.src)
+	}
+	l.emit(kind, start)
+}
+
+// readDollar 读取 PostgreSQL 的 $n 参数或 $tag$...$tag$ 字符串
+func (l *lexer) readDollar() {
+	start := l.pos
+	if c := l.peek(1); c >= '0' && c <= '9' {
+		l.pos++
+		for l.pos < len(l.src) && l.src[l.pos] >= '0' && l.src[l.pos] <= '9' {
+			l.pos++
+		}
+		l.emit(TokenParam, start)
+		return
+	}
+	end := l.pos + 1
+	for end < len(l.src) && (isIdentStart(l.src[end]) || (l.src[end] >= '0' && l.src[end] <= '9')) {
+		end++
+	}
+	if end >= len(l.src) || l.src[end] != '$' {
+		l.pos++
+		l.emit(TokenPunct, start)
+		return
+	}
+	tag := l.src[start : end+1]
+	idx := strings.Index(l.src[end+1:], tag)
+	if idx < 0 {
+		l.pos = len(l.src)
+	} else {
+		l.pos = end + 1 + idx + len(tag)
+	}
+	l.emit(TokenString, start)
+}
+
+// readOracleQ 读取 Oracle 的 q'[...]' 替代引号字符串
+func (l *lexer) readOracleQ() {
+	start := l.pos
+	if l.pos+2 >= len(l.src) {
+		l.pos = len(l.src)
+		l.emit(TokenString, start)
+		return
+	}
+	open := l.src[l.pos+2]
+	closer := open
+	switch open {
+	case '[':
+		closer = ']'
+	case '{':
+		closer = '}'
+	case '(':
+		closer = ')'
+	case '<':
+		closer = '>'
+	}
+	idx := strings.Index(l.src[l.pos+3:], string(closer)+"'")
+	if idx < 0 {
+		l.pos = len(l.src)
+	} else {
+		l.pos = l.pos + 3 + idx + 2
+	}
+	l.emit(TokenString, start)
+}
+
+func (l *lexer) readNumber() {
+	start := l.pos
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if (c >= '0' && c <= '9') || c == '.' {
+			l.pos++
+			continue
+		}
+		if (c == 'e' || c == 'E') && l.pos > start {
+			next := l.peek(1)
+			if next == '+' || next == '-' {
+				l.pos += 2
+				continue
+			}
+			if next >= '0' && next <= '9' {
+				l.pos++
+				continue
+			}
+		}
+		break
+	}
+	l.emit(TokenNumber, start)
+}
+
+// 多字符运算符，按长度优先匹配
+var multiPuncts = []string{"::", "<=>", "<>", "!=", "<=", ">=", "||", "->>", "->", ":=", "=>", "<<", ">>"}
+
+func (l *lexer) readPunct() {
+	start := l.pos
+	for _, p := range multiPuncts {
+		if strings.HasPrefix(l.src[l.pos:], p) {
+			l.pos += len(p)
+			l.emit(TokenPunct, start)
+			return
+		}
+	}
+	l.pos++
+	l.emit(TokenPunct, start)
+}
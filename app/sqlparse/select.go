@@ -0,0 +1,169 @@
+package sqlparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExprKind 表达式类别
+type ExprKind string
+
+const (
+	ExprColumn   ExprKind = "column"   // 列引用
+	ExprLiteral  ExprKind = "literal"  // 字面量
+	ExprFunc     ExprKind = "func"     // 函数/聚合调用
+	ExprCast     ExprKind = "cast"     // 类型转换
+	ExprCase     ExprKind = "case"     // CASE 表达式
+	ExprArith    ExprKind = "arith"    // 算术/位运算
+	ExprConcat   ExprKind = "concat"   // 字符串拼接运算（|| 或 SQL Server 的 +）
+	ExprCompare  ExprKind = "compare"  // 比较/逻辑运算，结果为布尔
+	ExprSubquery ExprKind = "subquery" // 标量子查询
+	ExprOther    ExprKind = "other"    // 无法细分的表达式
+)
+
+// Expr 与方言无关的表达式结构，只保留推断类型与血缘所需的信息
+type Expr struct {
+	Kind      ExprKind    `json:"kind"`
+	Name      string      `json:"name,omitempty"`      // 列名或函数名（函数名为大写）
+	Qualifier string      `json:"qualifier,omitempty"` // 列的表限定（别名或表名）
+	Type      string      `json:"type,omitempty"`      // CAST 目标类型或字面量类型
+	Args      []*Expr     `json:"args,omitempty"`      // 参数；CASE 为各分支结果（含 ELSE）
+	Conds     []*Expr     `json:"conds,omitempty"`     // CASE 的条件部分（简单 CASE 含比较对象）
+	HasElse   bool        `json:"hasElse,omitempty"`   // CASE 是否带 ELSE 分支
+	Subquery  *SelectInfo `json:"subquery,omitempty"`
+	Text      string      `json:"text"` // 原始表达式文本
+}
+
+// SelectColumn 查询输出列
+type SelectColumn struct {
+	Name          string `json:"name"` // 输出列名（别名优先）
+	Expr          *Expr  `json:"expr,omitempty"`
+	Star          bool   `json:"star"`                    // * 或 t.*
+	StarQualifier string `json:"starQualifier,omitempty"` // t.* 中的 t
+}
+
+// TableSource FROM/JOIN 中的数据来源
+type TableSource struct {
+	Database string      `json:"database,omitempty"`
+	Schema   string      `json:"schema,omitempty"`
+	Name     string      `json:"name,omitempty"`
+	Alias    string      `json:"alias,omitempty"`
+	Join     string      `json:"join,omitempty"` // 与前面来源的连接方式：INNER/LEFT/RIGHT/FULL/CROSS，首个来源为空
+	On       *Expr       `json:"on,omitempty"`
+	Using    []string    `json:"using,omitempty"`
+	Subquery *SelectInfo `json:"subquery,omitempty"` // 派生表或 CTE
+	Columns  []string    `json:"columns,omitempty"`  // 派生表的列别名，如 AS t(a, b)
+	Nullable bool        `json:"nullable"`           // 处于外连接的可空一侧
+}
+
+// SelectInfo 解析后的查询结构；UNION 等集合运算的输出列取自最左侧的查询，其余分支保存在 Unions 中
+type SelectInfo struct {
+	Columns  []SelectColumn `json:"columns"`
+	Tables   []TableSource  `json:"tables"`
+	Where    *Expr          `json:"where,omitempty"`
+	Unions   []*SelectInfo  `json:"unions,omitempty"`
+	Aliases  []string       `json:"aliases,omitempty"` // 按位置重命名输出列（CTE 或视图的列清单）
+	Warnings []string       `json:"warnings,omitempty"`
+}
+
+// RefName 返回来源在查询中的引用名（别名优先）
+func (t *TableSource) RefName() string {
+	if t.Alias != "" {
+		return t.Alias
+	}
+	return t.Name
+}
+
+// Matches 判断列限定符是否指向该来源（忽略大小写，支持 schema.table 形式）
+func (t *TableSource) Matches(qualifier string) bool {
+	if qualifier == "" {
+		return false
+	}
+	if t.Alias != "" {
+		return strings.EqualFold(t.Alias, qualifier)
+	}
+	if strings.EqualFold(t.Name, qualifier) {
+		return true
+	}
+	idx := strings.LastIndex(qualifier, ".")
+	return idx >= 0 && strings.EqualFold(t.Name, qualifier[idx+1:])
+}
+
+// ColumnRefs 收集表达式中引用的列（不进入子查询）
+func (e *Expr) ColumnRefs() []*Expr {
+	if e == nil {
+		return nil
+	}
+	if e.Kind == ExprColumn {
+		return []*Expr{e}
+	}
+	var refs []*Expr
+	for _, c := range e.Conds {
+		refs = append(refs, c.ColumnRefs()...)
+	}
+	for _, a := range e.Args {
+		refs = append(refs, a.ColumnRefs()...)
+	}
+	return refs
+}
+
+// markOuterJoins 根据连接类型标记可空一侧的来源
+func markOuterJoins(tables []TableSource) {
+	for i := range tables {
+		switch tables[i].Join {
+		case "LEFT":
+			tables[i].Nullable = true
+		case "RIGHT":
+			for j := 0; j < i; j++ {
+				tables[j].Nullable = true
+			}
+		case "FULL":
+			tables[i].Nullable = true
+			for j := 0; j < i; j++ {
+				tables[j].Nullable = true
+			}
+		}
+	}
+}
+
+// cteScope WITH 子句定义的命名查询，内层作用域可覆盖外层同名定义
+type cteScope struct {
+	parent *cteScope
+	defs   map[string]*SelectInfo
+}
+
+func newCTEScope(parent *cteScope) *cteScope {
+	return &cteScope{parent: parent, defs: map[string]*SelectInfo{}}
+}
+
+func (s *cteScope) define(name string, info *SelectInfo) {
+	s.defs[strings.ToLower(name)] = info
+}
+
+func (s *cteScope) lookup(name string) *SelectInfo {
+	for sc := s; sc != nil; sc = sc.parent {
+		if info, ok := sc.defs[strings.ToLower(name)]; ok {
+			return info
+		}
+	}
+	return nil
+}
+
+// ParseSelect 按方言解析 SELECT 语句（可带 CREATE VIEW 头部），返回输出列与数据来源；
+// 部分内容无法解析时仍返回已解析的部分，并在 Warnings 中说明
+func ParseSelect(dialect, sql string) (*SelectInfo, error) {
+	switch dialect {
+	case DialectMySQL, DialectMariaDB:
+		info, err := parseMySQLSelect(sql)
+		if err == nil {
+			return info, nil
+		}
+		fallback, ferr := parseTokenSelect(dialect, sql)
+		if ferr != nil {
+			return nil, err
+		}
+		fallback.Warnings = append([]string{fmt.Sprintf("mysql parser failed, used tolerant parser: %v", err)}, fallback.Warnings...)
+		return fallback, nil
+	}
+	return parseTokenSelect(dialect, sql)
+}
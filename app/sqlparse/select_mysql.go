@@ -0,0 +1,397 @@
+package sqlparse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"vitess.io/vitess/go/vt/sqlparser"
+)
+
+// parseMySQLSelect 使用 vitess 解析 MySQL 语法的 SELECT（或 CREATE VIEW）
+func parseMySQLSelect(sql string) (*SelectInfo, error) {
+	parser := sqlparser.NewTestParser()
+	stmt, err := parser.Parse(sql)
+	if err != nil {
+		return nil, fmt.Errorf("解析SQL失败: %w", err)
+	}
+	var aliases []string
+	if cv, ok := stmt.(*sqlparser.CreateView); ok {
+		for _, c := range cv.Columns {
+			aliases = append(aliases, c.String())
+		}
+		stmt = cv.Select
+	}
+	sel, ok := stmt.(sqlparser.SelectStatement)
+	if !ok {
+		return nil, fmt.Errorf("无法解析非SELECT语句，当前语句类型: %T", stmt)
+	}
+	c := &mysqlConverter{scope: newCTEScope(nil)}
+	info := c.convertSelectStatement(sel)
+	if info == nil {
+		return nil, fmt.Errorf("unsupported select statement: %T", sel)
+	}
+	info.Aliases = aliases
+	return info, nil
+}
+
+// mysqlConverter 将 vitess 的 AST 转换为 SelectInfo，并维护 CTE 作用域
+type mysqlConverter struct {
+	scope *cteScope
+}
+
+// convertSelectStatement 转换 SELECT 或 UNION；集合运算的输出列取自最左侧查询
+func (c *mysqlConverter) convertSelectStatement(stmt sqlparser.SelectStatement) *SelectInfo {
+	switch s := stmt.(type) {
+	case *sqlparser.Select:
+		return c.withCTEs(s.With, func() *SelectInfo { return c.convertSelect(s) })
+	case *sqlparser.Union:
+		return c.withCTEs(s.With, func() *SelectInfo {
+			left := c.convertSelectStatement(s.Left)
+			if left == nil {
+				return nil
+			}
+			if right := c.convertSelectStatement(s.Right); right != nil {
+				left.Unions = append(left.Unions, right)
+			}
+			return left
+		})
+	}
+	return nil
+}
+
+// withCTEs 在 WITH 子句定义的作用域内执行转换；递归 CTE 在定义完成前以空查询占位
+func (c *mysqlConverter) withCTEs(with *sqlparser.With, fn func() *SelectInfo) *SelectInfo {
+	if with == nil {
+		return fn()
+	}
+	outer := c.scope
+	c.scope = newCTEScope(outer)
+	defer func() { c.scope = outer }()
+	for _, cte := range with.CTEs {
+		name := cte.ID.String()
+		c.scope.define(name, &SelectInfo{})
+		info := c.convertSelectStatement(cte.Subquery)
+		if info == nil {
+			info = &SelectInfo{Warnings: []string{fmt.Sprintf("cannot parse CTE %s", name)}}
+		}
+		for _, col := range cte.Columns {
+			info.Aliases = append(info.Aliases, col.String())
+		}
+		c.scope.define(name, info)
+	}
+	return fn()
+}
+
+// convertSelect 将 vitess 的 Select 转换为 SelectInfo
+func (c *mysqlConverter) convertSelect(sel *sqlparser.Select) *SelectInfo {
+	info := &SelectInfo{}
+	for i, te := range sel.From {
+		join := ""
+		if i > 0 {
+			join = "CROSS"
+		}
+		info.Tables = append(info.Tables, c.convertTableExpr(te, join)...)
+	}
+	markOuterJoins(info.Tables)
+	if sel.Where != nil {
+		info.Where = c.convertExpr(sel.Where.Expr)
+	}
+
+	for _, se := range sel.SelectExprs {
+		switch expr := se.(type) {
+		case *sqlparser.StarExpr:
+			col := SelectColumn{Name: "*", Star: true}
+			if !expr.TableName.IsEmpty() {
+				col.StarQualifier = expr.TableName.Name.String()
+				col.Name = sqlparser.String(expr)
+			}
+			info.Columns = append(info.Columns, col)
+		case *sqlparser.AliasedExpr:
+			col := SelectColumn{Expr: c.convertExpr(expr.Expr)}
+			switch {
+			case !expr.As.IsEmpty():
+				col.Name = expr.As.String()
+			case col.Expr.Kind == ExprColumn:
+				col.Name = col.Expr.Name
+			default:
+				col.Name = sqlparser.String(expr.Expr)
+			}
+			info.Columns = append(info.Columns, col)
+		default:
+			text := sqlparser.String(expr)
+			info.Columns = append(info.Columns, SelectColumn{Name: text, Expr: &Expr{Kind: ExprOther, Text: text}})
+		}
+	}
+	return info
+}
+
+// convertTableExpr 展开 FROM 中的表达式，join 为该来源与前面来源的连接方式
+func (c *mysqlConverter) convertTableExpr(te sqlparser.TableExpr, join string) []TableSource {
+	switch t := te.(type) {
+	case *sqlparser.AliasedTableExpr:
+		src := TableSource{Join: join, Alias: t.As.String()}
+		for _, col := range t.Columns {
+			src.Columns = append(src.Columns, col.String())
+		}
+		switch e := t.Expr.(type) {
+		case sqlparser.TableName:
+			src.Name = e.Name.String()
+			src.Database = e.Qualifier.String()
+			if src.Database == "" {
+				src.Subquery = c.scope.lookup(src.Name)
+			}
+		case *sqlparser.DerivedTable:
+			src.Subquery = c.convertSelectStatement(e.Select)
+		}
+		return []TableSource{src}
+	case *sqlparser.JoinTableExpr:
+		left := c.convertTableExpr(t.LeftExpr, join)
+		right := c.convertTableExpr(t.RightExpr, mysqlJoinType(t.Join))
+		if len(right) > 0 && t.Condition != nil {
+			if t.Condition.On != nil {
+				right[0].On = c.convertExpr(t.Condition.On)
+			}
+			for _, u := range t.Condition.Using {
+				right[0].Using = append(right[0].Using, u.String())
+			}
+		}
+		return append(left, right...)
+	case *sqlparser.ParenTableExpr:
+		var out []TableSource
+		for i, e := range t.Exprs {
+			j := join
+			if i > 0 {
+				j = "CROSS"
+			}
+			out = append(out, c.convertTableExpr(e, j)...)
+		}
+		return out
+	}
+	return nil
+}
+
+func mysqlJoinType(j sqlparser.JoinType) string {
+	switch j {
+	case sqlparser.LeftJoinType, sqlparser.NaturalLeftJoinType:
+		return "LEFT"
+	case sqlparser.RightJoinType, sqlparser.NaturalRightJoinType:
+		return "RIGHT"
+	default:
+		return "INNER"
+	}
+}
+
+// 形如 name(...) 的表达式文本，用于识别未单独建模的函数
+var funcCallPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*\(`)
+
+// convertExpr 将 vitess 表达式转换为 Expr
+func (c *mysqlConverter) convertExpr(e sqlparser.Expr) *Expr {
+	if e == nil {
+		return nil
+	}
+	out := &Expr{Text: sqlparser.String(e)}
+	switch v := e.(type) {
+	case *sqlparser.ColName:
+		out.Kind = ExprColumn
+		out.Name = v.Name.String()
+		out.Qualifier = v.Qualifier.Name.String()
+	case *sqlparser.Literal:
+		out.Kind = ExprLiteral
+		out.Type = mysqlLiteralType(v.Type)
+	case *sqlparser.NullVal:
+		out.Kind = ExprLiteral
+		out.Type = "NULL"
+	case sqlparser.BoolVal:
+		out.Kind = ExprLiteral
+		out.Type = "BOOLEAN"
+	case *sqlparser.CastExpr:
+		out.Kind = ExprCast
+		out.Type = mysqlCastType(v.Type)
+		out.Args = []*Expr{c.convertExpr(v.Expr)}
+	case *sqlparser.ConvertExpr:
+		out.Kind = ExprCast
+		out.Type = mysqlCastType(v.Type)
+		out.Args = []*Expr{c.convertExpr(v.Expr)}
+	case *sqlparser.ConvertUsingExpr:
+		out.Kind = ExprCast
+		out.Type = "VARCHAR"
+		out.Args = []*Expr{c.convertExpr(v.Expr)}
+	case *sqlparser.CollateExpr:
+		return withText(c.convertExpr(v.Expr), out.Text)
+	case *sqlparser.IntroducerExpr:
+		return withText(c.convertExpr(v.Expr), out.Text)
+	case *sqlparser.FuncExpr:
+		out.Kind = ExprFunc
+		out.Name = strings.ToUpper(v.Name.String())
+		for _, a := range v.Exprs {
+			out.Args = append(out.Args, c.convertExpr(a))
+		}
+	case *sqlparser.CountStar:
+		out.Kind = ExprFunc
+		out.Name = "COUNT"
+	case *sqlparser.GroupConcatExpr:
+		out.Kind = ExprFunc
+		out.Name = "GROUP_CONCAT"
+		for _, a := range v.Exprs {
+			out.Args = append(out.Args, c.convertExpr(a))
+		}
+	case sqlparser.AggrFunc:
+		out.Kind = ExprFunc
+		out.Name = strings.ToUpper(v.AggrName())
+		for _, a := range v.GetArgs() {
+			out.Args = append(out.Args, c.convertExpr(a))
+		}
+	case *sqlparser.CurTimeFuncExpr:
+		out.Kind = ExprFunc
+		out.Name = strings.ToUpper(v.Name.String())
+	case *sqlparser.SubstrExpr:
+		out.Kind = ExprFunc
+		out.Name = "SUBSTRING"
+		out.Args = compactExprs(c.convertExpr(v.Name), c.convertExpr(v.From), c.convertExpr(v.To))
+	case *sqlparser.CaseExpr:
+		out.Kind = ExprCase
+		if v.Expr != nil {
+			out.Conds = append(out.Conds, c.convertExpr(v.Expr))
+		}
+		for _, w := range v.Whens {
+			out.Conds = append(out.Conds, c.convertExpr(w.Cond))
+			out.Args = append(out.Args, c.convertExpr(w.Val))
+		}
+		if v.Else != nil {
+			out.HasElse = true
+			out.Args = append(out.Args, c.convertExpr(v.Else))
+		}
+	case *sqlparser.BinaryExpr:
+		out.Kind = ExprArith
+		out.Name = v.Operator.ToString()
+		out.Args = []*Expr{c.convertExpr(v.Left), c.convertExpr(v.Right)}
+	case *sqlparser.UnaryExpr:
+		out.Kind = ExprArith
+		out.Args = []*Expr{c.convertExpr(v.Expr)}
+	case *sqlparser.ComparisonExpr:
+		out.Kind = ExprCompare
+		out.Name = strings.ToUpper(v.Operator.ToString())
+		out.Args = []*Expr{c.convertExpr(v.Left), c.convertExpr(v.Right)}
+	case *sqlparser.AndExpr:
+		out.Kind = ExprCompare
+		out.Name = "AND"
+		out.Args = []*Expr{c.convertExpr(v.Left), c.convertExpr(v.Right)}
+	case *sqlparser.OrExpr:
+		out.Kind = ExprCompare
+		out.Name = "OR"
+		out.Args = []*Expr{c.convertExpr(v.Left), c.convertExpr(v.Right)}
+	case *sqlparser.XorExpr:
+		out.Kind = ExprCompare
+		out.Name = "XOR"
+		out.Args = []*Expr{c.convertExpr(v.Left), c.convertExpr(v.Right)}
+	case *sqlparser.NotExpr:
+		out.Kind = ExprCompare
+		out.Name = "NOT"
+		out.Args = []*Expr{c.convertExpr(v.Expr)}
+	case *sqlparser.IsExpr:
+		out.Kind = ExprCompare
+		out.Name = "IS"
+		out.Args = []*Expr{c.convertExpr(v.Left)}
+	case *sqlparser.BetweenExpr:
+		out.Kind = ExprCompare
+		out.Name = "BETWEEN"
+		out.Args = []*Expr{c.convertExpr(v.Left), c.convertExpr(v.From), c.convertExpr(v.To)}
+	case *sqlparser.ExistsExpr:
+		out.Kind = ExprCompare
+		out.Name = "EXISTS"
+		out.Args = []*Expr{c.convertExpr(v.Subquery)}
+	case *sqlparser.Subquery:
+		out.Kind = ExprSubquery
+		out.Subquery = c.convertSelectStatement(v.Select)
+	default:
+		out.Kind = ExprOther
+		if m := funcCallPattern.FindStringSubmatch(out.Text); m != nil {
+			out.Kind = ExprFunc
+			out.Name = strings.ToUpper(m[1])
+		}
+		out.Args = c.collectColumns(e)
+	}
+	return out
+}
+
+// withText 保留外层表达式的原始文本
+func withText(e *Expr, text string) *Expr {
+	if e != nil {
+		e.Text = text
+	}
+	return e
+}
+
+func compactExprs(exprs ...*Expr) []*Expr {
+	out := exprs[:0]
+	for _, e := range exprs {
+		if e != nil {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// collectColumns 收集表达式中的列引用（不进入子查询）
+func (c *mysqlConverter) collectColumns(e sqlparser.Expr) []*Expr {
+	var cols []*Expr
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch n := node.(type) {
+		case *sqlparser.ColName:
+			cols = append(cols, c.convertExpr(n))
+			return false, nil
+		case *sqlparser.Subquery:
+			return false, nil
+		}
+		return true, nil
+	}, e)
+	return cols
+}
+
+func mysqlLiteralType(t sqlparser.ValType) string {
+	switch t {
+	case sqlparser.StrVal:
+		return "VARCHAR"
+	case sqlparser.IntVal:
+		return "BIGINT"
+	case sqlparser.DecimalVal:
+		return "DECIMAL"
+	case sqlparser.FloatVal:
+		return "DOUBLE"
+	case sqlparser.HexNum, sqlparser.HexVal, sqlparser.BitNum:
+		return "VARBINARY"
+	case sqlparser.DateVal:
+		return "DATE"
+	case sqlparser.TimeVal:
+		return "TIME"
+	case sqlparser.TimestampVal:
+		return "DATETIME"
+	}
+	return "UNKNOWN"
+}
+
+// mysqlCastType 将 CAST/CONVERT 的目标类型转换为列类型写法
+func mysqlCastType(ct *sqlparser.ConvertType) string {
+	if ct == nil {
+		return "UNKNOWN"
+	}
+	name := strings.ToUpper(ct.Type)
+	switch name {
+	case "SIGNED", "SIGNED INTEGER":
+		return "BIGINT"
+	case "UNSIGNED", "UNSIGNED INTEGER":
+		return "BIGINT UNSIGNED"
+	case "CHAR", "NCHAR":
+		name = "VARCHAR"
+	case "BINARY":
+		name = "VARBINARY"
+	}
+	if ct.Length != nil {
+		if ct.Scale != nil {
+			return fmt.Sprintf("%s(%d,%d)", name, *ct.Length, *ct.Scale)
+		}
+		return fmt.Sprintf("%s(%d)", name, *ct.Length)
+	}
+	return name
+}
@@ -0,0 +1,1239 @@
+package sqlparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 结束标记，peek 越界时返回
+const tokenEOF TokenKind = -1
+
+// 不能作为隐式别名的关键字
+var reservedAliasWords = map[string]bool{
+	"FROM": true, "WHERE": true, "GROUP": true, "ORDER": true, "HAVING": true, "UNION": true,
+	"INTERSECT": true, "EXCEPT": true, "MINUS": true, "LIMIT": true, "OFFSET": true, "FETCH": true,
+	"FOR": true, "INTO": true, "ON": true, "USING": true, "JOIN": true, "INNER": true, "LEFT": true,
+	"RIGHT": true, "FULL": true, "OUTER": true, "CROSS": true, "NATURAL": true, "WINDOW": true,
+	"CONNECT": true, "START": true, "WITH": true, "AS": true, "AND": true, "OR": true, "NOT": true,
+	"IS": true, "IN": true, "LIKE": true, "ILIKE": true, "BETWEEN": true, "THEN": true, "ELSE": true,
+	"END": true, "WHEN": true, "CASE": true, "SELECT": true, "APPLY": true, "PIVOT": true,
+	"UNPIVOT": true, "TABLESAMPLE": true, "QUALIFY": true, "RETURNING": true, "OPTION": true,
+	"VALUES": true, "LATERAL": true, "SAMPLE": true, "MODEL": true, "KEEP": true, "ESCAPE": true,
+}
+
+// 集合运算与查询尾部子句，标志一个 SELECT 主体的结束
+var selectEndWords = []string{"UNION", "INTERSECT", "EXCEPT", "MINUS", "ORDER", "LIMIT", "OFFSET", "FETCH", "FOR", "OPTION"}
+
+// 不带括号也可调用的函数
+var niladicFuncs = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "LOCALTIME": true,
+	"LOCALTIMESTAMP": true, "SYSDATE": true, "SYSTIMESTAMP": true, "CURRENT_USER": true,
+	"SESSION_USER": true, "USER": true, "ROWNUM": true, "LEVEL": true,
+}
+
+// tokenParser 基于词法单元的宽松 SELECT 解析器，用于 PostgreSQL、SQL Server、Oracle，
+// 以及 vitess 无法解析的 MySQL 语句
+type tokenParser struct {
+	dialect  string
+	src      string
+	toks     []Token
+	pos      int
+	scope    *cteScope
+	outer    []string // Oracle (+) 标记的可空一侧的表限定符
+	warnings []string
+}
+
+// parseTokenSelect 解析单条 SELECT（可带 CREATE VIEW 头部与 WITH 子句）
+func parseTokenSelect(dialect, sql string) (*SelectInfo, error) {
+	p := &tokenParser{dialect: dialect, src: sql, toks: Tokenize(dialect, sql), scope: newCTEScope(nil)}
+	for p.acceptPunct(";") {
+	}
+	aliases := p.skipViewHeader()
+	info, err := p.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+	if len(aliases) > 0 {
+		info.Aliases = aliases
+	}
+	info.Warnings = append(info.Warnings, p.warnings...)
+	return info, nil
+}
+
+func (p *tokenParser) warn(format string, args ...interface{}) {
+	p.warnings = append(p.warnings, fmt.Sprintf(format, args...))
+}
+
+func (p *tokenParser) peekAt(off int) Token {
+	if p.pos+off < len(p.toks) {
+		return p.toks[p.pos+off]
+	}
+	return Token{Kind: tokenEOF, Start: len(p.src), End: len(p.src)}
+}
+
+func (p *tokenParser) peek() Token { return p.peekAt(0) }
+
+func (p *tokenParser) next() Token {
+	t := p.peek()
+	if p.pos < len(p.toks) {
+		p.pos++
+	}
+	return t
+}
+
+func (p *tokenParser) eof() bool { return p.pos >= len(p.toks) }
+
+func (p *tokenParser) acceptWord(words ...string) bool {
+	if p.peek().IsWord(words...) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *tokenParser) acceptPunct(s string) bool {
+	if p.peek().IsPunct(s) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *tokenParser) expectPunct(s string) error {
+	if !p.acceptPunct(s) {
+		return fmt.Errorf("expected %q near %q", s, p.near())
+	}
+	return nil
+}
+
+// near 返回当前位置附近的原始文本，用于错误提示
+func (p *tokenParser) near() string {
+	if p.eof() {
+		return "end of input"
+	}
+	start := p.peek().Start
+	end := start + 30
+	if end > len(p.src) {
+		end = len(p.src)
+	}
+	return p.src[start:end]
+}
+
+// text 返回 [from, to) 词法单元对应的原始文本
+func (p *tokenParser) text(from, to int) string {
+	if from >= to || from >= len(p.toks) {
+		return ""
+	}
+	return p.src[p.toks[from].Start:p.toks[to-1].End]
+}
+
+func isIdentToken(t Token) bool {
+	return t.Kind == TokenWord || t.Kind == TokenQuotedIdent
+}
+
+// canAlias 判断词法单元能否作为不带 AS 的别名
+func canAlias(t Token) bool {
+	if t.Kind == TokenQuotedIdent {
+		return true
+	}
+	return t.Kind == TokenWord && !reservedAliasWords[t.Upper()]
+}
+
+// skipParens 跳过以当前 ( 开始的括号组
+func (p *tokenParser) skipParens() {
+	if !p.acceptPunct("(") {
+		return
+	}
+	depth := 1
+	for !p.eof() && depth > 0 {
+		t := p.next()
+		if t.IsPunct("(") {
+			depth++
+		} else if t.IsPunct(")") {
+			depth--
+		}
+	}
+}
+
+// skipUntil 跳过括号平衡的内容，直到遇到顶层的 stop 单元或未匹配的 )
+func (p *tokenParser) skipUntil(stop func(Token) bool) {
+	depth := 0
+	for !p.eof() {
+		t := p.peek()
+		if depth == 0 && stop(t) {
+			return
+		}
+		if t.IsPunct("(") {
+			depth++
+		} else if t.IsPunct(")") {
+			if depth == 0 {
+				return
+			}
+			depth--
+		}
+		p.pos++
+	}
+}
+
+func isSelectEnd(t Token) bool {
+	return t.IsPunct(";") || t.IsWord(selectEndWords...)
+}
+
+// skipViewHeader 跳过 CREATE [OR REPLACE|ALTER] VIEW name [(cols)] ... AS，返回视图的列清单
+func (p *tokenParser) skipViewHeader() []string {
+	if !p.peek().IsWord("CREATE", "ALTER") {
+		return nil
+	}
+	var aliases []string
+	for !p.eof() {
+		t := p.next()
+		if t.IsWord("VIEW") {
+			p.parseQualifiedName()
+			if p.peek().IsPunct("(") {
+				aliases, _ = p.parseIdentList()
+			}
+			continue
+		}
+		if t.IsWord("AS") && (p.peek().IsWord("SELECT", "WITH") || p.peek().IsPunct("(")) {
+			return aliases
+		}
+	}
+	return aliases
+}
+
+// parseIdentList 解析 (a, b, c)
+func (p *tokenParser) parseIdentList() ([]string, error) {
+	if err := p.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	for {
+		t := p.next()
+		if !isIdentToken(t) {
+			return names, fmt.Errorf("expected identifier near %q", p.near())
+		}
+		names = append(names, t.Ident())
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	return names, p.expectPunct(")")
+}
+
+// parseQualifiedName 解析 a.b.c 形式的名称，db..table 中省略的部分为空字符串
+func (p *tokenParser) parseQualifiedName() []string {
+	var parts []string
+	for isIdentToken(p.peek()) {
+		parts = append(parts, p.next().Ident())
+		if !p.peek().IsPunct(".") || !(isIdentToken(p.peekAt(1)) || p.peekAt(1).IsPunct(".")) {
+			break
+		}
+		p.next()
+		for p.peek().IsPunct(".") {
+			parts = append(parts, "")
+			p.next()
+		}
+	}
+	return parts
+}
+
+// parseQuery 解析 [WITH ...] 查询主体 [集合运算] [ORDER BY/LIMIT ...]
+func (p *tokenParser) parseQuery() (*SelectInfo, error) {
+	if p.peek().IsWord("WITH") {
+		outer := p.scope
+		p.scope = newCTEScope(outer)
+		defer func() { p.scope = outer }()
+		p.next()
+		p.acceptWord("RECURSIVE")
+		if err := p.parseCTEs(); err != nil {
+			return nil, err
+		}
+	}
+	info, err := p.parseSelectCore()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().IsWord("UNION", "INTERSECT", "EXCEPT", "MINUS") {
+		p.next()
+		p.acceptWord("ALL", "DISTINCT")
+		branch, err := p.parseSelectCore()
+		if err != nil {
+			p.warn("cannot parse set operation branch: %v", err)
+			p.skipUntil(func(t Token) bool { return t.IsPunct(";") })
+			break
+		}
+		info.Unions = append(info.Unions, branch)
+	}
+	// ORDER BY / LIMIT / OFFSET / FETCH / FOR UPDATE 不影响输出列
+	p.skipUntil(func(t Token) bool { return t.IsPunct(";") })
+	return info, nil
+}
+
+// parseCTEs 解析 WITH 子句中的命名查询，递归 CTE 在定义完成前以空查询占位
+func (p *tokenParser) parseCTEs() error {
+	for {
+		t := p.next()
+		if !isIdentToken(t) {
+			return fmt.Errorf("expected CTE name near %q", p.near())
+		}
+		name := t.Ident()
+		var cols []string
+		if p.peek().IsPunct("(") {
+			var err error
+			if cols, err = p.parseIdentList(); err != nil {
+				return err
+			}
+		}
+		if !p.acceptWord("AS") {
+			return fmt.Errorf("expected AS after CTE %s", name)
+		}
+		p.acceptWord("NOT")
+		p.acceptWord("MATERIALIZED")
+		if err := p.expectPunct("("); err != nil {
+			return err
+		}
+		p.scope.define(name, &SelectInfo{})
+		info, err := p.parseQuery()
+		if err != nil {
+			p.warn("cannot parse CTE %s: %v", name, err)
+			info = &SelectInfo{}
+			p.skipUntil(func(Token) bool { return false })
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return err
+		}
+		info.Aliases = cols
+		p.scope.define(name, info)
+		// Oracle 的 SEARCH/CYCLE 子句
+		p.skipUntil(func(t Token) bool { return t.IsPunct(",") || t.IsPunct("(") || t.IsWord("SELECT", "WITH") })
+		if !p.acceptPunct(",") {
+			return nil
+		}
+	}
+}
+
+// parseSelectCore 解析单个 SELECT 主体或括号中的查询
+func (p *tokenParser) parseSelectCore() (*SelectInfo, error) {
+	if p.acceptPunct("(") {
+		info, err := p.parseQuery()
+		if err != nil {
+			return nil, err
+		}
+		return info, p.expectPunct(")")
+	}
+	if !p.acceptWord("SELECT") {
+		return nil, fmt.Errorf("expected SELECT near %q", p.near())
+	}
+	p.acceptWord("ALL", "UNIQUE")
+	if p.acceptWord("DISTINCT") && p.acceptWord("ON") {
+		p.skipParens()
+	}
+	if p.acceptWord("TOP") {
+		if p.peek().IsPunct("(") {
+			p.skipParens()
+		} else {
+			p.next()
+		}
+		p.acceptWord("PERCENT")
+		if p.acceptWord("WITH") {
+			p.acceptWord("TIES")
+		}
+	}
+
+	outerMarks := p.outer
+	p.outer = nil
+	defer func() { p.outer = outerMarks }()
+
+	info := &SelectInfo{}
+	for {
+		info.Columns = append(info.Columns, p.parseSelectItem())
+		if !p.acceptPunct(",") {
+			break
+		}
+	}
+	if p.acceptWord("INTO") {
+		p.skipUntil(func(t Token) bool { return t.IsWord("FROM", "WHERE") || isSelectEnd(t) })
+	}
+	if p.acceptWord("FROM") {
+		tables, err := p.parseFromList()
+		info.Tables = tables
+		if err != nil {
+			p.warn("cannot fully parse FROM clause: %v", err)
+			p.skipUntil(func(t Token) bool { return t.IsWord("WHERE") || isSelectEnd(t) })
+		}
+	}
+	if p.acceptWord("WHERE") {
+		start := p.pos
+		where, err := p.parseExpr()
+		if err != nil {
+			p.pos = start
+		} else {
+			info.Where = where
+		}
+	}
+	// GROUP BY / HAVING / CONNECT BY / WINDOW 等不影响输出列
+	p.skipUntil(func(t Token) bool {
+		return isSelectEnd(t) || t.IsWord("UNION", "INTERSECT", "EXCEPT", "MINUS")
+	})
+
+	markOuterJoins(info.Tables)
+	for _, q := range p.outer {
+		for i := range info.Tables {
+			if info.Tables[i].Matches(q) {
+				info.Tables[i].Nullable = true
+			}
+		}
+	}
+	return info, nil
+}
+
+// parseSelectItem 解析输出列；无法解析的表达式保留原始文本并记录警告
+func (p *tokenParser) parseSelectItem() SelectColumn {
+	start := p.pos
+	if p.acceptPunct("*") {
+		return SelectColumn{Name: "*", Star: true}
+	}
+	if isIdentToken(p.peek()) {
+		parts := p.parseQualifiedName()
+		if len(parts) > 0 && p.peek().IsPunct(".") && p.peekAt(1).IsPunct("*") {
+			p.pos += 2
+			return SelectColumn{Name: p.text(start, p.pos), Star: true, StarQualifier: parts[len(parts)-1]}
+		}
+		p.pos = start
+	}
+
+	alias := ""
+	if p.dialect == DialectSQLServer && isIdentToken(p.peek()) && p.peekAt(1).IsPunct("=") {
+		alias = p.next().Ident()
+		p.next()
+	}
+	exprStart := p.pos
+	expr, err := p.parseExpr()
+	if err != nil {
+		p.warn("cannot parse select item: %v", err)
+		p.pos = exprStart
+		p.skipUntil(func(t Token) bool { return t.IsPunct(",") || t.IsWord("FROM") || isSelectEnd(t) })
+		text := p.text(exprStart, p.pos)
+		expr = &Expr{Kind: ExprOther, Text: text}
+	}
+
+	if p.acceptWord("AS") {
+		if t := p.next(); isIdentToken(t) || t.Kind == TokenString {
+			alias = unquoteAlias(t)
+		}
+	} else if canAlias(p.peek()) || (p.dialect == DialectSQLServer && p.peek().Kind == TokenString) {
+		alias = unquoteAlias(p.next())
+	}
+
+	if !p.selectItemEnd() {
+		p.warn("cannot parse select item near %q", p.near())
+		p.skipUntil(func(t Token) bool { return t.IsPunct(",") || t.IsWord("FROM") || isSelectEnd(t) })
+		expr = &Expr{Kind: ExprOther, Text: p.text(exprStart, p.pos)}
+		alias = ""
+	}
+
+	col := SelectColumn{Expr: expr, Name: alias}
+	if col.Name == "" {
+		if expr.Kind == ExprColumn {
+			col.Name = expr.Name
+		} else {
+			col.Name = expr.Text
+		}
+	}
+	return col
+}
+
+// selectItemEnd 判断当前位置是否为输出列的结束
+func (p *tokenParser) selectItemEnd() bool {
+	t := p.peek()
+	return t.Kind == tokenEOF || t.IsPunct(",") || t.IsPunct(")") || t.IsWord("FROM", "INTO", "WHERE") || isSelectEnd(t)
+}
+
+func unquoteAlias(t Token) string {
+	if t.Kind == TokenString && len(t.Text) >= 2 {
+		return strings.ReplaceAll(t.Text[1:len(t.Text)-1], "''", "'")
+	}
+	return t.Ident()
+}
+
+// parseFromList 解析 FROM 中以逗号分隔的来源
+func (p *tokenParser) parseFromList() ([]TableSource, error) {
+	var tables []TableSource
+	join := ""
+	for {
+		srcs, err := p.parseTableRef(join)
+		tables = append(tables, srcs...)
+		if err != nil {
+			return tables, err
+		}
+		if !p.acceptPunct(",") {
+			return tables, nil
+		}
+		join = "CROSS"
+	}
+}
+
+// parseTableRef 解析一个来源及其后续的 JOIN
+func (p *tokenParser) parseTableRef(join string) ([]TableSource, error) {
+	out, err := p.parseTablePrimary(join)
+	if err != nil {
+		return out, err
+	}
+	for {
+		jt, ok := p.parseJoinKeyword()
+		if !ok {
+			return out, nil
+		}
+		right, err := p.parseTablePrimary(jt)
+		if err != nil {
+			return append(out, right...), err
+		}
+		if len(right) > 0 {
+			if p.acceptWord("ON") {
+				on, err := p.parseExpr()
+				if err != nil {
+					return append(out, right...), err
+				}
+				right[0].On = on
+			} else if p.acceptWord("USING") {
+				cols, err := p.parseIdentList()
+				if err != nil {
+					return append(out, right...), err
+				}
+				right[0].Using = cols
+			}
+		}
+		out = append(out, right...)
+	}
+}
+
+// parseJoinKeyword 解析连接关键字，返回连接方式
+func (p *tokenParser) parseJoinKeyword() (string, bool) {
+	start := p.pos
+	p.acceptWord("NATURAL")
+	kind := "INNER"
+	switch {
+	case p.acceptWord("INNER"):
+	case p.acceptWord("LEFT"):
+		kind = "LEFT"
+	case p.acceptWord("RIGHT"):
+		kind = "RIGHT"
+	case p.acceptWord("FULL"):
+		kind = "FULL"
+	case p.acceptWord("CROSS"):
+		kind = "CROSS"
+		if p.acceptWord("APPLY") {
+			return kind, true
+		}
+	case p.peek().IsWord("OUTER") && p.peekAt(1).IsWord("APPLY"):
+		p.pos += 2
+		return "LEFT", true
+	}
+	p.acceptWord("OUTER")
+	// SQL Server 的连接提示
+	p.acceptWord("HASH", "MERGE", "LOOP", "REMOTE")
+	if p.acceptWord("JOIN") {
+		return kind, true
+	}
+	p.pos = start
+	return "", false
+}
+
+// parseTablePrimary 解析单个来源：表/视图、CTE、派生表、括号中的连接或表函数
+func (p *tokenParser) parseTablePrimary(join string) ([]TableSource, error) {
+	p.acceptWord("LATERAL")
+	p.acceptWord("ONLY")
+	src := TableSource{Join: join}
+	switch {
+	case p.peek().IsPunct("("):
+		if p.peekAt(1).IsWord("SELECT", "WITH") || p.peekAt(1).IsPunct("(") {
+			p.next()
+			sub, err := p.parseQuery()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			src.Subquery = sub
+		} else {
+			p.next()
+			inner, err := p.parseTableRef(join)
+			if err != nil {
+				return inner, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return inner, err
+			}
+			p.parseTableAlias(&TableSource{})
+			return inner, nil
+		}
+	case isIdentToken(p.peek()):
+		parts := p.parseQualifiedName()
+		if p.peek().IsPunct("(") {
+			// 表函数（如 generate_series、TABLE(...)），无法解析其列
+			p.skipParens()
+			p.warn("cannot resolve columns of table function %s", strings.Join(parts, "."))
+			src.Subquery = &SelectInfo{}
+			src.Name = parts[len(parts)-1]
+			break
+		}
+		p.assignTableName(&src, parts)
+		if len(parts) == 1 {
+			src.Subquery = p.scope.lookup(src.Name)
+		}
+		// Oracle 的数据库链接
+		if p.acceptPunct("@") {
+			p.parseQualifiedName()
+		}
+	default:
+		return nil, fmt.Errorf("expected table near %q", p.near())
+	}
+	p.parseTableAlias(&src)
+	return []TableSource{src}, nil
+}
+
+// assignTableName 按方言拆分限定名：MySQL 的限定符为数据库，其余方言为 Schema
+func (p *tokenParser) assignTableName(src *TableSource, parts []string) {
+	n := len(parts)
+	src.Name = parts[n-1]
+	if n >= 2 {
+		if p.dialect == DialectMySQL || p.dialect == DialectMariaDB {
+			src.Database = parts[n-2]
+		} else {
+			src.Schema = parts[n-2]
+		}
+	}
+	if n >= 3 {
+		src.Database = parts[n-3]
+	}
+}
+
+// parseTableAlias 解析来源后的采样、提示、别名与列别名
+func (p *tokenParser) parseTableAlias(src *TableSource) {
+	if p.acceptWord("TABLESAMPLE") {
+		p.acceptWord("SYSTEM", "BERNOULLI")
+		p.skipParens()
+	}
+	p.skipTableHints()
+	if p.acceptWord("AS") {
+		if t := p.next(); isIdentToken(t) {
+			src.Alias = t.Ident()
+		}
+	} else if canAlias(p.peek()) {
+		src.Alias = p.next().Ident()
+	}
+	if src.Alias != "" && p.peek().IsPunct("(") && (isIdentToken(p.peekAt(1)) && (p.peekAt(2).IsPunct(",") || p.peekAt(2).IsPunct(")"))) {
+		src.Columns, _ = p.parseIdentList()
+	}
+	p.skipTableHints()
+	if p.peek().IsWord("PIVOT", "UNPIVOT") {
+		p.warn("PIVOT/UNPIVOT columns are not resolved")
+		p.next()
+		p.skipParens()
+		p.parseTableAlias(&TableSource{})
+	}
+}
+
+// skipTableHints 跳过 SQL Server 的 WITH (NOLOCK) 表提示
+func (p *tokenParser) skipTableHints() {
+	if p.dialect == DialectSQLServer && p.peek().IsWord("WITH") && p.peekAt(1).IsPunct("(") {
+		p.next()
+		p.skipParens()
+	}
+}
+
+// ===== 表达式 =====
+
+func (p *tokenParser) parseExpr() (*Expr, error) {
+	return p.parseOr()
+}
+
+// compareExpr 构造比较/逻辑表达式
+func (p *tokenParser) compareExpr(start int, op string, args ...*Expr) *Expr {
+	return &Expr{Kind: ExprCompare, Name: op, Args: args, Text: p.text(start, p.pos)}
+}
+
+func (p *tokenParser) parseOr() (*Expr, error) {
+	start := p.pos
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().IsWord("OR", "XOR") {
+		op := p.next().Upper()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = p.compareExpr(start, op, left, right)
+	}
+	return left, nil
+}
+
+func (p *tokenParser) parseAnd() (*Expr, error) {
+	start := p.pos
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.acceptWord("AND") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = p.compareExpr(start, "AND", left, right)
+	}
+	return left, nil
+}
+
+func (p *tokenParser) parseNot() (*Expr, error) {
+	start := p.pos
+	if p.acceptWord("NOT") {
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return p.compareExpr(start, "NOT", e), nil
+	}
+	return p.parsePredicate()
+}
+
+// 比较运算符
+var compareOps = map[string]bool{
+	"=": true, "<>": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true, "<=>": true,
+	"~": true, "!~": true, "~*": true, "!~*": true,
+}
+
+func (p *tokenParser) parsePredicate() (*Expr, error) {
+	start := p.pos
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		switch {
+		case t.Kind == TokenPunct && compareOps[t.Text]:
+			p.next()
+			var right *Expr
+			if p.acceptWord("ANY", "ALL", "SOME") {
+				right, err = p.parsePrimary()
+			} else {
+				right, err = p.parseAdditive()
+			}
+			if err != nil {
+				return nil, err
+			}
+			left = p.compareExpr(start, t.Text, left, right)
+		case t.IsWord("IS"):
+			p.next()
+			p.acceptWord("NOT")
+			if p.acceptWord("DISTINCT") {
+				p.acceptWord("FROM")
+				right, err := p.parseAdditive()
+				if err != nil {
+					return nil, err
+				}
+				left = p.compareExpr(start, "IS DISTINCT FROM", left, right)
+				continue
+			}
+			p.next()
+			left = p.compareExpr(start, "IS", left)
+		case t.IsWord("NOT") && p.peekAt(1).IsWord("IN", "LIKE", "ILIKE", "BETWEEN", "SIMILAR", "REGEXP", "RLIKE"):
+			p.next()
+		case t.IsWord("IN") && p.peekAt(1).IsPunct("("):
+			p.next()
+			args := []*Expr{left}
+			if err := p.expectPunct("("); err != nil {
+				return nil, err
+			}
+			if p.peek().IsWord("SELECT", "WITH") {
+				sub, err := p.parseQuery()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, &Expr{Kind: ExprSubquery, Subquery: sub})
+			} else {
+				list, err := p.parseExprList()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, list...)
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			left = p.compareExpr(start, "IN", args...)
+		case t.IsWord("BETWEEN"):
+			p.next()
+			p.acceptWord("SYMMETRIC", "ASYMMETRIC")
+			from, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			if !p.acceptWord("AND") {
+				return nil, fmt.Errorf("expected AND in BETWEEN near %q", p.near())
+			}
+			to, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			left = p.compareExpr(start, "BETWEEN", left, from, to)
+		case t.IsWord("LIKE", "ILIKE", "SIMILAR", "REGEXP", "RLIKE"):
+			p.next()
+			p.acceptWord("TO")
+			right, err := p.parseAdditive()
+			if err != nil {
+				return nil, err
+			}
+			if p.acceptWord("ESCAPE") {
+				if _, err := p.parseAdditive(); err != nil {
+					return nil, err
+				}
+			}
+			left = p.compareExpr(start, t.Upper(), left, right)
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *tokenParser) parseExprList() ([]*Expr, error) {
+	var list []*Expr
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+		if !p.acceptPunct(",") {
+			return list, nil
+		}
+	}
+}
+
+// 加法级运算符（含拼接、位运算与 JSON 取值）
+var additiveOps = map[string]bool{
+	"+": true, "-": true, "||": true, "&": true, "|": true, "^": true, "<<": true, ">>": true,
+	"->": true, "->>": true, "#>": true, "#>>": true,
+}
+
+func (p *tokenParser) parseAdditive() (*Expr, error) {
+	start := p.pos
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		if t.Kind != TokenPunct || !additiveOps[t.Text] {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		e := &Expr{Kind: ExprArith, Name: t.Text, Args: []*Expr{left, right}}
+		switch t.Text {
+		case "||":
+			e.Kind = ExprConcat
+		case "->", "#>":
+			e.Kind, e.Name = ExprFunc, "JSON_EXTRACT"
+		case "->>", "#>>":
+			e.Kind, e.Name = ExprFunc, "JSON_UNQUOTE"
+		}
+		e.Text = p.text(start, p.pos)
+		left = e
+	}
+}
+
+func (p *tokenParser) parseMultiplicative() (*Expr, error) {
+	start := p.pos
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().IsPunct("*") || p.peek().IsPunct("/") || p.peek().IsPunct("%") || p.peek().IsWord("DIV", "MOD") {
+		op := p.next().Upper()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &Expr{Kind: ExprArith, Name: op, Args: []*Expr{left, right}, Text: p.text(start, p.pos)}
+	}
+	return left, nil
+}
+
+func (p *tokenParser) parseUnary() (*Expr, error) {
+	start := p.pos
+	if p.peek().IsPunct("-") || p.peek().IsPunct("+") || p.peek().IsPunct("~") {
+		p.next()
+		e, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if e.Kind == ExprLiteral {
+			e.Text = p.text(start, p.pos)
+			return e, nil
+		}
+		return &Expr{Kind: ExprArith, Args: []*Expr{e}, Text: p.text(start, p.pos)}, nil
+	}
+	// Oracle 层次查询中的 PRIOR
+	p.acceptWord("PRIOR")
+	return p.parsePostfix()
+}
+
+func (p *tokenParser) parsePostfix() (*Expr, error) {
+	start := p.pos
+	e, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.acceptPunct("::"):
+			e = &Expr{Kind: ExprCast, Type: p.parseTypeName(), Args: []*Expr{e}, Text: p.text(start, p.pos)}
+		case p.peek().IsPunct("[") && p.dialect != DialectSQLServer:
+			p.skipUntil(func(t Token) bool { return t.IsPunct("]") })
+			p.acceptPunct("]")
+			e = &Expr{Kind: ExprOther, Args: []*Expr{e}, Text: p.text(start, p.pos)}
+		case p.acceptWord("COLLATE"):
+			p.parseQualifiedName()
+			e.Text = p.text(start, p.pos)
+		case p.peek().IsWord("AT") && p.peekAt(1).IsWord("TIME"):
+			p.pos += 2
+			p.acceptWord("ZONE")
+			if _, err := p.parsePrimary(); err != nil {
+				return nil, err
+			}
+			e.Text = p.text(start, p.pos)
+		case p.dialect == DialectOracle && p.peek().IsPunct("(") && p.peekAt(1).IsPunct("+") && p.peekAt(2).IsPunct(")"):
+			p.pos += 3
+			if e.Kind == ExprColumn && e.Qualifier != "" {
+				p.outer = append(p.outer, e.Qualifier)
+			}
+		default:
+			return e, nil
+		}
+	}
+}
+
+// parsePrimary 解析字面量、列引用、函数调用、CASE、CAST 与括号表达式
+func (p *tokenParser) parsePrimary() (*Expr, error) {
+	start := p.pos
+	t := p.peek()
+	switch t.Kind {
+	case TokenNumber:
+		p.next()
+		typ := "BIGINT"
+		if strings.ContainsAny(t.Text, "eE") {
+			typ = "DOUBLE"
+		} else if strings.Contains(t.Text, ".") {
+			typ = "DECIMAL"
+		}
+		return &Expr{Kind: ExprLiteral, Type: typ, Text: t.Text}, nil
+	case TokenString:
+		p.next()
+		return &Expr{Kind: ExprLiteral, Type: "VARCHAR", Text: t.Text}, nil
+	case TokenParam:
+		p.next()
+		return &Expr{Kind: ExprOther, Text: t.Text}, nil
+	case TokenPunct:
+		if !t.IsPunct("(") {
+			return nil, fmt.Errorf("unexpected %q", p.near())
+		}
+		p.next()
+		if p.peek().IsWord("SELECT", "WITH") {
+			sub, err := p.parseQuery()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			return &Expr{Kind: ExprSubquery, Subquery: sub, Text: p.text(start, p.pos)}, nil
+		}
+		list, err := p.parseExprList()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		if len(list) == 1 {
+			e := *list[0]
+			e.Text = p.text(start, p.pos)
+			return &e, nil
+		}
+		return &Expr{Kind: ExprOther, Args: list, Text: p.text(start, p.pos)}, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of input")
+	}
+
+	if t.Kind == TokenWord {
+		switch t.Upper() {
+		case "NULL":
+			p.next()
+			return &Expr{Kind: ExprLiteral, Type: "NULL", Text: t.Text}, nil
+		case "TRUE", "FALSE":
+			p.next()
+			return &Expr{Kind: ExprLiteral, Type: "BOOLEAN", Text: t.Text}, nil
+		case "CASE":
+			return p.parseCase()
+		case "CAST", "TRY_CAST", "SAFE_CAST":
+			if p.peekAt(1).IsPunct("(") {
+				return p.parseCast()
+			}
+		case "CONVERT", "TRY_CONVERT":
+			if p.dialect == DialectSQLServer && p.peekAt(1).IsPunct("(") {
+				return p.parseSQLServerConvert()
+			}
+		case "EXISTS":
+			if p.peekAt(1).IsPunct("(") {
+				p.next()
+				sub, err := p.parsePrimary()
+				if err != nil {
+					return nil, err
+				}
+				return p.compareExpr(start, "EXISTS", sub), nil
+			}
+		case "INTERVAL":
+			p.next()
+			if _, err := p.parsePrimary(); err != nil {
+				return nil, err
+			}
+			for p.peek().IsWord("YEAR", "MONTH", "DAY", "HOUR", "MINUTE", "SECOND", "TO") {
+				p.next()
+				if p.peek().IsPunct("(") {
+					p.skipParens()
+				}
+			}
+			return &Expr{Kind: ExprLiteral, Type: "INTERVAL", Text: p.text(start, p.pos)}, nil
+		case "DATE", "TIME", "TIMESTAMP":
+			if p.peekAt(1).Kind == TokenString {
+				p.pos += 2
+				typ := t.Upper()
+				if typ == "TIMESTAMP" {
+					typ = "DATETIME"
+				}
+				return &Expr{Kind: ExprLiteral, Type: typ, Text: p.text(start, p.pos)}, nil
+			}
+		case "ARRAY":
+			if p.peekAt(1).IsPunct("[") || p.peekAt(1).IsPunct("(") {
+				p.next()
+				if p.peek().IsPunct("(") {
+					p.skipParens()
+				} else {
+					p.skipUntil(func(t Token) bool { return t.IsPunct("]") })
+					p.acceptPunct("]")
+				}
+				return &Expr{Kind: ExprLiteral, Type: "ARRAY", Text: p.text(start, p.pos)}, nil
+			}
+		}
+	}
+
+	if !isIdentToken(t) {
+		return nil, fmt.Errorf("unexpected %q", p.near())
+	}
+	parts := p.parseQualifiedName()
+	oracleOuter := p.dialect == DialectOracle && p.peekAt(1).IsPunct("+") && p.peekAt(2).IsPunct(")")
+	if p.peek().IsPunct("(") && !oracleOuter {
+		return p.parseFuncCall(start, strings.ToUpper(parts[len(parts)-1]))
+	}
+	if len(parts) == 1 && t.Kind == TokenWord && niladicFuncs[t.Upper()] {
+		return &Expr{Kind: ExprFunc, Name: t.Upper(), Text: t.Text}, nil
+	}
+	e := &Expr{Kind: ExprColumn, Name: parts[len(parts)-1], Text: p.text(start, p.pos)}
+	if len(parts) > 1 {
+		e.Qualifier = strings.Join(parts[:len(parts)-1], ".")
+	}
+	return e, nil
+}
+
+// parseFuncCall 解析函数参数及其后的 WITHIN GROUP / FILTER / OVER / KEEP 子句
+func (p *tokenParser) parseFuncCall(start int, name string) (*Expr, error) {
+	p.next() // (
+	e := &Expr{Kind: ExprFunc, Name: name}
+	p.acceptWord("DISTINCT", "ALL")
+	p.acceptWord("BOTH", "LEADING", "TRAILING")
+	if name == "EXTRACT" && p.peek().Kind == TokenWord && p.peekAt(1).IsWord("FROM") {
+		// EXTRACT(YEAR FROM x) 中的 YEAR 不是列引用
+		p.pos += 2
+	}
+	if p.acceptPunct("*") {
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+	} else if !p.acceptPunct(")") {
+		for {
+			if p.peek().IsWord("FROM") {
+				// TRIM(FROM x) 等省略了首个参数的写法
+				p.next()
+			}
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			e.Args = append(e.Args, arg)
+			if p.acceptPunct(",") {
+				continue
+			}
+			// POSITION(a IN b)、SUBSTRING(a FROM x FOR y)、EXTRACT(f FROM x)、CAST 风格的 AS
+			if p.acceptWord("FROM", "FOR", "IN", "PLACING", "USING", "AS") {
+				continue
+			}
+			if p.peek().IsWord("ORDER", "SEPARATOR", "IGNORE", "RESPECT", "ON") {
+				p.skipUntil(func(Token) bool { return false })
+			}
+			if err := p.expectPunct(")"); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	if p.peek().IsWord("WITHIN") && p.peekAt(1).IsWord("GROUP") {
+		p.pos += 2
+		p.skipParens()
+	}
+	if p.peek().IsWord("FILTER") && p.peekAt(1).IsPunct("(") {
+		p.next()
+		p.skipParens()
+	}
+	if p.peek().IsWord("KEEP") && p.peekAt(1).IsPunct("(") {
+		p.next()
+		p.skipParens()
+	}
+	p.acceptWord("IGNORE", "RESPECT")
+	p.acceptWord("NULLS")
+	if p.acceptWord("OVER") {
+		if p.peek().IsPunct("(") {
+			p.skipParens()
+		} else {
+			p.next()
+		}
+	}
+	e.Text = p.text(start, p.pos)
+	return e, nil
+}
+
+// parseCase 解析 CASE [x] WHEN ... THEN ... [ELSE ...] END
+func (p *tokenParser) parseCase() (*Expr, error) {
+	start := p.pos
+	p.next()
+	e := &Expr{Kind: ExprCase}
+	if !p.peek().IsWord("WHEN") {
+		operand, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		e.Conds = append(e.Conds, operand)
+	}
+	for p.acceptWord("WHEN") {
+		cond, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptWord("THEN") {
+			return nil, fmt.Errorf("expected THEN near %q", p.near())
+		}
+		val, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		e.Conds = append(e.Conds, cond)
+		e.Args = append(e.Args, val)
+	}
+	if p.acceptWord("ELSE") {
+		val, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		e.HasElse = true
+		e.Args = append(e.Args, val)
+	}
+	if !p.acceptWord("END") {
+		return nil, fmt.Errorf("expected END near %q", p.near())
+	}
+	e.Text = p.text(start, p.pos)
+	return e, nil
+}
+
+// parseCast 解析 CAST(x AS type)
+func (p *tokenParser) parseCast() (*Expr, error) {
+	start := p.pos
+	p.pos += 2
+	arg, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.acceptWord("AS") {
+		return nil, fmt.Errorf("expected AS in CAST near %q", p.near())
+	}
+	typ := p.parseTypeName()
+	p.skipUntil(func(Token) bool { return false })
+	if err := p.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	return &Expr{Kind: ExprCast, Type: typ, Args: []*Expr{arg}, Text: p.text(start, p.pos)}, nil
+}
+
+// parseSQLServerConvert 解析 SQL Server 的 CONVERT(type, x [, style])
+func (p *tokenParser) parseSQLServerConvert() (*Expr, error) {
+	start := p.pos
+	p.pos += 2
+	typ := p.parseTypeName()
+	if err := p.expectPunct(","); err != nil {
+		return nil, err
+	}
+	arg, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.acceptPunct(",") {
+		if _, err := p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	return &Expr{Kind: ExprCast, Type: typ, Args: []*Expr{arg}, Text: p.text(start, p.pos)}, nil
+}
+
+// 类型名可以包含的后续单词，如 DOUBLE PRECISION、CHARACTER VARYING
+var typeNameWords = map[string]bool{
+	"PRECISION": true, "VARYING": true, "UNSIGNED": true, "SIGNED": true, "INTEGER": true,
+	"CHAR": true, "BYTE": true, "VARCHAR": true, "RAW": true,
+}
+
+// parseTypeName 解析类型名称（含长度/精度、时区修饰与数组后缀），返回大写形式
+func (p *tokenParser) parseTypeName() string {
+	start := p.pos
+	parts := p.parseQualifiedName()
+	if len(parts) == 0 {
+		return "UNKNOWN"
+	}
+	for {
+		switch {
+		case p.peek().IsPunct("("):
+			p.skipParens()
+		case p.peek().Kind == TokenWord && typeNameWords[p.peek().Upper()]:
+			p.next()
+		case p.peek().IsWord("WITH", "WITHOUT") && p.peekAt(1).IsWord("TIME", "LOCAL"):
+			p.next()
+			p.acceptWord("LOCAL")
+			p.acceptWord("TIME")
+			p.acceptWord("ZONE")
+		case p.peek().IsPunct("[") && p.dialect != DialectSQLServer:
+			p.next()
+			p.acceptPunct("]")
+		default:
+			typ := strings.ToUpper(strings.Join(strings.Fields(p.text(start, p.pos)), " "))
+			typ = strings.TrimPrefix(typ, "PG_CATALOG.")
+			typ = strings.NewReplacer(`"`, "", "[", "", "]", "").Replace(typ)
+			return typ
+		}
+	}
+}
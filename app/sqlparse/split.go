@@ -0,0 +1,83 @@
+package sqlparse
+
+import "strings"
+
+// Piece 拆分后的单条语句
+type Piece struct {
+	SQL    string
+	Tokens []Token
+}
+
+// Split 按方言将多条语句拆分为单条语句，注释与空语句会被忽略
+func Split(dialect, sql string) []Piece {
+	tokens := Tokenize(dialect, sql)
+	var pieces []Piece
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			seg := tokens[start:end]
+			pieces = append(pieces, Piece{SQL: sql[seg[0].Start:seg[len(seg)-1].End], Tokens: seg})
+		}
+	}
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		// Oracle 的 PL/SQL 块内部包含分号，直到单独一行的 "/" 才结束
+		if dialect == DialectOracle && i == start && isPLSQLBlock(tokens[start:]) {
+			end := len(tokens)
+			for j := i; j < len(tokens); j++ {
+				if tokens[j].IsPunct("/") && onOwnLine(sql, tokens[j]) {
+					end = j
+					break
+				}
+			}
+			flush(end)
+			i = end
+			start = end + 1
+			continue
+		}
+		// SQL Server 的批处理分隔符 GO
+		if dialect == DialectSQLServer && t.IsWord("GO") && onOwnLine(sql, t) {
+			flush(i)
+			start = i + 1
+			continue
+		}
+		if t.IsPunct(";") {
+			flush(i)
+			start = i + 1
+		}
+	}
+	flush(len(tokens))
+	return pieces
+}
+
+// isPLSQLBlock 判断语句是否以 PL/SQL 块开头
+func isPLSQLBlock(tokens []Token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	if tokens[0].IsWord("DECLARE", "BEGIN") {
+		return true
+	}
+	if !tokens[0].IsWord("CREATE") {
+		return false
+	}
+	for i := 1; i < len(tokens) && i < 6; i++ {
+		if tokens[i].IsWord("FUNCTION", "PROCEDURE", "PACKAGE", "TRIGGER", "TYPE") {
+			return true
+		}
+	}
+	return false
+}
+
+// onOwnLine 判断词法单元是否独占一行
+func onOwnLine(sql string, t Token) bool {
+	lineStart := strings.LastIndexByte(sql[:t.Start], '\n') + 1
+	if strings.TrimSpace(sql[lineStart:t.Start]) != "" {
+		return false
+	}
+	rest := sql[t.End:]
+	if nl := strings.IndexByte(rest, '\n'); nl >= 0 {
+		rest = rest[:nl]
+	}
+	return strings.TrimSpace(rest) == ""
+}
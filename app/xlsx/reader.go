@@ -0,0 +1,207 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Sheet 读取出的工作表，单元格统一为字符串，空单元格为空串
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+type xmlRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xmlWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xmlText 共享字符串或内联字符串，富文本时由多个片段拼接
+type xmlText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xmlText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	b.WriteString(t.T)
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xmlWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string   `xml:"r,attr"`
+			T  string   `xml:"t,attr"`
+			V  string   `xml:"v"`
+			IS *xmlText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// OpenFile 读取 xlsx 文件中的全部工作表
+func OpenFile(name string) ([]Sheet, error) {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, fmt.Errorf("open workbook failed: %w", err)
+	}
+	defer zr.Close()
+	return ReadSheets(&zr.Reader)
+}
+
+// ReadSheets 按工作簿中的顺序读取全部工作表
+func ReadSheets(zr *zip.Reader) ([]Sheet, error) {
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	var wb xmlWorkbook
+	if err := decodeEntry(files, "xl/workbook.xml", &wb); err != nil {
+		return nil, err
+	}
+	var rels xmlRelationships
+	if err := decodeEntry(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	targets := map[string]string{}
+	sharedPath := "xl/sharedStrings.xml"
+	for _, r := range rels.Items {
+		target := r.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		targets[r.ID] = target
+		if strings.HasSuffix(r.Type, "/sharedStrings") {
+			sharedPath = target
+		}
+	}
+
+	var shared []string
+	if _, ok := files[sharedPath]; ok {
+		var sst struct {
+			Items []xmlText `xml:"si"`
+		}
+		if err := decodeEntry(files, sharedPath, &sst); err != nil {
+			return nil, err
+		}
+		shared = make([]string, len(sst.Items))
+		for i, si := range sst.Items {
+			shared[i] = si.String()
+		}
+	}
+
+	sheets := make([]Sheet, 0, len(wb.Sheets))
+	for _, s := range wb.Sheets {
+		target, ok := targets[s.RID]
+		if !ok {
+			return nil, fmt.Errorf("sheet %s has no worksheet part", s.Name)
+		}
+		var ws xmlWorksheet
+		if err := decodeEntry(files, target, &ws); err != nil {
+			return nil, err
+		}
+		sheet := Sheet{Name: s.Name}
+		for _, row := range ws.Rows {
+			r := row.R
+			if r <= 0 {
+				r = len(sheet.Rows) + 1
+			}
+			// 补齐被省略的空行
+			for len(sheet.Rows) < r {
+				sheet.Rows = append(sheet.Rows, nil)
+			}
+			var cells []string
+			for _, c := range row.Cells {
+				col := len(cells)
+				if idx, err := columnIndex(c.R); err == nil && idx > col {
+					col = idx
+				}
+				value := c.V
+				switch c.T {
+				case "s":
+					idx, err := strconv.Atoi(strings.TrimSpace(c.V))
+					if err != nil || idx < 0 || idx >= len(shared) {
+						return nil, fmt.Errorf("sheet %s: invalid shared string index %q", s.Name, c.V)
+					}
+					value = shared[idx]
+				case "inlineStr":
+					if c.IS != nil {
+						value = c.IS.String()
+					}
+				}
+				cells = append(padCells(cells, col), value)
+			}
+			sheet.Rows[r-1] = cells
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+func decodeEntry(files map[string]*zip.File, name string, v interface{}) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("invalid workbook: missing %s", name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("parse %s failed: %w", name, err)
+	}
+	return nil
+}
+
+func padCells(cells []string, n int) []string {
+	for len(cells) < n {
+		cells = append(cells, "")
+	}
+	return cells
+}
+
+// columnIndex 将单元格引用（如 "AB12"）的列部分转换为从 0 开始的序号
+func columnIndex(ref string) (int, error) {
+	n := 0
+	i := 0
+	for ; i < len(ref); i++ {
+		ch := ref[i]
+		if ch >= 'a' && ch <= 'z' {
+			ch -= 'a' - 'A'
+		}
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		n = n*26 + int(ch-'A'+1)
+	}
+	if i == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return n - 1, nil
+}
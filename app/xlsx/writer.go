@@ -0,0 +1,325 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxRows 单个工作表允许的最大行数
+const MaxRows = 1048576
+
+// Writer 流式写出 xlsx 文件
+// 单元格使用内联字符串（inlineStr），行数据直接写入压缩流，不在内存中保留
+type Writer struct {
+	zw        *zip.Writer
+	sheet     *bufio.Writer
+	sheetOpen bool
+	sheets    []string
+	rows      int // 当前工作表已写入的行数
+}
+
+// NewWriter 创建写出到 w 的 xlsx 写入器，调用方需在结束时调用 Close
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// Rows 返回当前工作表已写入的行数
+func (w *Writer) Rows() int { return w.rows }
+
+// NewSheet 结束当前工作表并开始新的工作表
+func (w *Writer) NewSheet(name string) error {
+	if err := w.closeSheet(); err != nil {
+		return err
+	}
+	name = w.uniqueSheetName(SanitizeSheetName(name))
+	entry, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)+1))
+	if err != nil {
+		return err
+	}
+	w.sheets = append(w.sheets, name)
+	w.sheet = bufio.NewWriterSize(entry, 64*1024)
+	w.sheetOpen = true
+	w.rows = 0
+	_, err = w.sheet.WriteString(xmlHeader +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetData>`)
+	return err
+}
+
+// WriteHeader 写入加粗的表头行
+func (w *Writer) WriteHeader(names []string) error {
+	cells := make([]interface{}, len(names))
+	for i, n := range names {
+		cells[i] = n
+	}
+	return w.writeRow(cells, styleBold)
+}
+
+// WriteRow 写入一行数据；支持字符串、整数、浮点、布尔、时间与 nil
+func (w *Writer) WriteRow(cells []interface{}) error {
+	return w.writeRow(cells, styleNone)
+}
+
+func (w *Writer) writeRow(cells []interface{}, style int) error {
+	if !w.sheetOpen {
+		if err := w.NewSheet(fmt.Sprintf("Sheet%d", len(w.sheets)+1)); err != nil {
+			return err
+		}
+	}
+	if w.rows >= MaxRows {
+		return fmt.Errorf("sheet %s exceeds the maximum of %d rows", w.sheets[len(w.sheets)-1], MaxRows)
+	}
+	w.rows++
+	b := w.sheet
+	b.WriteString(`<row r="`)
+	b.WriteString(strconv.Itoa(w.rows))
+	b.WriteString(`">`)
+	for i, v := range cells {
+		if v == nil {
+			continue
+		}
+		ref := ColumnName(i) + strconv.Itoa(w.rows)
+		writeCell(b, ref, v, style)
+	}
+	_, err := b.WriteString(`</row>`)
+	return err
+}
+
+// writeCell 写入单个单元格
+func writeCell(b *bufio.Writer, ref string, v interface{}, style int) {
+	styleAttr := ""
+	if style != styleNone {
+		styleAttr = ` s="` + strconv.Itoa(style) + `"`
+	}
+	number := func(s string) {
+		b.WriteString(`<c r="` + ref + `"` + styleAttr + `><v>` + s + `</v></c>`)
+	}
+	switch val := v.(type) {
+	case int:
+		number(strconv.Itoa(val))
+	case int32:
+		number(strconv.FormatInt(int64(val), 10))
+	case int64:
+		number(strconv.FormatInt(val, 10))
+	case uint64:
+		number(strconv.FormatUint(val, 10))
+	case float32:
+		writeFloat(b, ref, styleAttr, float64(val))
+	case float64:
+		writeFloat(b, ref, styleAttr, val)
+	case bool:
+		bv := "0"
+		if val {
+			bv = "1"
+		}
+		b.WriteString(`<c r="` + ref + `"` + styleAttr + ` t="b"><v>` + bv + `</v></c>`)
+	case time.Time:
+		writeInlineString(b, ref, styleAttr, val.Format("2006-01-02 15:04:05"))
+	case string:
+		writeInlineString(b, ref, styleAttr, val)
+	default:
+		writeInlineString(b, ref, styleAttr, fmt.Sprint(val))
+	}
+}
+
+func writeFloat(b *bufio.Writer, ref, styleAttr string, f float64) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		writeInlineString(b, ref, styleAttr, strconv.FormatFloat(f, 'g', -1, 64))
+		return
+	}
+	b.WriteString(`<c r="` + ref + `"` + styleAttr + `><v>` + strconv.FormatFloat(f, 'g', -1, 64) + `</v></c>`)
+}
+
+func writeInlineString(b *bufio.Writer, ref, styleAttr, s string) {
+	b.WriteString(`<c r="` + ref + `"` + styleAttr + ` t="inlineStr"><is><t xml:space="preserve">`)
+	b.WriteString(EscapeText(s))
+	b.WriteString(`</t></is></c>`)
+}
+
+// closeSheet 结束当前工作表
+func (w *Writer) closeSheet() error {
+	if !w.sheetOpen {
+		return nil
+	}
+	w.sheetOpen = false
+	if _, err := w.sheet.WriteString(`</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return w.sheet.Flush()
+}
+
+// Close 写入工作簿结构文件并关闭压缩流（不关闭底层 io.Writer）
+func (w *Writer) Close() error {
+	if len(w.sheets) == 0 {
+		if err := w.NewSheet("Sheet1"); err != nil {
+			return err
+		}
+	}
+	if err := w.closeSheet(); err != nil {
+		return err
+	}
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", w.contentTypes()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", w.workbook()},
+		{"xl/_rels/workbook.xml.rels", w.workbookRels()},
+		{"xl/styles.xml", stylesXML},
+	}
+	for _, f := range files {
+		entry, err := w.zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(entry, f.content); err != nil {
+			return err
+		}
+	}
+	return w.zw.Close()
+}
+
+func (w *Writer) contentTypes() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (w *Writer) workbook() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, name := range w.sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, EscapeText(name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (w *Writer) workbookRels() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// uniqueSheetName 工作表名称在工作簿内不区分大小写唯一
+func (w *Writer) uniqueSheetName(name string) string {
+	exists := func(n string) bool {
+		for _, s := range w.sheets {
+			if strings.EqualFold(s, n) {
+				return true
+			}
+		}
+		return false
+	}
+	if !exists(name) {
+		return name
+	}
+	for i := 2; ; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		base := []rune(name)
+		if len(base)+len([]rune(suffix)) > 31 {
+			base = base[:31-len([]rune(suffix))]
+		}
+		candidate := string(base) + suffix
+		if !exists(candidate) {
+			return candidate
+		}
+	}
+}
+
+// SanitizeSheetName 去除工作表名称中的非法字符并截断到31个字符
+func SanitizeSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '[', ']', ':', '*', '?', '/', '\\':
+			return '_'
+		}
+		return r
+	}, strings.Trim(name, "'"))
+	if r := []rune(name); len(r) > 31 {
+		name = string(r[:31])
+	}
+	if strings.TrimSpace(name) == "" {
+		name = "Sheet"
+	}
+	return name
+}
+
+// ColumnName 将从0开始的列序号转换为列名（A、B、...、Z、AA...）
+func ColumnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+// EscapeText 转义XML文本，并移除XML 1.0不允许的控制字符
+func EscapeText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		case '\t', '\n', '\r':
+			b.WriteRune(r)
+		default:
+			if r < 0x20 || r == 0xFFFE || r == 0xFFFF {
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+const (
+	styleNone = 0
+	styleBold = 1
+)
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRels = xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+// stylesXML 最小样式表：0 为默认样式，1 为加粗（表头）
+const stylesXML = xmlHeader + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -18,7 +18,7 @@ export function InitCache(arg1:string):Promise<void>;
 
 export function ListDatabasesByConfig(arg1:connect.Config):Promise<models.DBInfoVO>;
 
-export function ParseViewSQL(arg1:string):Promise<Array<models.FieldInfoVO>>;
+export function ParseViewSQL(arg1:number,arg2:string):Promise<models.ViewParseResult>;
 
 export function SetTableVOCacheByTableID(arg1:number,arg2:service.TableCacheVO):Promise<void>;
 
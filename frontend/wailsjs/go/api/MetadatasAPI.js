@@ -30,8 +30,8 @@ export function ListDatabasesByConfig(arg1) {
   return window['go']['api']['MetadatasAPI']['ListDatabasesByConfig'](arg1);
 }
 
-export function ParseViewSQL(arg1) {
-  return window['go']['api']['MetadatasAPI']['ParseViewSQL'](arg1);
+export function ParseViewSQL(arg1, arg2) {
+  return window['go']['api']['MetadatasAPI']['ParseViewSQL'](arg1, arg2);
 }
 
 export function SetTableVOCacheByTableID(arg1, arg2) {
@@ -268,6 +268,42 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class ViewParseResult {
+	    viewId: number;
+	    dialect: string;
+	    fields: FieldInfoVO[];
+	    warnings: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ViewParseResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.viewId = source["viewId"];
+	        this.dialect = source["dialect"];
+	        this.fields = this.convertValues(source["fields"], FieldInfoVO);
+	        this.warnings = source["warnings"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class SchemaVO {
 	    id: number;
 	    databaseId: number;
@@ -10,6 +10,7 @@ require (
 	github.com/sijms/go-ora/v2 v2.8.22
 	github.com/wailsapp/wails/v2 v2.10.1
 	go.etcd.io/bbolt v1.4.3
+	golang.org/x/text v0.22.0
 	gorm.io/driver/sqlite v1.6.0
 	gorm.io/gorm v1.30.0
 	vitess.io/vitess v0.21.3
@@ -49,7 +50,6 @@ require (
 	golang.org/x/crypto v0.33.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
-	golang.org/x/text v0.22.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240903143218-8af14fe29dc1 // indirect
 	google.golang.org/grpc v1.66.2 // indirect
 	google.golang.org/protobuf v1.34.2 // indirect
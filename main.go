@@ -32,6 +32,7 @@ func main() {
 			app.metadatasAPI,
 			app.appCache,
 			app.systemServiceAPI,
+			app.queryAPI,
 		},
 	})
 